@@ -0,0 +1,15 @@
+package conf
+
+import "time"
+
+const (
+	AnalyticsAggregateSpec     = "* * * * *"         // 每分钟将缓冲区中的统计事件汇总落库
+	AnalyticsDefaultRange      = 7 * 24 * time.Hour  // 不指定时间范围时统计最近 7 天
+	AnalyticsMaxRange          = 90 * 24 * time.Hour // 单次查询的最大时间范围
+	AnalyticsHourlyMaxRange    = 72 * time.Hour      // 时间范围不超过该值时按小时返回, 否则按天
+	AnalyticsHourlyRetention   = 30 * 24 * time.Hour // 小时粒度统计的保留时间, 更早的只保留按天统计
+	AnalyticsPruneBatchSize    = 1000                // 每次汇总后至多清理的过期小时统计行数
+	AnalyticsTopPostSize       = 10                  // 表现最好的帖子数
+	AnalyticsTopReferrerSize   = 10                  // 浏览数最多的来源数
+	AnalyticsReferrerMaxLength = 64                  // 来源域名的最大长度, 超出时截断
+)
@@ -0,0 +1,9 @@
+package conf
+
+const (
+	ArchiveImportMaxSize  = 20 << 20 // 导入的压缩包最大 20 MB
+	ArchiveImportMaxFiles = 500      // 压缩包中最多的 Markdown 文件数
+	ArchiveFileMaxSize    = 1 << 20  // 单个 Markdown 文件解压后最大 1 MB, 防止压缩炸弹
+	ArchiveExportMaxPosts = 1000     // 单次导出的最多帖子数
+	ArchiveExportPageSize = 100      // 导出时每批读取的帖子数
+)
@@ -0,0 +1,39 @@
+package conf
+
+import "time"
+
+const (
+	AttachmentMaxSize      = 10 << 20  // 单个附件最大 10 MB
+	AttachmentUserQuota    = 200 << 20 // 每个用户附件总容量 200 MB, 包含缩略图
+	AttachmentMaxPerPost   = 20        // 每篇帖子最多关联的附件数
+	AttachmentMaxPixels    = 40000000  // 图片最大像素数, 防止解码炸弹
+	AttachmentMediumWidth  = 1280      // 正文展示图最大宽度
+	AttachmentThumbWidth   = 320       // 缩略图最大宽度
+	AttachmentJPEGQuality  = 85
+	AttachmentOrphanTTL    = 24 * time.Hour // 上传后超过该时间仍未关联帖子的附件会被回收
+	AttachmentGCSpec       = "*/30 * * * *" // 每 30 分钟回收一次附件
+	AttachmentGCBatchSize  = 200            // 每批回收的附件数
+	AttachmentStorageLocal = "./uploads"    // 本地存储根目录
+	AttachmentURLPrefix    = "/uploads"     // 本地存储对外访问的 URL 前缀
+)
+
+// AttachmentMimeTypes 允许上传的 MIME 类型及其扩展名, 以嗅探结果为准而不是客户端声明的类型
+var AttachmentMimeTypes = map[string]string{
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"image/gif":       ".gif",
+	"image/webp":      ".webp",
+	"application/pdf": ".pdf",
+	"application/zip": ".zip",
+	"text/plain":      ".txt",
+}
+
+// 配置了 S3_ENDPOINT 时使用 S3 兼容存储, 否则使用本地磁盘
+const (
+	S3Endpoint  = "S3_ENDPOINT"
+	S3AccessKey = "S3_ACCESS_KEY"
+	S3SecretKey = "S3_SECRET_KEY"
+	S3Bucket    = "S3_BUCKET"
+	S3UseSSL    = "S3_USE_SSL"
+	S3PublicURL = "S3_PUBLIC_URL" // 对外访问的 URL 前缀, 如 CDN 域名
+)
@@ -0,0 +1,10 @@
+package conf
+
+import "time"
+
+const (
+	CommentInlineReplySize  = 3                // 评论列表中每条一级评论内联的热门回复数
+	CommentTreeMaxReplySize = 20               // 评论树接口中每条一级评论最多内联的回复数
+	CommentEditWindow       = 15 * time.Minute // 发表后多久内作者可以编辑评论
+	CommentEditMaxCount     = 10               // 每条评论最多编辑的次数
+)
@@ -0,0 +1,13 @@
+package conf
+
+import "time"
+
+const (
+	FeedSize   = 20               // 每个订阅源包含的最新帖子数
+	FeedMaxAge = 10 * time.Minute // 订阅源的 Cache-Control max-age
+)
+
+// 站点对外访问的根地址, 如 https://postery.example.com, 用于生成订阅源中的绝对链接; 不配置时取请求的 Host
+const (
+	SiteURL = "SITE_URL"
+)
@@ -7,6 +7,7 @@ const (
 	AccessTokenExpiration  = 60 * 60
 	RefreshTokenPrefix     = "auth:refresh:"
 	ClearTokenPrefix       = "auth:clear:"
+	UserTokensPrefix       = "auth:user:" // 用户持有的所有 RefreshToken, 用于封禁时统一吊销
 )
 
 const (
@@ -0,0 +1,6 @@
+package conf
+
+const (
+	MentionMaxPerItem   = 10 // 每篇帖子或每条评论最多记录和通知的被提及用户数, 超出的提及不生效
+	MentionMaxCandidate = 50 // 每篇帖子或每条评论最多解析的不同用户名, 限制查询规模
+)
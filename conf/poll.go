@@ -0,0 +1,11 @@
+package conf
+
+import "time"
+
+const (
+	PollOptionMin         = 2                // 投票最少选项数
+	PollOptionMax         = 10               // 投票最多选项数
+	PollCntFlushSpec      = "* * * * *"      // 每分钟将 Redis 中的得票数增量落库
+	PollCntFlushBatchSize = 200              // 每批落库的投票数
+	PollCntLockTTL        = 30 * time.Second // 落库任务锁的过期时间, 避免多个实例重复落库同一批增量
+)
@@ -0,0 +1,61 @@
+package conf
+
+import "time"
+
+const (
+	PostCntFlushSpec          = "* * * * *" // 每分钟将 Redis 中的互动计数增量落库
+	PostCntFlushBatchSize     = 200         // 每批落库的帖子数
+	PostCntReconcileSpec      = "0 4 * * *" // 每天 4 点根据 likes / comments / bookmarks 表校正计数
+	PostCntReconcileBatchSize = 500         // 每批校正的帖子数
+)
+
+const (
+	PostCntLockTTL      = 30 * time.Second       // 落库 / 校正每批持有互斥锁的最长时间
+	PostCntLockInterval = 100 * time.Millisecond // 校正任务等待锁的重试间隔
+)
+
+const (
+	PostViewDedupWindow = 30 * time.Minute // 同一访客在窗口内重复浏览只计一次去重浏览
+	PostExcerptLength   = 140              // 列表页摘要的字符数
+	PostPinMax          = 5                // 全站或每个标签下最多展示的置顶帖子数, 超出时只展示最晚置顶的
+)
+
+// 热榜 score = (去重浏览 * ViewWeight + 点赞 * LikeWeight + 评论 * CommentWeight + 转发 * ShareWeight) / (发布小时数 + AgeOffset) ^ Gravity
+// 全时段榜不做时间衰减; 原始浏览量不计入热度
+const (
+	PostHotSpec          = "*/5 * * * *" // 每 5 分钟重算一次 24h / 7d 热榜, 只扫描窗口内发布的帖子
+	PostHotAllSpec       = "0 * * * *"   // 每小时重算一次全时段热榜, 需要扫描全部帖子
+	PostHotBatchSize     = 500           // 每批参与计算的帖子数
+	PostHotBoardSize     = 100           // 每个榜单保留的帖子数
+	PostHotTopSize       = 10            // 接口返回的帖子数
+	PostHotViewWeight    = 1.0
+	PostHotLikeWeight    = 5.0
+	PostHotCommentWeight = 10.0
+	PostHotShareWeight   = 8.0
+	PostHotGravity       = 1.8
+	PostHotAgeOffset     = 2.0
+)
+
+// 相关推荐 score = 共同标签数 * TagWeight + 共同点赞用户数 * CoLikeWeight + 同一作者 AuthorWeight
+// 由定时任务预计算近期帖子写入 Redis, 尚未计算过的帖子实时按共同标签数推荐
+const (
+	PostRelatedSpec         = "15 * * * *"        // 每小时预计算一次相关推荐
+	PostRelatedBatchSize    = 200                 // 每批参与计算的帖子数
+	PostRelatedWindow       = 30 * 24 * time.Hour // 只预计算该时间窗口内发布的帖子, 更早的帖子实时按共同标签推荐
+	PostRelatedCandidates   = 50                  // 每种信号最多取的候选帖子数
+	PostRelatedLikers       = 200                 // 计算共同点赞时最多取的点赞用户数, 取最近点赞的
+	PostRelatedSize         = 20                  // 每个帖子缓存的相关帖子数
+	PostRelatedTopSize      = 5                   // 接口返回的帖子数
+	PostRelatedExpireTime   = 3 * time.Hour       // 缓存过期时间, 预计算连续失败时回退到实时计算
+	PostRelatedTagWeight    = 3.0
+	PostRelatedCoLikeWeight = 1.0
+	PostRelatedAuthorWeight = 2.0
+)
+
+// slug 由标题生成, 冲突时依次追加 -2, -3 ... 后缀, 超过 MaxAttempts 次仍冲突时以帖子 ID 作后缀
+const (
+	PostSlugMaxLength         = 72             // 不含冲突后缀的最大长度
+	PostSlugMaxAttempts       = 20             // 尝试的数字后缀个数
+	PostSlugBackfillSpec      = "*/10 * * * *" // 每 10 分钟为尚未生成 slug 的旧帖子补全 slug
+	PostSlugBackfillBatchSize = 200            // 每批补全的帖子数
+)
@@ -0,0 +1,5 @@
+package conf
+
+const (
+	SensitiveReloadSpec = "@every 30s" // 每 30 秒检查一次敏感词库版本号, 变化时重新加载
+)
@@ -0,0 +1,5 @@
+package conf
+
+const (
+	SeriesPostMax = 100 // 每个系列最多包含的帖子数
+)
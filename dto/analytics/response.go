@@ -0,0 +1,89 @@
+package analytics
+
+import (
+	"time"
+
+	"github.com/yzletter/go-postery/model"
+)
+
+// DashboardDTO 作者数据看板
+type DashboardDTO struct {
+	From        string        `json:"from"`
+	To          string        `json:"to"`
+	Granularity string        `json:"granularity"` // 时间序列的粒度 hour / day
+	Total       PointDTO      `json:"total"`       // 时间范围内的合计
+	Series      []PointDTO    `json:"series"`      // 按时间桶的事件数, 没有事件的时间桶补 0
+	TopPosts    []TopPostDTO  `json:"top_posts"`   // 浏览数最多的帖子
+	Followers   FollowersDTO  `json:"followers"`
+	Referrers   []ReferrerDTO `json:"referrers"` // 浏览数最多的来源
+}
+
+type PointDTO struct {
+	Bucket    string `json:"bucket,omitempty"` // 时间桶起点, 合计时不返回
+	Views     int    `json:"views"`
+	Likes     int    `json:"likes"`
+	Comments  int    `json:"comments"`
+	Bookmarks int    `json:"bookmarks"`
+}
+
+type TopPostDTO struct {
+	ID    int64  `json:"id,string"`
+	Title string `json:"title"`
+	Slug  string `json:"slug"`
+	PointDTO
+}
+
+type FollowersDTO struct {
+	Gained int                `json:"gained"`
+	Lost   int                `json:"lost"`
+	Net    int                `json:"net"`
+	Series []FollowerPointDTO `json:"series"` // 按天的粉丝变化, 没有变化的日期补 0
+}
+
+type FollowerPointDTO struct {
+	Bucket string `json:"bucket"`
+	Gained int    `json:"gained"`
+	Lost   int    `json:"lost"`
+}
+
+type ReferrerDTO struct {
+	Referrer string `json:"referrer"` // 来源站点域名, 没有来源时为 direct
+	Views    int    `json:"views"`
+}
+
+func ToPointDTO(stat *model.PostStat) PointDTO {
+	point := PointDTO{
+		Views:     stat.ViewCount,
+		Likes:     stat.LikeCount,
+		Comments:  stat.CommentCount,
+		Bookmarks: stat.BookmarkCount,
+	}
+	if !stat.Bucket.IsZero() {
+		point.Bucket = stat.Bucket.Format(time.RFC3339)
+	}
+	return point
+}
+
+func ToTopPostDTO(stat *model.PostStat, post *model.Post) TopPostDTO {
+	return TopPostDTO{
+		ID:       post.ID,
+		Title:    post.Title,
+		Slug:     post.Slug,
+		PointDTO: ToPointDTO(stat),
+	}
+}
+
+func ToFollowerPointDTO(stat *model.FollowerStat) FollowerPointDTO {
+	return FollowerPointDTO{
+		Bucket: stat.Bucket.Format(time.RFC3339),
+		Gained: stat.Gained,
+		Lost:   stat.Lost,
+	}
+}
+
+func ToReferrerDTO(stat *model.PostReferrerStat) ReferrerDTO {
+	return ReferrerDTO{
+		Referrer: stat.Referrer,
+		Views:    stat.ViewCount,
+	}
+}
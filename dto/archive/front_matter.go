@@ -0,0 +1,11 @@
+package archive
+
+// FrontMatter 导入导出的 Markdown 文件开头的 YAML 元信息
+type FrontMatter struct {
+	Title      string   `yaml:"title"`
+	Tags       []string `yaml:"tags,omitempty"`
+	CreatedAt  string   `yaml:"created_at,omitempty"` // 发布时间, 导入时兼容常见博客的时间格式
+	Date       string   `yaml:"date,omitempty"`       // 部分博客用 date 表示发布时间, 仅导入时读取
+	Author     string   `yaml:"author,omitempty"`     // 作者用户名
+	Visibility string   `yaml:"visibility,omitempty"` // public / followers / private
+}
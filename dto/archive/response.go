@@ -0,0 +1,28 @@
+package archive
+
+// ImportResultDTO 导入结果, 单个文件失败不影响其他文件
+type ImportResultDTO struct {
+	Total   int             `json:"total"`
+	Created int             `json:"created"`
+	Failed  int             `json:"failed"`
+	Files   []FileResultDTO `json:"files"`
+}
+
+type FileResultDTO struct {
+	File   string `json:"file"`
+	PostID int64  `json:"post_id,string,omitempty"` // 导入成功时的帖子 ID
+	Error  string `json:"error,omitempty"`          // 导入失败的原因
+}
+
+// Add 记录一个文件的导入结果
+func (r *ImportResultDTO) Add(file string, pid int64, err error) {
+	r.Total++
+	res := FileResultDTO{File: file, PostID: pid}
+	if err != nil {
+		r.Failed++
+		res.Error = err.Error()
+	} else {
+		r.Created++
+	}
+	r.Files = append(r.Files, res)
+}
@@ -0,0 +1,46 @@
+package attachment
+
+import (
+	"time"
+
+	"github.com/yzletter/go-postery/model"
+)
+
+type DTO struct {
+	ID        int64  `json:"id,string"`
+	PostID    int64  `json:"post_id,string"`
+	FileName  string `json:"file_name"`
+	MimeType  string `json:"mime_type"`
+	Size      int64  `json:"size"`
+	Width     int    `json:"width"`
+	Height    int    `json:"height"`
+	URL       string `json:"url"`
+	MediumURL string `json:"medium_url"`
+	ThumbURL  string `json:"thumb_url"`
+	CreatedAt string `json:"created_at"`
+}
+
+// ToDTO url 用于将存储 Key 转为访问地址, 没有变体时回退到原图
+func ToDTO(attachment *model.Attachment, url func(key string) string) DTO {
+	dto := DTO{
+		ID:        attachment.ID,
+		PostID:    attachment.PostID,
+		FileName:  attachment.FileName,
+		MimeType:  attachment.MimeType,
+		Size:      attachment.Size,
+		Width:     attachment.Width,
+		Height:    attachment.Height,
+		URL:       url(attachment.StorageKey),
+		CreatedAt: attachment.CreatedAt.Format(time.RFC3339),
+	}
+	if attachment.Width > 0 {
+		dto.MediumURL, dto.ThumbURL = dto.URL, dto.URL
+		if attachment.MediumKey != "" {
+			dto.MediumURL = url(attachment.MediumKey)
+		}
+		if attachment.ThumbKey != "" {
+			dto.ThumbURL = url(attachment.ThumbKey)
+		}
+	}
+	return dto
+}
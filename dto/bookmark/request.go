@@ -0,0 +1,17 @@
+package bookmark
+
+type CreateRequest struct {
+	CollectionID int64 `json:"collection_id,string"` // 为 0 时收藏到默认收藏夹
+}
+
+type MoveRequest struct {
+	CollectionID int64 `json:"collection_id,string"` // 为 0 时移回默认收藏夹
+}
+
+type CreateCollectionRequest struct {
+	Name string `json:"name" binding:"required,gte=1,lte=64"` // 长度 1 ~ 64
+}
+
+type RenameCollectionRequest struct {
+	Name string `json:"name" binding:"required,gte=1,lte=64"` // 长度 1 ~ 64
+}
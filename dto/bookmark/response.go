@@ -0,0 +1,23 @@
+package bookmark
+
+import (
+	"time"
+
+	"github.com/yzletter/go-postery/model"
+)
+
+type CollectionDTO struct {
+	ID            int64  `json:"id,string"`
+	Name          string `json:"name"`
+	BookmarkCount int    `json:"bookmark_count"`
+	CreatedAt     string `json:"created_at"`
+}
+
+func ToCollectionDTO(collection *model.BookmarkCollection, cnt int) CollectionDTO {
+	return CollectionDTO{
+		ID:            collection.ID,
+		Name:          collection.Name,
+		BookmarkCount: cnt,
+		CreatedAt:     collection.CreatedAt.Format(time.RFC3339),
+	}
+}
@@ -5,3 +5,11 @@ type CreateRequest struct {
 	ReplyID  int64  `json:"reply_id,string"`
 	Content  string `json:"content"  binding:"required,gte=1"`
 }
+
+type EditRequest struct {
+	Content string `json:"content" binding:"required,gte=1"`
+}
+
+type PolicyRequest struct {
+	Policy string `json:"policy" binding:"required"` // open / followers / closed
+}
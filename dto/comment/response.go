@@ -3,28 +3,65 @@ package comment
 import (
 	"time"
 
+	mentiondto "github.com/yzletter/go-postery/dto/mention"
 	userdto "github.com/yzletter/go-postery/dto/user"
 	"github.com/yzletter/go-postery/model"
 )
 
 type DTO struct {
-	ID        int64            `json:"id,string"`
-	PostID    int64            `json:"post_id,string"`
-	ParentID  int64            `json:"parent_id,string"`
-	ReplyID   int64            `json:"reply_id,string"`
-	Content   string           `json:"content"`
-	CreatedAt string           `json:"created_at"`
-	Author    userdto.BriefDTO `json:"author"`
+	ID        int64                `json:"id,string"`
+	PostID    int64                `json:"post_id,string"`
+	ParentID  int64                `json:"parent_id,string"`
+	ReplyID   int64                `json:"reply_id,string"`
+	Content   string               `json:"content"`
+	Reviewing bool                 `json:"reviewing,omitempty"` // 待审核, 通过前不展示
+	Pinned    bool                 `json:"pinned,omitempty"`    // 被作者置顶, 只在一级评论列表的第一页返回
+	LikeCount int                  `json:"like_count"`
+	Liked     bool                 `json:"liked"`              // 当前登录用户是否点赞, 未登录时为 false
+	Mentions  []mentiondto.SpanDTO `json:"mentions,omitempty"` // 正文中生效的 @用户名
+	EditCount int                  `json:"edit_count"`
+	EditedAt  string               `json:"edited_at,omitempty"` // 最近编辑时间, 未编辑过时不返回
+	CreatedAt string               `json:"created_at"`
+	Author    userdto.BriefDTO     `json:"author"`
+	ReplyTo   *userdto.BriefDTO    `json:"reply_to,omitempty"` // 回复目标评论的作者, 不是回复或目标评论已删除时不返回
+
+	// 以下字段只在一级评论列表中返回
+	ReplyCount int   `json:"reply_count,omitempty"` // 回复总数
+	Replies    []DTO `json:"replies,omitempty"`     // 热度最高的几条回复, 完整回复需分页获取
 }
 
 func ToDTO(comment *model.Comment, user *model.User) DTO {
-	return DTO{
+	commentDTO := DTO{
 		ID:        comment.ID,
 		PostID:    comment.PostID,
 		ParentID:  comment.ParentID,
 		ReplyID:   comment.ReplyID,
 		Content:   comment.Content,
+		Reviewing: comment.Status == model.CommentStatusReviewing,
+		LikeCount: comment.LikeCount,
+		EditCount: comment.EditCount,
 		CreatedAt: comment.CreatedAt.Format(time.RFC3339),
 		Author:    userdto.ToBriefDTO(user),
 	}
+	if comment.EditedAt != nil {
+		commentDTO.EditedAt = comment.EditedAt.Format(time.RFC3339)
+	}
+	return commentDTO
+}
+
+// RevisionDTO 评论的一个历史版本
+type RevisionDTO struct {
+	Version   int    `json:"version"` // 0 为最初发表的内容
+	Content   string `json:"content"`
+	Reviewing bool   `json:"reviewing,omitempty"` // 该版本是否处于待审核状态
+	CreatedAt string `json:"created_at"`          // 该版本的发表时间
+}
+
+func ToRevisionDTO(revision *model.CommentRevision) RevisionDTO {
+	return RevisionDTO{
+		Version:   revision.Version,
+		Content:   revision.Content,
+		Reviewing: revision.Status == model.CommentStatusReviewing,
+		CreatedAt: revision.CreatedAt.Format(time.RFC3339),
+	}
 }
@@ -0,0 +1,85 @@
+package feed
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+// AtomFeed Atom 1.0 (RFC 4287) 订阅源
+type AtomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Links   []AtomLink  `xml:"link"`
+	Entries []AtomEntry `xml:"entry"`
+}
+
+type AtomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type AtomEntry struct {
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Published  string         `xml:"published"`
+	Updated    string         `xml:"updated"`
+	Link       AtomLink       `xml:"link"`
+	Author     AtomPerson     `xml:"author"`
+	Summary    AtomText       `xml:"summary"`
+	Categories []AtomCategory `xml:"category"`
+}
+
+type AtomPerson struct {
+	Name string `xml:"name"`
+	URI  string `xml:"uri,omitempty"`
+}
+
+type AtomText struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+type AtomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+// ToAtom 将订阅源编码为 Atom, siteURL 为站点根地址, 不以 / 结尾
+func ToAtom(feed Feed, siteURL string) AtomFeed {
+	res := AtomFeed{
+		ID:      siteURL + feed.Path,
+		Title:   feed.Title,
+		Updated: atomTime(feed.Updated),
+		Links: []AtomLink{
+			{Href: siteURL + feed.Path + ".atom", Rel: "self", Type: "application/atom+xml"},
+			{Href: siteURL, Rel: "alternate", Type: "text/html"},
+		},
+		Entries: make([]AtomEntry, 0, len(feed.Items)),
+	}
+	for _, item := range feed.Items {
+		entry := AtomEntry{
+			ID:        postURL(siteURL, item.ID),
+			Title:     item.Title,
+			Published: atomTime(item.Published),
+			Updated:   atomTime(item.Updated),
+			Link:      AtomLink{Href: postURL(siteURL, item.ID), Rel: "alternate", Type: "text/html"},
+			Author:    AtomPerson{Name: item.Author, URI: userURL(siteURL, item.AuthorID)},
+			Summary:   AtomText{Type: "text", Body: item.Excerpt},
+		}
+		for _, tag := range item.Tags {
+			entry.Categories = append(entry.Categories, AtomCategory{Term: tag})
+		}
+		res.Entries = append(res.Entries, entry)
+	}
+	return res
+}
+
+// atomTime Atom 要求 updated 必填, 没有帖子时取 Unix 零点
+func atomTime(t time.Time) string {
+	if t.IsZero() {
+		t = time.Unix(0, 0)
+	}
+	return t.UTC().Format(time.RFC3339)
+}
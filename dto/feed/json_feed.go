@@ -0,0 +1,56 @@
+package feed
+
+import "time"
+
+const JSONFeedVersion = "https://jsonfeed.org/version/1.1"
+
+// JSONFeed JSON Feed 1.1 订阅源
+type JSONFeed struct {
+	Version     string         `json:"version"`
+	Title       string         `json:"title"`
+	HomePageURL string         `json:"home_page_url"`
+	FeedURL     string         `json:"feed_url"`
+	Items       []JSONFeedItem `json:"items"`
+}
+
+type JSONFeedItem struct {
+	ID            string           `json:"id"`
+	URL           string           `json:"url"`
+	Title         string           `json:"title"`
+	ContentText   string           `json:"content_text"`
+	Summary       string           `json:"summary"`
+	DatePublished string           `json:"date_published"`
+	DateModified  string           `json:"date_modified"`
+	Authors       []JSONFeedAuthor `json:"authors"`
+	Tags          []string         `json:"tags"`
+}
+
+type JSONFeedAuthor struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// ToJSONFeed 将订阅源编码为 JSON Feed, siteURL 为站点根地址, 不以 / 结尾
+func ToJSONFeed(feed Feed, siteURL string) JSONFeed {
+	res := JSONFeed{
+		Version:     JSONFeedVersion,
+		Title:       feed.Title,
+		HomePageURL: siteURL,
+		FeedURL:     siteURL + feed.Path + ".json",
+		Items:       make([]JSONFeedItem, 0, len(feed.Items)),
+	}
+	for _, item := range feed.Items {
+		res.Items = append(res.Items, JSONFeedItem{
+			ID:            postURL(siteURL, item.ID),
+			URL:           postURL(siteURL, item.ID),
+			Title:         item.Title,
+			ContentText:   item.Excerpt,
+			Summary:       item.Excerpt,
+			DatePublished: item.Published.UTC().Format(time.RFC3339),
+			DateModified:  item.Updated.UTC().Format(time.RFC3339),
+			Authors:       []JSONFeedAuthor{{Name: item.Author, URL: userURL(siteURL, item.AuthorID)}},
+			Tags:          item.Tags,
+		})
+	}
+	return res
+}
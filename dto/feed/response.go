@@ -0,0 +1,57 @@
+package feed
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/yzletter/go-postery/model"
+)
+
+// Feed 与格式无关的订阅源, 由 Handler 按请求的格式编码为 Atom 或 JSON Feed
+type Feed struct {
+	Path    string    // 订阅源在站点下的路径, 不含扩展名, 如 /feeds/tags/go
+	Title   string    // 订阅源标题
+	Updated time.Time // 最近一篇帖子的编辑时间, 没有帖子时为零值
+	Items   []Item
+}
+
+type Item struct {
+	ID        int64
+	Title     string
+	Excerpt   string
+	Author    string
+	AuthorID  int64
+	Tags      []string
+	Published time.Time
+	Updated   time.Time
+}
+
+// ToItem 将帖子转为订阅条目, 更新时间取作者最近编辑时间
+func ToItem(post *model.Post, author *model.User, tags []string) Item {
+	item := Item{
+		ID:        post.ID,
+		Title:     post.Title,
+		Excerpt:   post.Excerpt,
+		AuthorID:  post.UserID,
+		Tags:      tags,
+		Published: post.CreatedAt,
+		Updated:   post.LastEdited(),
+	}
+	if author != nil {
+		item.Author = author.Username
+	}
+	if item.Tags == nil {
+		item.Tags = []string{}
+	}
+	return item
+}
+
+// postURL 拼接帖子在站点下的链接
+func postURL(siteURL string, pid int64) string {
+	return siteURL + "/posts/" + strconv.FormatInt(pid, 10)
+}
+
+// userURL 拼接用户主页在站点下的链接
+func userURL(siteURL string, uid int64) string {
+	return siteURL + "/users/" + strconv.FormatInt(uid, 10)
+}
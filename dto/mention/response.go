@@ -0,0 +1,9 @@
+package mention
+
+// SpanDTO 正文中一处生效的 @用户名, Offset 和 Length 按 UTF-16 编码单元计, 与 JavaScript 字符串下标一致, 包含 @
+type SpanDTO struct {
+	UserID   int64  `json:"user_id,string"`
+	Username string `json:"username"`
+	Offset   int    `json:"offset"`
+	Length   int    `json:"length"`
+}
@@ -0,0 +1,12 @@
+package poll
+
+type CreateRequest struct {
+	Options   []string `json:"options" binding:"required,min=2,max=10,dive,required,lte=64"` // 2 ~ 10 个选项, 每个长度 <= 64
+	Multiple  bool     `json:"multiple"`                                                     // 是否多选, 默认单选
+	Anonymous bool     `json:"anonymous"`                                                    // 是否匿名, 默认公开投票人
+	Deadline  string   `json:"deadline"`                                                     // 截止时间 RFC3339, 不传则不截止
+}
+
+type VoteRequest struct {
+	OptionIDs []string `json:"option_ids" binding:"required,min=1"` // 单选时只能传一个
+}
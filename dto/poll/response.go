@@ -0,0 +1,50 @@
+package poll
+
+import (
+	"slices"
+	"time"
+
+	"github.com/yzletter/go-postery/model"
+)
+
+type DTO struct {
+	ID         int64       `json:"id,string"`
+	Multiple   bool        `json:"multiple"`
+	Anonymous  bool        `json:"anonymous"`
+	Deadline   string      `json:"deadline,omitempty"`
+	Closed     bool        `json:"closed"`
+	VoterCount int         `json:"voter_count"`
+	Voted      bool        `json:"voted"` // 当前用户是否已投票
+	Options    []OptionDTO `json:"options"`
+}
+
+type OptionDTO struct {
+	ID        int64  `json:"id,string"`
+	Text      string `json:"text"`
+	VoteCount int    `json:"vote_count"`
+	Voted     bool   `json:"voted"` // 当前用户是否投了该选项
+}
+
+func ToDTO(poll *model.Poll, options []*model.PollOption, voted []int64, now time.Time) DTO {
+	res := DTO{
+		ID:         poll.ID,
+		Multiple:   poll.Multiple,
+		Anonymous:  poll.Anonymous,
+		Closed:     poll.Closed(now),
+		VoterCount: poll.VoterCount,
+		Voted:      len(voted) > 0,
+		Options:    make([]OptionDTO, 0, len(options)),
+	}
+	if poll.Deadline != nil {
+		res.Deadline = poll.Deadline.Format(time.RFC3339)
+	}
+	for _, option := range options {
+		res.Options = append(res.Options, OptionDTO{
+			ID:        option.ID,
+			Text:      option.Text,
+			VoteCount: option.VoteCount,
+			Voted:     slices.Contains(voted, option.ID),
+		})
+	}
+	return res
+}
@@ -1,12 +1,26 @@
 package post
 
+import polldto "github.com/yzletter/go-postery/dto/poll"
+
 type CreateRequest struct {
-	Title   string   `json:"title"   binding:"required,gte=1"`  // 长度>=1
-	Content string   `json:"content"  binding:"required,gte=1"` // 长度>=1
-	Tags    []string `json:"tags"`
+	Title         string                 `json:"title"   binding:"required,gte=1"`  // 长度>=1
+	Content       string                 `json:"content"  binding:"required,gte=1"` // 长度>=1
+	Tags          []string               `json:"tags"`
+	AttachmentIDs []string               `json:"attachment_ids"`
+	Visibility    string                 `json:"visibility"` // public / followers / private, 默认 public
+	Poll          *polldto.CreateRequest `json:"poll"`       // 附带的投票, 不传则没有投票
+	QuoteID       string                 `json:"quote_id"`   // 引用转发的原帖 ID, 不传则是普通帖子
 }
 type UpdateRequest struct {
-	Title   string   `json:"title"  binding:"required,gte=1"`    // 长度>=1
-	Content string   `json:"content"   binding:"required,gte=1"` // 长度>=1
-	Tags    []string `json:"tags"`
+	Title         string   `json:"title"  binding:"required,gte=1"`    // 长度>=1
+	Content       string   `json:"content"   binding:"required,gte=1"` // 长度>=1
+	Tags          []string `json:"tags"`
+	AttachmentIDs []string `json:"attachment_ids"` // 不传则不修改附件, 传空数组则清空附件
+	Visibility    string   `json:"visibility"`     // 不传则不修改可见范围
+	Version       int      `json:"version"`        // 基于哪个版本修改, 也可通过 If-Match 传递
+}
+
+type PinRequest struct {
+	Tag       string `json:"tag"`        // 置顶到的标签, 不传则全站置顶
+	ExpiresAt string `json:"expires_at"` // 过期时间 RFC3339, 不传则永久置顶
 }
@@ -3,27 +3,70 @@ package post
 import (
 	"time"
 
+	mentiondto "github.com/yzletter/go-postery/dto/mention"
+	polldto "github.com/yzletter/go-postery/dto/poll"
 	userdto "github.com/yzletter/go-postery/dto/user"
 	"github.com/yzletter/go-postery/model"
 )
 
 type DetailDTO struct {
-	ID           int64            `json:"id,string"`
-	ViewCount    int              `json:"view_count"`
-	LikeCount    int              `json:"like_count"`
-	CommentCount int              `json:"comment_count"`
-	Title        string           `json:"title"`
-	Content      string           `json:"content"`
-	CreatedAt    string           `json:"created_at"`
-	Author       userdto.BriefDTO `json:"author"`
-	Tags         []string         `json:"tags"`
+	ID              int64                `json:"id,string"`
+	ViewCount       int                  `json:"view_count"`
+	UniqueViewCount int                  `json:"unique_view_count"`
+	LikeCount       int                  `json:"like_count"`
+	CommentCount    int                  `json:"comment_count"`
+	BookmarkCount   int                  `json:"bookmark_count"`
+	ShareCount      int                  `json:"share_count"`
+	Title           string               `json:"title"`
+	Slug            string               `json:"slug"` // 永久链接 /posts/:id-:slug 中的 slug, 旧帖子尚未生成时为空
+	Content         string               `json:"content"`
+	ContentHTML     string               `json:"content_html"`
+	Excerpt         string               `json:"excerpt"`
+	Visibility      string               `json:"visibility"`
+	CommentPolicy   string               `json:"comment_policy"`      // 谁可以评论 open / followers / closed
+	Reviewing       bool                 `json:"reviewing,omitempty"` // 待审核, 仅作者可见
+	Pinned          bool                 `json:"pinned,omitempty"`    // 在当前列表中置顶
+	Featured        bool                 `json:"featured"`            // 精华帖
+	Version         int                  `json:"version"`             // 版本号, 同时作为 ETag 返回
+	CreatedAt       string               `json:"created_at"`
+	Author          userdto.BriefDTO     `json:"author"`
+	Tags            []string             `json:"tags"`
+	Series          *SeriesNavDTO        `json:"series,omitempty"`   // 所属系列及前后篇, 不属于任何系列时不返回
+	Poll            *polldto.DTO         `json:"poll,omitempty"`     // 附带的投票及当前用户的投票情况, 没有投票时不返回
+	Quote           *QuoteDTO            `json:"quote,omitempty"`    // 引用的原帖, 不是引用转发时不返回
+	Mentions        []mentiondto.SpanDTO `json:"mentions,omitempty"` // 正文中生效的 @用户名, 只在创建和详情接口中返回
+}
+
+// QuoteDTO 引用转发的原帖, 原帖被删除、下架或当前用户无权查看时只返回 ID
+type QuoteDTO struct {
+	ID        int64     `json:"id,string"`
+	Available bool      `json:"available"`
+	Post      *BriefDTO `json:"post"` // 不可见时为 null
+}
+
+// SeriesNavDTO 帖子在所属系列中的位置, 位置和总数只计算当前用户能看到的帖子
+type SeriesNavDTO struct {
+	ID       int64    `json:"id,string"`
+	Title    string   `json:"title"`
+	Position int      `json:"position"`
+	Total    int      `json:"total"`
+	Prev     *LinkDTO `json:"prev"` // 上一篇, 没有时为 null
+	Next     *LinkDTO `json:"next"` // 下一篇, 没有时为 null
+}
+
+type LinkDTO struct {
+	ID    int64  `json:"id,string"`
+	Title string `json:"title"`
 }
 
 type BriefDTO struct {
-	ID        int64            `json:"id,string"`
-	Title     string           `json:"title"`
-	CreatedAt string           `json:"created_at"`
-	Author    userdto.BriefDTO `json:"author"`
+	ID         int64            `json:"id,string"`
+	Title      string           `json:"title"`
+	Slug       string           `json:"slug"`
+	Excerpt    string           `json:"excerpt"`
+	Visibility string           `json:"visibility"`
+	CreatedAt  string           `json:"created_at"`
+	Author     userdto.BriefDTO `json:"author"`
 }
 
 type TopDTO struct {
@@ -34,24 +77,38 @@ type TopDTO struct {
 
 func ToDetailDTO(post *model.Post, user *model.User) DetailDTO {
 	return DetailDTO{
-		ID:           post.ID,
-		Title:        post.Title,
-		Content:      post.Content,
-		CreatedAt:    post.CreatedAt.Format(time.RFC3339),
-		Author:       userdto.ToBriefDTO(user),
-		ViewCount:    post.ViewCount,
-		CommentCount: post.CommentCount,
-		LikeCount:    post.LikeCount,
-		Tags:         nil,
+		ID:              post.ID,
+		Title:           post.Title,
+		Slug:            post.Slug,
+		Content:         post.Content,
+		ContentHTML:     post.ContentHTML,
+		Excerpt:         post.Excerpt,
+		Visibility:      post.Visibility.String(),
+		CommentPolicy:   post.CommentPolicy.String(),
+		Reviewing:       post.Status == model.PostStatusReviewing,
+		Featured:        post.FeaturedAt != nil,
+		Version:         post.Version,
+		CreatedAt:       post.CreatedAt.Format(time.RFC3339),
+		Author:          userdto.ToBriefDTO(user),
+		ViewCount:       post.ViewCount,
+		UniqueViewCount: post.UniqueViewCount,
+		CommentCount:    post.CommentCount,
+		LikeCount:       post.LikeCount,
+		BookmarkCount:   post.BookmarkCount,
+		ShareCount:      post.ShareCount,
+		Tags:            nil,
 	}
 }
 
 func ToBriefDTO(post *model.Post, user *model.User) BriefDTO {
 	return BriefDTO{
-		ID:        post.ID,
-		Title:     post.Title,
-		CreatedAt: post.CreatedAt.Format(time.RFC3339),
-		Author:    userdto.ToBriefDTO(user),
+		ID:         post.ID,
+		Title:      post.Title,
+		Slug:       post.Slug,
+		Excerpt:    post.Excerpt,
+		Visibility: post.Visibility.String(),
+		CreatedAt:  post.CreatedAt.Format(time.RFC3339),
+		Author:     userdto.ToBriefDTO(user),
 	}
 }
 
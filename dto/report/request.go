@@ -0,0 +1,13 @@
+package report
+
+type CreateRequest struct {
+	TargetType string `json:"target_type" binding:"required"`      // post / comment / user
+	TargetID   int64  `json:"target_id,string" binding:"required"` // 举报对象 ID
+	Reason     string `json:"reason" binding:"required"`           // spam / abuse / porn / illegal / other
+	Detail     string `json:"detail" binding:"lte=500"`            // 补充说明, 长度 <= 500
+}
+
+type ResolveRequest struct {
+	Action string `json:"action" binding:"required"` // approve / dismiss / takedown
+	Note   string `json:"note" binding:"lte=500"`    // 处理备注, 长度 <= 500
+}
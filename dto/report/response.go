@@ -0,0 +1,57 @@
+package report
+
+import (
+	"strings"
+	"time"
+
+	"github.com/yzletter/go-postery/model"
+)
+
+// SummaryDTO 审核队列中的一项, 同一对象的待处理举报聚合为一项
+type SummaryDTO struct {
+	TargetType      string   `json:"target_type"`
+	TargetID        int64    `json:"target_id,string"`
+	ReportCount     int      `json:"report_count"`
+	Reasons         []string `json:"reasons"`
+	FirstReportedAt string   `json:"first_reported_at"`
+	LastReportedAt  string   `json:"last_reported_at"`
+}
+
+type LogDTO struct {
+	ID          int64  `json:"id,string"`
+	OperatorID  int64  `json:"operator_id,string"`
+	TargetType  string `json:"target_type"`
+	TargetID    int64  `json:"target_id,string"`
+	Action      string `json:"action"`
+	Note        string `json:"note"`
+	ReportCount int    `json:"report_count"`
+	CreatedAt   string `json:"created_at"`
+}
+
+func ToSummaryDTO(summary *model.ReportSummary) SummaryDTO {
+	var reasons []string
+	if summary.Reasons != "" {
+		reasons = strings.Split(summary.Reasons, ",")
+	}
+	return SummaryDTO{
+		TargetType:      summary.TargetType.String(),
+		TargetID:        summary.TargetID,
+		ReportCount:     summary.ReportCount,
+		Reasons:         reasons,
+		FirstReportedAt: summary.FirstReportedAt.Format(time.RFC3339),
+		LastReportedAt:  summary.LastReportedAt.Format(time.RFC3339),
+	}
+}
+
+func ToLogDTO(log *model.ModerationLog) LogDTO {
+	return LogDTO{
+		ID:          log.ID,
+		OperatorID:  log.OperatorID,
+		TargetType:  log.TargetType.String(),
+		TargetID:    log.TargetID,
+		Action:      log.Action.String(),
+		Note:        log.Note,
+		ReportCount: log.ReportCount,
+		CreatedAt:   log.CreatedAt.Format(time.RFC3339),
+	}
+}
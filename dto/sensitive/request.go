@@ -0,0 +1,10 @@
+package sensitive
+
+type AddWordsRequest struct {
+	Category string   `json:"category" binding:"required,lte=32"`                          // 所属分类, 长度 <= 32
+	Words    []string `json:"words" binding:"required,min=1,max=500,dive,required,lte=64"` // 一次最多 500 个, 每个长度 <= 64
+}
+
+type CategoryRequest struct {
+	Action string `json:"action" binding:"required"` // mask / review / reject
+}
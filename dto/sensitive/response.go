@@ -0,0 +1,37 @@
+package sensitive
+
+import (
+	"time"
+
+	"github.com/yzletter/go-postery/model"
+)
+
+type WordDTO struct {
+	ID        int64  `json:"id,string"`
+	Word      string `json:"word"`
+	Category  string `json:"category"`
+	CreatedAt string `json:"created_at"`
+}
+
+type CategoryDTO struct {
+	Name      string `json:"name"`
+	Action    string `json:"action"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+func ToWordDTO(word *model.SensitiveWord) WordDTO {
+	return WordDTO{
+		ID:        word.ID,
+		Word:      word.Word,
+		Category:  word.Category,
+		CreatedAt: word.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+func ToCategoryDTO(category *model.SensitiveCategory) CategoryDTO {
+	return CategoryDTO{
+		Name:      category.Name,
+		Action:    category.Action.String(),
+		UpdatedAt: category.UpdatedAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,19 @@
+package series
+
+type CreateRequest struct {
+	Title       string `json:"title" binding:"required,gte=1,lte=128"` // 长度 1 ~ 128
+	Description string `json:"description" binding:"lte=512"`          // 长度 <= 512
+}
+
+type UpdateRequest struct {
+	Title       string `json:"title" binding:"required,gte=1,lte=128"` // 长度 1 ~ 128
+	Description string `json:"description" binding:"lte=512"`          // 长度 <= 512
+}
+
+type AddPostRequest struct {
+	PostID int64 `json:"post_id,string" binding:"required"`
+}
+
+type ReorderRequest struct {
+	PostIDs []string `json:"post_ids" binding:"required"` // 系列中全部帖子的新顺序
+}
@@ -0,0 +1,28 @@
+package series
+
+import (
+	"time"
+
+	userdto "github.com/yzletter/go-postery/dto/user"
+	"github.com/yzletter/go-postery/model"
+)
+
+type DTO struct {
+	ID          int64            `json:"id,string"`
+	Title       string           `json:"title"`
+	Description string           `json:"description"`
+	PostCount   int              `json:"post_count"`
+	CreatedAt   string           `json:"created_at"`
+	Author      userdto.BriefDTO `json:"author"`
+}
+
+func ToDTO(series *model.Series, author *model.User, cnt int) DTO {
+	return DTO{
+		ID:          series.ID,
+		Title:       series.Title,
+		Description: series.Description,
+		PostCount:   cnt,
+		CreatedAt:   series.CreatedAt.Format(time.RFC3339),
+		Author:      userdto.ToBriefDTO(author),
+	}
+}
@@ -24,6 +24,7 @@ var (
 	ErrUnauthorized       = &Error{20006, 403, "没有权限"}
 	ErrLogoutFailed       = &Error{20007, 500, "登出失败"}
 	ErrOldPasswordInvalid = &Error{20008, 401, "旧密码错误"}
+	ErrUserBanned         = &Error{20009, 403, "账号已被封禁"}
 )
 
 // Post 错误 Code 3000X
@@ -31,18 +32,49 @@ var (
 	ErrPostNotFound     = &Error{30001, 404, "帖子不存在"}
 	ErrDuplicatedLike   = &Error{30002, 409, "已经点赞过该帖子"}
 	ErrDuplicatedUnLike = &Error{30003, 409, "尚未点赞，无法取消"}
+
+	ErrDuplicatedBookmark   = &Error{30004, 409, "已经收藏过该帖子"}
+	ErrDuplicatedUnBookmark = &Error{30005, 409, "尚未收藏，无法取消"}
+	ErrCollectionNotFound   = &Error{30006, 404, "收藏夹不存在"}
+	ErrDuplicatedCollection = &Error{30007, 409, "收藏夹名称已存在"}
+
+	ErrPostNotPublic = &Error{30008, 400, "只能置顶或加精所有人可见的帖子"}
+	ErrPinNotFound   = &Error{30009, 404, "帖子未置顶"}
+
+	ErrPostVersionRequired = &Error{30010, 428, "缺少帖子版本号"}
+	ErrPostVersionConflict = &Error{30011, 409, "帖子已被修改, 请刷新后重试"}
+
+	ErrDuplicatedRepost   = &Error{30012, 409, "已经转发过该帖子"}
+	ErrDuplicatedUnRepost = &Error{30013, 409, "尚未转发，无法取消"}
+	ErrPostNotShareable   = &Error{30014, 400, "只能转发或引用所有人可见的帖子"}
 )
 
 // Comment 错误 Code 4000X
 
 var (
-	ErrCommentNotFound = &Error{40001, 404, "评论不存在"}
+	ErrCommentNotFound         = &Error{40001, 404, "评论不存在"}
+	ErrDuplicatedCommentLike   = &Error{40002, 409, "已经点赞过该评论"}
+	ErrDuplicatedCommentUnLike = &Error{40003, 409, "尚未点赞该评论，无法取消"}
+
+	ErrCommentEditExpired  = &Error{40004, 403, "评论已超过可编辑时间"}
+	ErrCommentEditLimit    = &Error{40005, 403, "评论编辑次数已达上限"}
+	ErrCommentEditConflict = &Error{40006, 409, "评论已被修改, 请刷新后重试"}
+
+	ErrCommentClosed        = &Error{40007, 403, "帖子已关闭评论"}
+	ErrCommentFollowersOnly = &Error{40008, 403, "仅作者的粉丝可以评论"}
+	ErrCommentBlocked       = &Error{40009, 403, "作者已禁止你评论其帖子"}
+	ErrCommentPinInvalid    = &Error{40010, 400, "只能置顶本帖中已公开的一级评论"}
+
+	ErrDuplicatedCommentBlock   = &Error{40011, 409, "已经禁止该用户评论"}
+	ErrDuplicatedCommentUnBlock = &Error{40012, 409, "尚未禁止该用户评论，无法取消"}
+	ErrCommentBlockSelf         = &Error{40013, 400, "不能禁止自己评论"}
 )
 
 // Tag 错误 Code 5000X
 
 var (
 	ErrTagDuplicatedBind = &Error{50001, 409, "标签重复绑定"}
+	ErrTagNotFound       = &Error{50002, 404, "标签不存在"}
 )
 
 // Follow 错误 Code 6000X
@@ -62,3 +94,60 @@ var (
 	ErrOrderNotFound = &Error{80003, 404, "订单不存在"}
 	ErrLotteryNoting = &Error{80004, 404, "没有抽到奖品"}
 )
+
+// Attachment 错误 Code 9000X
+var (
+	ErrAttachmentNotFound       = &Error{90001, 404, "附件不存在"}
+	ErrAttachmentTooLarge       = &Error{90002, 413, "附件过大"}
+	ErrAttachmentTypeNotAllowed = &Error{90003, 415, "不支持的附件类型"}
+	ErrAttachmentQuotaExceeded  = &Error{90004, 403, "附件容量已用完"}
+	ErrAttachmentTooMany        = &Error{90005, 400, "关联的附件过多"}
+)
+
+// Report 错误 Code 9100X
+var (
+	ErrDuplicatedReport     = &Error{91001, 409, "已经举报过该内容"}
+	ErrReportTargetNotFound = &Error{91002, 404, "举报对象不存在"}
+	ErrReportNotFound       = &Error{91003, 404, "没有待处理的举报"}
+)
+
+// Sensitive 错误 Code 9200X
+var (
+	ErrContentRejected       = &Error{92001, 400, "内容包含违规信息"}
+	ErrSensitiveWordNotFound = &Error{92002, 404, "敏感词不存在"}
+)
+
+// Series 错误 Code 9300X
+var (
+	ErrSeriesNotFound      = &Error{93001, 404, "系列不存在"}
+	ErrPostInSeries        = &Error{93002, 409, "帖子已属于某个系列"}
+	ErrPostNotInSeries     = &Error{93003, 404, "帖子不在该系列中"}
+	ErrSeriesOrderMismatch = &Error{93004, 400, "排序需包含系列中的全部帖子且不能重复"}
+	ErrSeriesFull          = &Error{93005, 400, "系列中的帖子数已达上限"}
+)
+
+// Poll 错误 Code 9400X
+var (
+	ErrPollNotFound      = &Error{94001, 404, "投票不存在"}
+	ErrPollClosed        = &Error{94002, 400, "投票已截止"}
+	ErrPollVoted         = &Error{94003, 409, "已经投过票"}
+	ErrPollOptionInvalid = &Error{94004, 400, "投票选项无效"}
+	ErrPollAnonymous     = &Error{94005, 403, "匿名投票不公开投票人"}
+	ErrPollInvalid       = &Error{94006, 400, "投票参数不合法"}
+)
+
+// Analytics 错误 Code 9500X
+var (
+	ErrAnalyticsRangeInvalid = &Error{95001, 400, "统计时间范围不合法"}
+)
+
+// Archive 错误 Code 9600X
+var (
+	ErrArchiveInvalid      = &Error{96001, 400, "压缩包格式错误"}
+	ErrArchiveTooLarge     = &Error{96002, 413, "压缩包过大"}
+	ErrArchiveTooManyFiles = &Error{96003, 400, "压缩包中的文件过多"}
+	ErrArchiveFileInvalid  = &Error{96004, 400, "文件无法读取或缺少 front-matter"}
+	ErrArchiveFileTooLarge = &Error{96005, 400, "文件过大"}
+	ErrArchiveFileEmpty    = &Error{96006, 400, "标题或正文为空"}
+	ErrArchiveTimeInvalid  = &Error{96007, 400, "发布时间格式错误或晚于当前时间"}
+)
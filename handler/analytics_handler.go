@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yzletter/go-postery/errno"
+	"github.com/yzletter/go-postery/service"
+	"github.com/yzletter/go-postery/utils"
+	"github.com/yzletter/go-postery/utils/response"
+)
+
+type AnalyticsHandler struct {
+	analyticsSvc service.AnalyticsService
+}
+
+func NewAnalyticsHandler(analyticsSvc service.AnalyticsService) *AnalyticsHandler {
+	return &AnalyticsHandler{
+		analyticsSvc: analyticsSvc,
+	}
+}
+
+// Dashboard 获取当前用户帖子的数据统计
+func (hdl *AnalyticsHandler) Dashboard(ctx *gin.Context) {
+	// 由于前面有 Auth 中间件, 能走到这里默认上下文里已经被 Auth 塞了 uid, 直接拿即可
+	uid, err := utils.GetUidFromCTX(ctx, UserIDInContext)
+	if err != nil {
+		response.Error(ctx, errno.ErrUserNotLogin)
+		return
+	}
+
+	from, err1 := parseAnalyticsTime(ctx.Query("from"), false)
+	to, err2 := parseAnalyticsTime(ctx.Query("to"), true)
+	if err1 != nil || err2 != nil {
+		response.Error(ctx, errno.ErrInvalidParam)
+		return
+	}
+
+	// 不带 post 时统计全部帖子
+	var pid int64
+	if raw := ctx.Query("post"); raw != "" {
+		pid, err = strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			response.Error(ctx, errno.ErrInvalidParam)
+			return
+		}
+	}
+
+	dashboardDTO, err := hdl.analyticsSvc.Dashboard(ctx, uid, pid, from, to)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+
+	response.Success(ctx, "获取数据统计成功", dashboardDTO)
+}
+
+// parseAnalyticsTime 解析 RFC3339 时间或 2006-01-02 格式的日期, 日期按服务器时区解析; 作为结束时间的日期包含当天
+func parseAnalyticsTime(s string, end bool) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		// 统计桶按服务器本地时区划分, 带其他时区偏移的时间先换算到本地时区
+		return t.In(time.Local), nil
+	}
+	t, err := time.ParseInLocation(time.DateOnly, s, time.Local)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if end {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yzletter/go-postery/conf"
+	"github.com/yzletter/go-postery/errno"
+	"github.com/yzletter/go-postery/service"
+	"github.com/yzletter/go-postery/utils"
+	"github.com/yzletter/go-postery/utils/response"
+)
+
+type ArchiveHandler struct {
+	archiveSvc service.ArchiveService
+}
+
+func NewArchiveHandler(archiveSvc service.ArchiveService) *ArchiveHandler {
+	return &ArchiveHandler{
+		archiveSvc: archiveSvc,
+	}
+}
+
+// Import 从 ZIP 压缩包批量导入 Markdown 帖子
+func (hdl *ArchiveHandler) Import(ctx *gin.Context) {
+	// 由于前面有 Auth 中间件, 能走到这里默认上下文里已经被 Auth 塞了 uid, 直接拿即可
+	uid, err := utils.GetUidFromCTX(ctx, UserIDInContext)
+	if err != nil {
+		response.Error(ctx, errno.ErrUserNotLogin)
+		return
+	}
+
+	// 限制请求体大小, 预留 1 MB 给 multipart 的其他部分
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, conf.ArchiveImportMaxSize+1<<20)
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil {
+		response.Error(ctx, errno.ErrInvalidParam)
+		return
+	}
+	if fileHeader.Size > conf.ArchiveImportMaxSize {
+		response.Error(ctx, errno.ErrArchiveTooLarge)
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		response.Error(ctx, errno.ErrInvalidParam)
+		return
+	}
+	defer file.Close()
+
+	resultDTO, err := hdl.archiveSvc.Import(ctx, uid, file, fileHeader.Size)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+
+	response.Success(ctx, "帖子导入完成", resultDTO)
+}
+
+// Export 将用户的帖子导出为 Markdown 文件的 ZIP 压缩包, 只包含当前用户可见的帖子
+func (hdl *ArchiveHandler) Export(ctx *gin.Context) {
+	uid, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		response.Error(ctx, errno.ErrInvalidParam)
+		return
+	}
+
+	data, err := hdl.archiveSvc.Export(ctx, uid, viewerUid(ctx))
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+
+	ctx.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="posts-%d.zip"`, uid))
+	ctx.Data(http.StatusOK, "application/zip", data)
+}
@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yzletter/go-postery/conf"
+	"github.com/yzletter/go-postery/errno"
+	"github.com/yzletter/go-postery/service"
+	"github.com/yzletter/go-postery/utils"
+	"github.com/yzletter/go-postery/utils/response"
+)
+
+type AttachmentHandler struct {
+	attachmentSvc service.AttachmentService
+}
+
+func NewAttachmentHandler(attachmentSvc service.AttachmentService) *AttachmentHandler {
+	return &AttachmentHandler{
+		attachmentSvc: attachmentSvc,
+	}
+}
+
+// Upload 上传附件
+func (hdl *AttachmentHandler) Upload(ctx *gin.Context) {
+	// 由于前面有 Auth 中间件, 能走到这里默认上下文里已经被 Auth 塞了 uid, 直接拿即可
+	uid, err := utils.GetUidFromCTX(ctx, UserIDInContext)
+	if err != nil {
+		response.Error(ctx, errno.ErrUserNotLogin)
+		return
+	}
+
+	// 限制请求体大小, 预留 1 MB 给 multipart 的其他部分
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, conf.AttachmentMaxSize+1<<20)
+	fileHeader, err := ctx.FormFile("file")
+	if err != nil {
+		response.Error(ctx, errno.ErrInvalidParam)
+		return
+	}
+	if fileHeader.Size > conf.AttachmentMaxSize {
+		response.Error(ctx, errno.ErrAttachmentTooLarge)
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		response.Error(ctx, errno.ErrInvalidParam)
+		return
+	}
+	defer file.Close()
+
+	attachmentDTO, err := hdl.attachmentSvc.Upload(ctx, uid, fileHeader.Filename, file, fileHeader.Size)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+
+	response.Success(ctx, "附件上传成功", attachmentDTO)
+}
+
+// Delete 删除附件
+func (hdl *AttachmentHandler) Delete(ctx *gin.Context) {
+	// 由于前面有 Auth 中间件, 能走到这里默认上下文里已经被 Auth 塞了 uid, 直接拿即可
+	uid, err := utils.GetUidFromCTX(ctx, UserIDInContext)
+	if err != nil {
+		response.Error(ctx, errno.ErrUserNotLogin)
+		return
+	}
+
+	// 获取附件 id
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		response.Error(ctx, errno.ErrInvalidParam)
+		return
+	}
+
+	err = hdl.attachmentSvc.Delete(ctx, uid, id)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+
+	response.Success(ctx, "附件删除成功", nil)
+}
+
+// ListByPost 获取帖子附件
+func (hdl *AttachmentHandler) ListByPost(ctx *gin.Context) {
+	// 获取帖子 id
+	pid, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		response.Error(ctx, errno.ErrInvalidParam)
+		return
+	}
+
+	attachmentDTOs, err := hdl.attachmentSvc.ListByPostID(ctx, pid, viewerUid(ctx))
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+
+	response.Success(ctx, "获取帖子附件成功", attachmentDTOs)
+}
+
+// parseIDs 将字符串形式的 ID 列表转为 int64
+func parseIDs(raw []string) ([]int64, error) {
+	ids := make([]int64, 0, len(raw))
+	for _, s := range raw {
+		id, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
@@ -0,0 +1,297 @@
+package handler
+
+import (
+	"log/slog"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yzletter/go-postery/dto/bookmark"
+	"github.com/yzletter/go-postery/errno"
+	"github.com/yzletter/go-postery/service"
+	"github.com/yzletter/go-postery/utils"
+	"github.com/yzletter/go-postery/utils/response"
+)
+
+type BookmarkHandler struct {
+	bookmarkSvc service.BookmarkService
+}
+
+func NewBookmarkHandler(bookmarkSvc service.BookmarkService) *BookmarkHandler {
+	return &BookmarkHandler{
+		bookmarkSvc: bookmarkSvc,
+	}
+}
+
+// Bookmark 收藏帖子
+func (hdl *BookmarkHandler) Bookmark(ctx *gin.Context) {
+	// 由于前面有 Auth 中间件, 能走到这里默认上下文里已经被 Auth 塞了 uid, 直接拿即可
+	uid, err := utils.GetUidFromCTX(ctx, UserIDInContext)
+	if err != nil {
+		response.Error(ctx, errno.ErrUserNotLogin)
+		return
+	}
+
+	// 获取帖子 id
+	pid, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		response.Error(ctx, errno.ErrInvalidParam)
+		return
+	}
+
+	// 参数绑定, 请求体可以为空
+	var createRequest bookmark.CreateRequest
+	if ctx.Request.ContentLength > 0 {
+		if err = ctx.ShouldBindJSON(&createRequest); err != nil {
+			slog.Error("参数绑定失败", "error", utils.BindErrMsg(err))
+			response.Error(ctx, errno.ErrInvalidParam)
+			return
+		}
+	}
+
+	err = hdl.bookmarkSvc.Bookmark(ctx, pid, uid, createRequest.CollectionID)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+
+	response.Success(ctx, "收藏成功", nil)
+}
+
+// UnBookmark 取消收藏
+func (hdl *BookmarkHandler) UnBookmark(ctx *gin.Context) {
+	// 由于前面有 Auth 中间件, 能走到这里默认上下文里已经被 Auth 塞了 uid, 直接拿即可
+	uid, err := utils.GetUidFromCTX(ctx, UserIDInContext)
+	if err != nil {
+		response.Error(ctx, errno.ErrUserNotLogin)
+		return
+	}
+
+	// 获取帖子 id
+	pid, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		response.Error(ctx, errno.ErrInvalidParam)
+		return
+	}
+
+	err = hdl.bookmarkSvc.UnBookmark(ctx, pid, uid)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+
+	response.Success(ctx, "取消收藏成功", nil)
+}
+
+// IfBookmark 查询是否收藏了帖子
+func (hdl *BookmarkHandler) IfBookmark(ctx *gin.Context) {
+	// 由于前面有 Auth 中间件, 能走到这里默认上下文里已经被 Auth 塞了 uid, 直接拿即可
+	uid, err := utils.GetUidFromCTX(ctx, UserIDInContext)
+	if err != nil {
+		response.Error(ctx, errno.ErrUserNotLogin)
+		return
+	}
+
+	// 获取帖子 id
+	pid, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		response.Error(ctx, errno.ErrInvalidParam)
+		return
+	}
+
+	ok, err := hdl.bookmarkSvc.IfBookmark(ctx, pid, uid)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	response.Success(ctx, "", ok)
+}
+
+// ListBookmarks 按页获取收藏的帖子, 可按收藏夹筛选
+func (hdl *BookmarkHandler) ListBookmarks(ctx *gin.Context) {
+	// 由于前面有 Auth 中间件, 能走到这里默认上下文里已经被 Auth 塞了 uid, 直接拿即可
+	uid, err := utils.GetUidFromCTX(ctx, UserIDInContext)
+	if err != nil {
+		response.Error(ctx, errno.ErrUserNotLogin)
+		return
+	}
+
+	pageNo, err1 := strconv.Atoi(ctx.DefaultQuery("pageNo", "1"))
+	pageSize, err2 := strconv.Atoi(ctx.DefaultQuery("pageSize", "10"))
+	if err1 != nil || err2 != nil || pageNo < 1 || pageSize > 100 {
+		response.Error(ctx, errno.ErrInvalidParam)
+		return
+	}
+
+	// 不带 collection_id 时返回所有收藏
+	var cid *int64
+	if raw, ok := ctx.GetQuery("collection_id"); ok {
+		id, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			response.Error(ctx, errno.ErrInvalidParam)
+			return
+		}
+		cid = &id
+	}
+
+	total, postDTOs, err := hdl.bookmarkSvc.ListBookmarks(ctx, uid, cid, pageNo, pageSize)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+
+	hasMore := pageNo*pageSize < total
+
+	response.Success(ctx, "获取收藏列表成功", gin.H{
+		"posts":   postDTOs,
+		"total":   total,
+		"hasMore": hasMore,
+	})
+}
+
+// CreateCollection 新建收藏夹
+func (hdl *BookmarkHandler) CreateCollection(ctx *gin.Context) {
+	// 由于前面有 Auth 中间件, 能走到这里默认上下文里已经被 Auth 塞了 uid, 直接拿即可
+	uid, err := utils.GetUidFromCTX(ctx, UserIDInContext)
+	if err != nil {
+		response.Error(ctx, errno.ErrUserNotLogin)
+		return
+	}
+
+	// 参数绑定
+	var createRequest bookmark.CreateCollectionRequest
+	if err = ctx.ShouldBindJSON(&createRequest); err != nil {
+		slog.Error("参数绑定失败", "error", utils.BindErrMsg(err))
+		response.Error(ctx, errno.ErrInvalidParam)
+		return
+	}
+
+	collectionDTO, err := hdl.bookmarkSvc.CreateCollection(ctx, uid, createRequest.Name)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+
+	response.Success(ctx, "收藏夹创建成功", collectionDTO)
+}
+
+// ListCollections 按页获取收藏夹
+func (hdl *BookmarkHandler) ListCollections(ctx *gin.Context) {
+	// 由于前面有 Auth 中间件, 能走到这里默认上下文里已经被 Auth 塞了 uid, 直接拿即可
+	uid, err := utils.GetUidFromCTX(ctx, UserIDInContext)
+	if err != nil {
+		response.Error(ctx, errno.ErrUserNotLogin)
+		return
+	}
+
+	pageNo, err1 := strconv.Atoi(ctx.DefaultQuery("pageNo", "1"))
+	pageSize, err2 := strconv.Atoi(ctx.DefaultQuery("pageSize", "10"))
+	if err1 != nil || err2 != nil || pageNo < 1 || pageSize > 100 {
+		response.Error(ctx, errno.ErrInvalidParam)
+		return
+	}
+
+	total, collectionDTOs, err := hdl.bookmarkSvc.ListCollections(ctx, uid, pageNo, pageSize)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+
+	hasMore := pageNo*pageSize < total
+
+	response.Success(ctx, "获取收藏夹列表成功", gin.H{
+		"collections": collectionDTOs,
+		"total":       total,
+		"hasMore":     hasMore,
+	})
+}
+
+// MoveBookmark 将收藏的帖子移动到其他收藏夹
+func (hdl *BookmarkHandler) MoveBookmark(ctx *gin.Context) {
+	// 由于前面有 Auth 中间件, 能走到这里默认上下文里已经被 Auth 塞了 uid, 直接拿即可
+	uid, err := utils.GetUidFromCTX(ctx, UserIDInContext)
+	if err != nil {
+		response.Error(ctx, errno.ErrUserNotLogin)
+		return
+	}
+
+	// 获取帖子 id
+	pid, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		response.Error(ctx, errno.ErrInvalidParam)
+		return
+	}
+
+	// 参数绑定
+	var moveRequest bookmark.MoveRequest
+	if err = ctx.ShouldBindJSON(&moveRequest); err != nil {
+		slog.Error("参数绑定失败", "error", utils.BindErrMsg(err))
+		response.Error(ctx, errno.ErrInvalidParam)
+		return
+	}
+
+	err = hdl.bookmarkSvc.MoveBookmark(ctx, pid, uid, moveRequest.CollectionID)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+
+	response.Success(ctx, "移动收藏成功", nil)
+}
+
+// RenameCollection 修改收藏夹名称
+func (hdl *BookmarkHandler) RenameCollection(ctx *gin.Context) {
+	// 由于前面有 Auth 中间件, 能走到这里默认上下文里已经被 Auth 塞了 uid, 直接拿即可
+	uid, err := utils.GetUidFromCTX(ctx, UserIDInContext)
+	if err != nil {
+		response.Error(ctx, errno.ErrUserNotLogin)
+		return
+	}
+
+	// 获取收藏夹 id
+	cid, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		response.Error(ctx, errno.ErrInvalidParam)
+		return
+	}
+
+	// 参数绑定
+	var renameRequest bookmark.RenameCollectionRequest
+	if err = ctx.ShouldBindJSON(&renameRequest); err != nil {
+		slog.Error("参数绑定失败", "error", utils.BindErrMsg(err))
+		response.Error(ctx, errno.ErrInvalidParam)
+		return
+	}
+
+	err = hdl.bookmarkSvc.RenameCollection(ctx, uid, cid, renameRequest.Name)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+
+	response.Success(ctx, "收藏夹修改成功", nil)
+}
+
+// DeleteCollection 删除收藏夹
+func (hdl *BookmarkHandler) DeleteCollection(ctx *gin.Context) {
+	// 由于前面有 Auth 中间件, 能走到这里默认上下文里已经被 Auth 塞了 uid, 直接拿即可
+	uid, err := utils.GetUidFromCTX(ctx, UserIDInContext)
+	if err != nil {
+		response.Error(ctx, errno.ErrUserNotLogin)
+		return
+	}
+
+	// 获取收藏夹 id
+	cid, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		response.Error(ctx, errno.ErrInvalidParam)
+		return
+	}
+
+	err = hdl.bookmarkSvc.DeleteCollection(ctx, uid, cid)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+
+	response.Success(ctx, "收藏夹删除成功", nil)
+}
@@ -5,8 +5,10 @@ import (
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/yzletter/go-postery/conf"
 	"github.com/yzletter/go-postery/dto/comment"
 	"github.com/yzletter/go-postery/errno"
+	"github.com/yzletter/go-postery/model"
 	"github.com/yzletter/go-postery/service"
 	"github.com/yzletter/go-postery/utils"
 	"github.com/yzletter/go-postery/utils/response"
@@ -60,6 +62,64 @@ func (hdl *CommentHandler) Create(ctx *gin.Context) {
 	response.Success(ctx, "评论成功", commentDTO)
 }
 
+// Edit 编辑自己的评论
+func (hdl *CommentHandler) Edit(ctx *gin.Context) {
+	// 由于前面有 Auth 中间件, 能走到这里默认上下文里已经被 Auth 塞了 uid, 直接拿即可
+	uid, err := utils.GetUidFromCTX(ctx, UserIDInContext)
+	if err != nil {
+		response.Error(ctx, errno.ErrUserNotLogin)
+		return
+	}
+
+	pid, err1 := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	cid, err2 := strconv.ParseInt(ctx.Param("cid"), 10, 64)
+	if err1 != nil || err2 != nil {
+		response.Error(ctx, errno.ErrInvalidParam)
+		return
+	}
+
+	// 获取参数并校验
+	var editReq comment.EditRequest
+	if err := ctx.ShouldBindJSON(&editReq); err != nil {
+		slog.Error("参数绑定失败", "error", utils.BindErrMsg(err))
+		response.Error(ctx, errno.ErrInvalidParam)
+		return
+	}
+
+	commentDTO, err := hdl.commentSvc.Edit(ctx, pid, cid, uid, editReq.Content)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+
+	response.Success(ctx, "评论编辑成功", commentDTO)
+}
+
+// Revisions 获取评论的历史版本, 仅管理员和版主可用
+func (hdl *CommentHandler) Revisions(ctx *gin.Context) {
+	// 由于前面有 Auth 中间件, 能走到这里默认上下文里已经被 Auth 塞了 uid, 直接拿即可
+	uid, err := utils.GetUidFromCTX(ctx, UserIDInContext)
+	if err != nil {
+		response.Error(ctx, errno.ErrUserNotLogin)
+		return
+	}
+
+	pid, err1 := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	cid, err2 := strconv.ParseInt(ctx.Param("cid"), 10, 64)
+	if err1 != nil || err2 != nil {
+		response.Error(ctx, errno.ErrInvalidParam)
+		return
+	}
+
+	revisionDTOs, err := hdl.commentSvc.Revisions(ctx, pid, cid, uid)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+
+	response.Success(ctx, "获取评论历史版本成功", revisionDTOs)
+}
+
 func (hdl *CommentHandler) Delete(ctx *gin.Context) {
 	// 由于前面有 Auth 中间件, 能走到这里默认上下文里已经被 Auth 塞了 uid, 直接拿即可
 	uid, err := utils.GetUidFromCTX(ctx, UserIDInContext)
@@ -106,8 +166,13 @@ func (hdl *CommentHandler) ListByPage(ctx *gin.Context) {
 		response.Error(ctx, errno.ErrInvalidParam)
 		return
 	}
+	sort, err := model.ParseCommentSort(ctx.Query("sort"), model.CommentSortNewest)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
 
-	total, commentDTOs, err := hdl.commentSvc.List(ctx, pid, pageNo, pageSize)
+	total, commentDTOs, err := hdl.commentSvc.List(ctx, pid, viewerUid(ctx), sort, pageNo, pageSize)
 	if err != nil {
 		response.Error(ctx, err)
 		return
@@ -122,6 +187,43 @@ func (hdl *CommentHandler) ListByPage(ctx *gin.Context) {
 	})
 }
 
+// Tree 按页获取帖子的一级评论, 每条一级评论附带最早的几条回复
+func (hdl *CommentHandler) Tree(ctx *gin.Context) {
+	// 获取参数
+	pid, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		response.Error(ctx, errno.ErrInvalidParam)
+		return
+	}
+
+	pageNo, err1 := strconv.Atoi(ctx.DefaultQuery("pageNo", "1"))
+	pageSize, err2 := strconv.Atoi(ctx.DefaultQuery("pageSize", "10"))
+	replySize, err3 := strconv.Atoi(ctx.DefaultQuery("repliesPerRoot", strconv.Itoa(conf.CommentInlineReplySize)))
+	if err1 != nil || err2 != nil || err3 != nil || pageNo < 1 || pageSize > 100 || replySize < 0 || replySize > conf.CommentTreeMaxReplySize {
+		response.Error(ctx, errno.ErrInvalidParam)
+		return
+	}
+	sort, err := model.ParseCommentSort(ctx.Query("sort"), model.CommentSortNewest)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+
+	total, commentDTOs, err := hdl.commentSvc.Tree(ctx, pid, viewerUid(ctx), sort, pageNo, pageSize, replySize)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+
+	hasMore := pageNo*pageSize < total
+
+	response.Success(ctx, "获取评论树成功", gin.H{
+		"comments": commentDTOs,
+		"total":    total,
+		"hasMore":  hasMore,
+	})
+}
+
 func (hdl *CommentHandler) ListReplies(ctx *gin.Context) {
 	// 获取参数
 	// 从路由中获取 cid 参数
@@ -133,7 +235,7 @@ func (hdl *CommentHandler) ListReplies(ctx *gin.Context) {
 	}
 
 	// 从路由中获取 pid 参数
-	_, err = strconv.ParseInt(ctx.Param("id"), 10, 64)
+	pid, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
 	if err != nil {
 		// 获取帖子详情请求的参数不合法
 		response.Error(ctx, errno.ErrInvalidParam)
@@ -146,8 +248,13 @@ func (hdl *CommentHandler) ListReplies(ctx *gin.Context) {
 		response.Error(ctx, errno.ErrInvalidParam)
 		return
 	}
+	sort, err := model.ParseCommentSort(ctx.Query("sort"), model.CommentSortOldest)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
 
-	total, commentDTOs, err := hdl.commentSvc.ListReplies(ctx, cid, pageNo, pageSize)
+	total, commentDTOs, err := hdl.commentSvc.ListReplies(ctx, pid, cid, viewerUid(ctx), sort, pageNo, pageSize)
 	if err != nil {
 		response.Error(ctx, err)
 		return
@@ -162,6 +269,221 @@ func (hdl *CommentHandler) ListReplies(ctx *gin.Context) {
 	})
 }
 
+// Like 点赞评论
+func (hdl *CommentHandler) Like(ctx *gin.Context) {
+	// 由于前面有 Auth 中间件, 能走到这里默认上下文里已经被 Auth 塞了 uid, 直接拿即可
+	uid, err := utils.GetUidFromCTX(ctx, UserIDInContext)
+	if err != nil {
+		response.Error(ctx, errno.ErrUserNotLogin)
+		return
+	}
+
+	pid, err1 := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	cid, err2 := strconv.ParseInt(ctx.Param("cid"), 10, 64)
+	if err1 != nil || err2 != nil {
+		response.Error(ctx, errno.ErrInvalidParam)
+		return
+	}
+
+	err = hdl.commentSvc.Like(ctx, pid, cid, uid)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+
+	response.Success(ctx, "点赞成功", nil)
+}
+
+// UnLike 取消点赞评论
+func (hdl *CommentHandler) UnLike(ctx *gin.Context) {
+	// 由于前面有 Auth 中间件, 能走到这里默认上下文里已经被 Auth 塞了 uid, 直接拿即可
+	uid, err := utils.GetUidFromCTX(ctx, UserIDInContext)
+	if err != nil {
+		response.Error(ctx, errno.ErrUserNotLogin)
+		return
+	}
+
+	pid, err1 := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	cid, err2 := strconv.ParseInt(ctx.Param("cid"), 10, 64)
+	if err1 != nil || err2 != nil {
+		response.Error(ctx, errno.ErrInvalidParam)
+		return
+	}
+
+	err = hdl.commentSvc.UnLike(ctx, pid, cid, uid)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+
+	response.Success(ctx, "取消点赞成功", nil)
+}
+
+// SetPolicy 作者设置帖子的评论权限
+func (hdl *CommentHandler) SetPolicy(ctx *gin.Context) {
+	// 由于前面有 Auth 中间件, 能走到这里默认上下文里已经被 Auth 塞了 uid, 直接拿即可
+	uid, err := utils.GetUidFromCTX(ctx, UserIDInContext)
+	if err != nil {
+		response.Error(ctx, errno.ErrUserNotLogin)
+		return
+	}
+
+	pid, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		response.Error(ctx, errno.ErrInvalidParam)
+		return
+	}
+
+	var policyReq comment.PolicyRequest
+	if err := ctx.ShouldBindJSON(&policyReq); err != nil {
+		slog.Error("参数绑定失败", "error", utils.BindErrMsg(err))
+		response.Error(ctx, errno.ErrInvalidParam)
+		return
+	}
+	policy, err := model.ParseCommentPolicy(policyReq.Policy)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+
+	err = hdl.commentSvc.SetPolicy(ctx, pid, uid, policy)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+
+	response.Success(ctx, "评论设置已更新", nil)
+}
+
+// Pin 作者置顶评论
+func (hdl *CommentHandler) Pin(ctx *gin.Context) {
+	// 由于前面有 Auth 中间件, 能走到这里默认上下文里已经被 Auth 塞了 uid, 直接拿即可
+	uid, err := utils.GetUidFromCTX(ctx, UserIDInContext)
+	if err != nil {
+		response.Error(ctx, errno.ErrUserNotLogin)
+		return
+	}
+
+	pid, err1 := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	cid, err2 := strconv.ParseInt(ctx.Param("cid"), 10, 64)
+	if err1 != nil || err2 != nil {
+		response.Error(ctx, errno.ErrInvalidParam)
+		return
+	}
+
+	err = hdl.commentSvc.Pin(ctx, pid, cid, uid)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+
+	response.Success(ctx, "置顶评论成功", nil)
+}
+
+// Unpin 作者取消置顶评论
+func (hdl *CommentHandler) Unpin(ctx *gin.Context) {
+	// 由于前面有 Auth 中间件, 能走到这里默认上下文里已经被 Auth 塞了 uid, 直接拿即可
+	uid, err := utils.GetUidFromCTX(ctx, UserIDInContext)
+	if err != nil {
+		response.Error(ctx, errno.ErrUserNotLogin)
+		return
+	}
+
+	pid, err1 := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	cid, err2 := strconv.ParseInt(ctx.Param("cid"), 10, 64)
+	if err1 != nil || err2 != nil {
+		response.Error(ctx, errno.ErrInvalidParam)
+		return
+	}
+
+	err = hdl.commentSvc.Unpin(ctx, pid, cid, uid)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+
+	response.Success(ctx, "取消置顶评论成功", nil)
+}
+
+// Block 禁止用户评论自己的帖子
+func (hdl *CommentHandler) Block(ctx *gin.Context) {
+	// 由于前面有 Auth 中间件, 能走到这里默认上下文里已经被 Auth 塞了 uid, 直接拿即可
+	uid, err := utils.GetUidFromCTX(ctx, UserIDInContext)
+	if err != nil {
+		response.Error(ctx, errno.ErrUserNotLogin)
+		return
+	}
+
+	target, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		response.Error(ctx, errno.ErrInvalidParam)
+		return
+	}
+
+	err = hdl.commentSvc.Block(ctx, uid, target)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+
+	response.Success(ctx, "已禁止该用户评论", nil)
+}
+
+// UnBlock 取消禁止用户评论
+func (hdl *CommentHandler) UnBlock(ctx *gin.Context) {
+	// 由于前面有 Auth 中间件, 能走到这里默认上下文里已经被 Auth 塞了 uid, 直接拿即可
+	uid, err := utils.GetUidFromCTX(ctx, UserIDInContext)
+	if err != nil {
+		response.Error(ctx, errno.ErrUserNotLogin)
+		return
+	}
+
+	target, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		response.Error(ctx, errno.ErrInvalidParam)
+		return
+	}
+
+	err = hdl.commentSvc.UnBlock(ctx, uid, target)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+
+	response.Success(ctx, "已取消禁止该用户评论", nil)
+}
+
+// ListBlocked 按页获取被自己禁止评论的用户
+func (hdl *CommentHandler) ListBlocked(ctx *gin.Context) {
+	// 由于前面有 Auth 中间件, 能走到这里默认上下文里已经被 Auth 塞了 uid, 直接拿即可
+	uid, err := utils.GetUidFromCTX(ctx, UserIDInContext)
+	if err != nil {
+		response.Error(ctx, errno.ErrUserNotLogin)
+		return
+	}
+
+	pageNo, err1 := strconv.Atoi(ctx.DefaultQuery("pageNo", "1"))
+	pageSize, err2 := strconv.Atoi(ctx.DefaultQuery("pageSize", "10"))
+	if err1 != nil || err2 != nil || pageNo < 1 || pageSize > 100 {
+		response.Error(ctx, errno.ErrInvalidParam)
+		return
+	}
+
+	total, userDTOs, err := hdl.commentSvc.ListBlocked(ctx, uid, pageNo, pageSize)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+
+	hasMore := pageNo*pageSize < total
+
+	response.Success(ctx, "获取评论黑名单成功", gin.H{
+		"users":   userDTOs,
+		"total":   total,
+		"hasMore": hasMore,
+	})
+}
+
 func (hdl *CommentHandler) CheckAuth(ctx *gin.Context) {
 	// 由于前面有 Auth 中间件, 能走到这里默认上下文里已经被 Auth 塞了 uid, 直接拿即可
 	uid, err := utils.GetUidFromCTX(ctx, UserIDInContext)
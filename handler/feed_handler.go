@@ -0,0 +1,176 @@
+package handler
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"hash/fnv"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yzletter/go-postery/conf"
+	feeddto "github.com/yzletter/go-postery/dto/feed"
+	"github.com/yzletter/go-postery/errno"
+	"github.com/yzletter/go-postery/service"
+	"github.com/yzletter/go-postery/utils/response"
+)
+
+// 订阅源格式, 由路径的扩展名决定
+const (
+	feedFormatAtom = "atom"
+	feedFormatJSON = "json"
+)
+
+type FeedHandler struct {
+	feedSvc service.FeedService
+	siteURL string // 站点根地址, 为空时取请求的 Host
+}
+
+func NewFeedHandler(feedSvc service.FeedService, siteURL string) *FeedHandler {
+	return &FeedHandler{
+		feedSvc: feedSvc,
+		siteURL: strings.TrimSuffix(siteURL, "/"),
+	}
+}
+
+// Posts 全站最新帖子的订阅源
+func (hdl *FeedHandler) Posts(ctx *gin.Context) {
+	_, format, ok := splitFeedFormat(path.Base(ctx.Request.URL.Path))
+	if !ok {
+		response.Error(ctx, errno.ErrInvalidParam)
+		return
+	}
+
+	feed, err := hdl.feedSvc.Posts(ctx)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+
+	hdl.write(ctx, feed, format)
+}
+
+// Tag 某个标签下最新帖子的订阅源
+func (hdl *FeedHandler) Tag(ctx *gin.Context) {
+	slug, format, ok := splitFeedFormat(ctx.Param("file"))
+	if !ok {
+		response.Error(ctx, errno.ErrInvalidParam)
+		return
+	}
+
+	feed, err := hdl.feedSvc.Tag(ctx, slug)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+
+	hdl.write(ctx, feed, format)
+}
+
+// User 某个用户最新帖子的订阅源
+func (hdl *FeedHandler) User(ctx *gin.Context) {
+	name, format, ok := splitFeedFormat(ctx.Param("file"))
+	if !ok {
+		response.Error(ctx, errno.ErrInvalidParam)
+		return
+	}
+	uid, err := strconv.ParseInt(name, 10, 64)
+	if err != nil {
+		response.Error(ctx, errno.ErrInvalidParam)
+		return
+	}
+
+	feed, err := hdl.feedSvc.User(ctx, uid)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+
+	hdl.write(ctx, feed, format)
+}
+
+// write 设置缓存头, 条件请求命中时返回 304, 否则按 format 编码订阅源
+func (hdl *FeedHandler) write(ctx *gin.Context, feed feeddto.Feed, format string) {
+	// 未配置 SITE_URL 时链接由请求的 Host 拼接, 不同 Host 的响应不同, 不允许共享缓存
+	siteURL := hdl.siteURL
+	cacheControl := fmt.Sprintf("public, max-age=%d", int(conf.FeedMaxAge.Seconds()))
+	if siteURL == "" {
+		siteURL = requestOrigin(ctx)
+		cacheControl = fmt.Sprintf("private, max-age=%d", int(conf.FeedMaxAge.Seconds()))
+		ctx.Header("Vary", "Host, X-Forwarded-Proto")
+	}
+
+	etag := feedETag(feed, format, siteURL)
+	ctx.Header("ETag", etag)
+	ctx.Header("Cache-Control", cacheControl)
+	if !feed.Updated.IsZero() {
+		ctx.Header("Last-Modified", feed.Updated.UTC().Format(http.TimeFormat))
+	}
+	if feedNotModified(ctx, etag, feed.Updated) {
+		ctx.Status(http.StatusNotModified)
+		return
+	}
+
+	var (
+		body        []byte
+		contentType string
+		err         error
+	)
+	switch format {
+	case feedFormatAtom:
+		contentType = "application/atom+xml; charset=utf-8"
+		body, err = xml.MarshalIndent(feeddto.ToAtom(feed, siteURL), "", "  ")
+		body = append([]byte(xml.Header), body...)
+	case feedFormatJSON:
+		contentType = "application/feed+json; charset=utf-8"
+		body, err = json.MarshalIndent(feeddto.ToJSONFeed(feed, siteURL), "", "  ")
+	}
+	if err != nil {
+		response.Error(ctx, errno.ErrServerInternal)
+		return
+	}
+
+	ctx.Data(http.StatusOK, contentType, body)
+}
+
+// splitFeedFormat 将 go.atom 拆分为 go 和 atom, 不支持的扩展名返回 false
+func splitFeedFormat(file string) (string, string, bool) {
+	name, format, ok := strings.Cut(file, ".")
+	if !ok || name == "" || (format != feedFormatAtom && format != feedFormatJSON) {
+		return "", "", false
+	}
+	return name, format, true
+}
+
+// feedETag 由格式、站点地址和每篇帖子的 ID 与编辑时间计算, 互动计数变化不影响 ETag
+func feedETag(feed feeddto.Feed, format, siteURL string) string {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%s|%s|%s|%s", format, siteURL, feed.Path, feed.Title)
+	for _, item := range feed.Items {
+		fmt.Fprintf(h, "|%d:%d:%s", item.ID, item.Updated.Unix(), item.Author)
+	}
+	return fmt.Sprintf("W/\"%x\"", h.Sum64())
+}
+
+// feedNotModified 优先按 If-None-Match 判断, 没有时再按 If-Modified-Since 判断
+func feedNotModified(ctx *gin.Context, etag string, updated time.Time) bool {
+	if header := ctx.GetHeader("If-None-Match"); header != "" {
+		return matchETag(header, etag)
+	}
+
+	since, err := http.ParseTime(ctx.GetHeader("If-Modified-Since"))
+	return err == nil && !updated.IsZero() && !updated.After(since)
+}
+
+// requestOrigin 由请求拼接站点根地址, 支持反向代理传递的 X-Forwarded-Proto
+func requestOrigin(ctx *gin.Context) string {
+	scheme := "http"
+	if ctx.Request.TLS != nil || ctx.GetHeader("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return scheme + "://" + ctx.Request.Host
+}
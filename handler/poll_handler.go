@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yzletter/go-postery/dto/poll"
+	"github.com/yzletter/go-postery/errno"
+	"github.com/yzletter/go-postery/service"
+	"github.com/yzletter/go-postery/utils"
+	"github.com/yzletter/go-postery/utils/response"
+)
+
+type PollHandler struct {
+	pollSvc service.PollService
+}
+
+func NewPollHandler(pollSvc service.PollService) *PollHandler {
+	return &PollHandler{
+		pollSvc: pollSvc,
+	}
+}
+
+// Result 获取帖子的投票结果
+func (hdl *PollHandler) Result(ctx *gin.Context) {
+	pid, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		response.Error(ctx, errno.ErrInvalidParam)
+		return
+	}
+
+	pollDTO, err := hdl.pollSvc.Result(ctx, pid, viewerUid(ctx))
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+
+	response.Success(ctx, "获取投票结果成功", pollDTO)
+}
+
+// Vote 投票
+func (hdl *PollHandler) Vote(ctx *gin.Context) {
+	// 由于前面有 Auth 中间件, 能走到这里默认上下文里已经被 Auth 塞了 uid, 直接拿即可
+	uid, err := utils.GetUidFromCTX(ctx, UserIDInContext)
+	if err != nil {
+		response.Error(ctx, errno.ErrUserNotLogin)
+		return
+	}
+
+	pid, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		response.Error(ctx, errno.ErrInvalidParam)
+		return
+	}
+
+	// 参数绑定
+	var voteRequest poll.VoteRequest
+	if err = ctx.ShouldBindJSON(&voteRequest); err != nil {
+		slog.Error("参数绑定失败", "error", utils.BindErrMsg(err))
+		response.Error(ctx, errno.ErrInvalidParam)
+		return
+	}
+	optionIDs, err := parseIDs(voteRequest.OptionIDs)
+	if err != nil {
+		response.Error(ctx, errno.ErrInvalidParam)
+		return
+	}
+
+	pollDTO, err := hdl.pollSvc.Vote(ctx, pid, uid, optionIDs)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+
+	response.Success(ctx, "投票成功", pollDTO)
+}
+
+// ListVoters 按页获取投了某个选项的用户
+func (hdl *PollHandler) ListVoters(ctx *gin.Context) {
+	pid, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		response.Error(ctx, errno.ErrInvalidParam)
+		return
+	}
+	optionID, err := strconv.ParseInt(ctx.Query("option_id"), 10, 64)
+	if err != nil {
+		response.Error(ctx, errno.ErrInvalidParam)
+		return
+	}
+
+	pageNo, err1 := strconv.Atoi(ctx.DefaultQuery("pageNo", "1"))
+	pageSize, err2 := strconv.Atoi(ctx.DefaultQuery("pageSize", "10"))
+	if err1 != nil || err2 != nil || pageNo < 1 || pageSize > 100 {
+		response.Error(ctx, errno.ErrInvalidParam)
+		return
+	}
+
+	total, userDTOs, err := hdl.pollSvc.ListVoters(ctx, pid, optionID, viewerUid(ctx), pageNo, pageSize)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+
+	hasMore := pageNo*pageSize < total
+
+	response.Success(ctx, "获取投票人成功", gin.H{
+		"users":   userDTOs,
+		"total":   total,
+		"hasMore": hasMore,
+	})
+}
+
+// parseDeadline 解析 RFC3339 格式的截止时间, 为空时返回 nil 表示不截止
+func parseDeadline(s string) (*time.Time, error) {
+	if s == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return nil, errno.ErrInvalidParam
+	}
+	return &t, nil
+}
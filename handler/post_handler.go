@@ -1,29 +1,48 @@
 package handler
 
 import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"hash/fnv"
+	"io"
 	"log/slog"
+	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/yzletter/go-postery/dto/post"
 	"github.com/yzletter/go-postery/errno"
+	"github.com/yzletter/go-postery/model"
 	"github.com/yzletter/go-postery/service"
 	"github.com/yzletter/go-postery/utils"
 	"github.com/yzletter/go-postery/utils/response"
 )
 
 type PostHandler struct {
-	postSvc service.PostService
-	userSvc service.UserService
-	tagSvc  service.TagService
+	postSvc       service.PostService
+	userSvc       service.UserService
+	tagSvc        service.TagService
+	attachmentSvc service.AttachmentService
+	seriesSvc     service.SeriesService
+	pollSvc       service.PollService
 }
 
-func NewPostHandler(postService service.PostService, userService service.UserService, tagSvc service.TagService) *PostHandler {
+func NewPostHandler(postService service.PostService, userService service.UserService, tagSvc service.TagService,
+	attachmentSvc service.AttachmentService, seriesSvc service.SeriesService,
+	pollSvc service.PollService) *PostHandler {
 	return &PostHandler{
-		postSvc: postService,
-		userSvc: userService,
-		tagSvc:  tagSvc,
+		postSvc:       postService,
+		userSvc:       userService,
+		tagSvc:        tagSvc,
+		attachmentSvc: attachmentSvc,
+		seriesSvc:     seriesSvc,
+		pollSvc:       pollSvc,
 	}
 }
 
@@ -45,14 +64,6 @@ func (hdl *PostHandler) List(ctx *gin.Context) {
 		return
 	}
 
-	for k := range postDTOs {
-		res, err := hdl.tagSvc.FindTagsByPostID(ctx, postDTOs[k].ID)
-		if err != nil {
-			continue
-		}
-		postDTOs[k].Tags = res
-	}
-
 	// 计算是否还有帖子 = 判断已经加载的帖子数是否小于总帖子数
 	hasMore := pageNo*pageSize < total
 
@@ -85,14 +96,6 @@ func (hdl *PostHandler) ListByTagAndPage(ctx *gin.Context) {
 		return
 	}
 
-	for k := range postDTOs {
-		res, err := hdl.tagSvc.FindTagsByPostID(ctx, postDTOs[k].ID)
-		if err != nil {
-			continue
-		}
-		postDTOs[k].Tags = res
-	}
-
 	// 计算是否还有帖子 = 判断已经加载的帖子数是否小于总帖子数
 	hasMore := pageNo*pageSize < total
 
@@ -107,16 +110,38 @@ func (hdl *PostHandler) ListByTagAndPage(ctx *gin.Context) {
 
 // Detail 获取帖子详情
 func (hdl *PostHandler) Detail(ctx *gin.Context) {
-	// 从路由中获取 pid 参数
-	pid, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	// 从路由中获取 pid 参数, 永久链接形如 /posts/:id-:slug, 以 ID 为准, slug 部分只为可读
+	idPart, _, _ := strings.Cut(ctx.Param("id"), "-")
+	pid, err := strconv.ParseInt(idPart, 10, 64)
 	if err != nil {
 		// 获取帖子详情请求的参数不合法
 		response.Error(ctx, errno.ErrInvalidParam)
 		return
 	}
 
+	hdl.detail(ctx, pid)
+}
+
+// BySlug 根据 slug 获取帖子详情, 标题修改前的旧 slug 重定向到当前 slug
+func (hdl *PostHandler) BySlug(ctx *gin.Context) {
+	slug := ctx.Param("slug")
+	pid, current, err := hdl.postSvc.ResolveSlug(ctx, slug, viewerUid(ctx))
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	if current != slug {
+		ctx.Redirect(http.StatusMovedPermanently, "/api/v1/posts/by-slug/"+url.PathEscape(current))
+		return
+	}
+
+	hdl.detail(ctx, pid)
+}
+
+// detail 获取帖子详情并处理条件请求
+func (hdl *PostHandler) detail(ctx *gin.Context, pid int64) {
 	// 根据 pid 查找帖子详情
-	postDTO, err := hdl.postSvc.GetDetailById(ctx, pid, true)
+	postDTO, err := hdl.postSvc.GetDetailById(ctx, pid, viewerUid(ctx))
 	if err != nil {
 		response.Error(ctx, err)
 		return
@@ -128,6 +153,23 @@ func (hdl *PostHandler) Detail(ctx *gin.Context) {
 		return
 	}
 
+	postDTO.Series, err = hdl.seriesSvc.Navigation(ctx, postDTO.ID, viewerUid(ctx))
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+
+	// 条件请求, 版本号及不随版本号变化的状态都未变时不返回正文, 也不计浏览
+	etag := detailETag(postDTO)
+	ctx.Header("ETag", etag)
+	if matchETag(ctx.GetHeader("If-None-Match"), etag) {
+		ctx.Status(http.StatusNotModified)
+		return
+	}
+
+	// 只有返回正文时才记录一次浏览
+	hdl.postSvc.RecordView(ctx, &postDTO, viewerKey(ctx), ctx.Request.Referer())
+
 	response.Success(ctx, "获取帖子详情成功", postDTO)
 }
 
@@ -148,9 +190,49 @@ func (hdl *PostHandler) Create(ctx *gin.Context) {
 		response.Error(ctx, errno.ErrInvalidParam)
 		return
 	}
+	attachmentIDs, err := parseIDs(createRequest.AttachmentIDs)
+	if err != nil {
+		response.Error(ctx, errno.ErrInvalidParam)
+		return
+	}
+	visibility, err := parseVisibility(createRequest.Visibility)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	var quoteID int64
+	if createRequest.QuoteID != "" {
+		quoteID, err = strconv.ParseInt(createRequest.QuoteID, 10, 64)
+		if err != nil {
+			response.Error(ctx, errno.ErrInvalidParam)
+			return
+		}
+	}
+
+	// 附带投票时先校验, 避免帖子创建后投票创建失败
+	var deadline *time.Time
+	if createRequest.Poll != nil {
+		deadline, err = parseDeadline(createRequest.Poll.Deadline)
+		if err != nil {
+			response.Error(ctx, err)
+			return
+		}
+		if err = hdl.pollSvc.Validate(createRequest.Poll.Options, deadline); err != nil {
+			response.Error(ctx, err)
+			return
+		}
+	}
+
+	// 附带附件时同样先校验
+	if len(attachmentIDs) > 0 {
+		if err = hdl.attachmentSvc.CheckBindable(ctx, uid, 0, attachmentIDs); err != nil {
+			response.Error(ctx, err)
+			return
+		}
+	}
 
 	// 创建帖子
-	postDTO, err := hdl.postSvc.Create(ctx, uid, createRequest.Title, createRequest.Content)
+	postDTO, err := hdl.postSvc.Create(ctx, uid, createRequest.Title, createRequest.Content, visibility, quoteID)
 	if err != nil {
 		response.Error(ctx, err)
 		return
@@ -163,6 +245,26 @@ func (hdl *PostHandler) Create(ctx *gin.Context) {
 		return
 	}
 
+	// 关联附件
+	if len(attachmentIDs) > 0 {
+		err = hdl.attachmentSvc.BindToPost(ctx, uid, postDTO.ID, attachmentIDs)
+		if err != nil {
+			response.Error(ctx, err)
+			return
+		}
+	}
+
+	// 创建投票
+	if createRequest.Poll != nil {
+		pollDTO, err := hdl.pollSvc.Create(ctx, uid, postDTO.ID, createRequest.Poll.Options,
+			createRequest.Poll.Multiple, createRequest.Poll.Anonymous, deadline)
+		if err != nil {
+			response.Error(ctx, err)
+			return
+		}
+		postDTO.Poll = &pollDTO
+	}
+
 	response.Success(ctx, "帖子创建成功", postDTO)
 }
 
@@ -217,15 +319,63 @@ func (hdl *PostHandler) Update(ctx *gin.Context) {
 		response.Error(ctx, errno.ErrInvalidParam)
 		return
 	}
+	attachmentIDs, err := parseIDs(updateRequest.AttachmentIDs)
+	if err != nil {
+		response.Error(ctx, errno.ErrInvalidParam)
+		return
+	}
+	visibility, err := parseVisibility(updateRequest.Visibility)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+
+	// 基于哪个版本修改, If-Match 优先于请求体中的 version
+	version := updateRequest.Version
+	if ifMatch := ctx.GetHeader("If-Match"); ifMatch != "" {
+		var ok bool
+		if version, ok = parseETag(ifMatch); !ok {
+			response.Error(ctx, errno.ErrInvalidParam)
+			return
+		}
+	}
+	if version <= 0 {
+		response.Error(ctx, errno.ErrPostVersionRequired)
+		return
+	}
+
+	// 先校验附件, 避免帖子修改后附件关联失败
+	if updateRequest.AttachmentIDs != nil {
+		if err = hdl.attachmentSvc.CheckBindable(ctx, uid, pid, attachmentIDs); err != nil {
+			response.Error(ctx, err)
+			return
+		}
+	}
 
 	// 修改
-	err = hdl.postSvc.Update(ctx, pid, uid, updateRequest.Title, updateRequest.Content, updateRequest.Tags)
+	current, err := hdl.postSvc.Update(ctx, pid, uid, version, updateRequest.Title, updateRequest.Content, updateRequest.Tags, visibility)
 	if err != nil {
+		if errors.Is(err, errno.ErrPostVersionConflict) {
+			// 返回当前版本号, 便于客户端拉取最新内容后重试
+			ctx.Header("ETag", postETag(current))
+			response.ErrorWithData(ctx, err, gin.H{"version": current})
+			return
+		}
 		response.Error(ctx, err)
 		return
 	}
 
-	response.Success(ctx, "帖子更新成功", nil)
+	// 修改附件, 未传 attachment_ids 时保持不变
+	if updateRequest.AttachmentIDs != nil {
+		err = hdl.attachmentSvc.BindToPost(ctx, uid, pid, attachmentIDs)
+		if err != nil {
+			response.Error(ctx, err)
+			return
+		}
+	}
+
+	ctx.Header("ETag", postETag(current))
+	response.Success(ctx, "帖子更新成功", gin.H{"version": current})
 	return
 }
 
@@ -273,7 +423,7 @@ func (hdl *PostHandler) ListByPageAndUid(ctx *gin.Context) {
 		return
 	}
 
-	total, postDTOs, err := hdl.postSvc.ListByPageAndUid(ctx, uid, pageNo, pageSize)
+	total, postDTOs, err := hdl.postSvc.ListByPageAndUid(ctx, uid, viewerUid(ctx), pageNo, pageSize)
 	if err != nil {
 		response.Error(ctx, err)
 		return
@@ -360,8 +510,13 @@ func (hdl *PostHandler) IfLike(ctx *gin.Context) {
 	response.Success(ctx, "", ok)
 }
 
+// Top 获取热门帖子榜单, 支持按时间窗口和标签筛选
 func (hdl *PostHandler) Top(ctx *gin.Context) {
-	postDTOs, err := hdl.postSvc.Top(ctx)
+	// 从 /posts/top?window=7d&tag=go 路由中拿出 window 和 tag
+	window := ctx.DefaultQuery("window", string(model.HotWindowWeek))
+	tag := ctx.Query("tag")
+
+	postDTOs, err := hdl.postSvc.Top(ctx, window, tag)
 	if err != nil {
 		response.Error(ctx, err)
 		return
@@ -369,3 +524,217 @@ func (hdl *PostHandler) Top(ctx *gin.Context) {
 
 	response.Success(ctx, "获取热门帖子榜单成功", postDTOs)
 }
+
+// Related 获取相关推荐帖子
+func (hdl *PostHandler) Related(ctx *gin.Context) {
+	pid, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		response.Error(ctx, errno.ErrInvalidParam)
+		return
+	}
+
+	posts, err := hdl.postSvc.ListRelated(ctx, pid, viewerUid(ctx))
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+
+	response.Success(ctx, "获取相关推荐成功", posts)
+}
+
+// ListFeatured 按页获取精华帖子
+func (hdl *PostHandler) ListFeatured(ctx *gin.Context) {
+	pageNo, err1 := strconv.Atoi(ctx.DefaultQuery("pageNo", "1"))
+	pageSize, err2 := strconv.Atoi(ctx.DefaultQuery("pageSize", "10"))
+	if err1 != nil || err2 != nil || pageNo < 1 || pageSize > 100 {
+		response.Error(ctx, errno.ErrInvalidParam)
+		return
+	}
+
+	total, postDTOs, err := hdl.postSvc.ListFeatured(ctx, pageNo, pageSize)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+
+	hasMore := pageNo*pageSize < total
+
+	response.Success(ctx, "获取精华帖子成功", gin.H{
+		"posts":   postDTOs,
+		"total":   total,
+		"hasMore": hasMore,
+	})
+}
+
+// Pin 置顶帖子
+func (hdl *PostHandler) Pin(ctx *gin.Context) {
+	// 由于前面有 Auth 中间件, 能走到这里默认上下文里已经被 Auth 塞了 uid, 直接拿即可
+	uid, err := utils.GetUidFromCTX(ctx, UserIDInContext)
+	if err != nil {
+		response.Error(ctx, errno.ErrUserNotLogin)
+		return
+	}
+
+	pid, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		response.Error(ctx, errno.ErrInvalidParam)
+		return
+	}
+
+	// 参数绑定, 请求体可以为空, 表示全站永久置顶
+	var pinRequest post.PinRequest
+	if err = ctx.ShouldBindJSON(&pinRequest); err != nil && !errors.Is(err, io.EOF) {
+		slog.Error("参数绑定失败", "error", utils.BindErrMsg(err))
+		response.Error(ctx, errno.ErrInvalidParam)
+		return
+	}
+	var expiresAt *time.Time
+	if pinRequest.ExpiresAt != "" {
+		t, err := time.Parse(time.RFC3339, pinRequest.ExpiresAt)
+		if err != nil {
+			response.Error(ctx, errno.ErrInvalidParam)
+			return
+		}
+		expiresAt = &t
+	}
+
+	err = hdl.postSvc.Pin(ctx, uid, pid, pinRequest.Tag, expiresAt)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+
+	response.Success(ctx, "置顶成功", nil)
+}
+
+// Unpin 取消置顶, 通过 ?tag= 指定标签, 不传则取消全站置顶
+func (hdl *PostHandler) Unpin(ctx *gin.Context) {
+	// 由于前面有 Auth 中间件, 能走到这里默认上下文里已经被 Auth 塞了 uid, 直接拿即可
+	uid, err := utils.GetUidFromCTX(ctx, UserIDInContext)
+	if err != nil {
+		response.Error(ctx, errno.ErrUserNotLogin)
+		return
+	}
+
+	pid, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		response.Error(ctx, errno.ErrInvalidParam)
+		return
+	}
+
+	err = hdl.postSvc.Unpin(ctx, uid, pid, ctx.Query("tag"))
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+
+	response.Success(ctx, "取消置顶成功", nil)
+}
+
+// Feature 帖子加精
+func (hdl *PostHandler) Feature(ctx *gin.Context) {
+	hdl.setFeatured(ctx, true)
+}
+
+// Unfeature 取消加精
+func (hdl *PostHandler) Unfeature(ctx *gin.Context) {
+	hdl.setFeatured(ctx, false)
+}
+
+func (hdl *PostHandler) setFeatured(ctx *gin.Context, featured bool) {
+	// 由于前面有 Auth 中间件, 能走到这里默认上下文里已经被 Auth 塞了 uid, 直接拿即可
+	uid, err := utils.GetUidFromCTX(ctx, UserIDInContext)
+	if err != nil {
+		response.Error(ctx, errno.ErrUserNotLogin)
+		return
+	}
+
+	pid, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		response.Error(ctx, errno.ErrInvalidParam)
+		return
+	}
+
+	err = hdl.postSvc.Feature(ctx, uid, pid, featured)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+
+	if featured {
+		response.Success(ctx, "加精成功", nil)
+	} else {
+		response.Success(ctx, "取消加精成功", nil)
+	}
+}
+
+// viewerKey 生成浏览去重用的访客标识, 登录用户用 uid, 游客用 IP + UA 的哈希
+func viewerKey(ctx *gin.Context) string {
+	if uid, ok := ctx.Get(UserIDInContext); ok {
+		if id, ok := uid.(int64); ok {
+			return "u:" + strconv.FormatInt(id, 10)
+		}
+	}
+
+	h := sha1.Sum([]byte(ctx.ClientIP() + "|" + ctx.Request.UserAgent()))
+	return "g:" + hex.EncodeToString(h[:])[:16]
+}
+
+// viewerUid 获取可选登录时的当前用户 ID, 未登录时返回 0
+func viewerUid(ctx *gin.Context) int64 {
+	if uid, ok := ctx.Get(UserIDInContext); ok {
+		if id, ok := uid.(int64); ok {
+			return id
+		}
+	}
+	return 0
+}
+
+// postETag 用帖子版本号生成弱 ETag, 计数类字段的变化不影响 ETag
+func postETag(version int) string {
+	return fmt.Sprintf("W/\"%d\"", version)
+}
+
+// detailETag 在版本号之后拼接详情中不随版本号变化的内容摘要, 帖子本身的计数类字段不参与计算
+func detailETag(postDTO post.DetailDTO) string {
+	h := fnv.New64a()
+	enc := json.NewEncoder(h)
+	_ = enc.Encode([]bool{postDTO.Featured, postDTO.Reviewing}) // 加精和审核状态
+	_ = enc.Encode(postDTO.Series)                              // 所属系列及前后篇, 只计算当前用户能看到的帖子
+	_ = enc.Encode(postDTO.Poll)                                // 投票结果及当前用户的投票
+	_ = enc.Encode(postDTO.Quote)                               // 引用的原帖及其对当前用户的可见性
+	_ = enc.Encode(postDTO.Mentions)                            // 正文中生效的 @用户名, 被提及用户改名或注销时变化
+	_ = enc.Encode(postDTO.CommentPolicy)                       // 评论权限
+	return fmt.Sprintf("W/\"%d-%x\"", postDTO.Version, h.Sum64())
+}
+
+// matchETag 判断 If-None-Match 中是否有与 etag 相同的值, 按弱比较忽略 W/ 前缀
+func matchETag(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
+
+// parseETag 从 If-Match 中解析版本号, 有多个值时取第一个; 兼容详情接口返回的 "<version>-<摘要>" 格式
+func parseETag(header string) (int, bool) {
+	etag, _, _ := strings.Cut(header, ",")
+	etag = strings.TrimPrefix(strings.TrimSpace(etag), "W/")
+	etag, _, _ = strings.Cut(strings.Trim(etag, "\""), "-")
+	version, err := strconv.Atoi(etag)
+	if err != nil || version <= 0 {
+		return 0, false
+	}
+	return version, true
+}
+
+// parseVisibility 解析请求中的可见范围, 为空时返回 0
+func parseVisibility(s string) (model.PostVisibility, error) {
+	if s == "" {
+		return 0, nil
+	}
+	return model.ParsePostVisibility(s)
+}
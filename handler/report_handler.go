@@ -0,0 +1,170 @@
+package handler
+
+import (
+	"log/slog"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yzletter/go-postery/dto/report"
+	"github.com/yzletter/go-postery/errno"
+	"github.com/yzletter/go-postery/model"
+	"github.com/yzletter/go-postery/service"
+	"github.com/yzletter/go-postery/utils"
+	"github.com/yzletter/go-postery/utils/response"
+)
+
+type ReportHandler struct {
+	reportSvc service.ReportService
+}
+
+func NewReportHandler(reportSvc service.ReportService) *ReportHandler {
+	return &ReportHandler{
+		reportSvc: reportSvc,
+	}
+}
+
+// Create 举报帖子、评论或用户
+func (hdl *ReportHandler) Create(ctx *gin.Context) {
+	// 由于前面有 Auth 中间件, 能走到这里默认上下文里已经被 Auth 塞了 uid, 直接拿即可
+	uid, err := utils.GetUidFromCTX(ctx, UserIDInContext)
+	if err != nil {
+		response.Error(ctx, errno.ErrUserNotLogin)
+		return
+	}
+
+	// 参数绑定
+	var createRequest report.CreateRequest
+	if err = ctx.ShouldBindJSON(&createRequest); err != nil {
+		slog.Error("参数绑定失败", "error", utils.BindErrMsg(err))
+		response.Error(ctx, errno.ErrInvalidParam)
+		return
+	}
+	targetType, err := model.ParseReportTargetType(createRequest.TargetType)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+
+	err = hdl.reportSvc.Create(ctx, uid, targetType, createRequest.TargetID, createRequest.Reason, createRequest.Detail)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+
+	response.Success(ctx, "举报成功", nil)
+}
+
+// ListPending 按页获取审核队列
+func (hdl *ReportHandler) ListPending(ctx *gin.Context) {
+	// 由于前面有 Auth 中间件, 能走到这里默认上下文里已经被 Auth 塞了 uid, 直接拿即可
+	uid, err := utils.GetUidFromCTX(ctx, UserIDInContext)
+	if err != nil {
+		response.Error(ctx, errno.ErrUserNotLogin)
+		return
+	}
+
+	pageNo, err1 := strconv.Atoi(ctx.DefaultQuery("pageNo", "1"))
+	pageSize, err2 := strconv.Atoi(ctx.DefaultQuery("pageSize", "10"))
+	if err1 != nil || err2 != nil || pageNo < 1 || pageSize > 100 {
+		response.Error(ctx, errno.ErrInvalidParam)
+		return
+	}
+
+	// 不带 target_type 时返回所有类型
+	var targetType model.ReportTargetType
+	if raw := ctx.Query("target_type"); raw != "" {
+		targetType, err = model.ParseReportTargetType(raw)
+		if err != nil {
+			response.Error(ctx, err)
+			return
+		}
+	}
+
+	total, summaryDTOs, err := hdl.reportSvc.ListPending(ctx, uid, targetType, pageNo, pageSize)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+
+	hasMore := pageNo*pageSize < total
+
+	response.Success(ctx, "获取审核队列成功", gin.H{
+		"reports": summaryDTOs,
+		"total":   total,
+		"hasMore": hasMore,
+	})
+}
+
+// Resolve 处理对象的所有待处理举报
+func (hdl *ReportHandler) Resolve(ctx *gin.Context) {
+	// 由于前面有 Auth 中间件, 能走到这里默认上下文里已经被 Auth 塞了 uid, 直接拿即可
+	uid, err := utils.GetUidFromCTX(ctx, UserIDInContext)
+	if err != nil {
+		response.Error(ctx, errno.ErrUserNotLogin)
+		return
+	}
+
+	// 获取举报对象
+	targetType, err := model.ParseReportTargetType(ctx.Param("type"))
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+	targetID, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		response.Error(ctx, errno.ErrInvalidParam)
+		return
+	}
+
+	// 参数绑定
+	var resolveRequest report.ResolveRequest
+	if err = ctx.ShouldBindJSON(&resolveRequest); err != nil {
+		slog.Error("参数绑定失败", "error", utils.BindErrMsg(err))
+		response.Error(ctx, errno.ErrInvalidParam)
+		return
+	}
+	action, err := model.ParseReportAction(resolveRequest.Action)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+
+	err = hdl.reportSvc.Resolve(ctx, uid, targetType, targetID, action, resolveRequest.Note)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+
+	response.Success(ctx, "处理成功", nil)
+}
+
+// ListLogs 按页获取处理记录
+func (hdl *ReportHandler) ListLogs(ctx *gin.Context) {
+	// 由于前面有 Auth 中间件, 能走到这里默认上下文里已经被 Auth 塞了 uid, 直接拿即可
+	uid, err := utils.GetUidFromCTX(ctx, UserIDInContext)
+	if err != nil {
+		response.Error(ctx, errno.ErrUserNotLogin)
+		return
+	}
+
+	pageNo, err1 := strconv.Atoi(ctx.DefaultQuery("pageNo", "1"))
+	pageSize, err2 := strconv.Atoi(ctx.DefaultQuery("pageSize", "10"))
+	if err1 != nil || err2 != nil || pageNo < 1 || pageSize > 100 {
+		response.Error(ctx, errno.ErrInvalidParam)
+		return
+	}
+
+	total, logDTOs, err := hdl.reportSvc.ListLogs(ctx, uid, pageNo, pageSize)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+
+	hasMore := pageNo*pageSize < total
+
+	response.Success(ctx, "获取处理记录成功", gin.H{
+		"logs":    logDTOs,
+		"total":   total,
+		"hasMore": hasMore,
+	})
+}
@@ -0,0 +1,169 @@
+package handler
+
+import (
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yzletter/go-postery/dto/sensitive"
+	"github.com/yzletter/go-postery/errno"
+	"github.com/yzletter/go-postery/model"
+	"github.com/yzletter/go-postery/service"
+	"github.com/yzletter/go-postery/utils"
+	"github.com/yzletter/go-postery/utils/response"
+)
+
+type SensitiveHandler struct {
+	sensitiveSvc service.SensitiveService
+}
+
+func NewSensitiveHandler(sensitiveSvc service.SensitiveService) *SensitiveHandler {
+	return &SensitiveHandler{
+		sensitiveSvc: sensitiveSvc,
+	}
+}
+
+// AddWords 向分类中批量添加敏感词
+func (hdl *SensitiveHandler) AddWords(ctx *gin.Context) {
+	// 由于前面有 Auth 中间件, 能走到这里默认上下文里已经被 Auth 塞了 uid, 直接拿即可
+	uid, err := utils.GetUidFromCTX(ctx, UserIDInContext)
+	if err != nil {
+		response.Error(ctx, errno.ErrUserNotLogin)
+		return
+	}
+
+	// 参数绑定
+	var addWordsRequest sensitive.AddWordsRequest
+	if err = ctx.ShouldBindJSON(&addWordsRequest); err != nil {
+		slog.Error("参数绑定失败", "error", utils.BindErrMsg(err))
+		response.Error(ctx, errno.ErrInvalidParam)
+		return
+	}
+	category := strings.TrimSpace(addWordsRequest.Category)
+	if category == "" {
+		response.Error(ctx, errno.ErrInvalidParam)
+		return
+	}
+
+	cnt, err := hdl.sensitiveSvc.AddWords(ctx, uid, category, addWordsRequest.Words)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+
+	response.Success(ctx, "添加敏感词成功", gin.H{
+		"added": cnt,
+	})
+}
+
+// DeleteWord 删除敏感词
+func (hdl *SensitiveHandler) DeleteWord(ctx *gin.Context) {
+	// 由于前面有 Auth 中间件, 能走到这里默认上下文里已经被 Auth 塞了 uid, 直接拿即可
+	uid, err := utils.GetUidFromCTX(ctx, UserIDInContext)
+	if err != nil {
+		response.Error(ctx, errno.ErrUserNotLogin)
+		return
+	}
+
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		response.Error(ctx, errno.ErrInvalidParam)
+		return
+	}
+
+	err = hdl.sensitiveSvc.DeleteWord(ctx, uid, id)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+
+	response.Success(ctx, "删除敏感词成功", nil)
+}
+
+// ListWords 按页获取敏感词
+func (hdl *SensitiveHandler) ListWords(ctx *gin.Context) {
+	// 由于前面有 Auth 中间件, 能走到这里默认上下文里已经被 Auth 塞了 uid, 直接拿即可
+	uid, err := utils.GetUidFromCTX(ctx, UserIDInContext)
+	if err != nil {
+		response.Error(ctx, errno.ErrUserNotLogin)
+		return
+	}
+
+	pageNo, err1 := strconv.Atoi(ctx.DefaultQuery("pageNo", "1"))
+	pageSize, err2 := strconv.Atoi(ctx.DefaultQuery("pageSize", "10"))
+	if err1 != nil || err2 != nil || pageNo < 1 || pageSize > 100 {
+		response.Error(ctx, errno.ErrInvalidParam)
+		return
+	}
+
+	// 不带 category 时返回所有分类
+	total, wordDTOs, err := hdl.sensitiveSvc.ListWords(ctx, uid, ctx.Query("category"), pageNo, pageSize)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+
+	hasMore := pageNo*pageSize < total
+
+	response.Success(ctx, "获取敏感词成功", gin.H{
+		"words":   wordDTOs,
+		"total":   total,
+		"hasMore": hasMore,
+	})
+}
+
+// ListCategories 获取所有分类及其处理动作
+func (hdl *SensitiveHandler) ListCategories(ctx *gin.Context) {
+	// 由于前面有 Auth 中间件, 能走到这里默认上下文里已经被 Auth 塞了 uid, 直接拿即可
+	uid, err := utils.GetUidFromCTX(ctx, UserIDInContext)
+	if err != nil {
+		response.Error(ctx, errno.ErrUserNotLogin)
+		return
+	}
+
+	categoryDTOs, err := hdl.sensitiveSvc.ListCategories(ctx, uid)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+
+	response.Success(ctx, "获取敏感词分类成功", categoryDTOs)
+}
+
+// SaveCategory 设置分类的处理动作
+func (hdl *SensitiveHandler) SaveCategory(ctx *gin.Context) {
+	// 由于前面有 Auth 中间件, 能走到这里默认上下文里已经被 Auth 塞了 uid, 直接拿即可
+	uid, err := utils.GetUidFromCTX(ctx, UserIDInContext)
+	if err != nil {
+		response.Error(ctx, errno.ErrUserNotLogin)
+		return
+	}
+
+	name := strings.TrimSpace(ctx.Param("name"))
+	if name == "" || len(name) > 32 {
+		response.Error(ctx, errno.ErrInvalidParam)
+		return
+	}
+
+	// 参数绑定
+	var categoryRequest sensitive.CategoryRequest
+	if err = ctx.ShouldBindJSON(&categoryRequest); err != nil {
+		slog.Error("参数绑定失败", "error", utils.BindErrMsg(err))
+		response.Error(ctx, errno.ErrInvalidParam)
+		return
+	}
+	action, err := model.ParseSensitiveAction(categoryRequest.Action)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+
+	err = hdl.sensitiveSvc.SaveCategory(ctx, uid, name, action)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+
+	response.Success(ctx, "设置分类成功", nil)
+}
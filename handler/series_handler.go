@@ -0,0 +1,249 @@
+package handler
+
+import (
+	"log/slog"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/yzletter/go-postery/dto/series"
+	"github.com/yzletter/go-postery/errno"
+	"github.com/yzletter/go-postery/service"
+	"github.com/yzletter/go-postery/utils"
+	"github.com/yzletter/go-postery/utils/response"
+)
+
+type SeriesHandler struct {
+	seriesSvc service.SeriesService
+}
+
+func NewSeriesHandler(seriesSvc service.SeriesService) *SeriesHandler {
+	return &SeriesHandler{
+		seriesSvc: seriesSvc,
+	}
+}
+
+// Create 新建系列
+func (hdl *SeriesHandler) Create(ctx *gin.Context) {
+	// 由于前面有 Auth 中间件, 能走到这里默认上下文里已经被 Auth 塞了 uid, 直接拿即可
+	uid, err := utils.GetUidFromCTX(ctx, UserIDInContext)
+	if err != nil {
+		response.Error(ctx, errno.ErrUserNotLogin)
+		return
+	}
+
+	// 参数绑定
+	var createRequest series.CreateRequest
+	if err = ctx.ShouldBindJSON(&createRequest); err != nil {
+		slog.Error("参数绑定失败", "error", utils.BindErrMsg(err))
+		response.Error(ctx, errno.ErrInvalidParam)
+		return
+	}
+
+	seriesDTO, err := hdl.seriesSvc.Create(ctx, uid, createRequest.Title, createRequest.Description)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+
+	response.Success(ctx, "系列创建成功", seriesDTO)
+}
+
+// Update 修改系列
+func (hdl *SeriesHandler) Update(ctx *gin.Context) {
+	// 由于前面有 Auth 中间件, 能走到这里默认上下文里已经被 Auth 塞了 uid, 直接拿即可
+	uid, err := utils.GetUidFromCTX(ctx, UserIDInContext)
+	if err != nil {
+		response.Error(ctx, errno.ErrUserNotLogin)
+		return
+	}
+
+	sid, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		response.Error(ctx, errno.ErrInvalidParam)
+		return
+	}
+
+	// 参数绑定
+	var updateRequest series.UpdateRequest
+	if err = ctx.ShouldBindJSON(&updateRequest); err != nil {
+		slog.Error("参数绑定失败", "error", utils.BindErrMsg(err))
+		response.Error(ctx, errno.ErrInvalidParam)
+		return
+	}
+
+	err = hdl.seriesSvc.Update(ctx, uid, sid, updateRequest.Title, updateRequest.Description)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+
+	response.Success(ctx, "系列更新成功", nil)
+}
+
+// Delete 删除系列
+func (hdl *SeriesHandler) Delete(ctx *gin.Context) {
+	// 由于前面有 Auth 中间件, 能走到这里默认上下文里已经被 Auth 塞了 uid, 直接拿即可
+	uid, err := utils.GetUidFromCTX(ctx, UserIDInContext)
+	if err != nil {
+		response.Error(ctx, errno.ErrUserNotLogin)
+		return
+	}
+
+	sid, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		response.Error(ctx, errno.ErrInvalidParam)
+		return
+	}
+
+	err = hdl.seriesSvc.Delete(ctx, uid, sid)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+
+	response.Success(ctx, "系列删除成功", nil)
+}
+
+// Detail 获取系列及其中的帖子
+func (hdl *SeriesHandler) Detail(ctx *gin.Context) {
+	sid, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		response.Error(ctx, errno.ErrInvalidParam)
+		return
+	}
+
+	seriesDTO, postDTOs, err := hdl.seriesSvc.Detail(ctx, sid, viewerUid(ctx))
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+
+	response.Success(ctx, "获取系列成功", gin.H{
+		"series": seriesDTO,
+		"posts":  postDTOs,
+	})
+}
+
+// ListByUid 按页获取用户的系列
+func (hdl *SeriesHandler) ListByUid(ctx *gin.Context) {
+	uid, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		response.Error(ctx, errno.ErrInvalidParam)
+		return
+	}
+
+	pageNo, err1 := strconv.Atoi(ctx.DefaultQuery("pageNo", "1"))
+	pageSize, err2 := strconv.Atoi(ctx.DefaultQuery("pageSize", "10"))
+	if err1 != nil || err2 != nil || pageNo < 1 || pageSize > 100 {
+		response.Error(ctx, errno.ErrInvalidParam)
+		return
+	}
+
+	total, seriesDTOs, err := hdl.seriesSvc.ListByUid(ctx, uid, pageNo, pageSize)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+
+	hasMore := pageNo*pageSize < total
+
+	response.Success(ctx, "获取系列列表成功", gin.H{
+		"series":  seriesDTOs,
+		"total":   total,
+		"hasMore": hasMore,
+	})
+}
+
+// AddPost 将帖子追加到系列末尾
+func (hdl *SeriesHandler) AddPost(ctx *gin.Context) {
+	// 由于前面有 Auth 中间件, 能走到这里默认上下文里已经被 Auth 塞了 uid, 直接拿即可
+	uid, err := utils.GetUidFromCTX(ctx, UserIDInContext)
+	if err != nil {
+		response.Error(ctx, errno.ErrUserNotLogin)
+		return
+	}
+
+	sid, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		response.Error(ctx, errno.ErrInvalidParam)
+		return
+	}
+
+	// 参数绑定
+	var addRequest series.AddPostRequest
+	if err = ctx.ShouldBindJSON(&addRequest); err != nil {
+		slog.Error("参数绑定失败", "error", utils.BindErrMsg(err))
+		response.Error(ctx, errno.ErrInvalidParam)
+		return
+	}
+
+	err = hdl.seriesSvc.AddPost(ctx, uid, sid, addRequest.PostID)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+
+	response.Success(ctx, "加入系列成功", nil)
+}
+
+// RemovePost 将帖子移出系列
+func (hdl *SeriesHandler) RemovePost(ctx *gin.Context) {
+	// 由于前面有 Auth 中间件, 能走到这里默认上下文里已经被 Auth 塞了 uid, 直接拿即可
+	uid, err := utils.GetUidFromCTX(ctx, UserIDInContext)
+	if err != nil {
+		response.Error(ctx, errno.ErrUserNotLogin)
+		return
+	}
+
+	sid, err1 := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	pid, err2 := strconv.ParseInt(ctx.Param("pid"), 10, 64)
+	if err1 != nil || err2 != nil {
+		response.Error(ctx, errno.ErrInvalidParam)
+		return
+	}
+
+	err = hdl.seriesSvc.RemovePost(ctx, uid, sid, pid)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+
+	response.Success(ctx, "移出系列成功", nil)
+}
+
+// Reorder 调整系列中帖子的顺序
+func (hdl *SeriesHandler) Reorder(ctx *gin.Context) {
+	// 由于前面有 Auth 中间件, 能走到这里默认上下文里已经被 Auth 塞了 uid, 直接拿即可
+	uid, err := utils.GetUidFromCTX(ctx, UserIDInContext)
+	if err != nil {
+		response.Error(ctx, errno.ErrUserNotLogin)
+		return
+	}
+
+	sid, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		response.Error(ctx, errno.ErrInvalidParam)
+		return
+	}
+
+	// 参数绑定
+	var reorderRequest series.ReorderRequest
+	if err = ctx.ShouldBindJSON(&reorderRequest); err != nil {
+		slog.Error("参数绑定失败", "error", utils.BindErrMsg(err))
+		response.Error(ctx, errno.ErrInvalidParam)
+		return
+	}
+	pids, err := parseIDs(reorderRequest.PostIDs)
+	if err != nil {
+		response.Error(ctx, errno.ErrInvalidParam)
+		return
+	}
+
+	err = hdl.seriesSvc.Reorder(ctx, uid, sid, pids)
+	if err != nil {
+		response.Error(ctx, err)
+		return
+	}
+
+	response.Success(ctx, "系列排序成功", nil)
+}
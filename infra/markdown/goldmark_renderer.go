@@ -0,0 +1,54 @@
+package markdown
+
+import (
+	"bytes"
+	"html"
+	"regexp"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yzletter/go-postery/service/ports"
+)
+
+type GoldmarkRenderer struct {
+	md     goldmark.Markdown
+	policy *bluemonday.Policy // 白名单清洗 HTML
+	strict *bluemonday.Policy // 去掉所有标签, 用于提取纯文本
+}
+
+// NewGoldmarkRenderer 将 Markdown (GFM) 渲染为 HTML, 原始 HTML 一律按白名单清洗
+func NewGoldmarkRenderer() ports.ContentRenderer {
+	md := goldmark.New(
+		goldmark.WithExtensions(extension.GFM), // 表格、删除线、任务列表、自动链接
+	)
+
+	policy := bluemonday.UGCPolicy()
+	// 保留围栏代码块的语言标记, 供前端高亮
+	policy.AllowAttrs("class").Matching(regexp.MustCompile(`^language-[\w+#-]+$`)).OnElements("code")
+	// 任务列表的复选框
+	policy.AllowAttrs("type").Matching(regexp.MustCompile(`^checkbox$`)).OnElements("input")
+	policy.AllowAttrs("checked", "disabled").OnElements("input")
+	policy.AddTargetBlankToFullyQualifiedLinks(true)
+
+	return &GoldmarkRenderer{
+		md:     md,
+		policy: policy,
+		strict: bluemonday.StrictPolicy(),
+	}
+}
+
+func (renderer *GoldmarkRenderer) Render(source string) (ports.RenderedContent, error) {
+	var buf bytes.Buffer
+	if err := renderer.md.Convert([]byte(source), &buf); err != nil {
+		return ports.RenderedContent{}, ports.ErrRenderFailed
+	}
+
+	safeHTML := renderer.policy.SanitizeBytes(buf.Bytes())
+	text := html.UnescapeString(string(renderer.strict.SanitizeBytes(safeHTML)))
+
+	return ports.RenderedContent{
+		HTML: string(safeHTML),
+		Text: text,
+	}, nil
+}
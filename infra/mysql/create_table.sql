@@ -24,6 +24,7 @@ CREATE TABLE IF NOT EXISTS users
     location      VARCHAR(64)                                      DEFAULT NULL COMMENT '地区',
     country       VARCHAR(64)                                      DEFAULT NULL COMMENT '国家',
     status        TINYINT                                 NOT NULL DEFAULT 1 COMMENT '用户状态 1 正常, 2 封禁, 3 注销',
+    role          TINYINT                                 NOT NULL DEFAULT 0 COMMENT '角色 0 普通用户, 1 管理员, 2 版主',
     last_login_ip VARCHAR(45)                                      DEFAULT NULL COMMENT '最后登录 IP',
     last_login_at DATETIME                                         DEFAULT NULL COMMENT '最后登录时间',
     created_at    DATETIME                                NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
@@ -38,32 +39,80 @@ CREATE TABLE IF NOT EXISTS users
     KEY idx_user_status_deleted (status, deleted_at),
 
     CHECK (gender IN (0, 1, 2, 3)),
-    CHECK (status IN (1, 2, 3))
+    CHECK (status IN (1, 2, 3)),
+    CHECK (role IN (0, 1, 2))
 ) ENGINE = InnoDB
   DEFAULT CHARSET = utf8mb4 COMMENT '用户表';
 
 # 创建 post 表
 CREATE TABLE IF NOT EXISTS posts
 (
-    id            BIGINT       NOT NULL COMMENT '帖子 ID',
-    user_id       BIGINT       NOT NULL COMMENT '发布者 ID',
-    title         varchar(255) NOT NULL COMMENT '标题',
-    content       TEXT COMMENT '正文',
-    status        TINYINT      NOT NULL DEFAULT 1 COMMENT '状态 1 正常, 2 封禁',
-    view_count    INT          NOT NULL DEFAULT 0 COMMENT '浏览量',
-    like_count    INT          NOT NULL DEFAULT 0 COMMENT '点赞数',
-    comment_count INT          NOT NULL DEFAULT 0 COMMENT '评论数',
-
-    created_at    DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
-    updated_at    DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
-    deleted_at    DATETIME              DEFAULT NULL COMMENT '逻辑删除时间',
+    id                BIGINT       NOT NULL COMMENT '帖子 ID',
+    user_id           BIGINT       NOT NULL COMMENT '发布者 ID',
+    title             varchar(255) NOT NULL COMMENT '标题',
+    slug              varchar(96)  NOT NULL DEFAULT '' COMMENT '由标题生成的当前 slug, 为空表示尚未生成',
+    content           TEXT         COMMENT '正文 Markdown 源文',
+    content_html      MEDIUMTEXT   COMMENT '渲染并清洗后的正文 HTML',
+    excerpt           varchar(512) NOT NULL DEFAULT '' COMMENT '纯文本摘要',
+    status            TINYINT      NOT NULL DEFAULT 1 COMMENT '状态 1 正常, 2 封禁, 3 待审核',
+    visibility        TINYINT      NOT NULL DEFAULT 1 COMMENT '可见范围 1 所有人, 2 仅粉丝, 3 仅自己',
+    view_count        INT          NOT NULL DEFAULT 0 COMMENT '浏览量',
+    unique_view_count INT          NOT NULL DEFAULT 0 COMMENT '去重浏览量',
+    like_count        INT          NOT NULL DEFAULT 0 COMMENT '点赞数',
+    comment_count     INT          NOT NULL DEFAULT 0 COMMENT '评论数',
+    bookmark_count    INT          NOT NULL DEFAULT 0 COMMENT '收藏数',
+    share_count       INT          NOT NULL DEFAULT 0 COMMENT '转发数, 包括直接转发和引用转发',
+    quote_id          BIGINT       NOT NULL DEFAULT 0 COMMENT '引用的帖子 id, 0 表示不是引用转发',
+    comment_policy    TINYINT      NOT NULL DEFAULT 1 COMMENT '评论权限 1 所有人, 2 仅粉丝, 3 关闭',
+    pinned_comment_id BIGINT       NOT NULL DEFAULT 0 COMMENT '作者置顶的评论 id, 0 表示没有置顶',
+    featured_at       DATETIME              DEFAULT NULL COMMENT '加精时间, 为空表示未加精',
+    version           INT          NOT NULL DEFAULT 1 COMMENT '版本号, 作者每次编辑自增',
+    edited_at         DATETIME              DEFAULT NULL COMMENT '作者最近编辑时间, 为空表示未编辑过',
+
+    created_at        DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at        DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    deleted_at        DATETIME              DEFAULT NULL COMMENT '逻辑删除时间',
 
     PRIMARY KEY (id),
     KEY idx_user_created (user_id, created_at DESC),
     KEY idx_created (created_at DESC),
-    KEY idx_status_deleted_created (status, deleted_at, created_at DESC)
+    KEY idx_visibility_created (visibility, created_at DESC),
+    KEY idx_status_deleted_created (status, deleted_at, created_at DESC),
+    KEY idx_featured (featured_at DESC),
+    KEY idx_slug (slug),
+    KEY idx_quote (quote_id)
 ) DEFAULT CHARSET = utf8mb4 COMMENT '帖子信息表';
 
+# Post Pin 表
+CREATE TABLE IF NOT EXISTS post_pins
+(
+    id         BIGINT   NOT NULL COMMENT '置顶 ID',
+    post_id    BIGINT   NOT NULL COMMENT '帖子 id',
+    tag_id     BIGINT   NOT NULL DEFAULT 0 COMMENT '置顶范围 0 全站, 否则为标签 id',
+    pinned_by  BIGINT   NOT NULL COMMENT '操作人 id',
+    expires_at DATETIME          DEFAULT NULL COMMENT '过期时间, 为空表示永久置顶',
+
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '置顶时间',
+
+    PRIMARY KEY (id),
+    UNIQUE KEY uq_tag_post (tag_id, post_id),
+    KEY idx_tag_created (tag_id, created_at DESC)
+) DEFAULT CHARSET = utf8mb4 COMMENT '帖子置顶表';
+
+# Post Slug 表
+CREATE TABLE IF NOT EXISTS post_slugs
+(
+    id         BIGINT      NOT NULL COMMENT '记录 id',
+    post_id    BIGINT      NOT NULL COMMENT '帖子 id',
+    slug       varchar(96) NOT NULL COMMENT 'slug, 包括标题修改前用过的',
+
+    created_at DATETIME    NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '生成时间',
+
+    PRIMARY KEY (id),
+    UNIQUE KEY uq_slug (slug),
+    KEY idx_post (post_id)
+) DEFAULT CHARSET = utf8mb4 COMMENT '帖子 slug 表';
+
 # 创建 follow 表
 CREATE TABLE IF NOT EXISTS follows
 (
@@ -91,6 +140,10 @@ CREATE TABLE IF NOT EXISTS comments
     parent_id  BIGINT   NOT NULL DEFAULT 0 COMMENT '父评论 id',
     reply_id   BIGINT   NOT NULL DEFAULT 0 COMMENT '回复评论 id',
     content    TEXT     NOT NULL COMMENT '正文',
+    status     TINYINT  NOT NULL DEFAULT 1 COMMENT '状态 1 正常, 2 待审核',
+    like_count INT      NOT NULL DEFAULT 0 COMMENT '点赞数',
+    edit_count INT      NOT NULL DEFAULT 0 COMMENT '作者编辑次数',
+    edited_at  DATETIME          DEFAULT NULL COMMENT '作者最近编辑时间',
 
     created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
     updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
@@ -99,9 +152,74 @@ CREATE TABLE IF NOT EXISTS comments
     PRIMARY KEY (id),
     KEY idx_post_created (post_id, created_at),
     KEY idx_post_parent_created (post_id, parent_id, created_at),
-    KEY idx_post_reply_created (post_id, reply_id, created_at)
+    KEY idx_post_reply_created (post_id, reply_id, created_at),
+    KEY idx_parent_created (parent_id, created_at)
 ) DEFAULT CHARSET = utf8mb4 COMMENT '评论信息表';
 
+# CommentLike 表
+CREATE TABLE IF NOT EXISTS comment_likes
+(
+    id         BIGINT COMMENT '记录 ID',
+    comment_id BIGINT   NOT NULL COMMENT '被点赞评论 id',
+    user_id    BIGINT   NOT NULL COMMENT '点赞者 id',
+
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    deleted_at DATETIME          DEFAULT NULL COMMENT '逻辑删除时间',
+
+    PRIMARY KEY (id),
+    UNIQUE KEY uq_user_comment (user_id, comment_id),
+    KEY idx_comment (comment_id)
+) DEFAULT CHARSET = utf8mb4 COMMENT '评论点赞表';
+
+# CommentRevision 表
+CREATE TABLE IF NOT EXISTS comment_revisions
+(
+    id         BIGINT   NOT NULL COMMENT '记录 ID',
+    comment_id BIGINT   NOT NULL COMMENT '评论 id',
+    version    INT      NOT NULL COMMENT '该内容对应的编辑次数, 0 为最初发表的内容',
+    content    TEXT     NOT NULL COMMENT '被替换前的内容',
+    status     TINYINT  NOT NULL COMMENT '被替换前的评论状态',
+
+    created_at DATETIME NOT NULL COMMENT '该内容的发表时间',
+
+    PRIMARY KEY (id),
+    UNIQUE KEY uq_comment_version (comment_id, version)
+) DEFAULT CHARSET = utf8mb4 COMMENT '评论历史版本表';
+
+# CommentBlock 表
+CREATE TABLE IF NOT EXISTS comment_blocks
+(
+    id         BIGINT   NOT NULL COMMENT '记录 ID',
+    author_id  BIGINT   NOT NULL COMMENT '作者 id',
+    user_id    BIGINT   NOT NULL COMMENT '被禁止评论的用户 id',
+
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '拉黑时间',
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    deleted_at DATETIME          DEFAULT NULL COMMENT '逻辑删除时间',
+
+    PRIMARY KEY (id),
+    UNIQUE KEY uq_author_user (author_id, user_id),
+    KEY idx_author_created (author_id, created_at)
+) DEFAULT CHARSET = utf8mb4 COMMENT '评论黑名单表';
+
+# Mention 表
+CREATE TABLE IF NOT EXISTS mentions
+(
+    id          BIGINT   NOT NULL COMMENT '记录 ID',
+    target_type TINYINT  NOT NULL COMMENT '提及所在的对象类型 1 帖子, 2 评论',
+    target_id   BIGINT   NOT NULL COMMENT '提及所在的对象 id',
+    post_id     BIGINT   NOT NULL COMMENT '所属帖子 id',
+    from_uid    BIGINT   NOT NULL COMMENT '发出提及的用户 id',
+    user_id     BIGINT   NOT NULL COMMENT '被提及的用户 id',
+
+    created_at  DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+
+    PRIMARY KEY (id),
+    UNIQUE KEY uq_target_user (target_type, target_id, user_id),
+    KEY idx_user_created (user_id, created_at)
+) DEFAULT CHARSET = utf8mb4 COMMENT '用户提及表';
+
 # Like 表
 CREATE TABLE IF NOT EXISTS likes
 (
@@ -120,6 +238,270 @@ CREATE TABLE IF NOT EXISTS likes
     KEY idx_post_deleted (post_id, deleted_at)
 ) DEFAULT CHARSET = utf8mb4 COMMENT '用户点赞表';
 
+# Repost 表
+CREATE TABLE IF NOT EXISTS reposts
+(
+    id         BIGINT   NOT NULL COMMENT '记录 ID',
+    post_id    BIGINT   NOT NULL COMMENT '被转发帖子 id',
+    user_id    BIGINT   NOT NULL COMMENT '转发者 id',
+
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '转发时间',
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    deleted_at DATETIME          DEFAULT NULL COMMENT '逻辑删除时间',
+
+    PRIMARY KEY (id),
+    UNIQUE KEY uq_user_post (user_id, post_id),
+    KEY idx_user_created (user_id, created_at DESC),
+    KEY idx_post_deleted (post_id, deleted_at)
+) DEFAULT CHARSET = utf8mb4 COMMENT '用户转发表';
+
+# Bookmark 表
+CREATE TABLE IF NOT EXISTS bookmarks
+(
+    id            BIGINT   NOT NULL COMMENT '记录 ID',
+    post_id       BIGINT   NOT NULL COMMENT '被收藏帖子 id',
+    user_id       BIGINT   NOT NULL COMMENT '收藏者 id',
+    collection_id BIGINT   NOT NULL DEFAULT 0 COMMENT '所属收藏夹 id, 0 表示默认收藏夹',
+
+    created_at    DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at    DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    deleted_at    DATETIME          DEFAULT NULL COMMENT '逻辑删除时间',
+
+    PRIMARY KEY (id),
+    UNIQUE KEY uq_user_post (user_id, post_id),
+    KEY idx_user_collection_created (user_id, collection_id, created_at DESC),
+    KEY idx_post_deleted (post_id, deleted_at)
+) DEFAULT CHARSET = utf8mb4 COMMENT '用户收藏表';
+
+# Bookmark Collection 表
+CREATE TABLE IF NOT EXISTS bookmark_collections
+(
+    id         BIGINT      NOT NULL COMMENT '收藏夹 ID',
+    user_id    BIGINT      NOT NULL COMMENT '创建者 id',
+    name       varchar(64) NOT NULL COMMENT '收藏夹名称',
+
+    created_at DATETIME    NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at DATETIME    NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    deleted_at DATETIME             DEFAULT NULL COMMENT '逻辑删除时间',
+
+    PRIMARY KEY (id),
+    UNIQUE KEY uq_user_name (user_id, name),
+    KEY idx_user_created (user_id, created_at)
+) DEFAULT CHARSET = utf8mb4 COMMENT '用户收藏夹表';
+
+# Series 表
+CREATE TABLE IF NOT EXISTS series
+(
+    id          BIGINT       NOT NULL COMMENT '系列 ID',
+    user_id     BIGINT       NOT NULL COMMENT '作者 id',
+    title       varchar(128) NOT NULL COMMENT '标题',
+    description varchar(512) NOT NULL DEFAULT '' COMMENT '简介',
+
+    created_at  DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at  DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    deleted_at  DATETIME              DEFAULT NULL COMMENT '逻辑删除时间',
+
+    PRIMARY KEY (id),
+    KEY idx_user_created (user_id, created_at)
+) DEFAULT CHARSET = utf8mb4 COMMENT '帖子系列表';
+
+# Series Post 表
+CREATE TABLE IF NOT EXISTS series_posts
+(
+    id         BIGINT   NOT NULL COMMENT '记录 id',
+    series_id  BIGINT   NOT NULL COMMENT '系列 id',
+    post_id    BIGINT   NOT NULL COMMENT '帖子 id',
+    position   INT      NOT NULL COMMENT '在系列中的位置, 从 1 开始连续',
+
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '加入时间',
+
+    PRIMARY KEY (id),
+    UNIQUE KEY uq_post (post_id),
+    KEY idx_series_position (series_id, position)
+) DEFAULT CHARSET = utf8mb4 COMMENT '系列——帖子信息表';
+
+# Poll 表
+CREATE TABLE IF NOT EXISTS polls
+(
+    id          BIGINT   NOT NULL COMMENT '投票 ID',
+    post_id     BIGINT   NOT NULL COMMENT '帖子 id',
+    multiple    TINYINT  NOT NULL DEFAULT 0 COMMENT '是否多选',
+    anonymous   TINYINT  NOT NULL DEFAULT 0 COMMENT '是否匿名, 匿名时不公开投票人',
+    deadline    DATETIME          DEFAULT NULL COMMENT '截止时间, 为空表示不截止',
+    voter_count INT      NOT NULL DEFAULT 0 COMMENT '投票人数',
+
+    created_at  DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at  DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+
+    PRIMARY KEY (id),
+    UNIQUE KEY uq_post (post_id)
+) DEFAULT CHARSET = utf8mb4 COMMENT '帖子投票表';
+
+# Poll Option 表
+CREATE TABLE IF NOT EXISTS poll_options
+(
+    id         BIGINT      NOT NULL COMMENT '选项 ID',
+    poll_id    BIGINT      NOT NULL COMMENT '投票 id',
+    position   INT         NOT NULL COMMENT '选项顺序, 从 1 开始',
+    text       varchar(64) NOT NULL COMMENT '选项内容',
+    vote_count INT         NOT NULL DEFAULT 0 COMMENT '得票数',
+
+    PRIMARY KEY (id),
+    KEY idx_poll_position (poll_id, position)
+) DEFAULT CHARSET = utf8mb4 COMMENT '投票选项表';
+
+# Poll Vote 表
+CREATE TABLE IF NOT EXISTS poll_votes
+(
+    id         BIGINT   NOT NULL COMMENT '记录 id',
+    poll_id    BIGINT   NOT NULL COMMENT '投票 id',
+    option_id  BIGINT   NOT NULL COMMENT '选项 id',
+    user_id    BIGINT   NOT NULL COMMENT '投票人 id',
+
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '投票时间',
+
+    PRIMARY KEY (id),
+    UNIQUE KEY uq_poll_user_option (poll_id, user_id, option_id),
+    KEY idx_option (option_id)
+) DEFAULT CHARSET = utf8mb4 COMMENT '投票记录表';
+
+# Attachment 表
+CREATE TABLE IF NOT EXISTS attachments
+(
+    id          BIGINT       NOT NULL COMMENT '附件 ID',
+    user_id     BIGINT       NOT NULL COMMENT '上传者 id',
+    post_id     BIGINT       NOT NULL DEFAULT 0 COMMENT '关联帖子 id, 0 表示尚未关联',
+    file_name   varchar(255) NOT NULL DEFAULT '' COMMENT '原始文件名',
+    mime_type   varchar(64)  NOT NULL COMMENT '嗅探得到的 MIME 类型',
+    size        BIGINT       NOT NULL COMMENT '原文件大小',
+    total_size  BIGINT       NOT NULL COMMENT '原文件与图片变体的总大小, 用于配额统计',
+    width       INT          NOT NULL DEFAULT 0 COMMENT '图片宽度, 非图片为 0',
+    height      INT          NOT NULL DEFAULT 0 COMMENT '图片高度, 非图片为 0',
+    storage_key varchar(255) NOT NULL COMMENT '原文件存储 Key',
+    medium_key  varchar(255) NOT NULL DEFAULT '' COMMENT '展示图存储 Key',
+    thumb_key   varchar(255) NOT NULL DEFAULT '' COMMENT '缩略图存储 Key',
+    status      TINYINT      NOT NULL DEFAULT 1 COMMENT '状态: 1 正常, 2 待回收',
+
+    created_at  DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at  DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+    deleted_at  DATETIME              DEFAULT NULL COMMENT '逻辑删除时间',
+
+    PRIMARY KEY (id),
+    KEY idx_user_deleted (user_id, deleted_at),
+    KEY idx_post_status (post_id, status),
+    KEY idx_status_updated (status, updated_at)
+) DEFAULT CHARSET = utf8mb4 COMMENT '附件表';
+
+# Report 表
+CREATE TABLE IF NOT EXISTS reports
+(
+    id          BIGINT       NOT NULL COMMENT '举报 ID',
+    reporter_id BIGINT       NOT NULL COMMENT '举报人 id, 0 表示命中敏感词由系统提交',
+    target_type TINYINT      NOT NULL COMMENT '举报对象类型 1 帖子, 2 评论, 3 用户',
+    target_id   BIGINT       NOT NULL COMMENT '举报对象 id',
+    reason      varchar(16)  NOT NULL COMMENT '举报原因',
+    detail      varchar(500) NOT NULL DEFAULT '' COMMENT '补充说明',
+    status      TINYINT      NOT NULL DEFAULT 1 COMMENT '状态 1 待处理, 2 成立, 3 驳回, 4 已下架',
+    handled_by  BIGINT       NOT NULL DEFAULT 0 COMMENT '处理人 id',
+    handled_at  DATETIME              DEFAULT NULL COMMENT '处理时间',
+
+    created_at  DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at  DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+
+    PRIMARY KEY (id),
+    UNIQUE KEY uq_reporter_target (reporter_id, target_type, target_id),
+    KEY idx_status_target (status, target_type, target_id)
+) DEFAULT CHARSET = utf8mb4 COMMENT '举报表';
+
+# Moderation Log 表
+CREATE TABLE IF NOT EXISTS moderation_logs
+(
+    id           BIGINT       NOT NULL COMMENT '记录 ID',
+    operator_id  BIGINT       NOT NULL COMMENT '处理人 id',
+    target_type  TINYINT      NOT NULL COMMENT '处理对象类型 1 帖子, 2 评论, 3 用户',
+    target_id    BIGINT       NOT NULL COMMENT '处理对象 id',
+    action       TINYINT      NOT NULL COMMENT '处理结果 2 成立, 3 驳回, 4 下架',
+    note         varchar(500) NOT NULL DEFAULT '' COMMENT '处理备注',
+    report_count INT          NOT NULL DEFAULT 0 COMMENT '本次处理的举报数',
+
+    created_at   DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+
+    PRIMARY KEY (id),
+    KEY idx_created (created_at DESC),
+    KEY idx_target (target_type, target_id)
+) DEFAULT CHARSET = utf8mb4 COMMENT '审核处理记录表';
+
+# Sensitive Word 表
+CREATE TABLE IF NOT EXISTS sensitive_words
+(
+    id         BIGINT      NOT NULL COMMENT '敏感词 ID',
+    word       varchar(64) NOT NULL COMMENT '敏感词, 统一小写',
+    category   varchar(32) NOT NULL COMMENT '所属分类',
+
+    created_at DATETIME    NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at DATETIME    NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+
+    PRIMARY KEY (id),
+    UNIQUE KEY uq_word (word),
+    KEY idx_category_created (category, created_at)
+) DEFAULT CHARSET = utf8mb4 COLLATE = utf8mb4_bin COMMENT '敏感词表';
+
+# Sensitive Category 表
+CREATE TABLE IF NOT EXISTS sensitive_categories
+(
+    id         BIGINT      NOT NULL COMMENT '分类 ID',
+    name       varchar(32) NOT NULL COMMENT '分类名',
+    action     TINYINT     NOT NULL COMMENT '处理动作 1 打码, 2 审核, 3 拒绝',
+
+    created_at DATETIME    NOT NULL DEFAULT CURRENT_TIMESTAMP COMMENT '创建时间',
+    updated_at DATETIME    NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP COMMENT '更新时间',
+
+    PRIMARY KEY (id),
+    UNIQUE KEY uq_name (name),
+    CHECK (action IN (1, 2, 3))
+) DEFAULT CHARSET = utf8mb4 COMMENT '敏感词分类表';
+
+# Post Stat 表
+CREATE TABLE IF NOT EXISTS post_stats
+(
+    post_id        BIGINT   NOT NULL COMMENT '帖子 id',
+    granularity    TINYINT  NOT NULL COMMENT '统计粒度 1 小时, 2 天',
+    bucket         DATETIME NOT NULL COMMENT '时间桶起点',
+    author_id      BIGINT   NOT NULL COMMENT '帖子作者 id',
+    view_count     INT      NOT NULL DEFAULT 0 COMMENT '浏览数',
+    like_count     INT      NOT NULL DEFAULT 0 COMMENT '点赞数',
+    comment_count  INT      NOT NULL DEFAULT 0 COMMENT '评论数',
+    bookmark_count INT      NOT NULL DEFAULT 0 COMMENT '收藏数',
+
+    PRIMARY KEY (post_id, granularity, bucket),
+    KEY idx_author_bucket (author_id, granularity, bucket),
+    KEY idx_granularity_bucket (granularity, bucket)
+) DEFAULT CHARSET = utf8mb4 COMMENT '帖子分时统计表';
+
+# Post Referrer Stat 表
+CREATE TABLE IF NOT EXISTS post_referrer_stats
+(
+    post_id    BIGINT      NOT NULL COMMENT '帖子 id',
+    bucket     DATETIME    NOT NULL COMMENT '当天零点',
+    referrer   varchar(64) NOT NULL COMMENT '来源站点域名, direct 表示没有来源',
+    author_id  BIGINT      NOT NULL COMMENT '帖子作者 id',
+    view_count INT         NOT NULL DEFAULT 0 COMMENT '浏览数',
+
+    PRIMARY KEY (post_id, bucket, referrer),
+    KEY idx_author_bucket (author_id, bucket)
+) DEFAULT CHARSET = utf8mb4 COMMENT '帖子浏览来源统计表';
+
+# Follower Stat 表
+CREATE TABLE IF NOT EXISTS follower_stats
+(
+    user_id BIGINT   NOT NULL COMMENT '用户 id',
+    bucket  DATETIME NOT NULL COMMENT '当天零点',
+    gained  INT      NOT NULL DEFAULT 0 COMMENT '新增粉丝数',
+    lost    INT      NOT NULL DEFAULT 0 COMMENT '流失粉丝数',
+
+    PRIMARY KEY (user_id, bucket)
+) DEFAULT CHARSET = utf8mb4 COMMENT '粉丝变化统计表';
+
 # Tag 表
 CREATE TABLE IF NOT EXISTS tags
 (
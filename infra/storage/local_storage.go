@@ -0,0 +1,70 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/yzletter/go-postery/service/ports"
+)
+
+// LocalStorage 将文件保存在本地磁盘, 由 gin 的静态文件路由对外提供访问
+type LocalStorage struct {
+	root      string // 存储根目录
+	urlPrefix string // 对外访问的 URL 前缀
+}
+
+func NewLocalStorage(root, urlPrefix string) ports.ObjectStorage {
+	return &LocalStorage{root: root, urlPrefix: urlPrefix}
+}
+
+func (storage *LocalStorage) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	p := storage.path(key)
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		slog.Error("Local Storage MkdirAll Failed", "key", key, "error", err)
+		return ports.ErrStoragePutFailed
+	}
+
+	// 先写临时文件再重命名, 避免读到写了一半的文件
+	tmp := p + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		slog.Error("Local Storage Create Failed", "key", key, "error", err)
+		return ports.ErrStoragePutFailed
+	}
+	_, err = io.Copy(f, r)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp, p)
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+		slog.Error("Local Storage Write Failed", "key", key, "error", err)
+		return ports.ErrStoragePutFailed
+	}
+	return nil
+}
+
+func (storage *LocalStorage) Delete(ctx context.Context, key string) error {
+	err := os.Remove(storage.path(key))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		slog.Error("Local Storage Delete Failed", "key", key, "error", err)
+		return ports.ErrStorageDeleteFailed
+	}
+	return nil
+}
+
+func (storage *LocalStorage) URL(key string) string {
+	return path.Join(storage.urlPrefix, key)
+}
+
+// path 将 key 转为本地路径, key 由服务端生成, Clean 只是兜底防止跳出根目录
+func (storage *LocalStorage) path(key string) string {
+	return filepath.Join(storage.root, filepath.FromSlash(path.Clean("/"+key)))
+}
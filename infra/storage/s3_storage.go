@@ -0,0 +1,58 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/yzletter/go-postery/service/ports"
+)
+
+// S3Storage 将文件保存在 S3 兼容的对象存储中 (AWS S3、MinIO、OSS 等)
+type S3Storage struct {
+	client    *minio.Client
+	bucket    string
+	publicURL string // 对外访问的 URL 前缀
+}
+
+func NewS3Storage(endpoint, accessKey, secretKey, bucket, publicURL string, useSSL bool) ports.ObjectStorage {
+	client, err := minio.New(endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(accessKey, secretKey, ""),
+		Secure: useSSL,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	return &S3Storage{
+		client:    client,
+		bucket:    bucket,
+		publicURL: strings.TrimRight(publicURL, "/"),
+	}
+}
+
+func (storage *S3Storage) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	_, err := storage.client.PutObject(ctx, storage.bucket, key, r, size, minio.PutObjectOptions{ContentType: contentType})
+	if err != nil {
+		slog.Error("S3 PutObject Failed", "key", key, "error", err)
+		return ports.ErrStoragePutFailed
+	}
+	return nil
+}
+
+func (storage *S3Storage) Delete(ctx context.Context, key string) error {
+	// 对象不存在时 RemoveObject 不返回错误, 天然幂等
+	err := storage.client.RemoveObject(ctx, storage.bucket, key, minio.RemoveObjectOptions{})
+	if err != nil {
+		slog.Error("S3 RemoveObject Failed", "key", key, "error", err)
+		return ports.ErrStorageDeleteFailed
+	}
+	return nil
+}
+
+func (storage *S3Storage) URL(key string) string {
+	return storage.publicURL + "/" + key
+}
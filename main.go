@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"syscall"
@@ -13,6 +14,7 @@ import (
 	"github.com/yzletter/go-postery/handler"
 	"github.com/yzletter/go-postery/infra/crontab"
 	"github.com/yzletter/go-postery/infra/graceful_stop"
+	"github.com/yzletter/go-postery/infra/markdown"
 	infraMySQL "github.com/yzletter/go-postery/infra/mysql"
 	infraRabbitMQ "github.com/yzletter/go-postery/infra/rabbitmq"
 	infraRedis "github.com/yzletter/go-postery/infra/redis"
@@ -21,6 +23,7 @@ import (
 	"github.com/yzletter/go-postery/infra/slog"
 	"github.com/yzletter/go-postery/infra/sms"
 	"github.com/yzletter/go-postery/infra/snowflake"
+	"github.com/yzletter/go-postery/infra/storage"
 	"github.com/yzletter/go-postery/infra/viper"
 	"github.com/yzletter/go-postery/middleware"
 	"github.com/yzletter/go-postery/repository"
@@ -39,12 +42,6 @@ func main() {
 		AddFuncWithSpec("*/10 * * * *", infraRedis.Ping).
 		Build()
 
-	// 初始化 GracefulStop
-	graceful_stop.NewGracefulStopBuilder().
-		NotifySignal(syscall.SIGINT).NotifySignal(syscall.SIGTERM).
-		AddFunc(infraMySQL.Close).AddFunc(infraRedis.Close).AddFunc(infraRabbitMQ.Close).AddFunc(infraRocketMQ.Close).
-		Build()
-
 	GormDB := infraMySQL.Init("./conf", "db", viper.YAML, "./logs") // 初始化 MySQL
 	RedisClient := infraRedis.Init("./conf", "cache", viper.YAML)   // 初始化 Redis
 	RabbitMQ := infraRabbitMQ.Init("./conf", "mq", viper.YAML)      // 初始化 RabbitMQ
@@ -52,6 +49,12 @@ func main() {
 
 	IDGenerator := snowflake.NewSnowflakeIDGenerator(0)   // 初始化 雪花算法
 	PasswordHasher := security.NewBcryptPasswordHasher(0) // 初始化 密码哈希器
+	ContentRenderer := markdown.NewGoldmarkRenderer()     // 初始化 正文渲染器
+	ObjectStorage := storage.NewLocalStorage(conf.AttachmentStorageLocal, conf.AttachmentURLPrefix)
+	if endpoint := os.Getenv(conf.S3Endpoint); endpoint != "" { // 初始化 对象存储, 配置了 S3 时优先使用
+		ObjectStorage = storage.NewS3Storage(endpoint, os.Getenv(conf.S3AccessKey), os.Getenv(conf.S3SecretKey),
+			os.Getenv(conf.S3Bucket), os.Getenv(conf.S3PublicURL), os.Getenv(conf.S3UseSSL) == "true")
+	}
 	JwtManager := security.NewJwtManager(conf.JwtTokenKey)
 	SmsClient := sms.NewAliyunSmsClient(os.Getenv(conf.AliyunAccessTokenKeyID), os.Getenv(conf.AliyunAccessTokenKeySecret)) // 初始化 短信服务商
 
@@ -59,19 +62,38 @@ func main() {
 	UserDAO := dao.NewUserDAO(GormDB)
 	PostDAO := dao.NewPostDAO(GormDB)
 	CommentDAO := dao.NewCommentDAO(GormDB)
+	CommentLikeDAO := dao.NewCommentLikeDAO(GormDB)
+	CommentBlockDAO := dao.NewCommentBlockDAO(GormDB)
+	MentionDAO := dao.NewMentionDAO(GormDB)
 	LikeDAO := dao.NewLikeDAO(GormDB)
+	BookmarkDAO := dao.NewBookmarkDAO(GormDB)
+	RepostDAO := dao.NewRepostDAO(GormDB)
+	AnalyticsDAO := dao.NewAnalyticsDAO(GormDB)
+	SeriesDAO := dao.NewSeriesDAO(GormDB)
+	PollDAO := dao.NewPollDAO(GormDB)
 	FollowDAO := dao.NewFollowDAO(GormDB)
 	TagDAO := dao.NewTagDAO(GormDB)
 	MessageDAO := dao.NewMessageDAO(GormDB)
 	SessionDAO := dao.NewSessionDAO(GormDB)
 	OrderDAO := dao.NewOrderDAO(GormDB)
 	GiftDAO := dao.NewGiftDAO(GormDB)
+	AttachmentDAO := dao.NewAttachmentDAO(GormDB)
+	ReportDAO := dao.NewReportDAO(GormDB)
+	SensitiveDAO := dao.NewSensitiveDAO(GormDB)
 
 	// Cache 层
 	UserCache := cache.NewUserCache(RedisClient)
 	PostCache := cache.NewPostCache(RedisClient)
 	CommentCache := cache.NewCommentCache(RedisClient)
+	CommentLikeCache := cache.NewCommentLikeCache(RedisClient)
+	CommentBlockCache := cache.NewCommentBlockCache(RedisClient)
+	MentionCache := cache.NewMentionCache(RedisClient)
 	LikeCache := cache.NewLikeCache(RedisClient)
+	BookmarkCache := cache.NewBookmarkCache(RedisClient)
+	RepostCache := cache.NewRepostCache(RedisClient)
+	AnalyticsCache := cache.NewAnalyticsCache(RedisClient)
+	SeriesCache := cache.NewSeriesCache(RedisClient)
+	PollCache := cache.NewPollCache(RedisClient)
 	FollowCache := cache.NewFollowCache(RedisClient)
 	TagCache := cache.NewTagCache(RedisClient)
 	MessageCache := cache.NewMessageCache(RedisClient)
@@ -79,57 +101,119 @@ func main() {
 	SmsCache := cache.NewSmsCache(RedisClient)
 	OrderCache := cache.NewOrderCache(RedisClient)
 	GiftCache := cache.NewGiftCache(RedisClient)
+	AttachmentCache := cache.NewAttachmentCache(RedisClient)
+	ReportCache := cache.NewReportCache(RedisClient)
+	SensitiveCache := cache.NewSensitiveCache(RedisClient)
 
 	// Repository 层
-	UserRepo := repository.NewUserRepository(UserDAO, UserCache)             // 注册 userRepo
-	PostRepo := repository.NewPostRepository(PostDAO, PostCache)             // 注册 PostRepository
-	CommentRepo := repository.NewCommentRepository(CommentDAO, CommentCache) // 注册 CommentRepository
-	LikeRepo := repository.NewLikeRepository(LikeDAO, LikeCache)             // 注册 LikeRepository
-	FollowRepo := repository.NewFollowRepository(FollowDAO, FollowCache)     // 注册 FollowRepository
-	TagRepo := repository.NewTagRepository(TagDAO, TagCache)                 // 注册 TagRepository
-	MessageRepo := repository.NewMessageRepository(MessageDAO, MessageCache) // 注册 MessageRepository
-	SessionRepo := repository.NewSessionRepository(SessionDAO, SessionCache) // 注册 SessionRepository
-	SmsRepo := repository.NewSmsRepository(SmsCache)                         // 注册 SmsRepository
-	OrderRepo := repository.NewOrderRepository(OrderDAO, OrderCache)         // 注册 OrderRepository
-	GiftRepo := repository.NewGiftRepository(GiftDAO, GiftCache)             // 注册 GiftRepository
+	UserRepo := repository.NewUserRepository(UserDAO, UserCache)                                 // 注册 userRepo
+	PostRepo := repository.NewPostRepository(PostDAO, PostCache)                                 // 注册 PostRepository
+	CommentRepo := repository.NewCommentRepository(CommentDAO, CommentCache)                     // 注册 CommentRepository
+	CommentLikeRepo := repository.NewCommentLikeRepository(CommentLikeDAO, CommentLikeCache)     // 注册 CommentLikeRepository
+	CommentBlockRepo := repository.NewCommentBlockRepository(CommentBlockDAO, CommentBlockCache) // 注册 CommentBlockRepository
+	MentionRepo := repository.NewMentionRepository(MentionDAO, MentionCache)                     // 注册 MentionRepository
+	LikeRepo := repository.NewLikeRepository(LikeDAO, LikeCache)                                 // 注册 LikeRepository
+	BookmarkRepo := repository.NewBookmarkRepository(BookmarkDAO, BookmarkCache)                 // 注册 BookmarkRepository
+	RepostRepo := repository.NewRepostRepository(RepostDAO, RepostCache)                         // 注册 RepostRepository
+	AnalyticsRepo := repository.NewAnalyticsRepository(AnalyticsDAO, AnalyticsCache)             // 注册 AnalyticsRepository
+	SeriesRepo := repository.NewSeriesRepository(SeriesDAO, SeriesCache)                         // 注册 SeriesRepository
+	PollRepo := repository.NewPollRepository(PollDAO, PollCache)                                 // 注册 PollRepository
+	FollowRepo := repository.NewFollowRepository(FollowDAO, FollowCache)                         // 注册 FollowRepository
+	TagRepo := repository.NewTagRepository(TagDAO, TagCache)                                     // 注册 TagRepository
+	MessageRepo := repository.NewMessageRepository(MessageDAO, MessageCache)                     // 注册 MessageRepository
+	SessionRepo := repository.NewSessionRepository(SessionDAO, SessionCache)                     // 注册 SessionRepository
+	SmsRepo := repository.NewSmsRepository(SmsCache)                                             // 注册 SmsRepository
+	OrderRepo := repository.NewOrderRepository(OrderDAO, OrderCache)                             // 注册 OrderRepository
+	GiftRepo := repository.NewGiftRepository(GiftDAO, GiftCache)                                 // 注册 GiftRepository
+	AttachmentRepo := repository.NewAttachmentRepository(AttachmentDAO, AttachmentCache)         // 注册 AttachmentRepository
+	ReportRepo := repository.NewReportRepository(ReportDAO, ReportCache)                         // 注册 ReportRepository
+	SensitiveRepo := repository.NewSensitiveRepository(SensitiveDAO, SensitiveCache)             // 注册 SensitiveRepository
 
 	// Service 层
-	MetricSvc := service.NewMetricService()                                                                // 注册 MetricService
-	RateLimitSvc := service.NewRateLimitService(RedisClient, conf.RateLimitInterval, conf.RateLimitRate)   // 注册 RateLimitService
-	AuthSvc := service.NewAuthService(UserRepo, JwtManager, PasswordHasher, IDGenerator, RedisClient)      // 注册 AuthService
-	UserSvc := service.NewUserService(UserRepo, IDGenerator, PasswordHasher)                               // 注册 userSvc
-	PostSvc := service.NewPostService(PostRepo, UserRepo, LikeRepo, TagRepo, IDGenerator)                  // 注册 postSvc
-	FollowSvc := service.NewFollowService(FollowRepo, UserRepo, IDGenerator)                               // 注册 FollowService
-	CommentSvc := service.NewCommentService(CommentRepo, UserRepo, PostRepo, IDGenerator)                  // 注册 commentService
-	TagSvc := service.NewTagService(TagRepo, IDGenerator)                                                  // 注册 TagService
-	SessionSvc := service.NewSessionService(SessionRepo, MessageRepo, UserRepo, RabbitMQ, IDGenerator)     // 注册 SessionService
-	WebsocketSvc := service.NewWebsocketService(SessionRepo, MessageRepo, UserRepo, RabbitMQ, IDGenerator) // 注册 WebsocketService
-	SmsSvc := service.NewSmsService(SmsClient, SmsRepo)                                                    // 注册 SmsService
-	LotterySvc := service.NewLotteryService(OrderRepo, GiftRepo, UserRepo, RocketMQ, IDGenerator)          // 注册 LotteryService
+	MetricSvc := service.NewMetricService()                                                                                                                                                                       // 注册 MetricService
+	RateLimitSvc := service.NewRateLimitService(RedisClient, conf.RateLimitInterval, conf.RateLimitRate)                                                                                                          // 注册 RateLimitService
+	AuthSvc := service.NewAuthService(UserRepo, JwtManager, PasswordHasher, IDGenerator, RedisClient)                                                                                                             // 注册 AuthService
+	UserSvc := service.NewUserService(UserRepo, IDGenerator, PasswordHasher)                                                                                                                                      // 注册 userSvc
+	SensitiveSvc := service.NewSensitiveService(SensitiveRepo, UserRepo, IDGenerator)                                                                                                                             // 注册 SensitiveService
+	MentionSvc := service.NewMentionService(MentionRepo, UserRepo, IDGenerator)                                                                                                                                   // 注册 MentionService
+	PostSvc := service.NewPostService(PostRepo, UserRepo, LikeRepo, TagRepo, AttachmentRepo, FollowRepo, ReportRepo, SeriesRepo, PollRepo, AnalyticsRepo, IDGenerator, ContentRenderer, SensitiveSvc, MentionSvc) // 注册 postSvc
+	BookmarkSvc := service.NewBookmarkService(BookmarkRepo, PostRepo, UserRepo, TagRepo, FollowRepo, AnalyticsRepo, IDGenerator, ContentRenderer)                                                                 // 注册 BookmarkService
+	RepostSvc := service.NewRepostService(RepostRepo, PostRepo, UserRepo, TagRepo, FollowRepo, IDGenerator, ContentRenderer)                                                                                      // 注册 RepostService
+	SeriesSvc := service.NewSeriesService(SeriesRepo, PostRepo, UserRepo, FollowRepo, IDGenerator, ContentRenderer)                                                                                               // 注册 SeriesService
+	PollSvc := service.NewPollService(PollRepo, PostRepo, UserRepo, FollowRepo, IDGenerator, SensitiveSvc)                                                                                                        // 注册 PollService
+	AnalyticsSvc := service.NewAnalyticsService(AnalyticsRepo, PostRepo)                                                                                                                                          // 注册 AnalyticsService
+	FeedSvc := service.NewFeedService(PostRepo, UserRepo, TagRepo, ContentRenderer)                                                                                                                               // 注册 FeedService
+	FollowSvc := service.NewFollowService(FollowRepo, UserRepo, AnalyticsRepo, IDGenerator)                                                                                                                       // 注册 FollowService
+	CommentSvc := service.NewCommentService(CommentRepo, CommentLikeRepo, CommentBlockRepo, UserRepo, PostRepo, FollowRepo, ReportRepo, AnalyticsRepo, IDGenerator, SensitiveSvc, MentionSvc)                     // 注册 commentService
+	TagSvc := service.NewTagService(TagRepo, IDGenerator)                                                                                                                                                         // 注册 TagService
+	SessionSvc := service.NewSessionService(SessionRepo, MessageRepo, UserRepo, RabbitMQ, IDGenerator)                                                                                                            // 注册 SessionService
+	WebsocketSvc := service.NewWebsocketService(SessionRepo, MessageRepo, UserRepo, RabbitMQ, IDGenerator, SensitiveSvc)                                                                                          // 注册 WebsocketService
+	SmsSvc := service.NewSmsService(SmsClient, SmsRepo)                                                                                                                                                           // 注册 SmsService
+	LotterySvc := service.NewLotteryService(OrderRepo, GiftRepo, UserRepo, RocketMQ, IDGenerator)                                                                                                                 // 注册 LotteryService
+	AttachmentSvc := service.NewAttachmentService(AttachmentRepo, PostRepo, FollowRepo, ObjectStorage, IDGenerator)                                                                                               // 注册 AttachmentService
+	ReportSvc := service.NewReportService(ReportRepo, PostRepo, CommentRepo, UserRepo, AuthSvc, IDGenerator)                                                                                                      // 注册 ReportService
+	ArchiveSvc := service.NewArchiveService(PostSvc, TagSvc, PostRepo, UserRepo, TagRepo, FollowRepo)                                                                                                             // 注册 ArchiveService
+
+	// 启动时先加载一次敏感词库, 之后由定时任务按版本号热加载; 加载失败时暂不过滤, 等待定时任务重试
+	SensitiveSvc.Reload(context.Background())
+
+	// 初始化 定时任务
+	crontab.NewCrontabBuilder().
+		AddFuncWithSpec(conf.PostCntFlushSpec, func() { PostSvc.FlushCount(context.Background()) }).
+		AddFuncWithSpec(conf.PostCntReconcileSpec, func() { PostSvc.ReconcileCount(context.Background()) }).
+		AddFuncWithSpec(conf.PostHotSpec, func() { PostSvc.RefreshHot(context.Background()) }).
+		AddFuncWithSpec(conf.PostHotAllSpec, func() { PostSvc.RefreshHotAll(context.Background()) }).
+		AddFuncWithSpec(conf.PostRelatedSpec, func() { PostSvc.RefreshRelated(context.Background()) }).
+		AddFuncWithSpec(conf.PostSlugBackfillSpec, func() { PostSvc.BackfillSlugs(context.Background()) }).
+		AddFuncWithSpec(conf.PollCntFlushSpec, func() { PollSvc.FlushCount(context.Background()) }).
+		AddFuncWithSpec(conf.AnalyticsAggregateSpec, func() { AnalyticsSvc.Aggregate(context.Background()) }).
+		AddFuncWithSpec(conf.AttachmentGCSpec, func() { AttachmentSvc.CollectGarbage(context.Background()) }).
+		AddFuncWithSpec(conf.SensitiveReloadSpec, func() { SensitiveSvc.Reload(context.Background()) }).
+		Build()
+
+	// 初始化 GracefulStop, 退出前先将互动计数落库再关闭连接
+	graceful_stop.NewGracefulStopBuilder().
+		NotifySignal(syscall.SIGINT).NotifySignal(syscall.SIGTERM).
+		AddFunc(func() { PostSvc.FlushCount(context.Background()) }).
+		AddFunc(func() { PollSvc.FlushCount(context.Background()) }).
+		AddFunc(func() { AnalyticsSvc.Aggregate(context.Background()) }).
+		AddFunc(infraMySQL.Close).AddFunc(infraRedis.Close).AddFunc(infraRabbitMQ.Close).AddFunc(infraRocketMQ.Close).
+		Build()
 
 	// Handler 层
-	AuthHdl := handler.NewAuthHandler(AuthSvc, SessionSvc)                // 注册 AuthHandler
-	UserHdl := handler.NewUserHandler(UserSvc)                            // 注册 UserHandler
-	PostHdl := handler.NewPostHandler(PostSvc, UserSvc, TagSvc)           // 注册 PostHandler
-	CommentHdl := handler.NewCommentHandler(CommentSvc, UserSvc, PostSvc) // 注册 CommentHandler
-	FollowHdl := handler.NewFollowHandler(FollowSvc, UserSvc)             // 注册 FollowHandler
-	SessionHdl := handler.NewSessionHandler(SessionSvc)                   // 注册 SessionHandler
-	WebsocketHdl := handler.NewWebsocketHandler(WebsocketSvc)             // 注册 WebsocketHandler
-	SmsHdl := handler.NewSmsHandler(SmsSvc)                               // 注册 SmsHandler
-	LotteryHdl := handler.NewLotteryHandler(LotterySvc)                   // 注册 LotteryHandler
+	AuthHdl := handler.NewAuthHandler(AuthSvc, SessionSvc)                                         // 注册 AuthHandler
+	UserHdl := handler.NewUserHandler(UserSvc)                                                     // 注册 UserHandler
+	PostHdl := handler.NewPostHandler(PostSvc, UserSvc, TagSvc, AttachmentSvc, SeriesSvc, PollSvc) // 注册 PostHandler
+	CommentHdl := handler.NewCommentHandler(CommentSvc, UserSvc, PostSvc)                          // 注册 CommentHandler
+	BookmarkHdl := handler.NewBookmarkHandler(BookmarkSvc)                                         // 注册 BookmarkHandler
+	RepostHdl := handler.NewRepostHandler(RepostSvc)                                               // 注册 RepostHandler
+	AnalyticsHdl := handler.NewAnalyticsHandler(AnalyticsSvc)                                      // 注册 AnalyticsHandler
+	ArchiveHdl := handler.NewArchiveHandler(ArchiveSvc)                                            // 注册 ArchiveHandler
+	SeriesHdl := handler.NewSeriesHandler(SeriesSvc)                                               // 注册 SeriesHandler
+	PollHdl := handler.NewPollHandler(PollSvc)                                                     // 注册 PollHandler
+	FeedHdl := handler.NewFeedHandler(FeedSvc, os.Getenv(conf.SiteURL))                            // 注册 FeedHandler
+	FollowHdl := handler.NewFollowHandler(FollowSvc, UserSvc)                                      // 注册 FollowHandler
+	SessionHdl := handler.NewSessionHandler(SessionSvc)                                            // 注册 SessionHandler
+	WebsocketHdl := handler.NewWebsocketHandler(WebsocketSvc)                                      // 注册 WebsocketHandler
+	SmsHdl := handler.NewSmsHandler(SmsSvc)                                                        // 注册 SmsHandler
+	LotteryHdl := handler.NewLotteryHandler(LotterySvc)                                            // 注册 LotteryHandler
+	AttachmentHdl := handler.NewAttachmentHandler(AttachmentSvc)                                   // 注册 AttachmentHandler
+	ReportHdl := handler.NewReportHandler(ReportSvc)                                               // 注册 ReportHandler
+	SensitiveHdl := handler.NewSensitiveHandler(SensitiveSvc)                                      // 注册 SensitiveHandler
 
 	fmt.Println(LotteryHdl)
 
 	// 中间件层
 	AuthRequiredMdl := middleware.AuthRequiredMiddleware(AuthSvc, RedisClient) // AuthRequiredMdl 强制登录
+	AuthOptionalMdl := middleware.AuthOptionalMiddleware(AuthSvc, RedisClient) // AuthOptionalMdl 可选登录
 	MetricMdl := middleware.MetricMiddleware(MetricSvc)                        // MetricMdl 用于 Prometheus 监控中间件
 	RateLimitMdl := middleware.RateLimitMiddleware(RateLimitSvc)               // RateLimitMdl 限流中间件
 	CorsMdl := cors.New(cors.Config{                                           // CorsMdl 跨域中间件
 		AllowOrigins:     []string{conf.FrontendEndPoint}, // 允许域名跨域
 		AllowMethods:     []string{"GET", "POST", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", "If-Match", "If-None-Match"},
 		AllowCredentials: true, // 是否允许携带 cookie 之类的用户认证信息
-		ExposeHeaders:    []string{"Content-Length", "Authorization"},
+		ExposeHeaders:    []string{"Content-Length", "Authorization", "ETag"},
 		MaxAge:           12 * time.Hour,
 	})
 
@@ -148,6 +232,20 @@ func main() {
 		promhttp.Handler().ServeHTTP(ctx.Writer, ctx.Request) // 固定写法
 	})
 
+	// 本地存储的附件由 gin 直接提供静态访问
+	if os.Getenv(conf.S3Endpoint) == "" {
+		engine.Static(conf.AttachmentURLPrefix, conf.AttachmentStorageLocal)
+	}
+
+	// 订阅源, 供 RSS 阅读器和机器人订阅, 不走 /api 前缀
+	feeds := engine.Group("/feeds")
+	{
+		feeds.GET("/posts.atom", FeedHdl.Posts) // GET /feeds/posts.atom		全站最新帖子 Atom
+		feeds.GET("/posts.json", FeedHdl.Posts) // GET /feeds/posts.json		全站最新帖子 JSON Feed
+		feeds.GET("/tags/:file", FeedHdl.Tag)   // GET /feeds/tags/go.atom		标签最新帖子, 扩展名为 .atom 或 .json
+		feeds.GET("/users/:file", FeedHdl.User) // GET /feeds/users/1001.json	用户最新帖子, 扩展名为 .atom 或 .json
+	}
+
 	// 业务接口
 	api := engine.Group("/api")
 	v1 := api.Group("/v1")
@@ -171,16 +269,26 @@ func main() {
 	// 用户模块
 	users := v1.Group("/users")
 	{
-		users.GET("/:id", UserHdl.Profile)                // GET /api/v1/users/:id									获取个人资料
-		users.GET("/:id/posts", PostHdl.ListByPageAndUid) // GET /api/v1/users/:id/posts?pageNo=1&pageSize=10		按页获取用户所发帖子
-		users.GET("/top", UserHdl.Top)                    // GET /api/v1/users/top 									获取推荐关注
+		users.GET("/:id", UserHdl.Profile)                                 // GET /api/v1/users/:id									获取个人资料
+		users.GET("/:id/posts", AuthOptionalMdl, PostHdl.ListByPageAndUid) // GET /api/v1/users/:id/posts?pageNo=1&pageSize=10		按页获取用户所发帖子
+		users.GET("/:id/posts/export", AuthOptionalMdl, ArchiveHdl.Export) // GET /api/v1/users/:id/posts/export		导出用户的帖子为 Markdown 压缩包
+		users.GET("/:id/series", SeriesHdl.ListByUid)                      // GET /api/v1/users/:id/series?pageNo=1&pageSize=10		按页获取用户的系列
+		users.GET("/:id/reposts", AuthOptionalMdl, RepostHdl.ListByUid)    // GET /api/v1/users/:id/reposts?pageNo=1&pageSize=10	按页获取用户转发的帖子
+		users.GET("/top", UserHdl.Top)                                     // GET /api/v1/users/top 									获取推荐关注
 		// 个人模块
 		me := users.Group("/me")
 		me.Use(AuthRequiredMdl)
-		me.POST("", UserHdl.ModifyProfile)            // POST /api/v1/users/me									修改个人资料
-		me.POST("/password", UserHdl.ModifyPass)      // POST /api/v1/users/me/password 							修改密码
-		me.GET("/followers", FollowHdl.ListFollowers) // GET /api/v1/users/me/followers?pageNo=1&pageSize=10		按页获取用户粉丝
-		me.GET("/followees", FollowHdl.ListFollowees) // GET /api/v1/users/me/followees?pageNo=1&pageSize=10 	按页获取用户关注的人
+		me.POST("", UserHdl.ModifyProfile)                          // POST /api/v1/users/me									修改个人资料
+		me.POST("/password", UserHdl.ModifyPass)                    // POST /api/v1/users/me/password 							修改密码
+		me.GET("/followers", FollowHdl.ListFollowers)               // GET /api/v1/users/me/followers?pageNo=1&pageSize=10		按页获取用户粉丝
+		me.GET("/followees", FollowHdl.ListFollowees)               // GET /api/v1/users/me/followees?pageNo=1&pageSize=10 	按页获取用户关注的人
+		me.GET("/bookmarks", BookmarkHdl.ListBookmarks)             // GET /api/v1/users/me/bookmarks?collection_id=1&pageNo=1&pageSize=10	按页获取收藏的帖子
+		me.GET("/collections", BookmarkHdl.ListCollections)         // GET /api/v1/users/me/collections?pageNo=1&pageSize=10	按页获取收藏夹
+		me.POST("/collections", BookmarkHdl.CreateCollection)       // POST /api/v1/users/me/collections						新建收藏夹
+		me.POST("/collections/:id", BookmarkHdl.RenameCollection)   // POST /api/v1/users/me/collections/:id					修改收藏夹名称
+		me.DELETE("/collections/:id", BookmarkHdl.DeleteCollection) // DELETE /api/v1/users/me/collections/:id				删除收藏夹
+		me.GET("/comment-blocks", CommentHdl.ListBlocked)           // GET /api/v1/users/me/comment-blocks?pageNo=1&pageSize=10	按页获取被禁止评论的用户
+		me.GET("/analytics", AnalyticsHdl.Dashboard)                // GET /api/v1/users/me/analytics?from=2024-01-01&to=2024-01-07&post=2001	获取帖子数据统计
 
 		// 关注模块
 		follow := users.Group("/:id/follow")
@@ -191,6 +299,14 @@ func main() {
 			follow.GET("", FollowHdl.IfFollow)    // GET /api/v1/users/:id/follow 		是否关注
 		}
 
+		// 评论黑名单模块
+		commentBlock := users.Group("/:id/comment-block")
+		commentBlock.Use(AuthRequiredMdl)
+		{
+			commentBlock.POST("", CommentHdl.Block)     // POST /api/v1/users/:id/comment-block 		禁止该用户评论我的帖子
+			commentBlock.DELETE("", CommentHdl.UnBlock) // DELETE /api/v1/users/:id/comment-block 	取消禁止
+		}
+
 		// 私信模块
 		chat := users.Group("/:id/sessions")
 		chat.Use(AuthRequiredMdl)
@@ -203,28 +319,97 @@ func main() {
 	// 帖子模块
 	posts := v1.Group("/posts")
 	{
-		posts.GET("", PostHdl.List)                             // POST /api/v1/posts?pageNo=1&pageSize=10				按页获取帖子列表
-		posts.GET("/top", PostHdl.Top)                          // GET /api/v1/posts/top								获取热门帖子榜单
-		posts.GET("/tags", PostHdl.ListByTagAndPage)            // POST /api/v1/posts/tags?pageNo=1&pageSize=10&tag=go 根据标签按页获取帖子列表
-		posts.GET("/:id", PostHdl.Detail)                       // GET /api/v1/posts/:id								获取帖子详情
-		posts.GET("/:id/comments", CommentHdl.ListByPage)       // GET /api/v1/posts/:id/comments?pageNo=1&pageSize=10	按页获取帖子评论
-		posts.GET("/:id/comments/:cid", CommentHdl.ListReplies) // GET /api/v1/posts/:pid/comments/:cid?pageNo=1&pageSize=10	按页获取主评论回复
+		posts.GET("", PostHdl.List)                                              // POST /api/v1/posts?pageNo=1&pageSize=10				按页获取帖子列表
+		posts.GET("/top", PostHdl.Top)                                           // GET /api/v1/posts/top?window=7d&tag=go				获取热门帖子榜单
+		posts.GET("/featured", PostHdl.ListFeatured)                             // GET /api/v1/posts/featured?pageNo=1&pageSize=10		按页获取精华帖子
+		posts.GET("/tags", PostHdl.ListByTagAndPage)                             // POST /api/v1/posts/tags?pageNo=1&pageSize=10&tag=go 根据标签按页获取帖子列表
+		posts.GET("/by-slug/:slug", AuthOptionalMdl, PostHdl.BySlug)             // GET /api/v1/posts/by-slug/:slug						根据 slug 获取帖子详情, 旧 slug 301 到当前 slug
+		posts.GET("/:id", AuthOptionalMdl, PostHdl.Detail)                       // GET /api/v1/posts/:id								获取帖子详情
+		posts.GET("/:id/related", AuthOptionalMdl, PostHdl.Related)              // GET /api/v1/posts/:id/related							获取相关推荐帖子
+		posts.GET("/:id/comments", AuthOptionalMdl, CommentHdl.ListByPage)       // GET /api/v1/posts/:id/comments?pageNo=1&pageSize=10&sort=hot	按页获取帖子评论
+		posts.GET("/:id/comments/tree", AuthOptionalMdl, CommentHdl.Tree)        // GET /api/v1/posts/:id/comments/tree?pageNo=1&pageSize=10&repliesPerRoot=3	获取评论树, 一级评论附带最早的几条回复
+		posts.GET("/:id/comments/:cid", AuthOptionalMdl, CommentHdl.ListReplies) // GET /api/v1/posts/:pid/comments/:cid?pageNo=1&pageSize=10&sort=oldest	按页获取主评论回复
+		posts.GET("/:id/attachments", AuthOptionalMdl, AttachmentHdl.ListByPost) // GET /api/v1/posts/:id/attachments						获取帖子附件
+		posts.GET("/:id/poll", AuthOptionalMdl, PollHdl.Result)                  // GET /api/v1/posts/:id/poll							获取投票结果
+		posts.GET("/:id/poll/voters", AuthOptionalMdl, PollHdl.ListVoters)       // GET /api/v1/posts/:id/poll/voters?option_id=1&pageNo=1&pageSize=10	按页获取选项的投票人
 
 		//todo
 		authedPosts := posts.Group("")
 		authedPosts.Use(AuthRequiredMdl)
-		authedPosts.POST("", PostHdl.Create)       // POST /api/v1/posts 		创建帖子
-		authedPosts.POST("/:id", PostHdl.Update)   // POST /api/v1/posts/:id 	更新帖子
-		authedPosts.DELETE("/:id", PostHdl.Delete) // DELETE /api/v1/posts/:id 	删除帖子
-
-		authedPosts.POST("/:id/comments", CommentHdl.Create)        // POST /api/v1/posts/:id/comments 创建评论
-		authedPosts.DELETE("/:id/comments/:cid", CommentHdl.Delete) // DELETE /api/v1/posts/:id/comments/:cid 删除评论
-		authedPosts.GET("/:id/likes", PostHdl.IfLike)               // GET /api/v1/posts/:id/likes	查询是否点赞了帖子
-		authedPosts.POST("/:id/likes", PostHdl.Like)                // POST /api/v1/posts/:id/likes	点赞帖子
-		authedPosts.DELETE("/:id/likes", PostHdl.Unlike)            // DELETE /api/v1/posts/:id/likes 取消点赞帖子
+		authedPosts.POST("", PostHdl.Create)           // POST /api/v1/posts 		创建帖子
+		authedPosts.POST("/import", ArchiveHdl.Import) // POST /api/v1/posts/import 	从 Markdown 压缩包导入帖子
+		authedPosts.POST("/:id", PostHdl.Update)       // POST /api/v1/posts/:id 	更新帖子
+		authedPosts.DELETE("/:id", PostHdl.Delete)     // DELETE /api/v1/posts/:id 	删除帖子
+
+		authedPosts.POST("/:id/comments", CommentHdl.Create)                  // POST /api/v1/posts/:id/comments 创建评论
+		authedPosts.POST("/:id/comments/:cid", CommentHdl.Edit)               // POST /api/v1/posts/:id/comments/:cid 编辑评论
+		authedPosts.DELETE("/:id/comments/:cid", CommentHdl.Delete)           // DELETE /api/v1/posts/:id/comments/:cid 删除评论
+		authedPosts.GET("/:id/comments/:cid/revisions", CommentHdl.Revisions) // GET /api/v1/posts/:id/comments/:cid/revisions	获取评论历史版本
+		authedPosts.POST("/:id/comments/:cid/likes", CommentHdl.Like)         // POST /api/v1/posts/:id/comments/:cid/likes	点赞评论
+		authedPosts.POST("/:id/comments/:cid/pin", CommentHdl.Pin)            // POST /api/v1/posts/:id/comments/:cid/pin	置顶评论
+		authedPosts.DELETE("/:id/comments/:cid/pin", CommentHdl.Unpin)        // DELETE /api/v1/posts/:id/comments/:cid/pin 取消置顶评论
+		authedPosts.POST("/:id/comment-policy", CommentHdl.SetPolicy)         // POST /api/v1/posts/:id/comment-policy	设置谁可以评论
+		authedPosts.DELETE("/:id/comments/:cid/likes", CommentHdl.UnLike)     // DELETE /api/v1/posts/:id/comments/:cid/likes 取消点赞评论
+		authedPosts.GET("/:id/likes", PostHdl.IfLike)                         // GET /api/v1/posts/:id/likes	查询是否点赞了帖子
+		authedPosts.POST("/:id/likes", PostHdl.Like)                          // POST /api/v1/posts/:id/likes	点赞帖子
+		authedPosts.DELETE("/:id/likes", PostHdl.Unlike)                      // DELETE /api/v1/posts/:id/likes 取消点赞帖子
+		authedPosts.GET("/:id/bookmarks", BookmarkHdl.IfBookmark)             // GET /api/v1/posts/:id/bookmarks	查询是否收藏了帖子
+		authedPosts.POST("/:id/bookmarks", BookmarkHdl.Bookmark)              // POST /api/v1/posts/:id/bookmarks	收藏帖子
+		authedPosts.DELETE("/:id/bookmarks", BookmarkHdl.UnBookmark)          // DELETE /api/v1/posts/:id/bookmarks 取消收藏帖子
+		authedPosts.POST("/:id/bookmarks/move", BookmarkHdl.MoveBookmark)     // POST /api/v1/posts/:id/bookmarks/move 将收藏移动到其他收藏夹
+		authedPosts.GET("/:id/reposts", RepostHdl.IfRepost)                   // GET /api/v1/posts/:id/reposts	查询是否转发了帖子
+		authedPosts.POST("/:id/reposts", RepostHdl.Repost)                    // POST /api/v1/posts/:id/reposts	转发帖子
+		authedPosts.DELETE("/:id/reposts", RepostHdl.UnRepost)                // DELETE /api/v1/posts/:id/reposts 取消转发帖子
+		authedPosts.POST("/:id/poll/votes", PollHdl.Vote)                     // POST /api/v1/posts/:id/poll/votes	投票
+	}
+
+	// 系列模块
+	series := v1.Group("/series")
+	{
+		series.GET("/:id", AuthOptionalMdl, SeriesHdl.Detail) // GET /api/v1/series/:id	获取系列及其中的帖子
+
+		authedSeries := series.Group("")
+		authedSeries.Use(AuthRequiredMdl)
+		authedSeries.POST("", SeriesHdl.Create)                      // POST /api/v1/series					新建系列
+		authedSeries.POST("/:id", SeriesHdl.Update)                  // POST /api/v1/series/:id				修改系列
+		authedSeries.DELETE("/:id", SeriesHdl.Delete)                // DELETE /api/v1/series/:id				删除系列
+		authedSeries.POST("/:id/posts", SeriesHdl.AddPost)           // POST /api/v1/series/:id/posts			将帖子加入系列
+		authedSeries.DELETE("/:id/posts/:pid", SeriesHdl.RemovePost) // DELETE /api/v1/series/:id/posts/:pid	将帖子移出系列
+		authedSeries.POST("/:id/order", SeriesHdl.Reorder)           // POST /api/v1/series/:id/order			调整系列中帖子的顺序
+	}
+
+	// 附件模块
+	attachments := v1.Group("/attachments")
+	attachments.Use(AuthRequiredMdl)
+	{
+		attachments.POST("", AttachmentHdl.Upload)       // POST /api/v1/attachments 		上传附件
+		attachments.DELETE("/:id", AttachmentHdl.Delete) // DELETE /api/v1/attachments/:id 删除附件
+	}
+
+	reports := v1.Group("/reports")
+	reports.Use(AuthRequiredMdl)
+	{
+		reports.POST("", ReportHdl.Create) // POST /api/v1/reports 举报帖子、评论或用户
+	}
+
+	// 管理员接口, 权限在 Service 层校验
+	admin := v1.Group("/admin")
+	admin.Use(AuthRequiredMdl)
+	{
+		admin.GET("/reports", ReportHdl.ListPending)                         // GET /api/v1/admin/reports?target_type=post&pageNo=1&pageSize=10	按页获取审核队列
+		admin.POST("/reports/:type/:id", ReportHdl.Resolve)                  // POST /api/v1/admin/reports/:type/:id							处理举报
+		admin.GET("/moderation-logs", ReportHdl.ListLogs)                    // GET /api/v1/admin/moderation-logs?pageNo=1&pageSize=10		按页获取处理记录
+		admin.GET("/sensitive-words", SensitiveHdl.ListWords)                // GET /api/v1/admin/sensitive-words?category=fraud&pageNo=1&pageSize=10	按页获取敏感词
+		admin.POST("/sensitive-words", SensitiveHdl.AddWords)                // POST /api/v1/admin/sensitive-words								批量添加敏感词
+		admin.DELETE("/sensitive-words/:id", SensitiveHdl.DeleteWord)        // DELETE /api/v1/admin/sensitive-words/:id						删除敏感词
+		admin.GET("/sensitive-categories", SensitiveHdl.ListCategories)      // GET /api/v1/admin/sensitive-categories							获取敏感词分类
+		admin.POST("/sensitive-categories/:name", SensitiveHdl.SaveCategory) // POST /api/v1/admin/sensitive-categories/:name					设置分类的处理动作
+		admin.POST("/posts/:id/pin", PostHdl.Pin)                            // POST /api/v1/admin/posts/:id/pin								置顶帖子
+		admin.DELETE("/posts/:id/pin", PostHdl.Unpin)                        // DELETE /api/v1/admin/posts/:id/pin?tag=go						取消置顶
+		admin.POST("/posts/:id/feature", PostHdl.Feature)                    // POST /api/v1/admin/posts/:id/feature							帖子加精
+		admin.DELETE("/posts/:id/feature", PostHdl.Unfeature)                // DELETE /api/v1/admin/posts/:id/feature							取消加精
 	}
 
-	// 私信模块
 	sessions := v1.Group("/sessions")
 	sessions.Use(AuthRequiredMdl)
 	{
@@ -67,7 +67,7 @@ func AuthRequiredMiddleware(authSvc service.AuthService, redisClient redis.Unive
 
 		// 黑名单检查
 		ok, err := redisClient.Exists(ctx, conf.ClearTokenPrefix+ssid).Result()
-		if err != nil || ok > 1 {
+		if err != nil || ok > 0 {
 			unauthorized(ctx)
 			return
 		}
@@ -75,6 +75,12 @@ func AuthRequiredMiddleware(authSvc service.AuthService, redisClient redis.Unive
 		// redis 中清除旧 token
 		_ = authSvc.ClearTokens(ctx, accessToken, refreshToken)
 
+		// 被封禁的用户不再续签
+		if err := authSvc.CheckStatus(ctx, id); err != nil {
+			unauthorized(ctx)
+			return
+		}
+
 		// 重新签发 新token
 		newAccessToken, newRefreshToken, err := authSvc.IssueTokens(ctx, id, role, ctx.Request.UserAgent())
 		if err != nil {
@@ -92,6 +98,33 @@ func AuthRequiredMiddleware(authSvc service.AuthService, redisClient redis.Unive
 	}
 }
 
+// AuthOptionalMiddleware 可选登录, AccessToken 有效时把 uid 放入上下文, 否则按游客继续处理
+func AuthOptionalMiddleware(authSvc service.AuthService, redisClient redis.UniversalClient) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		accessToken := handler.ExtractToken(ctx) // 获取 AccessToken
+		if accessToken == "" {
+			ctx.Next()
+			return
+		}
+
+		claim, err := authSvc.VerifyAccessToken(accessToken)
+		if err != nil || claim == nil || claim.SSid == "" {
+			ctx.Next()
+			return
+		}
+
+		// 黑名单检查
+		ok, err := redisClient.Exists(ctx, conf.ClearTokenPrefix+claim.SSid).Result()
+		if err != nil || ok > 0 {
+			ctx.Next()
+			return
+		}
+
+		ctx.Set(handler.UserIDInContext, claim.Uid) // 把用户 ID 放入上下文, 以便后续直接使用
+		ctx.Next()
+	}
+}
+
 func setTokens(ctx *gin.Context, accessToken, refreshToken string) {
 	// 将 AccessToken 放进 Header, RefreshToken 放进 Cookie
 	ctx.Header("Authorization", "Bearer "+accessToken)
@@ -0,0 +1,123 @@
+package model
+
+import "time"
+
+// AnalyticsGranularity 统计粒度
+type AnalyticsGranularity int
+
+const (
+	AnalyticsHourly AnalyticsGranularity = iota + 1 // 按小时
+	AnalyticsDaily                                  // 按天
+)
+
+func (g AnalyticsGranularity) String() string {
+	if g == AnalyticsHourly {
+		return "hour"
+	}
+	return "day"
+}
+
+// Truncate 将时间截断到所在时间桶的起点, 按天统计时以服务器本地时区的零点为界
+func (g AnalyticsGranularity) Truncate(t time.Time) time.Time {
+	if g == AnalyticsHourly {
+		return t.Truncate(time.Hour)
+	}
+	y, m, d := t.In(time.Local).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.Local)
+}
+
+// Next 返回下一个时间桶的起点
+func (g AnalyticsGranularity) Next(bucket time.Time) time.Time {
+	if g == AnalyticsHourly {
+		return bucket.Add(time.Hour)
+	}
+	return bucket.AddDate(0, 0, 1)
+}
+
+// AnalyticsMetric 计入帖子统计的事件
+type AnalyticsMetric string
+
+const (
+	AnalyticsView     AnalyticsMetric = "view"     // 浏览
+	AnalyticsLike     AnalyticsMetric = "like"     // 点赞
+	AnalyticsComment  AnalyticsMetric = "comment"  // 评论
+	AnalyticsBookmark AnalyticsMetric = "bookmark" // 收藏
+)
+
+// AnalyticsDirectReferrer 没有来源页面时的来源名
+const AnalyticsDirectReferrer = "direct"
+
+// PostStat 帖子在一个时间桶内发生的事件数, 由后台任务从缓冲区汇总
+type PostStat struct {
+	PostID        int64                `gorm:"column:post_id;primaryKey"`
+	Granularity   AnalyticsGranularity `gorm:"column:granularity;primaryKey"`
+	Bucket        time.Time            `gorm:"column:bucket;primaryKey"` // 时间桶起点
+	AuthorID      int64                `gorm:"column:author_id"`
+	ViewCount     int                  `gorm:"column:view_count"`
+	LikeCount     int                  `gorm:"column:like_count"`
+	CommentCount  int                  `gorm:"column:comment_count"`
+	BookmarkCount int                  `gorm:"column:bookmark_count"`
+}
+
+func (s PostStat) TableName() string {
+	return "post_stats"
+}
+
+// Add 累加事件数, 未知事件忽略
+func (s *PostStat) Add(metric AnalyticsMetric, delta int) {
+	switch metric {
+	case AnalyticsView:
+		s.ViewCount += delta
+	case AnalyticsLike:
+		s.LikeCount += delta
+	case AnalyticsComment:
+		s.CommentCount += delta
+	case AnalyticsBookmark:
+		s.BookmarkCount += delta
+	}
+}
+
+// Merge 累加另一个时间桶的事件数
+func (s *PostStat) Merge(other *PostStat) {
+	s.ViewCount += other.ViewCount
+	s.LikeCount += other.LikeCount
+	s.CommentCount += other.CommentCount
+	s.BookmarkCount += other.BookmarkCount
+}
+
+// PostReferrerStat 帖子每天来自各来源站点的浏览数
+type PostReferrerStat struct {
+	PostID    int64     `gorm:"column:post_id;primaryKey"`
+	Bucket    time.Time `gorm:"column:bucket;primaryKey"`   // 当天零点
+	Referrer  string    `gorm:"column:referrer;primaryKey"` // 来源站点域名, 没有来源时为 direct
+	AuthorID  int64     `gorm:"column:author_id"`
+	ViewCount int       `gorm:"column:view_count"`
+}
+
+func (s PostReferrerStat) TableName() string {
+	return "post_referrer_stats"
+}
+
+// FollowerStat 用户每天新增和流失的粉丝数
+type FollowerStat struct {
+	UserID int64     `gorm:"column:user_id;primaryKey"`
+	Bucket time.Time `gorm:"column:bucket;primaryKey"` // 当天零点
+	Gained int       `gorm:"column:gained"`
+	Lost   int       `gorm:"column:lost"`
+}
+
+func (s FollowerStat) TableName() string {
+	return "follower_stats"
+}
+
+// AnalyticsBatch 从缓冲区取出的一批待汇总事件; 帖子事件按小时分桶, 来源和粉丝按天分桶
+type AnalyticsBatch struct {
+	Posts     []*PostStat
+	Referrers []*PostReferrerStat
+	Followers []*FollowerStat
+}
+
+// Empty 判断批次是否没有任何事件
+func (b *AnalyticsBatch) Empty() bool {
+	return b == nil || len(b.Posts)+len(b.Referrers)+len(b.Followers) == 0
+}
@@ -0,0 +1,45 @@
+package model
+
+import "time"
+
+// Attachment 定义数据库模型, PostID 为 0 表示尚未关联帖子
+type Attachment struct {
+	ID         int64      `gorm:"primaryKey"`         // 附件 ID
+	UserID     int64      `gorm:"column:user_id"`     // 上传者 ID
+	PostID     int64      `gorm:"column:post_id"`     // 关联的帖子 ID
+	FileName   string     `gorm:"column:file_name"`   // 原始文件名
+	MimeType   string     `gorm:"column:mime_type"`   // 嗅探得到的 MIME 类型
+	Size       int64      `gorm:"column:size"`        // 原文件大小
+	TotalSize  int64      `gorm:"column:total_size"`  // 原文件与所有变体的大小之和, 计入配额
+	Width      int        `gorm:"column:width"`       // 图片宽度, 非图片为 0
+	Height     int        `gorm:"column:height"`      // 图片高度, 非图片为 0
+	StorageKey string     `gorm:"column:storage_key"` // 原文件存储 Key
+	MediumKey  string     `gorm:"column:medium_key"`  // 展示图存储 Key, 非图片为空
+	ThumbKey   string     `gorm:"column:thumb_key"`   // 缩略图存储 Key, 非图片为空
+	Status     int        `gorm:"column:status"`      // 状态 1 正常, 2 待回收
+	CreatedAt  time.Time  `gorm:"column:created_at"`  // 创建时间
+	UpdatedAt  time.Time  `gorm:"column:updated_at"`  // 更新时间
+	DeletedAt  *time.Time `gorm:"column:deleted_at"`  // 逻辑删除时间
+}
+
+// TableName 指定表名
+func (a Attachment) TableName() string {
+	return "attachments"
+}
+
+const (
+	AttachmentStatusNormal    = 1
+	AttachmentStatusDiscarded = 2
+)
+
+// Keys 返回附件在存储中的所有 Key
+func (a *Attachment) Keys() []string {
+	keys := []string{a.StorageKey}
+	if a.MediumKey != "" {
+		keys = append(keys, a.MediumKey)
+	}
+	if a.ThumbKey != "" {
+		keys = append(keys, a.ThumbKey)
+	}
+	return keys
+}
@@ -0,0 +1,34 @@
+package model
+
+import "time"
+
+// Bookmark 定义数据库模型, 同一用户对同一帖子只有一条收藏记录
+type Bookmark struct {
+	ID           int64      `gorm:"primaryKey"`           // 记录 ID
+	UserID       int64      `gorm:"column:user_id"`       // 收藏者 ID
+	PostID       int64      `gorm:"column:post_id"`       // 帖子 ID
+	CollectionID int64      `gorm:"column:collection_id"` // 所属收藏夹 ID, 0 表示默认收藏夹
+	CreatedAt    time.Time  `gorm:"column:created_at"`    // 创建时间
+	UpdatedAt    time.Time  `gorm:"column:updated_at"`    // 更新时间
+	DeletedAt    *time.Time `gorm:"column:deleted_at"`    // 逻辑删除时间
+}
+
+// TableName 指定表名
+func (b Bookmark) TableName() string {
+	return "bookmarks"
+}
+
+// BookmarkCollection 定义数据库模型, 用户自建的收藏夹
+type BookmarkCollection struct {
+	ID        int64      `gorm:"primaryKey"`        // 收藏夹 ID
+	UserID    int64      `gorm:"column:user_id"`    // 创建者 ID
+	Name      string     `gorm:"column:name"`       // 收藏夹名称
+	CreatedAt time.Time  `gorm:"column:created_at"` // 创建时间
+	UpdatedAt time.Time  `gorm:"column:updated_at"` // 更新时间
+	DeletedAt *time.Time `gorm:"column:deleted_at"` // 逻辑删除时间
+}
+
+// TableName 指定表名
+func (c BookmarkCollection) TableName() string {
+	return "bookmark_collections"
+}
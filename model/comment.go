@@ -1,6 +1,10 @@
 package model
 
-import "time"
+import (
+	"time"
+
+	"github.com/yzletter/go-postery/errno"
+)
 
 type Comment struct {
 	ID        int64      `gorm:"primaryKey"`
@@ -9,6 +13,10 @@ type Comment struct {
 	ReplyID   int64      `gorm:"column:reply_id"`
 	UserID    int64      `gorm:"column:user_id"`
 	Content   string     `gorm:"column:content"`
+	Status    int        `gorm:"column:status"`     // 状态 1 正常, 2 待审核
+	LikeCount int        `gorm:"column:like_count"` // 点赞数
+	EditCount int        `gorm:"column:edit_count"` // 作者编辑次数, 同时作为编辑时的版本号
+	EditedAt  *time.Time `gorm:"column:edited_at"`  // 作者最近编辑时间, 为空表示未编辑过
 	CreatedAt time.Time  `gorm:"column:created_at"` // 创建时间
 	UpdatedAt time.Time  `gorm:"column:updated_at"` // 更新时间
 	DeletedAt *time.Time `gorm:"column:deleted_at"` // 逻辑删除时间
@@ -18,3 +26,79 @@ type Comment struct {
 func (c Comment) TableName() string {
 	return "comments"
 }
+
+// 评论状态
+const (
+	CommentStatusNormal    = 1
+	CommentStatusReviewing = 2 // 命中敏感词, 审核通过前不展示也不计入评论数
+)
+
+// CommentSort 评论排序方式
+type CommentSort string
+
+const (
+	CommentSortNewest CommentSort = "newest"
+	CommentSortOldest CommentSort = "oldest"
+	CommentSortHot    CommentSort = "hot" // 按点赞数和发布时间综合排序, 越新、赞越多越靠前
+)
+
+// CommentSorts 所有评论排序方式
+var CommentSorts = []CommentSort{CommentSortNewest, CommentSortOldest, CommentSortHot}
+
+// ParseCommentSort 解析评论排序方式, 为空时返回 def
+func ParseCommentSort(s string, def CommentSort) (CommentSort, error) {
+	if s == "" {
+		return def, nil
+	}
+	for _, sort := range CommentSorts {
+		if string(sort) == s {
+			return sort, nil
+		}
+	}
+	return "", errno.ErrInvalidParam
+}
+
+// CommentLike 定义数据库模型, 同一用户对同一评论只有一条点赞记录
+type CommentLike struct {
+	ID        int64      `gorm:"primaryKey"`        // 记录 ID
+	UserID    int64      `gorm:"column:user_id"`    // 点赞者 ID
+	CommentID int64      `gorm:"column:comment_id"` // 被点赞的评论 ID
+	CreatedAt time.Time  `gorm:"column:created_at"` // 点赞时间
+	UpdatedAt time.Time  `gorm:"column:updated_at"` // 更新时间
+	DeletedAt *time.Time `gorm:"column:deleted_at"` // 逻辑删除时间
+}
+
+// TableName 指定表名
+func (l CommentLike) TableName() string {
+	return "comment_likes"
+}
+
+// CommentRevision 定义数据库模型, 评论每次被编辑前的内容, 仅管理员和版主可查看
+type CommentRevision struct {
+	ID        int64     `gorm:"primaryKey"`        // 记录 ID
+	CommentID int64     `gorm:"column:comment_id"` // 评论 ID
+	Version   int       `gorm:"column:version"`    // 该内容对应的编辑次数, 0 为最初发表的内容
+	Content   string    `gorm:"column:content"`    // 被替换前的内容
+	Status    int       `gorm:"column:status"`     // 被替换前的评论状态
+	CreatedAt time.Time `gorm:"column:created_at"` // 该内容的发表时间
+}
+
+// TableName 指定表名
+func (r CommentRevision) TableName() string {
+	return "comment_revisions"
+}
+
+// CommentBlock 定义数据库模型, 被作者拉黑的用户不能评论作者的任何帖子
+type CommentBlock struct {
+	ID        int64      `gorm:"primaryKey"`        // 记录 ID
+	AuthorID  int64      `gorm:"column:author_id"`  // 作者 ID
+	UserID    int64      `gorm:"column:user_id"`    // 被禁止评论的用户 ID
+	CreatedAt time.Time  `gorm:"column:created_at"` // 拉黑时间
+	UpdatedAt time.Time  `gorm:"column:updated_at"` // 更新时间
+	DeletedAt *time.Time `gorm:"column:deleted_at"` // 逻辑删除时间
+}
+
+// TableName 指定表名
+func (b CommentBlock) TableName() string {
+	return "comment_blocks"
+}
@@ -0,0 +1,38 @@
+package model
+
+import "time"
+
+// Mention 定义数据库模型, 记录帖子或评论中 @ 到的用户, 同一对象中同一用户只记录一次
+type Mention struct {
+	ID         int64         `gorm:"primaryKey"`         // 记录 ID
+	TargetType MentionTarget `gorm:"column:target_type"` // 提及所在的对象类型
+	TargetID   int64         `gorm:"column:target_id"`   // 提及所在的对象 ID
+	PostID     int64         `gorm:"column:post_id"`     // 所属帖子 ID, 对象为帖子时与 TargetID 相同
+	FromUID    int64         `gorm:"column:from_uid"`    // 发出提及的用户 ID
+	UserID     int64         `gorm:"column:user_id"`     // 被提及的用户 ID
+	CreatedAt  time.Time     `gorm:"column:created_at"`  // 创建时间
+}
+
+// TableName 指定表名
+func (m Mention) TableName() string {
+	return "mentions"
+}
+
+// MentionTarget 提及所在的对象类型
+type MentionTarget int
+
+const (
+	MentionTargetPost MentionTarget = iota + 1
+	MentionTargetComment
+)
+
+func (t MentionTarget) String() string {
+	switch t {
+	case MentionTargetPost:
+		return "post"
+	case MentionTargetComment:
+		return "comment"
+	default:
+		return ""
+	}
+}
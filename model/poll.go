@@ -0,0 +1,59 @@
+package model
+
+import "time"
+
+// Poll 定义数据库模型, 每篇帖子最多附带一个投票
+type Poll struct {
+	ID         int64      `gorm:"primaryKey"`         // 投票 ID
+	PostID     int64      `gorm:"column:post_id"`     // 帖子 ID
+	Multiple   bool       `gorm:"column:multiple"`    // 是否多选
+	Anonymous  bool       `gorm:"column:anonymous"`   // 是否匿名, 匿名时不公开投票人
+	Deadline   *time.Time `gorm:"column:deadline"`    // 截止时间, 为空表示不截止
+	VoterCount int        `gorm:"column:voter_count"` // 投票人数
+	CreatedAt  time.Time  `gorm:"column:created_at"`  // 创建时间
+	UpdatedAt  time.Time  `gorm:"column:updated_at"`  // 更新时间
+}
+
+// TableName 指定表名
+func (p Poll) TableName() string {
+	return "polls"
+}
+
+// Closed 判断投票在 now 时是否已截止
+func (p *Poll) Closed(now time.Time) bool {
+	return p.Deadline != nil && !now.Before(*p.Deadline)
+}
+
+// PollOption 定义数据库模型
+type PollOption struct {
+	ID        int64  `gorm:"primaryKey"`        // 选项 ID
+	PollID    int64  `gorm:"column:poll_id"`    // 投票 ID
+	Position  int    `gorm:"column:position"`   // 选项顺序, 从 1 开始
+	Text      string `gorm:"column:text"`       // 选项内容
+	VoteCount int    `gorm:"column:vote_count"` // 得票数
+}
+
+// TableName 指定表名
+func (o PollOption) TableName() string {
+	return "poll_options"
+}
+
+// PollVote 定义数据库模型, 同一用户对同一选项只有一条记录, 多选时一人多条
+type PollVote struct {
+	ID        int64     `gorm:"primaryKey"`        // 记录 ID
+	PollID    int64     `gorm:"column:poll_id"`    // 投票 ID
+	OptionID  int64     `gorm:"column:option_id"`  // 选项 ID
+	UserID    int64     `gorm:"column:user_id"`    // 投票人 ID
+	CreatedAt time.Time `gorm:"column:created_at"` // 投票时间
+}
+
+// TableName 指定表名
+func (v PollVote) TableName() string {
+	return "poll_votes"
+}
+
+// PollCnt 投票尚未落库的计数增量
+type PollCnt struct {
+	Voters  int           // 投票人数增量
+	Options map[int64]int // 选项 ID -> 得票数增量
+}
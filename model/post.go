@@ -1,6 +1,7 @@
 package model
 
 import (
+	"strconv"
 	"time"
 
 	"github.com/yzletter/go-postery/errno"
@@ -8,17 +9,30 @@ import (
 
 // Post 定义数据库模型
 type Post struct {
-	ID           int64      `gorm:"primaryKey"`           // 帖子 ID
-	UserID       int64      `gorm:"column:user_id"`       // 作者 ID
-	ViewCount    int        `gorm:"column:view_count"`    // 浏览量
-	LikeCount    int        `gorm:"column:like_count"`    // 点赞数
-	CommentCount int        `gorm:"column:comment_count"` // 评论数
-	Status       int        `gorm:"column:status"`        // 状态 1 正常, 2 封禁
-	Title        string     `gorm:"column:title"`         // 标题
-	Content      string     `gorm:"column:content"`       // 正文
-	CreatedAt    time.Time  `gorm:"column:created_at"`    // 创建时间
-	UpdatedAt    time.Time  `gorm:"column:updated_at"`    // 更新时间
-	DeletedAt    *time.Time `gorm:"column:deleted_at"`    // 逻辑删除时间
+	ID              int64          `gorm:"primaryKey"`               // 帖子 ID
+	UserID          int64          `gorm:"column:user_id"`           // 作者 ID
+	ViewCount       int            `gorm:"column:view_count"`        // 浏览量
+	UniqueViewCount int            `gorm:"column:unique_view_count"` // 去重浏览量
+	LikeCount       int            `gorm:"column:like_count"`        // 点赞数
+	CommentCount    int            `gorm:"column:comment_count"`     // 评论数
+	BookmarkCount   int            `gorm:"column:bookmark_count"`    // 收藏数
+	ShareCount      int            `gorm:"column:share_count"`       // 转发数, 包括直接转发和引用转发
+	Status          int            `gorm:"column:status"`            // 状态 1 正常, 2 封禁, 3 待审核
+	Visibility      PostVisibility `gorm:"column:visibility"`        // 可见范围
+	QuoteID         int64          `gorm:"column:quote_id"`          // 引用的帖子 ID, 0 表示不是引用转发
+	CommentPolicy   CommentPolicy  `gorm:"column:comment_policy"`    // 谁可以评论
+	PinnedCommentID int64          `gorm:"column:pinned_comment_id"` // 作者置顶的评论 ID, 0 表示没有置顶
+	Title           string         `gorm:"column:title"`             // 标题
+	Slug            string         `gorm:"column:slug"`              // 由标题生成的当前 slug, 为空表示尚未生成
+	Content         string         `gorm:"column:content"`           // 正文 Markdown 源文
+	ContentHTML     string         `gorm:"column:content_html"`      // 渲染并清洗后的正文 HTML
+	Excerpt         string         `gorm:"column:excerpt"`           // 纯文本摘要
+	FeaturedAt      *time.Time     `gorm:"column:featured_at"`       // 加精时间, 为空表示未加精
+	Version         int            `gorm:"column:version"`           // 版本号, 作者每次编辑自增, 用于乐观并发控制
+	EditedAt        *time.Time     `gorm:"column:edited_at"`         // 作者最近编辑时间, 互动计数落库不影响它, 为空表示未编辑过
+	CreatedAt       time.Time      `gorm:"column:created_at"`        // 创建时间
+	UpdatedAt       time.Time      `gorm:"column:updated_at"`        // 更新时间
+	DeletedAt       *time.Time     `gorm:"column:deleted_at"`        // 逻辑删除时间
 }
 
 // TableName 指定表名
@@ -26,6 +40,115 @@ func (p Post) TableName() string {
 	return "posts"
 }
 
+// LastEdited 返回作者最近编辑时间, 未编辑过时为创建时间
+func (p *Post) LastEdited() time.Time {
+	if p.EditedAt != nil {
+		return *p.EditedAt
+	}
+	return p.CreatedAt
+}
+
+// 帖子状态
+const (
+	PostStatusNormal    = 1
+	PostStatusBanned    = 2 // 被管理员下架
+	PostStatusReviewing = 3 // 命中敏感词, 审核通过前仅作者可见
+)
+
+// PostPin 定义数据库模型, 同一帖子在同一范围内只有一条置顶记录
+type PostPin struct {
+	ID        int64      `gorm:"primaryKey"`        // 置顶 ID
+	PostID    int64      `gorm:"column:post_id"`    // 帖子 ID
+	TagID     int64      `gorm:"column:tag_id"`     // 置顶范围, 0 为全站, 否则为标签 ID
+	PinnedBy  int64      `gorm:"column:pinned_by"`  // 操作人 ID
+	ExpiresAt *time.Time `gorm:"column:expires_at"` // 过期时间, 为空表示永久置顶
+	CreatedAt time.Time  `gorm:"column:created_at"` // 置顶时间, 越晚置顶越靠前
+}
+
+// TableName 指定表名
+func (p PostPin) TableName() string {
+	return "post_pins"
+}
+
+// PostSlug 定义数据库模型, 记录帖子用过的全部 slug, 标题修改后旧 slug 仍指向原帖子, 用于重定向
+type PostSlug struct {
+	ID        int64     `gorm:"primaryKey"`        // 记录 ID
+	PostID    int64     `gorm:"column:post_id"`    // 帖子 ID
+	Slug      string    `gorm:"column:slug"`       // slug, 全局唯一
+	CreatedAt time.Time `gorm:"column:created_at"` // 生成时间
+}
+
+// TableName 指定表名
+func (s PostSlug) TableName() string {
+	return "post_slugs"
+}
+
+// PostVisibility 帖子可见范围, 数值越大可见的人越少
+type PostVisibility int
+
+const (
+	PostVisibilityPublic    PostVisibility = iota + 1 // 所有人可见
+	PostVisibilityFollowers                           // 仅粉丝可见
+	PostVisibilityPrivate                             // 仅自己可见
+)
+
+// PostVisibilities 所有可见范围
+var PostVisibilities = []PostVisibility{PostVisibilityPublic, PostVisibilityFollowers, PostVisibilityPrivate}
+
+func (v PostVisibility) String() string {
+	switch v {
+	case PostVisibilityFollowers:
+		return "followers"
+	case PostVisibilityPrivate:
+		return "private"
+	default:
+		return "public"
+	}
+}
+
+// ParsePostVisibility 解析可见范围
+func ParsePostVisibility(s string) (PostVisibility, error) {
+	for _, visibility := range PostVisibilities {
+		if visibility.String() == s {
+			return visibility, nil
+		}
+	}
+	return 0, errno.ErrInvalidParam
+}
+
+// CommentPolicy 帖子的评论权限, 由作者设置, 作者本人不受限制
+type CommentPolicy int
+
+const (
+	CommentPolicyOpen      CommentPolicy = iota + 1 // 所有人可评论
+	CommentPolicyFollowers                          // 仅作者的粉丝可评论
+	CommentPolicyClosed                             // 关闭评论
+)
+
+// CommentPolicies 所有评论权限
+var CommentPolicies = []CommentPolicy{CommentPolicyOpen, CommentPolicyFollowers, CommentPolicyClosed}
+
+func (p CommentPolicy) String() string {
+	switch p {
+	case CommentPolicyFollowers:
+		return "followers"
+	case CommentPolicyClosed:
+		return "closed"
+	default:
+		return "open"
+	}
+}
+
+// ParseCommentPolicy 解析评论权限
+func ParseCommentPolicy(s string) (CommentPolicy, error) {
+	for _, policy := range CommentPolicies {
+		if policy.String() == s {
+			return policy, nil
+		}
+	}
+	return 0, errno.ErrInvalidParam
+}
+
 // PostCntField 用来枚举指定列名
 type PostCntField int
 
@@ -33,8 +156,14 @@ const (
 	PostViewCount PostCntField = iota + 1
 	PostCommentCount
 	PostLikeCount
+	PostUniqueViewCount
+	PostBookmarkCount
+	PostShareCount
 )
 
+// PostCntFields 所有互动计数列
+var PostCntFields = []PostCntField{PostViewCount, PostCommentCount, PostLikeCount, PostUniqueViewCount, PostBookmarkCount, PostShareCount}
+
 func (f PostCntField) Column() (string, error) {
 	switch f {
 	case PostViewCount:
@@ -43,13 +172,104 @@ func (f PostCntField) Column() (string, error) {
 		return "comment_count", nil
 	case PostLikeCount:
 		return "like_count", nil
+	case PostUniqueViewCount:
+		return "unique_view_count", nil
+	case PostBookmarkCount:
+		return "bookmark_count", nil
+	case PostShareCount:
+		return "share_count", nil
 	default:
 		return "", errno.ErrInvalidParam
 	}
 }
 
+// ParsePostCntField 根据列名获取 PostCntField
+func ParsePostCntField(col string) (PostCntField, error) {
+	for _, field := range PostCntFields {
+		if c, _ := field.Column(); c == col {
+			return field, nil
+		}
+	}
+	return 0, errno.ErrInvalidParam
+}
+
+// AddCnt 将增量累加到对应计数上
+func (p *Post) AddCnt(field PostCntField, delta int) {
+	switch field {
+	case PostViewCount:
+		p.ViewCount += delta
+	case PostCommentCount:
+		p.CommentCount += delta
+	case PostLikeCount:
+		p.LikeCount += delta
+	case PostUniqueViewCount:
+		p.UniqueViewCount += delta
+	case PostBookmarkCount:
+		p.BookmarkCount += delta
+	case PostShareCount:
+		p.ShareCount += delta
+	}
+}
+
 // Redis Key
 const (
-	KeyPostScore = "post:score"
-	KeyPostTime  = "post:time"
+	KeyPostScore       = "post:score"        // 全站全时段热榜, 其余热榜以此为前缀
+	KeyPostScoreBoards = "post:score:boards" // 当前存在的热榜 Key 集合前缀, 每个时间窗口一个 Set
+	KeyPostRelated     = "post:related"      // 相关推荐前缀, 每个帖子一个 ZSet
 )
+
+// HotBoardsKey 拼接时间窗口下当前存在的热榜 Key 集合
+func HotBoardsKey(window HotWindow) string {
+	return KeyPostScoreBoards + ":" + string(window)
+}
+
+// RelatedKey 拼接帖子的相关推荐 Key
+func RelatedKey(pid int64) string {
+	return KeyPostRelated + ":" + strconv.FormatInt(pid, 10)
+}
+
+// HotWindow 热榜时间窗口
+type HotWindow string
+
+const (
+	HotWindowDay  HotWindow = "24h"
+	HotWindowWeek HotWindow = "7d"
+	HotWindowAll  HotWindow = "all"
+)
+
+// HotWindows 所有热榜时间窗口
+var HotWindows = []HotWindow{HotWindowDay, HotWindowWeek, HotWindowAll}
+
+// ParseHotWindow 解析热榜时间窗口
+func ParseHotWindow(s string) (HotWindow, error) {
+	for _, window := range HotWindows {
+		if string(window) == s {
+			return window, nil
+		}
+	}
+	return "", errno.ErrInvalidParam
+}
+
+// Duration 窗口时长, 全时段返回 0
+func (w HotWindow) Duration() time.Duration {
+	switch w {
+	case HotWindowDay:
+		return 24 * time.Hour
+	case HotWindowWeek:
+		return 7 * 24 * time.Hour
+	default:
+		return 0
+	}
+}
+
+// HotBoardKey 拼接热榜 Key, tid 为 0 表示全站榜
+func HotBoardKey(window HotWindow, tid int64) string {
+	key := KeyPostScore
+	if window != HotWindowAll {
+		key += ":" + string(window)
+	}
+	if tid != 0 {
+		key += ":tag:" + strconv.FormatInt(tid, 10)
+	}
+	return key
+}
@@ -0,0 +1,133 @@
+package model
+
+import (
+	"time"
+
+	"github.com/yzletter/go-postery/errno"
+)
+
+// Report 定义数据库模型, 同一用户对同一对象只能举报一次
+type Report struct {
+	ID         int64            `gorm:"primaryKey"`         // 举报 ID
+	ReporterID int64            `gorm:"column:reporter_id"` // 举报人 ID
+	TargetType ReportTargetType `gorm:"column:target_type"` // 举报对象类型
+	TargetID   int64            `gorm:"column:target_id"`   // 举报对象 ID
+	Reason     string           `gorm:"column:reason"`      // 举报原因
+	Detail     string           `gorm:"column:detail"`      // 补充说明
+	Status     ReportStatus     `gorm:"column:status"`      // 处理状态
+	HandledBy  int64            `gorm:"column:handled_by"`  // 处理人 ID
+	HandledAt  *time.Time       `gorm:"column:handled_at"`  // 处理时间
+	CreatedAt  time.Time        `gorm:"column:created_at"`  // 创建时间
+	UpdatedAt  time.Time        `gorm:"column:updated_at"`  // 更新时间
+}
+
+// TableName 指定表名
+func (r Report) TableName() string {
+	return "reports"
+}
+
+// ReportSummary 按举报对象聚合的待处理举报, 不对应数据库表
+type ReportSummary struct {
+	TargetType      ReportTargetType
+	TargetID        int64
+	ReportCount     int
+	Reasons         string // 逗号分隔的去重原因
+	FirstReportedAt time.Time
+	LastReportedAt  time.Time
+}
+
+// ModerationLog 定义数据库模型, 记录管理员的每次处理
+type ModerationLog struct {
+	ID          int64            `gorm:"primaryKey"`          // 记录 ID
+	OperatorID  int64            `gorm:"column:operator_id"`  // 处理人 ID
+	TargetType  ReportTargetType `gorm:"column:target_type"`  // 处理对象类型
+	TargetID    int64            `gorm:"column:target_id"`    // 处理对象 ID
+	Action      ReportStatus     `gorm:"column:action"`       // 处理结果
+	Note        string           `gorm:"column:note"`         // 处理备注
+	ReportCount int              `gorm:"column:report_count"` // 本次处理的举报数
+	CreatedAt   time.Time        `gorm:"column:created_at"`   // 创建时间
+}
+
+// TableName 指定表名
+func (l ModerationLog) TableName() string {
+	return "moderation_logs"
+}
+
+// ReportTargetType 举报对象类型
+type ReportTargetType int
+
+const (
+	ReportTargetPost ReportTargetType = iota + 1
+	ReportTargetComment
+	ReportTargetUser
+)
+
+// ReportTargetTypes 所有举报对象类型
+var ReportTargetTypes = []ReportTargetType{ReportTargetPost, ReportTargetComment, ReportTargetUser}
+
+func (t ReportTargetType) String() string {
+	switch t {
+	case ReportTargetPost:
+		return "post"
+	case ReportTargetComment:
+		return "comment"
+	case ReportTargetUser:
+		return "user"
+	default:
+		return ""
+	}
+}
+
+// ParseReportTargetType 解析举报对象类型
+func ParseReportTargetType(s string) (ReportTargetType, error) {
+	for _, t := range ReportTargetTypes {
+		if t.String() == s {
+			return t, nil
+		}
+	}
+	return 0, errno.ErrInvalidParam
+}
+
+// ReportReasons 允许的举报原因
+var ReportReasons = []string{"spam", "abuse", "porn", "illegal", "other"}
+
+// ReportReasonSensitive 内容命中审核类敏感词时由系统提交的举报原因, 此时 ReporterID 为 0
+const ReportReasonSensitive = "sensitive"
+
+// ReportStatus 举报处理状态, 除 Pending 外也作为管理员的处理动作
+type ReportStatus int
+
+const (
+	ReportPending   ReportStatus = iota + 1 // 待处理
+	ReportApproved                          // 举报成立, 不下架内容
+	ReportDismissed                         // 举报不成立
+	ReportTakenDown                         // 举报成立, 下架内容或封禁用户
+)
+
+// ReportActions 管理员可执行的处理动作
+var ReportActions = []ReportStatus{ReportApproved, ReportDismissed, ReportTakenDown}
+
+func (s ReportStatus) String() string {
+	switch s {
+	case ReportPending:
+		return "pending"
+	case ReportApproved:
+		return "approve"
+	case ReportDismissed:
+		return "dismiss"
+	case ReportTakenDown:
+		return "takedown"
+	default:
+		return ""
+	}
+}
+
+// ParseReportAction 解析管理员的处理动作
+func ParseReportAction(s string) (ReportStatus, error) {
+	for _, action := range ReportActions {
+		if action.String() == s {
+			return action, nil
+		}
+	}
+	return 0, errno.ErrInvalidParam
+}
@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+// Repost 定义数据库模型, 同一用户对同一帖子只有一条转发记录; 引用转发是一篇带 QuoteID 的新帖子, 不记录在这里
+type Repost struct {
+	ID        int64      `gorm:"primaryKey"`        // 记录 ID
+	UserID    int64      `gorm:"column:user_id"`    // 转发者 ID
+	PostID    int64      `gorm:"column:post_id"`    // 被转发的帖子 ID
+	CreatedAt time.Time  `gorm:"column:created_at"` // 转发时间
+	UpdatedAt time.Time  `gorm:"column:updated_at"` // 更新时间
+	DeletedAt *time.Time `gorm:"column:deleted_at"` // 逻辑删除时间
+}
+
+// TableName 指定表名
+func (r Repost) TableName() string {
+	return "reposts"
+}
@@ -0,0 +1,74 @@
+package model
+
+import (
+	"time"
+
+	"github.com/yzletter/go-postery/errno"
+)
+
+// SensitiveWord 定义数据库模型, 同一个词只能属于一个分类
+type SensitiveWord struct {
+	ID        int64     `gorm:"primaryKey"`        // 敏感词 ID
+	Word      string    `gorm:"column:word"`       // 敏感词, 匹配时忽略大小写
+	Category  string    `gorm:"column:category"`   // 所属分类
+	CreatedAt time.Time `gorm:"column:created_at"` // 创建时间
+	UpdatedAt time.Time `gorm:"column:updated_at"` // 更新时间
+}
+
+// TableName 指定表名
+func (w SensitiveWord) TableName() string {
+	return "sensitive_words"
+}
+
+// SensitiveCategory 定义数据库模型, 命中某个分类的词时执行该分类的处理动作
+type SensitiveCategory struct {
+	ID        int64           `gorm:"primaryKey"`        // 分类 ID
+	Name      string          `gorm:"column:name"`       // 分类名
+	Action    SensitiveAction `gorm:"column:action"`     // 处理动作
+	CreatedAt time.Time       `gorm:"column:created_at"` // 创建时间
+	UpdatedAt time.Time       `gorm:"column:updated_at"` // 更新时间
+}
+
+// TableName 指定表名
+func (c SensitiveCategory) TableName() string {
+	return "sensitive_categories"
+}
+
+// KeySensitiveVersion 敏感词库版本号, 词库变更时自增, 各实例据此判断是否需要重新加载
+const KeySensitiveVersion = "sensitive:version"
+
+// SensitiveAction 命中敏感词后的处理动作, 数值越大越严格, 同时命中多个分类时取最严格的
+type SensitiveAction int
+
+const (
+	SensitivePass   SensitiveAction = iota // 未命中
+	SensitiveMask                          // 用 * 替换命中的词后放行
+	SensitiveReview                        // 放行但先隐藏, 进入审核队列
+	SensitiveReject                        // 直接拒绝
+)
+
+// SensitiveActions 可配置给分类的处理动作
+var SensitiveActions = []SensitiveAction{SensitiveMask, SensitiveReview, SensitiveReject}
+
+func (a SensitiveAction) String() string {
+	switch a {
+	case SensitiveMask:
+		return "mask"
+	case SensitiveReview:
+		return "review"
+	case SensitiveReject:
+		return "reject"
+	default:
+		return "pass"
+	}
+}
+
+// ParseSensitiveAction 解析分类的处理动作
+func ParseSensitiveAction(s string) (SensitiveAction, error) {
+	for _, action := range SensitiveActions {
+		if action.String() == s {
+			return action, nil
+		}
+	}
+	return 0, errno.ErrInvalidParam
+}
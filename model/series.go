@@ -0,0 +1,33 @@
+package model
+
+import "time"
+
+// Series 定义数据库模型, 用户将自己的多篇帖子按顺序组织成系列
+type Series struct {
+	ID          int64      `gorm:"primaryKey"`         // 系列 ID
+	UserID      int64      `gorm:"column:user_id"`     // 作者 ID
+	Title       string     `gorm:"column:title"`       // 标题
+	Description string     `gorm:"column:description"` // 简介
+	CreatedAt   time.Time  `gorm:"column:created_at"`  // 创建时间
+	UpdatedAt   time.Time  `gorm:"column:updated_at"`  // 更新时间
+	DeletedAt   *time.Time `gorm:"column:deleted_at"`  // 逻辑删除时间
+}
+
+// TableName 指定表名
+func (s Series) TableName() string {
+	return "series"
+}
+
+// SeriesPost 定义数据库模型, 一篇帖子最多属于一个系列, 同一系列内的位置从 1 开始连续
+type SeriesPost struct {
+	ID        int64     `gorm:"primaryKey"`        // 记录 ID
+	SeriesID  int64     `gorm:"column:series_id"`  // 系列 ID
+	PostID    int64     `gorm:"column:post_id"`    // 帖子 ID
+	Position  int       `gorm:"column:position"`   // 在系列中的位置, 从 1 开始
+	CreatedAt time.Time `gorm:"column:created_at"` // 加入时间
+}
+
+// TableName 指定表名
+func (s SeriesPost) TableName() string {
+	return "series_posts"
+}
@@ -15,6 +15,7 @@ type User struct {
 	Location     string     `gorm:"column:location"`               // 地区
 	Country      string     `gorm:"column:country"`                // 国家
 	Status       int        `gorm:"column:status"`                 // 状态 1 正常, 2 封禁, 3 注销
+	Role         int        `gorm:"column:role"`                   // 角色 0 普通用户, 1 管理员, 2 版主
 	LastLoginIP  string     `gorm:"column:last_login_ip"`          // 最后登录 IP
 	LastLoginAt  *time.Time `gorm:"column:last_login_at"`          // 最后登录时间
 	CreatedAt    time.Time  `gorm:"column:created_at"`             // 创建时间
@@ -30,3 +31,17 @@ func (u User) TableName() string {
 const (
 	KeyUserScore = "user:score"
 )
+
+// 用户状态
+const (
+	UserStatusNormal    = 1
+	UserStatusBanned    = 2
+	UserStatusCancelled = 3
+)
+
+// 用户角色
+const (
+	UserRoleNormal    = 0
+	UserRoleAdmin     = 1
+	UserRoleModerator = 2 // 可以置顶和加精帖子, 不能处理举报
+)
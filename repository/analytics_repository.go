@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/yzletter/go-postery/model"
+	"github.com/yzletter/go-postery/repository/cache"
+	"github.com/yzletter/go-postery/repository/dao"
+)
+
+type analyticsRepository struct {
+	dao   dao.AnalyticsDAO
+	cache cache.AnalyticsCache
+}
+
+func NewAnalyticsRepository(analyticsDAO dao.AnalyticsDAO, analyticsCache cache.AnalyticsCache) AnalyticsRepository {
+	return &analyticsRepository{dao: analyticsDAO, cache: analyticsCache}
+}
+
+// RecordPostEvent 将帖子事件计入 at 所在小时的缓冲区, 由后台任务汇总落库
+func (repo *analyticsRepository) RecordPostEvent(ctx context.Context, pid int64, metric model.AnalyticsMetric, at time.Time) error {
+	return repo.cache.IncrPostEvent(ctx, pid, metric, model.AnalyticsHourly.Truncate(at))
+}
+
+// RecordReferrer 将帖子的一次浏览来源计入 at 所在当天的缓冲区
+func (repo *analyticsRepository) RecordReferrer(ctx context.Context, pid int64, referrer string, at time.Time) error {
+	return repo.cache.IncrReferrer(ctx, pid, referrer, model.AnalyticsDaily.Truncate(at))
+}
+
+// RecordFollower 将用户新增或流失的一个粉丝计入 at 所在当天的缓冲区
+func (repo *analyticsRepository) RecordFollower(ctx context.Context, uid int64, gained bool, at time.Time) error {
+	return repo.cache.IncrFollower(ctx, uid, gained, model.AnalyticsDaily.Truncate(at))
+}
+
+func (repo *analyticsRepository) TakePending(ctx context.Context) (*model.AnalyticsBatch, error) {
+	return repo.cache.TakePending(ctx)
+}
+
+func (repo *analyticsRepository) RestorePending(ctx context.Context, batch *model.AnalyticsBatch) error {
+	return repo.cache.RestorePending(ctx, batch)
+}
+
+func (repo *analyticsRepository) SaveBatch(ctx context.Context, batch *model.AnalyticsBatch) error {
+	err := repo.dao.SaveBatch(ctx, batch)
+	if err != nil {
+		return toRepositoryErr(err)
+	}
+	return nil
+}
+
+func (repo *analyticsRepository) PruneHourly(ctx context.Context, before time.Time, limit int) (int64, error) {
+	cnt, err := repo.dao.PruneHourly(ctx, before, limit)
+	if err != nil {
+		return 0, toRepositoryErr(err)
+	}
+	return cnt, nil
+}
+
+func (repo *analyticsRepository) GetPostSeries(ctx context.Context, authorID, pid int64, granularity model.AnalyticsGranularity, from, to time.Time) ([]*model.PostStat, error) {
+	stats, err := repo.dao.GetPostSeries(ctx, authorID, pid, granularity, from, to)
+	if err != nil {
+		return nil, toRepositoryErr(err)
+	}
+	return stats, nil
+}
+
+func (repo *analyticsRepository) GetTopPosts(ctx context.Context, authorID int64, granularity model.AnalyticsGranularity, from, to time.Time, limit int) ([]*model.PostStat, error) {
+	stats, err := repo.dao.GetTopPosts(ctx, authorID, granularity, from, to, limit)
+	if err != nil {
+		return nil, toRepositoryErr(err)
+	}
+	return stats, nil
+}
+
+func (repo *analyticsRepository) GetReferrers(ctx context.Context, authorID, pid int64, from, to time.Time, limit int) ([]*model.PostReferrerStat, error) {
+	stats, err := repo.dao.GetReferrers(ctx, authorID, pid, from, to, limit)
+	if err != nil {
+		return nil, toRepositoryErr(err)
+	}
+	return stats, nil
+}
+
+func (repo *analyticsRepository) GetFollowerSeries(ctx context.Context, uid int64, from, to time.Time) ([]*model.FollowerStat, error) {
+	stats, err := repo.dao.GetFollowerSeries(ctx, uid, from, to)
+	if err != nil {
+		return nil, toRepositoryErr(err)
+	}
+	return stats, nil
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/yzletter/go-postery/model"
+	"github.com/yzletter/go-postery/repository/cache"
+	"github.com/yzletter/go-postery/repository/dao"
+)
+
+type attachmentRepository struct {
+	dao   dao.AttachmentDAO
+	cache cache.AttachmentCache
+}
+
+func NewAttachmentRepository(attachmentDAO dao.AttachmentDAO, attachmentCache cache.AttachmentCache) AttachmentRepository {
+	return &attachmentRepository{dao: attachmentDAO, cache: attachmentCache}
+}
+
+func (repo *attachmentRepository) Create(ctx context.Context, attachment *model.Attachment, quota int64) error {
+	err := repo.dao.Create(ctx, attachment, quota)
+	if err != nil {
+		return toRepositoryErr(err)
+	}
+	return nil
+}
+
+func (repo *attachmentRepository) GetByID(ctx context.Context, id int64) (*model.Attachment, error) {
+	attachment, err := repo.dao.GetByID(ctx, id)
+	if err != nil {
+		return nil, toRepositoryErr(err)
+	}
+	return attachment, nil
+}
+
+func (repo *attachmentRepository) GetByPostID(ctx context.Context, pid int64) ([]*model.Attachment, error) {
+	attachments, err := repo.dao.GetByPostID(ctx, pid)
+	if err != nil {
+		return nil, toRepositoryErr(err)
+	}
+	return attachments, nil
+}
+
+func (repo *attachmentRepository) CountBindable(ctx context.Context, uid, pid int64, ids []int64) (int64, error) {
+	cnt, err := repo.dao.CountBindable(ctx, uid, pid, ids)
+	if err != nil {
+		return 0, toRepositoryErr(err)
+	}
+	return cnt, nil
+}
+
+func (repo *attachmentRepository) SumSizeByUid(ctx context.Context, uid int64) (int64, error) {
+	total, err := repo.dao.SumSizeByUid(ctx, uid)
+	if err != nil {
+		return 0, toRepositoryErr(err)
+	}
+	return total, nil
+}
+
+func (repo *attachmentRepository) BindToPost(ctx context.Context, uid, pid int64, ids []int64) error {
+	err := repo.dao.BindToPost(ctx, uid, pid, ids)
+	if err != nil {
+		return toRepositoryErr(err)
+	}
+	return nil
+}
+
+func (repo *attachmentRepository) Discard(ctx context.Context, uid, id int64) error {
+	err := repo.dao.Discard(ctx, uid, id)
+	if err != nil {
+		return toRepositoryErr(err)
+	}
+	return nil
+}
+
+func (repo *attachmentRepository) DiscardByPostID(ctx context.Context, pid int64) error {
+	err := repo.dao.DiscardByPostID(ctx, pid)
+	if err != nil {
+		return toRepositoryErr(err)
+	}
+	return nil
+}
+
+func (repo *attachmentRepository) ListGarbage(ctx context.Context, orphanBefore time.Time, limit int) ([]*model.Attachment, error) {
+	attachments, err := repo.dao.ListGarbage(ctx, orphanBefore, limit)
+	if err != nil {
+		return nil, toRepositoryErr(err)
+	}
+	return attachments, nil
+}
+
+func (repo *attachmentRepository) Delete(ctx context.Context, ids []int64) error {
+	err := repo.dao.Delete(ctx, ids)
+	if err != nil {
+		return toRepositoryErr(err)
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/yzletter/go-postery/model"
+	"github.com/yzletter/go-postery/repository/cache"
+	"github.com/yzletter/go-postery/repository/dao"
+)
+
+type bookmarkRepository struct {
+	dao   dao.BookmarkDAO
+	cache cache.BookmarkCache
+}
+
+func NewBookmarkRepository(bookmarkDAO dao.BookmarkDAO, bookmarkCache cache.BookmarkCache) BookmarkRepository {
+	return &bookmarkRepository{dao: bookmarkDAO, cache: bookmarkCache}
+}
+
+func (repo *bookmarkRepository) Bookmark(ctx context.Context, bookmark *model.Bookmark) error {
+	err := repo.dao.Create(ctx, bookmark)
+	if err != nil {
+		return toRepositoryErr(err)
+	}
+	return nil
+}
+
+func (repo *bookmarkRepository) UnBookmark(ctx context.Context, uid, pid int64) error {
+	err := repo.dao.Delete(ctx, uid, pid)
+	if err != nil {
+		return toRepositoryErr(err)
+	}
+	return nil
+}
+
+func (repo *bookmarkRepository) HasBookmarked(ctx context.Context, uid, pid int64) (bool, error) {
+	// todo 查 Cache
+	ok, err := repo.dao.Exists(ctx, uid, pid)
+	if err != nil {
+		return false, toRepositoryErr(err)
+	}
+
+	return ok, nil
+}
+
+func (repo *bookmarkRepository) GetPostIDs(ctx context.Context, uid int64, cid *int64, pageNo, pageSize int) (int64, []int64, error) {
+	total, ids, err := repo.dao.GetPostIDs(ctx, uid, cid, pageNo, pageSize)
+	if err != nil {
+		return 0, nil, toRepositoryErr(err)
+	}
+	return total, ids, nil
+}
+
+func (repo *bookmarkRepository) CreateCollection(ctx context.Context, collection *model.BookmarkCollection) error {
+	err := repo.dao.CreateCollection(ctx, collection)
+	if err != nil {
+		return toRepositoryErr(err)
+	}
+	return nil
+}
+
+func (repo *bookmarkRepository) MoveBookmark(ctx context.Context, uid, pid, cid int64) error {
+	err := repo.dao.MoveBookmark(ctx, uid, pid, cid)
+	if err != nil {
+		return toRepositoryErr(err)
+	}
+	return nil
+}
+
+func (repo *bookmarkRepository) RenameCollection(ctx context.Context, id int64, name string) error {
+	err := repo.dao.RenameCollection(ctx, id, name)
+	if err != nil {
+		return toRepositoryErr(err)
+	}
+	return nil
+}
+
+func (repo *bookmarkRepository) DeleteCollection(ctx context.Context, id int64) error {
+	err := repo.dao.DeleteCollection(ctx, id)
+	if err != nil {
+		return toRepositoryErr(err)
+	}
+	return nil
+}
+
+func (repo *bookmarkRepository) GetCollectionByID(ctx context.Context, id int64) (*model.BookmarkCollection, error) {
+	collection, err := repo.dao.GetCollectionByID(ctx, id)
+	if err != nil {
+		return nil, toRepositoryErr(err)
+	}
+	return collection, nil
+}
+
+func (repo *bookmarkRepository) GetCollections(ctx context.Context, uid int64, pageNo, pageSize int) (int64, []*model.BookmarkCollection, error) {
+	total, collections, err := repo.dao.GetCollections(ctx, uid, pageNo, pageSize)
+	if err != nil {
+		return 0, nil, toRepositoryErr(err)
+	}
+	return total, collections, nil
+}
+
+func (repo *bookmarkRepository) CountByCollections(ctx context.Context, uid int64, cids []int64) (map[int64]int, error) {
+	res, err := repo.dao.CountByCollections(ctx, uid, cids)
+	if err != nil {
+		return nil, toRepositoryErr(err)
+	}
+	return res, nil
+}
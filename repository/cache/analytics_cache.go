@@ -0,0 +1,173 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/yzletter/go-postery/model"
+)
+
+const (
+	analyticsPendingKey = "analytics:pending"
+
+	// 缓冲区字段形如 <类型>|<ID>|<时间桶 Unix 秒>|<事件>
+	analyticsPostField     = "p"
+	analyticsReferrerField = "r"
+	analyticsFollowerField = "f"
+	analyticsGained        = "gained"
+	analyticsLost          = "lost"
+)
+
+// redisAnalyticsCache 用 Redis 哈希缓冲统计事件, 由后台任务定期取出汇总
+type redisAnalyticsCache struct {
+	client redis.UniversalClient
+}
+
+// NewAnalyticsCache 构造函数
+func NewAnalyticsCache(redisClient redis.UniversalClient) AnalyticsCache {
+	return &redisAnalyticsCache{client: redisClient}
+}
+
+// IncrPostEvent 记录帖子在 bucket 时间桶内发生一次事件
+func (cache *redisAnalyticsCache) IncrPostEvent(ctx context.Context, pid int64, metric model.AnalyticsMetric, bucket time.Time) error {
+	return cache.client.HIncrBy(ctx, analyticsPendingKey, analyticsField(analyticsPostField, pid, bucket, string(metric)), 1).Err()
+}
+
+// IncrReferrer 记录帖子在 bucket 时间桶内来自 referrer 的一次浏览
+func (cache *redisAnalyticsCache) IncrReferrer(ctx context.Context, pid int64, referrer string, bucket time.Time) error {
+	return cache.client.HIncrBy(ctx, analyticsPendingKey, analyticsField(analyticsReferrerField, pid, bucket, referrer), 1).Err()
+}
+
+// IncrFollower 记录用户在 bucket 时间桶内新增或流失一个粉丝
+func (cache *redisAnalyticsCache) IncrFollower(ctx context.Context, uid int64, gained bool, bucket time.Time) error {
+	event := analyticsLost
+	if gained {
+		event = analyticsGained
+	}
+	return cache.client.HIncrBy(ctx, analyticsPendingKey, analyticsField(analyticsFollowerField, uid, bucket, event), 1).Err()
+}
+
+// TakePending 取出缓冲区中的全部事件, 取出后缓冲区被清空
+func (cache *redisAnalyticsCache) TakePending(ctx context.Context) (*model.AnalyticsBatch, error) {
+	// 读取和清空放在同一个事务中, 避免丢失两步之间写入的事件
+	pipe := cache.client.TxPipeline()
+	getCmd := pipe.HGetAll(ctx, analyticsPendingKey)
+	pipe.Del(ctx, analyticsPendingKey)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+
+	return parseAnalyticsBatch(getCmd.Val()), nil
+}
+
+// RestorePending 汇总失败时将事件归还缓冲区
+func (cache *redisAnalyticsCache) RestorePending(ctx context.Context, batch *model.AnalyticsBatch) error {
+	if batch.Empty() {
+		return nil
+	}
+
+	pipe := cache.client.Pipeline()
+	for _, stat := range batch.Posts {
+		deltas := map[model.AnalyticsMetric]int{
+			model.AnalyticsView:     stat.ViewCount,
+			model.AnalyticsLike:     stat.LikeCount,
+			model.AnalyticsComment:  stat.CommentCount,
+			model.AnalyticsBookmark: stat.BookmarkCount,
+		}
+		for metric, delta := range deltas {
+			if delta != 0 {
+				pipe.HIncrBy(ctx, analyticsPendingKey, analyticsField(analyticsPostField, stat.PostID, stat.Bucket, string(metric)), int64(delta))
+			}
+		}
+	}
+	for _, stat := range batch.Referrers {
+		pipe.HIncrBy(ctx, analyticsPendingKey, analyticsField(analyticsReferrerField, stat.PostID, stat.Bucket, stat.Referrer), int64(stat.ViewCount))
+	}
+	for _, stat := range batch.Followers {
+		if stat.Gained != 0 {
+			pipe.HIncrBy(ctx, analyticsPendingKey, analyticsField(analyticsFollowerField, stat.UserID, stat.Bucket, analyticsGained), int64(stat.Gained))
+		}
+		if stat.Lost != 0 {
+			pipe.HIncrBy(ctx, analyticsPendingKey, analyticsField(analyticsFollowerField, stat.UserID, stat.Bucket, analyticsLost), int64(stat.Lost))
+		}
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// analyticsField 拼接缓冲区字段
+func analyticsField(kind string, id int64, bucket time.Time, event string) string {
+	return fmt.Sprintf("%s|%d|%d|%s", kind, id, bucket.Unix(), event)
+}
+
+// parseAnalyticsBatch 将缓冲区字段解析为按 ID 和时间桶合并后的事件, 无法解析的字段直接丢弃
+func parseAnalyticsBatch(mp map[string]string) *model.AnalyticsBatch {
+	type key struct {
+		id     int64
+		bucket int64
+		extra  string
+	}
+	posts := make(map[key]*model.PostStat)
+	referrers := make(map[key]*model.PostReferrerStat)
+	followers := make(map[key]*model.FollowerStat)
+
+	for field, val := range mp {
+		parts := strings.SplitN(field, "|", 4)
+		if len(parts) != 4 {
+			continue
+		}
+		id, err1 := strconv.ParseInt(parts[1], 10, 64)
+		sec, err2 := strconv.ParseInt(parts[2], 10, 64)
+		delta, err3 := strconv.Atoi(val)
+		if err1 != nil || err2 != nil || err3 != nil || delta == 0 {
+			continue
+		}
+		bucket := time.Unix(sec, 0)
+
+		switch parts[0] {
+		case analyticsPostField:
+			k := key{id: id, bucket: sec}
+			stat, ok := posts[k]
+			if !ok {
+				stat = &model.PostStat{PostID: id, Granularity: model.AnalyticsHourly, Bucket: bucket}
+				posts[k] = stat
+			}
+			stat.Add(model.AnalyticsMetric(parts[3]), delta)
+		case analyticsReferrerField:
+			referrers[key{id: id, bucket: sec, extra: parts[3]}] = &model.PostReferrerStat{
+				PostID:    id,
+				Bucket:    bucket,
+				Referrer:  parts[3],
+				ViewCount: delta,
+			}
+		case analyticsFollowerField:
+			k := key{id: id, bucket: sec}
+			stat, ok := followers[k]
+			if !ok {
+				stat = &model.FollowerStat{UserID: id, Bucket: bucket}
+				followers[k] = stat
+			}
+			if parts[3] == analyticsGained {
+				stat.Gained += delta
+			} else {
+				stat.Lost += delta
+			}
+		}
+	}
+
+	batch := &model.AnalyticsBatch{}
+	for _, stat := range posts {
+		batch.Posts = append(batch.Posts, stat)
+	}
+	for _, stat := range referrers {
+		batch.Referrers = append(batch.Referrers, stat)
+	}
+	for _, stat := range followers {
+		batch.Followers = append(batch.Followers, stat)
+	}
+	return batch
+}
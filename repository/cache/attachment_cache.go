@@ -0,0 +1,13 @@
+package cache
+
+import "github.com/redis/go-redis/v9"
+
+// redisAttachmentCache 用 Redis 实现 AttachmentCache
+type redisAttachmentCache struct {
+	client redis.UniversalClient
+}
+
+// NewAttachmentCache 构造函数
+func NewAttachmentCache(redisClient redis.UniversalClient) AttachmentCache {
+	return &redisAttachmentCache{client: redisClient}
+}
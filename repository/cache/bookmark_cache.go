@@ -0,0 +1,13 @@
+package cache
+
+import "github.com/redis/go-redis/v9"
+
+// redisBookmarkCache 用 Redis 实现 BookmarkCache
+type redisBookmarkCache struct {
+	client redis.UniversalClient
+}
+
+// NewBookmarkCache 构造函数
+func NewBookmarkCache(redisClient redis.UniversalClient) BookmarkCache {
+	return &redisBookmarkCache{client: redisClient}
+}
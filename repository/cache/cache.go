@@ -2,6 +2,7 @@ package cache
 
 import (
 	"context"
+	"time"
 
 	"github.com/yzletter/go-postery/model"
 )
@@ -11,23 +12,72 @@ import (
 type UserCache interface {
 	ChangeScore(ctx context.Context, uid int64, delta int) error
 	Top(ctx context.Context) ([]int64, []float64, error)
+	GetUsers(ctx context.Context, ids []int64) (map[int64]*model.User, error)
+	SetUsers(ctx context.Context, users []*model.User) error
+	DeleteUser(ctx context.Context, id int64) error
 }
 
 type PostCache interface {
-	ChangeInteractiveCnt(ctx context.Context, pid int64, field model.PostCntField, delta int) (bool, error)
-	SetInteractiveKey(ctx context.Context, pid int64, fields []model.PostCntField, vals []int)
-	SetScore(ctx context.Context, pid int64) error
-	CheckPostLikeTime(ctx context.Context, pid int64) (float64, error)
-	ChangeScore(ctx context.Context, pid int64, delta int) error
-	Top(ctx context.Context) ([]int64, []float64, error)
-	DeleteScore(ctx context.Context, id int64) error
+	IncrPendingCnt(ctx context.Context, pid int64, field model.PostCntField, delta int) error
+	GetPendingCnt(ctx context.Context, pids []int64) (map[int64]map[model.PostCntField]int, error)
+	PeekPendingCnt(ctx context.Context, batchSize int) (map[int64]map[model.PostCntField]int, error)
+	AckPendingCnt(ctx context.Context, pid int64, deltas map[model.PostCntField]int) error
+	DeletePendingCnt(ctx context.Context, pid int64) error
+	LockPendingCnt(ctx context.Context, token string, ttl time.Duration) (bool, error)
+	UnlockPendingCnt(ctx context.Context, token string) error
+	MarkViewed(ctx context.Context, pid int64, viewer string, window time.Duration) (bool, error)
+	Top(ctx context.Context, key string, limit int) ([]int64, []float64, error)
+	StageHot(ctx context.Context, key string, scores map[int64]float64, limit int, fresh bool) error
+	PublishHot(ctx context.Context, boardsKey string, keys []string) error
+	RemoveHot(ctx context.Context, pid int64) error
+	GetRelated(ctx context.Context, pid int64) ([]int64, error)
+	SetRelated(ctx context.Context, pid int64, scores map[int64]float64, limit int, expiration time.Duration) error
 }
 
 type CommentCache interface {
 }
+type CommentLikeCache interface {
+}
+type CommentBlockCache interface {
+}
+type MentionCache interface {
+}
 type LikeCache interface {
 }
+type BookmarkCache interface {
+}
+type RepostCache interface {
+}
+type AttachmentCache interface {
+}
+type ReportCache interface {
+}
+type SensitiveCache interface {
+	GetVersion(ctx context.Context) (int64, error)
+	IncrVersion(ctx context.Context) error
+}
 type TagCache interface {
+	GetPostTags(ctx context.Context, pids []int64) (map[int64][]string, error)
+	SetPostTags(ctx context.Context, tags map[int64][]string) error
+	DeletePostTags(ctx context.Context, pid int64) error
+}
+type PollCache interface {
+	Vote(ctx context.Context, pollID, uid int64, optionIDs []int64) (bool, error)
+	RevertVote(ctx context.Context, pollID, uid int64, optionIDs []int64) error
+	GetPendingCnt(ctx context.Context, pollID int64) (*model.PollCnt, error)
+	PeekPendingCnt(ctx context.Context, batchSize int) (map[int64]*model.PollCnt, error)
+	AckPendingCnt(ctx context.Context, pollID int64, cnt *model.PollCnt) error
+	LockPendingCnt(ctx context.Context, token string, ttl time.Duration) (bool, error)
+	UnlockPendingCnt(ctx context.Context, token string) error
+}
+type SeriesCache interface {
+}
+type AnalyticsCache interface {
+	IncrPostEvent(ctx context.Context, pid int64, metric model.AnalyticsMetric, bucket time.Time) error
+	IncrReferrer(ctx context.Context, pid int64, referrer string, bucket time.Time) error
+	IncrFollower(ctx context.Context, uid int64, gained bool, bucket time.Time) error
+	TakePending(ctx context.Context) (*model.AnalyticsBatch, error)
+	RestorePending(ctx context.Context, batch *model.AnalyticsBatch) error
 }
 type FollowCache interface {
 }
@@ -0,0 +1,13 @@
+package cache
+
+import "github.com/redis/go-redis/v9"
+
+// redisCommentBlockCache 用 Redis 实现 CommentBlockCache
+type redisCommentBlockCache struct {
+	client redis.UniversalClient
+}
+
+// NewCommentBlockCache 构造函数
+func NewCommentBlockCache(redisClient redis.UniversalClient) CommentBlockCache {
+	return &redisCommentBlockCache{client: redisClient}
+}
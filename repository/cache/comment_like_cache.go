@@ -0,0 +1,13 @@
+package cache
+
+import "github.com/redis/go-redis/v9"
+
+// redisCommentLikeCache 用 Redis 实现 CommentLikeCache
+type redisCommentLikeCache struct {
+	client redis.UniversalClient
+}
+
+// NewCommentLikeCache 构造函数
+func NewCommentLikeCache(redisClient redis.UniversalClient) CommentLikeCache {
+	return &redisCommentLikeCache{client: redisClient}
+}
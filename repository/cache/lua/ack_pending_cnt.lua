@@ -0,0 +1,19 @@
+local key = KEYS[1]               -- 帖子 / 投票待落库增量 key
+local dirty = KEYS[2]             -- 待落库帖子 / 投票集合
+local pid = ARGV[1]               -- 帖子 / 投票 ID
+
+-- ARGV[2..] 为 field, delta 交替排列, 扣除已落库的增量
+for i = 2, #ARGV, 2 do
+    redis.call("HINCRBY", key, ARGV[i], -tonumber(ARGV[i + 1]))
+end
+
+-- 落库期间没有新增量时清空并移出待落库集合
+local vals = redis.call("HVALS", key)
+for _, val in ipairs(vals) do
+    if tonumber(val) ~= 0 then
+        return 0
+    end
+end
+redis.call("DEL", key)
+redis.call("SREM", dirty, pid)
+return 1
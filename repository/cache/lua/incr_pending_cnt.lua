@@ -0,0 +1,9 @@
+local key = KEYS[1]               -- 帖子待落库增量 key
+local dirty = KEYS[2]             -- 待落库帖子集合
+local field = ARGV[1]             -- 字段
+local delta = tonumber(ARGV[2])   -- 修改值
+local pid = ARGV[3]               -- 帖子 ID
+
+redis.call("HINCRBY", key, field, delta)
+redis.call("SADD", dirty, pid)    -- 标记为待落库
+return 1
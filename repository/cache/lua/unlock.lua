@@ -0,0 +1,8 @@
+local key = KEYS[1]               -- 锁 key
+local token = ARGV[1]             -- 加锁时写入的令牌
+
+-- 仅释放自己持有的锁
+if redis.call("GET", key) == token then
+    return redis.call("DEL", key)
+end
+return 0
@@ -0,0 +1,17 @@
+local voters = KEYS[1]            -- 投票人集合
+local key = KEYS[2]               -- 投票待落库增量 key
+local dirty = KEYS[3]             -- 待落库投票集合
+local uid = ARGV[1]               -- 投票人 ID
+local pollID = ARGV[2]            -- 投票 ID
+
+-- 已经投过票时不计数
+if redis.call("SADD", voters, uid) == 0 then
+    return 0
+end
+
+redis.call("HINCRBY", key, "voters", 1)
+for i = 3, #ARGV do               -- 其余参数为选项 ID
+    redis.call("HINCRBY", key, ARGV[i], 1)
+end
+redis.call("SADD", dirty, pollID) -- 标记为待落库
+return 1
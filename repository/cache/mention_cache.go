@@ -0,0 +1,13 @@
+package cache
+
+import "github.com/redis/go-redis/v9"
+
+// redisMentionCache 用 Redis 实现 MentionCache
+type redisMentionCache struct {
+	client redis.UniversalClient
+}
+
+// NewMentionCache 构造函数
+func NewMentionCache(redisClient redis.UniversalClient) MentionCache {
+	return &redisMentionCache{client: redisClient}
+}
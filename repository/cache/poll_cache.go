@@ -0,0 +1,151 @@
+package cache
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/yzletter/go-postery/model"
+)
+
+const (
+	pollVotersKeyPrefix     = "poll:voters"
+	pollPendingCntKeyPrefix = "poll:cnt:pending"
+	pollPendingCntDirtyKey  = "poll:cnt:dirty"
+	pollPendingCntLockKey   = "poll:cnt:lock"
+	pollVotersField         = "voters"
+)
+
+//go:embed lua/vote_poll.lua
+var votePollScript string
+
+// redisPollCache 用 Redis 实现 PollCache
+type redisPollCache struct {
+	client redis.UniversalClient
+}
+
+// NewPollCache 构造函数
+func NewPollCache(redisClient redis.UniversalClient) PollCache {
+	return &redisPollCache{client: redisClient}
+}
+
+// Vote 记录投票人并累加待落库的得票数, 返回是否为首次投票
+func (cache *redisPollCache) Vote(ctx context.Context, pollID, uid int64, optionIDs []int64) (bool, error) {
+	keys := []string{pollVotersKey(pollID), pollPendingCntKey(pollID), pollPendingCntDirtyKey}
+	args := make([]any, 0, len(optionIDs)+2)
+	args = append(args, uid, pollID)
+	for _, optionID := range optionIDs {
+		args = append(args, optionID)
+	}
+	return cache.client.Eval(ctx, votePollScript, keys, args...).Bool()
+}
+
+// RevertVote 撤销 Vote 记录的投票人和得票数, 用于落库选票失败时回滚
+func (cache *redisPollCache) RevertVote(ctx context.Context, pollID, uid int64, optionIDs []int64) error {
+	key := pollPendingCntKey(pollID)
+	pipe := cache.client.TxPipeline()
+	pipe.SRem(ctx, pollVotersKey(pollID), uid)
+	pipe.HIncrBy(ctx, key, pollVotersField, -1)
+	for _, optionID := range optionIDs {
+		pipe.HIncrBy(ctx, key, strconv.FormatInt(optionID, 10), -1)
+	}
+	pipe.SAdd(ctx, pollPendingCntDirtyKey, pollID)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// GetPendingCnt 获取投票尚未落库的计数增量
+func (cache *redisPollCache) GetPendingCnt(ctx context.Context, pollID int64) (*model.PollCnt, error) {
+	mp, err := cache.client.HGetAll(ctx, pollPendingCntKey(pollID)).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+	return parsePollCnt(mp), nil
+}
+
+// PeekPendingCnt 读取至多 batchSize 个投票的待落库增量, 不清空 Redis, 落库成功后由 AckPendingCnt 扣除
+func (cache *redisPollCache) PeekPendingCnt(ctx context.Context, batchSize int) (map[int64]*model.PollCnt, error) {
+	members, err := cache.client.SRandMemberN(ctx, pollPendingCntDirtyKey, int64(batchSize)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(map[int64]*model.PollCnt, len(members))
+	for _, member := range members {
+		pollID, err := strconv.ParseInt(member, 10, 64)
+		if err != nil {
+			cache.client.SRem(ctx, pollPendingCntDirtyKey, member)
+			continue
+		}
+
+		cnt, err := cache.GetPendingCnt(ctx, pollID)
+		if err != nil {
+			return res, err
+		}
+		if cnt.Voters != 0 || len(cnt.Options) > 0 {
+			res[pollID] = cnt
+			continue
+		}
+		// 增量已相互抵消的投票直接移出待落库集合, 避免反复被读到
+		if err := cache.AckPendingCnt(ctx, pollID, cnt); err != nil {
+			return res, err
+		}
+	}
+	return res, nil
+}
+
+// AckPendingCnt 落库成功后扣除已落库的增量, 扣除后无剩余增量时移出待落库集合
+func (cache *redisPollCache) AckPendingCnt(ctx context.Context, pollID int64, cnt *model.PollCnt) error {
+	args := make([]any, 0, 3+2*len(cnt.Options))
+	args = append(args, pollID, pollVotersField, cnt.Voters)
+	for optionID, delta := range cnt.Options {
+		args = append(args, strconv.FormatInt(optionID, 10), delta)
+	}
+	keys := []string{pollPendingCntKey(pollID), pollPendingCntDirtyKey}
+	return cache.client.Eval(ctx, ackPendingCntScript, keys, args...).Err()
+}
+
+// LockPendingCnt 获取得票数落库任务的互斥锁, 锁在 ttl 后自动过期
+func (cache *redisPollCache) LockPendingCnt(ctx context.Context, token string, ttl time.Duration) (bool, error) {
+	return cache.client.SetNX(ctx, pollPendingCntLockKey, token, ttl).Result()
+}
+
+// UnlockPendingCnt 释放得票数落库任务的互斥锁, 只释放 token 对应的锁
+func (cache *redisPollCache) UnlockPendingCnt(ctx context.Context, token string) error {
+	return cache.client.Eval(ctx, unlockScript, []string{pollPendingCntLockKey}, token).Err()
+}
+
+// pollVotersKey 拼接投票人集合 Key
+func pollVotersKey(pollID int64) string {
+	return fmt.Sprintf("%s:%d", pollVotersKeyPrefix, pollID)
+}
+
+// pollPendingCntKey 拼接投票待落库增量 Key
+func pollPendingCntKey(pollID int64) string {
+	return fmt.Sprintf("%s:%d", pollPendingCntKeyPrefix, pollID)
+}
+
+// parsePollCnt 将 Redis Hash 解析为投票人数和各选项的得票数增量
+func parsePollCnt(mp map[string]string) *model.PollCnt {
+	cnt := &model.PollCnt{Options: make(map[int64]int, len(mp))}
+	for field, val := range mp {
+		delta, err := strconv.Atoi(val)
+		if err != nil || delta == 0 {
+			continue
+		}
+		if field == pollVotersField {
+			cnt.Voters = delta
+			continue
+		}
+		optionID, err := strconv.ParseInt(field, 10, 64)
+		if err != nil {
+			continue
+		}
+		cnt.Options[optionID] = delta
+	}
+	return cnt
+}
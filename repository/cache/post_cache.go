@@ -3,6 +3,7 @@ package cache
 import (
 	"context"
 	_ "embed"
+	"errors"
 	"fmt"
 	"strconv"
 	"time"
@@ -12,12 +13,22 @@ import (
 )
 
 const (
-	postInteractiveKeyPrefix = "post:interactive"
-	PostExpireTime           = time.Minute * 15
+	postPendingCntKeyPrefix = "post:cnt:pending"
+	postPendingCntDirtyKey  = "post:cnt:dirty"
+	postPendingCntLockKey   = "post:cnt:lock"
+	postViewedKeyPrefix     = "post:viewed"
+	hotStagingSuffix        = ":staging"
+	hotStagingExpireTime    = time.Hour
 )
 
-//go:embed lua/change_cnt_script.lua
-var addCntScript string
+//go:embed lua/incr_pending_cnt.lua
+var incrPendingCntScript string
+
+//go:embed lua/ack_pending_cnt.lua
+var ackPendingCntScript string
+
+//go:embed lua/unlock.lua
+var unlockScript string
 
 type redisPostCache struct {
 	client redis.UniversalClient
@@ -27,20 +38,66 @@ func NewPostCache(client redis.UniversalClient) PostCache {
 	return &redisPostCache{client: client}
 }
 
-func (cache *redisPostCache) DeleteScore(ctx context.Context, id int64) error {
-	_, err := cache.client.ZRem(ctx, model.KeyPostScore, id).Result()
-	if err != nil {
-		return err
+// RemoveHot 将帖子从所有热榜中移除
+func (cache *redisPostCache) RemoveHot(ctx context.Context, pid int64) error {
+	keys := []string{model.KeyPostScore}
+	for _, window := range model.HotWindows {
+		boards, err := cache.client.SMembers(ctx, model.HotBoardsKey(window)).Result()
+		if err != nil {
+			return err
+		}
+		keys = append(keys, boards...)
+	}
+
+	pipe := cache.client.Pipeline()
+	for _, key := range keys {
+		pipe.ZRem(ctx, key, strconv.FormatInt(pid, 10))
 	}
-	_, err = cache.client.ZRem(ctx, model.KeyPostTime, id).Result()
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// GetRelated 按分数从高到低获取帖子的相关推荐, 未计算过或已过期时为空
+func (cache *redisPostCache) GetRelated(ctx context.Context, pid int64) ([]int64, error) {
+	members, err := cache.client.ZRevRange(ctx, model.RelatedKey(pid), 0, -1).Result()
 	if err != nil {
-		return err
+		return nil, err
+	}
+	ids := make([]int64, 0, len(members))
+	for _, member := range members {
+		id, err := strconv.ParseInt(member, 10, 64)
+		if err != nil {
+			continue
+		}
+		ids = append(ids, id)
 	}
+	return ids, nil
+}
+
+// SetRelated 覆盖帖子的相关推荐, 只保留分数最高的 limit 个; 没有相关帖子时删除旧结果
+func (cache *redisPostCache) SetRelated(ctx context.Context, pid int64, scores map[int64]float64, limit int, expiration time.Duration) error {
+	key := model.RelatedKey(pid)
+	if len(scores) == 0 {
+		return cache.client.Del(ctx, key).Err()
+	}
+
+	members := make([]redis.Z, 0, len(scores))
+	for id, score := range scores {
+		members = append(members, redis.Z{Score: score, Member: id})
+	}
+
+	pipe := cache.client.TxPipeline()
+	pipe.Del(ctx, key)
+	pipe.ZAdd(ctx, key, members...)
+	pipe.ZRemRangeByRank(ctx, key, 0, int64(-limit-1)) // 去掉排名 limit 之后的
+	pipe.Expire(ctx, key, expiration)
+	_, err := pipe.Exec(ctx)
 	return err
 }
 
-func (cache *redisPostCache) Top(ctx context.Context) ([]int64, []float64, error) {
-	pairs, err := cache.client.ZRevRangeWithScores(ctx, model.KeyPostScore, 0, 9).Result()
+// Top 获取热榜前 limit 名
+func (cache *redisPostCache) Top(ctx context.Context, key string, limit int) ([]int64, []float64, error) {
+	pairs, err := cache.client.ZRevRangeWithScores(ctx, key, 0, int64(limit-1)).Result()
 	if err != nil {
 		return nil, nil, err
 	}
@@ -59,59 +116,198 @@ func (cache *redisPostCache) Top(ctx context.Context) ([]int64, []float64, error
 	return ids, scores, nil
 }
 
-func (cache *redisPostCache) ChangeScore(ctx context.Context, pid int64, delta int) error {
-	_, err := cache.client.ZIncrBy(ctx, model.KeyPostScore, float64(delta), strconv.FormatInt(pid, 10)).Result()
+// StageHot 将分数写入热榜的暂存 Key, 只保留分数最高的 limit 个; fresh 时先清掉上一轮残留的暂存数据
+func (cache *redisPostCache) StageHot(ctx context.Context, key string, scores map[int64]float64, limit int, fresh bool) error {
+	if len(scores) == 0 {
+		return nil
+	}
+
+	members := make([]redis.Z, 0, len(scores))
+	for pid, score := range scores {
+		members = append(members, redis.Z{Score: score, Member: pid})
+	}
+
+	stagingKey := key + hotStagingSuffix
+	pipe := cache.client.TxPipeline()
+	if fresh {
+		pipe.Del(ctx, stagingKey)
+	}
+	pipe.ZAdd(ctx, stagingKey, members...)
+	pipe.ZRemRangeByRank(ctx, stagingKey, 0, int64(-limit-1)) // 去掉排名 limit 之后的
+	pipe.Expire(ctx, stagingKey, hotStagingExpireTime)
+	_, err := pipe.Exec(ctx)
 	return err
 }
 
-func (cache *redisPostCache) CheckPostLikeTime(ctx context.Context, pid int64) (float64, error) {
-	return cache.client.ZScore(ctx, model.KeyPostTime, strconv.Itoa(int(pid))).Result()
+// PublishHot 用暂存 Key 替换 boardsKey 登记的线上热榜, 本轮没有暂存的旧榜单会被删除
+func (cache *redisPostCache) PublishHot(ctx context.Context, boardsKey string, keys []string) error {
+	oldKeys, err := cache.client.SMembers(ctx, boardsKey).Result()
+	if err != nil {
+		return err
+	}
+
+	// 暂存 Key 不存在时 RENAME 会失败, 视为空榜
+	staged := make([]string, 0, len(keys))
+	for _, key := range keys {
+		n, err := cache.client.Exists(ctx, key+hotStagingSuffix).Result()
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			staged = append(staged, key)
+		}
+	}
+
+	current := make(map[string]struct{}, len(staged))
+	pipe := cache.client.TxPipeline()
+	for _, key := range staged {
+		current[key] = struct{}{}
+		pipe.Rename(ctx, key+hotStagingSuffix, key)
+	}
+	for _, key := range oldKeys {
+		if _, ok := current[key]; !ok {
+			pipe.Del(ctx, key)
+		}
+	}
+	pipe.Del(ctx, boardsKey)
+	if len(staged) > 0 {
+		members := make([]any, 0, len(staged))
+		for _, key := range staged {
+			members = append(members, key)
+		}
+		pipe.SAdd(ctx, boardsKey, members...)
+	}
+	_, err = pipe.Exec(ctx)
+	return err
 }
 
-// ChangeInteractiveCnt HIncrBy KEY 对应 Field 的值, 值为 Delta
-func (cache *redisPostCache) ChangeInteractiveCnt(ctx context.Context, pid int64, field model.PostCntField, delta int) (bool, error) {
+// IncrPendingCnt 累加帖子尚未落库的互动计数增量, 并将帖子标记为待落库
+func (cache *redisPostCache) IncrPendingCnt(ctx context.Context, pid int64, field model.PostCntField, delta int) error {
 	col, err := field.Column()
 	if err != nil {
-		return false, err
+		return err
 	}
-	redisKey := fmt.Sprintf("%s:%d", postInteractiveKeyPrefix, pid)
-	return cache.client.Eval(ctx, addCntScript, []string{redisKey}, col, delta).Bool()
+	keys := []string{pendingCntKey(pid), postPendingCntDirtyKey}
+	return cache.client.Eval(ctx, incrPendingCntScript, keys, col, delta, pid).Err()
 }
 
-// SetInteractiveKey 设置 Post 的 浏览、点赞、评论 Key
-func (cache *redisPostCache) SetInteractiveKey(ctx context.Context, pid int64, fields []model.PostCntField, vals []int) {
-	// 拼接 Key
-	redisKey := fmt.Sprintf("%s:%d", postInteractiveKeyPrefix, pid)
+// GetPendingCnt 批量获取帖子尚未落库的互动计数增量
+func (cache *redisPostCache) GetPendingCnt(ctx context.Context, pids []int64) (map[int64]map[model.PostCntField]int, error) {
+	res := make(map[int64]map[model.PostCntField]int, len(pids))
+	if len(pids) == 0 {
+		return res, nil
+	}
 
-	// 设置 Key
-	mp := make(map[string]interface{})
-	for k, v := range fields {
-		s, _ := v.Column()
-		mp[s] = vals[k]
+	// Pipeline 批量 HGETALL
+	pipe := cache.client.Pipeline()
+	cmds := make([]*redis.MapStringStringCmd, 0, len(pids))
+	for _, pid := range pids {
+		cmds = append(cmds, pipe.HGetAll(ctx, pendingCntKey(pid)))
+	}
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
 	}
-	cache.client.HSet(ctx, redisKey, mp)
 
-	// 设置过期时间
-	cache.client.Expire(ctx, redisKey, PostExpireTime)
+	for k, cmd := range cmds {
+		if deltas := parsePendingCnt(cmd.Val()); len(deltas) > 0 {
+			res[pids[k]] = deltas
+		}
+	}
+	return res, nil
 }
 
-// SetScore 设置初始分数
-func (cache *redisPostCache) SetScore(ctx context.Context, pid int64) error {
-	_, err := cache.client.ZAdd(ctx, model.KeyPostScore, redis.Z{
-		Score:  float64(time.Now().Unix()),
-		Member: pid,
-	}).Result()
+// PeekPendingCnt 读取至多 batchSize 个帖子的待落库增量, 不清空 Redis, 落库成功后由 AckPendingCnt 扣除
+func (cache *redisPostCache) PeekPendingCnt(ctx context.Context, batchSize int) (map[int64]map[model.PostCntField]int, error) {
+	members, err := cache.client.SRandMemberN(ctx, postPendingCntDirtyKey, int64(batchSize)).Result()
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	pids := make([]int64, 0, len(members))
+	for _, member := range members {
+		pid, err := strconv.ParseInt(member, 10, 64)
+		if err != nil {
+			cache.client.SRem(ctx, postPendingCntDirtyKey, member)
+			continue
+		}
+		pids = append(pids, pid)
 	}
 
-	_, err = cache.client.ZAdd(ctx, model.KeyPostTime, redis.Z{
-		Score:  float64(time.Now().Unix()),
-		Member: pid,
-	}).Result()
+	res, err := cache.GetPendingCnt(ctx, pids)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	// 增量已相互抵消的帖子直接移出待落库集合, 避免反复被读到
+	for _, pid := range pids {
+		if _, ok := res[pid]; ok {
+			continue
+		}
+		if err := cache.AckPendingCnt(ctx, pid, nil); err != nil {
+			return res, err
+		}
 	}
+	return res, nil
+}
 
-	return nil
+// AckPendingCnt 落库成功后扣除已落库的增量, 扣除后无剩余增量时移出待落库集合
+func (cache *redisPostCache) AckPendingCnt(ctx context.Context, pid int64, deltas map[model.PostCntField]int) error {
+	args := make([]any, 0, 1+2*len(deltas))
+	args = append(args, pid)
+	for field, delta := range deltas {
+		col, err := field.Column()
+		if err != nil {
+			return err
+		}
+		args = append(args, col, delta)
+	}
+	keys := []string{pendingCntKey(pid), postPendingCntDirtyKey}
+	return cache.client.Eval(ctx, ackPendingCntScript, keys, args...).Err()
+}
+
+// DeletePendingCnt 删除帖子的待落库增量
+func (cache *redisPostCache) DeletePendingCnt(ctx context.Context, pid int64) error {
+	pipe := cache.client.TxPipeline()
+	pipe.Del(ctx, pendingCntKey(pid))
+	pipe.SRem(ctx, postPendingCntDirtyKey, pid)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// LockPendingCnt 获取互动计数落库 / 校正任务的互斥锁, 锁在 ttl 后自动过期
+func (cache *redisPostCache) LockPendingCnt(ctx context.Context, token string, ttl time.Duration) (bool, error) {
+	return cache.client.SetNX(ctx, postPendingCntLockKey, token, ttl).Result()
+}
+
+// UnlockPendingCnt 释放互动计数任务的互斥锁, 只释放 token 对应的锁
+func (cache *redisPostCache) UnlockPendingCnt(ctx context.Context, token string) error {
+	return cache.client.Eval(ctx, unlockScript, []string{postPendingCntLockKey}, token).Err()
+}
+
+// MarkViewed 记录 viewer 在 window 内浏览过帖子, 返回是否为窗口内的首次浏览
+func (cache *redisPostCache) MarkViewed(ctx context.Context, pid int64, viewer string, window time.Duration) (bool, error) {
+	redisKey := fmt.Sprintf("%s:%d:%s", postViewedKeyPrefix, pid, viewer)
+	return cache.client.SetNX(ctx, redisKey, 1, window).Result()
+}
+
+// pendingCntKey 拼接帖子待落库增量 Key
+func pendingCntKey(pid int64) string {
+	return fmt.Sprintf("%s:%d", postPendingCntKeyPrefix, pid)
+}
+
+// parsePendingCnt 将 Redis Hash 解析为 PostCntField -> 增量
+func parsePendingCnt(mp map[string]string) map[model.PostCntField]int {
+	deltas := make(map[model.PostCntField]int, len(mp))
+	for col, val := range mp {
+		field, err := model.ParsePostCntField(col)
+		if err != nil {
+			continue
+		}
+		delta, err := strconv.Atoi(val)
+		if err != nil || delta == 0 {
+			continue
+		}
+		deltas[field] = delta
+	}
+	return deltas
 }
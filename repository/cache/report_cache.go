@@ -0,0 +1,13 @@
+package cache
+
+import "github.com/redis/go-redis/v9"
+
+// redisReportCache 用 Redis 实现 ReportCache
+type redisReportCache struct {
+	client redis.UniversalClient
+}
+
+// NewReportCache 构造函数
+func NewReportCache(redisClient redis.UniversalClient) ReportCache {
+	return &redisReportCache{client: redisClient}
+}
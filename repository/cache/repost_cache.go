@@ -0,0 +1,13 @@
+package cache
+
+import "github.com/redis/go-redis/v9"
+
+// redisRepostCache 用 Redis 实现 RepostCache
+type redisRepostCache struct {
+	client redis.UniversalClient
+}
+
+// NewRepostCache 构造函数
+func NewRepostCache(redisClient redis.UniversalClient) RepostCache {
+	return &redisRepostCache{client: redisClient}
+}
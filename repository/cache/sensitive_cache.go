@@ -0,0 +1,36 @@
+package cache
+
+import (
+	"context"
+	"errors"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/yzletter/go-postery/model"
+)
+
+// redisSensitiveCache 用 Redis 实现 SensitiveCache
+type redisSensitiveCache struct {
+	client redis.UniversalClient
+}
+
+// NewSensitiveCache 构造函数
+func NewSensitiveCache(redisClient redis.UniversalClient) SensitiveCache {
+	return &redisSensitiveCache{client: redisClient}
+}
+
+// GetVersion 获取敏感词库版本号, 从未变更过时为 0
+func (cache *redisSensitiveCache) GetVersion(ctx context.Context) (int64, error) {
+	version, err := cache.client.Get(ctx, model.KeySensitiveVersion).Int64()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return version, nil
+}
+
+// IncrVersion 敏感词库变更后自增版本号, 通知所有实例重新加载
+func (cache *redisSensitiveCache) IncrVersion(ctx context.Context) error {
+	return cache.client.Incr(ctx, model.KeySensitiveVersion).Err()
+}
@@ -0,0 +1,13 @@
+package cache
+
+import "github.com/redis/go-redis/v9"
+
+// redisSeriesCache 用 Redis 实现 SeriesCache
+type redisSeriesCache struct {
+	client redis.UniversalClient
+}
+
+// NewSeriesCache 构造函数
+func NewSeriesCache(redisClient redis.UniversalClient) SeriesCache {
+	return &redisSeriesCache{client: redisClient}
+}
@@ -1,6 +1,18 @@
 package cache
 
-import "github.com/redis/go-redis/v9"
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	postTagsKeyPrefix  = "post:tags"
+	postTagsExpireTime = 30 * time.Minute
+)
 
 // redisTagCache 用 Redis 实现 TagCache
 type redisTagCache struct {
@@ -11,3 +23,64 @@ type redisTagCache struct {
 func NewTagCache(redisClient redis.UniversalClient) TagCache {
 	return &redisTagCache{client: redisClient}
 }
+
+// GetPostTags 用 MGET 批量读取帖子的 Tags, 未命中的帖子不出现在结果中
+func (cache *redisTagCache) GetPostTags(ctx context.Context, pids []int64) (map[int64][]string, error) {
+	res := make(map[int64][]string, len(pids))
+	if len(pids) == 0 {
+		return res, nil
+	}
+
+	keys := make([]string, 0, len(pids))
+	for _, pid := range pids {
+		keys = append(keys, postTagsKey(pid))
+	}
+	vals, err := cache.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	for k, val := range vals {
+		str, ok := val.(string)
+		if !ok {
+			continue
+		}
+		var tags []string
+		if err := json.Unmarshal([]byte(str), &tags); err != nil {
+			continue
+		}
+		res[pids[k]] = tags
+	}
+	return res, nil
+}
+
+// SetPostTags 批量写入帖子的 Tags, 没有 Tag 的帖子也会写入空列表防止穿透
+func (cache *redisTagCache) SetPostTags(ctx context.Context, tags map[int64][]string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	pipe := cache.client.Pipeline()
+	for pid, names := range tags {
+		if names == nil {
+			names = []string{}
+		}
+		data, err := json.Marshal(names)
+		if err != nil {
+			return err
+		}
+		pipe.Set(ctx, postTagsKey(pid), data, postTagsExpireTime)
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// DeletePostTags 删除帖子的 Tags 缓存
+func (cache *redisTagCache) DeletePostTags(ctx context.Context, pid int64) error {
+	return cache.client.Del(ctx, postTagsKey(pid)).Err()
+}
+
+// postTagsKey 拼接帖子 Tags Key
+func postTagsKey(pid int64) string {
+	return fmt.Sprintf("%s:%d", postTagsKeyPrefix, pid)
+}
@@ -2,12 +2,20 @@ package cache
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/redis/go-redis/v9"
 	"github.com/yzletter/go-postery/model"
 )
 
+const (
+	userInfoKeyPrefix  = "user:info"
+	userInfoExpireTime = 30 * time.Minute
+)
+
 // redisUserCache 用 Redis 实现 UserCache
 type redisUserCache struct {
 	client redis.UniversalClient
@@ -41,3 +49,62 @@ func (cache *redisUserCache) ChangeScore(ctx context.Context, pid int64, delta i
 	_, err := cache.client.ZIncrBy(ctx, model.KeyUserScore, float64(delta), strconv.FormatInt(pid, 10)).Result()
 	return err
 }
+
+// GetUsers 用 MGET 批量读取用户信息, 未命中的 ID 不出现在结果中
+func (cache *redisUserCache) GetUsers(ctx context.Context, ids []int64) (map[int64]*model.User, error) {
+	res := make(map[int64]*model.User, len(ids))
+	if len(ids) == 0 {
+		return res, nil
+	}
+
+	keys := make([]string, 0, len(ids))
+	for _, id := range ids {
+		keys = append(keys, userInfoKey(id))
+	}
+	vals, err := cache.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	for k, val := range vals {
+		str, ok := val.(string)
+		if !ok {
+			continue
+		}
+		user := &model.User{}
+		if err := json.Unmarshal([]byte(str), user); err != nil {
+			continue
+		}
+		res[ids[k]] = user
+	}
+	return res, nil
+}
+
+// SetUsers 批量写入用户信息, 不缓存密码哈希
+func (cache *redisUserCache) SetUsers(ctx context.Context, users []*model.User) error {
+	if len(users) == 0 {
+		return nil
+	}
+
+	pipe := cache.client.Pipeline()
+	for _, user := range users {
+		// PasswordHash 带有 json:"-", 不会被序列化
+		data, err := json.Marshal(user)
+		if err != nil {
+			return err
+		}
+		pipe.Set(ctx, userInfoKey(user.ID), data, userInfoExpireTime)
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// DeleteUser 删除用户信息缓存
+func (cache *redisUserCache) DeleteUser(ctx context.Context, id int64) error {
+	return cache.client.Del(ctx, userInfoKey(id)).Err()
+}
+
+// userInfoKey 拼接用户信息 Key
+func userInfoKey(id int64) string {
+	return fmt.Sprintf("%s:%d", userInfoKeyPrefix, id)
+}
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/yzletter/go-postery/model"
+	"github.com/yzletter/go-postery/repository/cache"
+	"github.com/yzletter/go-postery/repository/dao"
+)
+
+type commentBlockRepository struct {
+	dao   dao.CommentBlockDAO
+	cache cache.CommentBlockCache
+}
+
+func NewCommentBlockRepository(commentBlockDAO dao.CommentBlockDAO, commentBlockCache cache.CommentBlockCache) CommentBlockRepository {
+	return &commentBlockRepository{dao: commentBlockDAO, cache: commentBlockCache}
+}
+
+func (repo *commentBlockRepository) Block(ctx context.Context, block *model.CommentBlock) error {
+	err := repo.dao.Create(ctx, block)
+	if err != nil {
+		return toRepositoryErr(err)
+	}
+	return nil
+}
+
+func (repo *commentBlockRepository) UnBlock(ctx context.Context, authorID, uid int64) error {
+	err := repo.dao.Delete(ctx, authorID, uid)
+	if err != nil {
+		return toRepositoryErr(err)
+	}
+	return nil
+}
+
+func (repo *commentBlockRepository) Exists(ctx context.Context, authorID, uid int64) (bool, error) {
+	ok, err := repo.dao.Exists(ctx, authorID, uid)
+	if err != nil {
+		return false, toRepositoryErr(err)
+	}
+	return ok, nil
+}
+
+func (repo *commentBlockRepository) GetByAuthor(ctx context.Context, authorID int64, pageNo, pageSize int) (int64, []int64, error) {
+	total, uids, err := repo.dao.GetByAuthor(ctx, authorID, pageNo, pageSize)
+	if err != nil {
+		return 0, nil, toRepositoryErr(err)
+	}
+	return total, uids, nil
+}
@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/yzletter/go-postery/model"
+	"github.com/yzletter/go-postery/repository/cache"
+	"github.com/yzletter/go-postery/repository/dao"
+)
+
+type commentLikeRepository struct {
+	dao   dao.CommentLikeDAO
+	cache cache.CommentLikeCache
+}
+
+func NewCommentLikeRepository(commentLikeDAO dao.CommentLikeDAO, commentLikeCache cache.CommentLikeCache) CommentLikeRepository {
+	return &commentLikeRepository{dao: commentLikeDAO, cache: commentLikeCache}
+}
+
+func (repo *commentLikeRepository) Like(ctx context.Context, like *model.CommentLike) error {
+	err := repo.dao.Create(ctx, like)
+	if err != nil {
+		return toRepositoryErr(err)
+	}
+	return nil
+}
+
+func (repo *commentLikeRepository) UnLike(ctx context.Context, uid, cid int64) error {
+	err := repo.dao.Delete(ctx, uid, cid)
+	if err != nil {
+		return toRepositoryErr(err)
+	}
+	return nil
+}
+
+func (repo *commentLikeRepository) GetLikedIDs(ctx context.Context, uid int64, cids []int64) (map[int64]bool, error) {
+	liked, err := repo.dao.GetLikedIDs(ctx, uid, cids)
+	if err != nil {
+		return nil, toRepositoryErr(err)
+	}
+	return liked, nil
+}
@@ -44,8 +44,44 @@ func (repo *commentRepository) Delete(ctx context.Context, id int64) (int, error
 	return cnt, nil
 }
 
-func (repo *commentRepository) GetByPostID(ctx context.Context, id int64, pageNo, pageSize int) (int64, []*model.Comment, error) {
-	total, comments, err := repo.dao.GetByPostID(ctx, id, pageNo, pageSize)
+func (repo *commentRepository) UpdateStatus(ctx context.Context, id int64, status int) error {
+	err := repo.dao.UpdateStatus(ctx, id, status)
+	if err != nil {
+		return toRepositoryErr(err)
+	}
+
+	return nil
+}
+
+func (repo *commentRepository) UpdateLikeCount(ctx context.Context, id int64, delta int) error {
+	err := repo.dao.UpdateLikeCount(ctx, id, delta)
+	if err != nil {
+		return toRepositoryErr(err)
+	}
+
+	return nil
+}
+
+func (repo *commentRepository) Edit(ctx context.Context, revision *model.CommentRevision, content string, status int) error {
+	err := repo.dao.Edit(ctx, revision, content, status)
+	if err != nil {
+		return toRepositoryErr(err)
+	}
+
+	return nil
+}
+
+func (repo *commentRepository) GetRevisions(ctx context.Context, id int64) ([]*model.CommentRevision, error) {
+	revisions, err := repo.dao.GetRevisions(ctx, id)
+	if err != nil {
+		return nil, toRepositoryErr(err)
+	}
+
+	return revisions, nil
+}
+
+func (repo *commentRepository) GetByPostID(ctx context.Context, id int64, excludeIDs []int64, sort model.CommentSort, offset, limit int) (int64, []*model.Comment, error) {
+	total, comments, err := repo.dao.GetByPostID(ctx, id, excludeIDs, sort, offset, limit)
 	if err != nil {
 		return 0, nil, toRepositoryErr(err)
 	}
@@ -53,11 +89,38 @@ func (repo *commentRepository) GetByPostID(ctx context.Context, id int64, pageNo
 	return total, comments, nil
 }
 
-func (repo *commentRepository) GetRepliesByParentID(ctx context.Context, id int64, pageNo, pageSize int) (int64, []*model.Comment, error) {
-	total, comments, err := repo.dao.GetRepliesByParentID(ctx, id, pageNo, pageSize)
+func (repo *commentRepository) GetRepliesByParentID(ctx context.Context, id int64, sort model.CommentSort, pageNo, pageSize int) (int64, []*model.Comment, error) {
+	total, comments, err := repo.dao.GetRepliesByParentID(ctx, id, sort, pageNo, pageSize)
 	if err != nil {
 		return 0, nil, toRepositoryErr(err)
 	}
 
 	return total, comments, nil
 }
+
+func (repo *commentRepository) GetUserIDs(ctx context.Context, ids []int64) (map[int64]int64, error) {
+	uids, err := repo.dao.GetUserIDs(ctx, ids)
+	if err != nil {
+		return nil, toRepositoryErr(err)
+	}
+
+	return uids, nil
+}
+
+func (repo *commentRepository) GetTopReplies(ctx context.Context, parentIDs []int64, sort model.CommentSort, limit int) (map[int64][]*model.Comment, error) {
+	replies, err := repo.dao.GetTopReplies(ctx, parentIDs, sort, limit)
+	if err != nil {
+		return nil, toRepositoryErr(err)
+	}
+
+	return replies, nil
+}
+
+func (repo *commentRepository) CountReplies(ctx context.Context, parentIDs []int64) (map[int64]int, error) {
+	cnts, err := repo.dao.CountReplies(ctx, parentIDs)
+	if err != nil {
+		return nil, toRepositoryErr(err)
+	}
+
+	return cnts, nil
+}
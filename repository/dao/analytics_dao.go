@@ -0,0 +1,168 @@
+package dao
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/yzletter/go-postery/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// analyticsSaveBatchSize 每条 INSERT 语句最多写入的行数
+const analyticsSaveBatchSize = 500
+
+// gormAnalyticsDAO 用 Gorm 实现 AnalyticsDAO
+type gormAnalyticsDAO struct {
+	db *gorm.DB
+}
+
+// NewAnalyticsDAO 构造函数
+func NewAnalyticsDAO(db *gorm.DB) AnalyticsDAO {
+	return &gormAnalyticsDAO{db: db}
+}
+
+// SaveBatch 将一批事件累加到统计表, 时间桶已存在时在原有计数上累加
+func (dao *gormAnalyticsDAO) SaveBatch(ctx context.Context, batch *model.AnalyticsBatch) error {
+	// 0. 兜底
+	if batch.Empty() {
+		return nil
+	}
+
+	// 1. 在事务中逐表累加
+	err := dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if len(batch.Posts) > 0 {
+			if err := upsertAdd(tx, batch.Posts, "view_count", "like_count", "comment_count", "bookmark_count"); err != nil {
+				return err
+			}
+		}
+		if len(batch.Referrers) > 0 {
+			if err := upsertAdd(tx, batch.Referrers, "view_count"); err != nil {
+				return err
+			}
+		}
+		if len(batch.Followers) > 0 {
+			if err := upsertAdd(tx, batch.Followers, "gained", "lost"); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		// 系统层面错误
+		slog.Error(CreateFailed, "posts", len(batch.Posts), "referrers", len(batch.Referrers), "followers", len(batch.Followers), "error", err)
+		return ErrServerInternal
+	}
+
+	// 2. 返回结果
+	return nil
+}
+
+// PruneHourly 删除 before 之前的小时粒度统计, 每次至多删除 limit 行, 返回删除的行数
+func (dao *gormAnalyticsDAO) PruneHourly(ctx context.Context, before time.Time, limit int) (int64, error) {
+	// 1. 操作数据库
+	result := dao.db.WithContext(ctx).Where("granularity = ? AND bucket < ?", model.AnalyticsHourly, before).
+		Limit(limit).Delete(&model.PostStat{})
+	if result.Error != nil {
+		// 系统层面错误
+		slog.Error(DeleteFailed, "before", before, "error", result.Error)
+		return 0, ErrServerInternal
+	}
+
+	// 2. 返回结果
+	return result.RowsAffected, nil
+}
+
+// GetPostSeries 按时间桶汇总作者帖子在 [from, to) 内的事件数, pid 非 0 时只统计该帖子
+func (dao *gormAnalyticsDAO) GetPostSeries(ctx context.Context, authorID, pid int64, granularity model.AnalyticsGranularity, from, to time.Time) ([]*model.PostStat, error) {
+	// 1. 操作数据库
+	var stats []*model.PostStat
+	result := dao.postStats(ctx, authorID, pid, granularity, from, to).
+		Select("bucket, SUM(view_count) AS view_count, SUM(like_count) AS like_count, " +
+			"SUM(comment_count) AS comment_count, SUM(bookmark_count) AS bookmark_count").
+		Group("bucket").Order("bucket ASC").Scan(&stats)
+	if result.Error != nil {
+		// 系统层面错误
+		slog.Error(FindFailed, "author_id", authorID, "post_id", pid, "error", result.Error)
+		return nil, ErrServerInternal
+	}
+
+	// 2. 返回结果
+	return stats, nil
+}
+
+// GetTopPosts 按帖子汇总作者在 [from, to) 内的事件数, 取浏览数最多的 limit 篇
+func (dao *gormAnalyticsDAO) GetTopPosts(ctx context.Context, authorID int64, granularity model.AnalyticsGranularity, from, to time.Time, limit int) ([]*model.PostStat, error) {
+	// 1. 操作数据库
+	var stats []*model.PostStat
+	result := dao.postStats(ctx, authorID, 0, granularity, from, to).
+		Select("post_id, SUM(view_count) AS view_count, SUM(like_count) AS like_count, " +
+			"SUM(comment_count) AS comment_count, SUM(bookmark_count) AS bookmark_count").
+		Group("post_id").Order("SUM(view_count) DESC, post_id DESC").Limit(limit).Scan(&stats)
+	if result.Error != nil {
+		// 系统层面错误
+		slog.Error(FindFailed, "author_id", authorID, "error", result.Error)
+		return nil, ErrServerInternal
+	}
+
+	// 2. 返回结果
+	return stats, nil
+}
+
+// GetReferrers 按来源汇总作者帖子在 [from, to) 内的浏览数, 取浏览数最多的 limit 个来源, pid 非 0 时只统计该帖子
+func (dao *gormAnalyticsDAO) GetReferrers(ctx context.Context, authorID, pid int64, from, to time.Time, limit int) ([]*model.PostReferrerStat, error) {
+	// 1. 操作数据库
+	query := dao.db.WithContext(ctx).Model(&model.PostReferrerStat{}).
+		Where("author_id = ? AND bucket >= ? AND bucket < ?", authorID, from, to)
+	if pid != 0 {
+		query = query.Where("post_id = ?", pid)
+	}
+	var stats []*model.PostReferrerStat
+	result := query.Select("referrer, SUM(view_count) AS view_count").
+		Group("referrer").Order("SUM(view_count) DESC, referrer ASC").Limit(limit).Scan(&stats)
+	if result.Error != nil {
+		// 系统层面错误
+		slog.Error(FindFailed, "author_id", authorID, "post_id", pid, "error", result.Error)
+		return nil, ErrServerInternal
+	}
+
+	// 2. 返回结果
+	return stats, nil
+}
+
+// GetFollowerSeries 获取用户在 [from, to) 内每天新增和流失的粉丝数
+func (dao *gormAnalyticsDAO) GetFollowerSeries(ctx context.Context, uid int64, from, to time.Time) ([]*model.FollowerStat, error) {
+	// 1. 操作数据库
+	var stats []*model.FollowerStat
+	result := dao.db.WithContext(ctx).Where("user_id = ? AND bucket >= ? AND bucket < ?", uid, from, to).
+		Order("bucket ASC").Find(&stats)
+	if result.Error != nil {
+		// 系统层面错误
+		slog.Error(FindFailed, "user_id", uid, "error", result.Error)
+		return nil, ErrServerInternal
+	}
+
+	// 2. 返回结果
+	return stats, nil
+}
+
+// postStats 帖子统计的公共筛选条件
+func (dao *gormAnalyticsDAO) postStats(ctx context.Context, authorID, pid int64, granularity model.AnalyticsGranularity, from, to time.Time) *gorm.DB {
+	query := dao.db.WithContext(ctx).Model(&model.PostStat{}).
+		Where("author_id = ? AND granularity = ? AND bucket >= ? AND bucket < ?", authorID, granularity, from, to)
+	if pid != 0 {
+		query = query.Where("post_id = ?", pid)
+	}
+	return query
+}
+
+// upsertAdd 批量插入统计行, 主键冲突时将 cols 累加到已有行
+func upsertAdd(tx *gorm.DB, rows any, cols ...string) error {
+	assignments := make(map[string]any, len(cols))
+	for _, col := range cols {
+		assignments[col] = gorm.Expr(col + " + VALUES(" + col + ")")
+	}
+	return tx.Clauses(clause.OnConflict{DoUpdates: clause.Assignments(assignments)}).
+		CreateInBatches(rows, analyticsSaveBatchSize).Error
+}
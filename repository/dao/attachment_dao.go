@@ -0,0 +1,244 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/yzletter/go-postery/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// gormAttachmentDAO 用 Gorm 实现 AttachmentDAO
+type gormAttachmentDAO struct {
+	db *gorm.DB
+}
+
+// NewAttachmentDAO 构造函数
+func NewAttachmentDAO(db *gorm.DB) AttachmentDAO {
+	return &gormAttachmentDAO{db: db}
+}
+
+// Create 创建 Attachment, 创建后用户的附件总容量不能超过 quota
+func (dao *gormAttachmentDAO) Create(ctx context.Context, attachment *model.Attachment, quota int64) error {
+	// 0. 兜底
+	if attachment == nil || attachment.ID == 0 || attachment.UserID == 0 || attachment.StorageKey == "" {
+		return ErrParamsInvalid
+	}
+
+	// 1. 操作数据库, 锁住用户记录使同一用户的上传串行执行, 避免并发上传同时通过配额校验
+	err := dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var uid int64
+		result := tx.Model(&model.User{}).Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id").Where("id = ?", attachment.UserID).Limit(1).Scan(&uid)
+		if result.Error != nil {
+			// 系统层面错误
+			slog.Error(FindFailed, "user_id", attachment.UserID, "error", result.Error)
+			return ErrServerInternal
+		}
+		if result.RowsAffected == 0 {
+			return ErrRecordNotFound
+		}
+
+		var used int64
+		result = tx.Model(&model.Attachment{}).Select("COALESCE(SUM(total_size), 0)").
+			Where("user_id = ? AND deleted_at IS NULL", attachment.UserID).Scan(&used)
+		if result.Error != nil {
+			// 系统层面错误
+			slog.Error(FindFailed, "user_id", attachment.UserID, "error", result.Error)
+			return ErrServerInternal
+		}
+		if used+attachment.TotalSize > quota {
+			return ErrLimitExceeded
+		}
+
+		result = tx.Create(attachment)
+		if result.Error != nil {
+			// 业务层面错误
+			var mysqlErr *mysql.MySQLError
+			if errors.As(result.Error, &mysqlErr) && mysqlErr.Number == 1062 {
+				return ErrUniqueKey
+			}
+
+			// 系统层面错误
+			slog.Error(CreateFailed, "attachment_id", attachment.ID, "error", result.Error)
+			return ErrServerInternal
+		}
+		return nil
+	})
+
+	// 2. 返回结果
+	return err
+}
+
+// GetByID 根据 ID 查找 Attachment
+func (dao *gormAttachmentDAO) GetByID(ctx context.Context, id int64) (*model.Attachment, error) {
+	attachment := &model.Attachment{}
+	result := dao.db.WithContext(ctx).Where("id = ? AND status = ? AND deleted_at IS NULL", id, model.AttachmentStatusNormal).First(attachment)
+	if result.Error != nil {
+		// 业务层面错误
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrRecordNotFound
+		}
+		// 系统层面错误
+		slog.Error(FindFailed, "id", id, "error", result.Error)
+		return nil, ErrServerInternal
+	}
+	return attachment, nil
+}
+
+// GetByPostID 查找帖子关联的所有 Attachment
+func (dao *gormAttachmentDAO) GetByPostID(ctx context.Context, pid int64) ([]*model.Attachment, error) {
+	var attachments []*model.Attachment
+	result := dao.db.WithContext(ctx).
+		Where("post_id = ? AND status = ? AND deleted_at IS NULL", pid, model.AttachmentStatusNormal).
+		Order("created_at ASC").Find(&attachments)
+	if result.Error != nil {
+		// 系统层面错误
+		slog.Error(FindFailed, "post_id", pid, "error", result.Error)
+		return nil, ErrServerInternal
+	}
+	return attachments, nil
+}
+
+// SumSizeByUid 统计用户已占用的附件容量, 待回收的附件在真正删除前仍占用配额
+func (dao *gormAttachmentDAO) SumSizeByUid(ctx context.Context, uid int64) (int64, error) {
+	var total int64
+	result := dao.db.WithContext(ctx).Model(&model.Attachment{}).Select("COALESCE(SUM(total_size), 0)").
+		Where("user_id = ? AND deleted_at IS NULL", uid).Scan(&total)
+	if result.Error != nil {
+		// 系统层面错误
+		slog.Error(FindFailed, "user_id", uid, "error", result.Error)
+		return 0, ErrServerInternal
+	}
+	return total, nil
+}
+
+// CountBindable 统计 ids 中可以关联到帖子 pid 的附件数, 即 uid 上传且未被其他帖子使用的附件
+func (dao *gormAttachmentDAO) CountBindable(ctx context.Context, uid, pid int64, ids []int64) (int64, error) {
+	var cnt int64
+	if len(ids) == 0 {
+		return cnt, nil
+	}
+
+	result := dao.db.WithContext(ctx).Model(&model.Attachment{}).
+		Where("id IN ? AND user_id = ? AND post_id IN ? AND status = ? AND deleted_at IS NULL",
+			ids, uid, []int64{0, pid}, model.AttachmentStatusNormal).
+		Count(&cnt)
+	if result.Error != nil {
+		// 系统层面错误
+		slog.Error(FindFailed, "user_id", uid, "post_id", pid, "ids", ids, "error", result.Error)
+		return 0, ErrServerInternal
+	}
+	return cnt, nil
+}
+
+// BindToPost 在同一事务中将帖子的附件设置为 ids, 不在 ids 中的旧附件解除关联
+func (dao *gormAttachmentDAO) BindToPost(ctx context.Context, uid, pid int64, ids []int64) error {
+	err := dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 1. 解除旧关联, 解除后成为孤儿附件等待回收
+		unbind := tx.Model(&model.Attachment{}).Where("post_id = ? AND deleted_at IS NULL", pid)
+		if len(ids) > 0 {
+			unbind = unbind.Where("id NOT IN ?", ids)
+		}
+		if result := unbind.Update("post_id", 0); result.Error != nil {
+			slog.Error(UpdateFailed, "post_id", pid, "error", result.Error)
+			return ErrServerInternal
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+
+		// 2. 建立新关联, 只能关联自己上传且未被其他帖子使用的附件
+		result := tx.Model(&model.Attachment{}).
+			Where("id IN ? AND user_id = ? AND post_id IN ? AND status = ? AND deleted_at IS NULL",
+				ids, uid, []int64{0, pid}, model.AttachmentStatusNormal).
+			Update("post_id", pid)
+		if result.Error != nil {
+			slog.Error(UpdateFailed, "post_id", pid, "ids", ids, "error", result.Error)
+			return ErrServerInternal
+		}
+
+		// 已经关联到该帖子的附件 MySQL 不计入影响行数, 需要重新统计
+		var cnt int64
+		result = tx.Model(&model.Attachment{}).Where("id IN ? AND post_id = ? AND deleted_at IS NULL", ids, pid).Count(&cnt)
+		if result.Error != nil {
+			slog.Error(FindFailed, "post_id", pid, "ids", ids, "error", result.Error)
+			return ErrServerInternal
+		}
+		if int(cnt) != len(ids) {
+			return ErrRecordNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, ErrRecordNotFound) {
+			return ErrRecordNotFound
+		}
+		return ErrServerInternal
+	}
+
+	return nil
+}
+
+// Discard 将用户的附件标记为待回收
+func (dao *gormAttachmentDAO) Discard(ctx context.Context, uid, id int64) error {
+	result := dao.db.WithContext(ctx).Model(&model.Attachment{}).
+		Where("id = ? AND user_id = ? AND status = ? AND deleted_at IS NULL", id, uid, model.AttachmentStatusNormal).
+		Update("status", model.AttachmentStatusDiscarded)
+	if result.Error != nil {
+		// 系统层面错误
+		slog.Error(UpdateFailed, "id", id, "user_id", uid, "error", result.Error)
+		return ErrServerInternal
+	} else if result.RowsAffected == 0 {
+		// 业务层面错误
+		return ErrRecordNotFound
+	}
+	return nil
+}
+
+// DiscardByPostID 将帖子关联的所有附件标记为待回收
+func (dao *gormAttachmentDAO) DiscardByPostID(ctx context.Context, pid int64) error {
+	result := dao.db.WithContext(ctx).Model(&model.Attachment{}).
+		Where("post_id = ? AND status = ? AND deleted_at IS NULL", pid, model.AttachmentStatusNormal).
+		Update("status", model.AttachmentStatusDiscarded)
+	if result.Error != nil {
+		// 系统层面错误
+		slog.Error(UpdateFailed, "post_id", pid, "error", result.Error)
+		return ErrServerInternal
+	}
+	return nil
+}
+
+// ListGarbage 查找待回收的附件, 以及在 orphanBefore 之前就已是孤儿的附件
+func (dao *gormAttachmentDAO) ListGarbage(ctx context.Context, orphanBefore time.Time, limit int) ([]*model.Attachment, error) {
+	var attachments []*model.Attachment
+	result := dao.db.WithContext(ctx).
+		Where("deleted_at IS NULL AND (status = ? OR (post_id = 0 AND updated_at < ?))", model.AttachmentStatusDiscarded, orphanBefore).
+		Order("id ASC").Limit(limit).Find(&attachments)
+	if result.Error != nil {
+		// 系统层面错误
+		slog.Error(FindFailed, "orphan_before", orphanBefore, "error", result.Error)
+		return nil, ErrServerInternal
+	}
+	return attachments, nil
+}
+
+// Delete 删除 Attachment
+func (dao *gormAttachmentDAO) Delete(ctx context.Context, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	result := dao.db.WithContext(ctx).Model(&model.Attachment{}).Where("id IN ? AND deleted_at IS NULL", ids).Update("deleted_at", &now)
+	if result.Error != nil {
+		// 系统层面错误
+		slog.Error(DeleteFailed, "ids", ids, "error", result.Error)
+		return ErrServerInternal
+	}
+	return nil
+}
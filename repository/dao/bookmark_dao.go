@@ -0,0 +1,351 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/yzletter/go-postery/model"
+	"gorm.io/gorm"
+)
+
+// gormBookmarkDAO 用 Gorm 实现 BookmarkDAO
+type gormBookmarkDAO struct {
+	db *gorm.DB
+}
+
+// NewBookmarkDAO 构造函数
+func NewBookmarkDAO(db *gorm.DB) BookmarkDAO {
+	return &gormBookmarkDAO{db: db}
+}
+
+// Create 创建 Bookmark
+func (dao *gormBookmarkDAO) Create(ctx context.Context, bookmark *model.Bookmark) error {
+	// 0. 兜底
+	if bookmark == nil || bookmark.UserID == 0 || bookmark.PostID == 0 {
+		return ErrParamsInvalid
+	}
+
+	// 1. 恢复软删除, 同时更新所属收藏夹
+	result := dao.db.WithContext(ctx).Model(&model.Bookmark{}).
+		Where("user_id = ? AND post_id = ? AND deleted_at IS NOT NULL", bookmark.UserID, bookmark.PostID).
+		Updates(map[string]any{"deleted_at": nil, "collection_id": bookmark.CollectionID})
+	if result.Error != nil {
+		// 系统层面错误
+		slog.Error(UpdateFailed, "bookmark", bookmark, "error", result.Error)
+		return ErrServerInternal
+	}
+	if result.RowsAffected > 0 {
+		// 恢复成功
+		return nil
+	}
+
+	// 2. 创建新记录
+	result = dao.db.WithContext(ctx).Create(bookmark)
+	if result.Error != nil {
+		var mysqlErr *mysql.MySQLError
+		if errors.As(result.Error, &mysqlErr) && mysqlErr.Number == 1062 { // 记录没有被软删且已存在 -> 已经收藏
+			// 幂等
+			return ErrUniqueKey
+		}
+
+		// 系统层面错误
+		slog.Error(CreateFailed, "bookmark", bookmark, "error", result.Error)
+		return ErrServerInternal
+	}
+
+	return nil
+}
+
+// Delete 删除 Bookmark
+func (dao *gormBookmarkDAO) Delete(ctx context.Context, uid, pid int64) error {
+	now := time.Now()
+	result := dao.db.WithContext(ctx).Model(&model.Bookmark{}).Where("user_id = ? AND post_id = ? AND deleted_at IS NULL", uid, pid).Update("deleted_at", &now)
+	if result.Error != nil {
+		// 系统层面错误
+		slog.Error(DeleteFailed, "user_id", uid, "post_id", pid, "error", result.Error)
+		return ErrServerInternal
+	}
+	if result.RowsAffected == 0 {
+		// 幂等
+		return ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// Exists 判断 Bookmark 存在
+func (dao *gormBookmarkDAO) Exists(ctx context.Context, uid, pid int64) (bool, error) {
+	bookmark := model.Bookmark{}
+	result := dao.db.WithContext(ctx).Where("user_id = ? AND post_id = ? AND deleted_at IS NULL", uid, pid).First(&bookmark)
+	if result.Error != nil {
+		if !errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			// 系统层面错误
+			slog.Error(FindFailed, "user_id", uid, "post_id", pid, "error", result.Error)
+			return false, ErrServerInternal
+		}
+		return false, nil
+	}
+	return true, nil
+}
+
+// GetPostIDs 按页返回用户收藏的帖子 ID 并按收藏时间排序, cid 为 nil 时不限收藏夹; 已删除或 uid 无权查看的帖子不计入
+func (dao *gormBookmarkDAO) GetPostIDs(ctx context.Context, uid int64, cid *int64, pageNo, pageSize int) (int64, []int64, error) {
+	base := dao.visible(ctx, uid)
+	if cid != nil {
+		base = base.Where("b.collection_id = ?", *cid)
+	}
+
+	var total int64
+	result := base.Count(&total)
+	if result.Error != nil {
+		// 系统层面错误
+		slog.Error(FindFailed, "user_id", uid, "pageNo", pageNo, "pageSize", pageSize, "error", result.Error)
+		return 0, nil, ErrServerInternal
+	}
+	if total == 0 {
+		return 0, []int64{}, nil
+	}
+
+	var ids []int64
+	offset := (pageNo - 1) * pageSize
+	result = base.Order("b.created_at DESC").Offset(offset).Limit(pageSize).Pluck("b.post_id", &ids)
+	if result.Error != nil {
+		slog.Error(FindFailed, "user_id", uid, "pageNo", pageNo, "pageSize", pageSize, "error", result.Error)
+		return 0, nil, ErrServerInternal
+	}
+
+	// 2. 返回结果
+	return total, ids, nil
+}
+
+// MoveBookmark 将收藏移动到收藏夹 cid, 0 为默认收藏夹
+func (dao *gormBookmarkDAO) MoveBookmark(ctx context.Context, uid, pid, cid int64) error {
+	result := dao.db.WithContext(ctx).Model(&model.Bookmark{}).
+		Where("user_id = ? AND post_id = ? AND deleted_at IS NULL", uid, pid).Update("collection_id", cid)
+	if result.Error != nil {
+		// 系统层面错误
+		slog.Error(UpdateFailed, "user_id", uid, "post_id", pid, "collection_id", cid, "error", result.Error)
+		return ErrServerInternal
+	}
+	if result.RowsAffected > 0 {
+		return nil
+	}
+
+	// 已在目标收藏夹时同样没有行被修改, 需要区分收藏不存在
+	ok, err := dao.Exists(ctx, uid, pid)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrRecordNotFound
+	}
+	return nil
+}
+
+// CreateCollection 创建收藏夹, 同名的收藏夹被删除过时直接恢复
+func (dao *gormBookmarkDAO) CreateCollection(ctx context.Context, collection *model.BookmarkCollection) error {
+	// 0. 兜底
+	if collection == nil || collection.ID == 0 || collection.UserID == 0 || collection.Name == "" {
+		return ErrParamsInvalid
+	}
+
+	// 1. 恢复软删除, 沿用旧收藏夹的 ID
+	deleted := &model.BookmarkCollection{}
+	result := dao.db.WithContext(ctx).Where("user_id = ? AND name = ? AND deleted_at IS NOT NULL", collection.UserID, collection.Name).Limit(1).Find(deleted)
+	if result.Error != nil {
+		// 系统层面错误
+		slog.Error(FindFailed, "collection", collection, "error", result.Error)
+		return ErrServerInternal
+	}
+	if result.RowsAffected > 0 {
+		now := time.Now()
+		result = dao.db.WithContext(ctx).Model(&model.BookmarkCollection{}).
+			Where("id = ? AND deleted_at IS NOT NULL", deleted.ID).Updates(map[string]any{"deleted_at": nil, "created_at": now})
+		if result.Error != nil {
+			// 系统层面错误
+			slog.Error(UpdateFailed, "collection", collection, "error", result.Error)
+			return ErrServerInternal
+		}
+		if result.RowsAffected > 0 {
+			collection.ID, collection.CreatedAt = deleted.ID, now
+			return nil
+		}
+	}
+
+	// 2. 创建新记录
+	result = dao.db.WithContext(ctx).Create(collection)
+	if result.Error != nil {
+		// 业务层面错误
+		var mysqlErr *mysql.MySQLError
+		if errors.As(result.Error, &mysqlErr) && mysqlErr.Number == 1062 {
+			return ErrUniqueKey
+		}
+
+		// 系统层面错误
+		slog.Error(CreateFailed, "collection", collection, "error", result.Error)
+		return ErrServerInternal
+	}
+
+	// 2. 返回结果
+	return nil
+}
+
+// RenameCollection 修改收藏夹名称
+func (dao *gormBookmarkDAO) RenameCollection(ctx context.Context, id int64, name string) error {
+	// 0. 兜底
+	if id == 0 || name == "" {
+		return ErrParamsInvalid
+	}
+
+	// 1. 操作数据库
+	err := dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		collection := &model.BookmarkCollection{}
+		result := tx.Where("id = ? AND deleted_at IS NULL", id).First(collection)
+		if result.Error != nil {
+			if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+				return ErrRecordNotFound
+			}
+			// 系统层面错误
+			slog.Error(FindFailed, "id", id, "error", result.Error)
+			return ErrServerInternal
+		}
+		if collection.Name == name {
+			return nil
+		}
+
+		// 同名的收藏夹已被删除时清掉其记录, 否则唯一索引会拦住改名
+		result = tx.Unscoped().Where("user_id = ? AND name = ? AND deleted_at IS NOT NULL", collection.UserID, name).Delete(&model.BookmarkCollection{})
+		if result.Error != nil {
+			// 系统层面错误
+			slog.Error(DeleteFailed, "user_id", collection.UserID, "name", name, "error", result.Error)
+			return ErrServerInternal
+		}
+
+		result = tx.Model(&model.BookmarkCollection{}).Where("id = ?", id).Update("name", name)
+		if result.Error != nil {
+			// 业务层面错误
+			var mysqlErr *mysql.MySQLError
+			if errors.As(result.Error, &mysqlErr) && mysqlErr.Number == 1062 {
+				return ErrUniqueKey
+			}
+
+			// 系统层面错误
+			slog.Error(UpdateFailed, "id", id, "name", name, "error", result.Error)
+			return ErrServerInternal
+		}
+		return nil
+	})
+
+	// 2. 返回结果
+	return err
+}
+
+// DeleteCollection 删除收藏夹, 其中的收藏移回默认收藏夹
+func (dao *gormBookmarkDAO) DeleteCollection(ctx context.Context, id int64) error {
+	err := dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		result := tx.Model(&model.BookmarkCollection{}).Where("id = ? AND deleted_at IS NULL", id).Update("deleted_at", &now)
+		if result.Error != nil {
+			// 系统层面错误
+			slog.Error(DeleteFailed, "id", id, "error", result.Error)
+			return ErrServerInternal
+		}
+		if result.RowsAffected == 0 {
+			return ErrRecordNotFound
+		}
+
+		// 已取消的收藏也一并移回, 避免重新收藏时恢复到已删除的收藏夹
+		result = tx.Model(&model.Bookmark{}).Where("collection_id = ?", id).Update("collection_id", 0)
+		if result.Error != nil {
+			// 系统层面错误
+			slog.Error(UpdateFailed, "collection_id", id, "error", result.Error)
+			return ErrServerInternal
+		}
+		return nil
+	})
+	return err
+}
+
+// GetCollectionByID 根据 ID 查找收藏夹
+func (dao *gormBookmarkDAO) GetCollectionByID(ctx context.Context, id int64) (*model.BookmarkCollection, error) {
+	collection := &model.BookmarkCollection{}
+	result := dao.db.WithContext(ctx).Where("id = ? AND deleted_at IS NULL", id).First(collection)
+	if result.Error != nil {
+		// 业务层面错误
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			return nil, ErrRecordNotFound
+		}
+		// 系统层面错误
+		slog.Error(FindFailed, "id", id, "error", result.Error)
+		return nil, ErrServerInternal
+	}
+	return collection, nil
+}
+
+// GetCollections 按页返回用户的收藏夹并按创建时间排序
+func (dao *gormBookmarkDAO) GetCollections(ctx context.Context, uid int64, pageNo, pageSize int) (int64, []*model.BookmarkCollection, error) {
+	base := dao.db.WithContext(ctx).Model(&model.BookmarkCollection{}).Where("user_id = ? AND deleted_at IS NULL", uid)
+
+	var total int64
+	result := base.Count(&total)
+	if result.Error != nil {
+		// 系统层面错误
+		slog.Error(FindFailed, "user_id", uid, "pageNo", pageNo, "pageSize", pageSize, "error", result.Error)
+		return 0, nil, ErrServerInternal
+	}
+	if total == 0 {
+		return 0, []*model.BookmarkCollection{}, nil
+	}
+
+	var collections []*model.BookmarkCollection
+	offset := (pageNo - 1) * pageSize
+	result = base.Order("created_at ASC").Offset(offset).Limit(pageSize).Find(&collections)
+	if result.Error != nil {
+		slog.Error(FindFailed, "user_id", uid, "pageNo", pageNo, "pageSize", pageSize, "error", result.Error)
+		return 0, nil, ErrServerInternal
+	}
+
+	return total, collections, nil
+}
+
+// CountByCollections 统计用户各收藏夹内的收藏数, 与 GetPostIDs 一样不计入已删除或无权查看的帖子
+func (dao *gormBookmarkDAO) CountByCollections(ctx context.Context, uid int64, cids []int64) (map[int64]int, error) {
+	res := make(map[int64]int, len(cids))
+	if len(cids) == 0 {
+		return res, nil
+	}
+
+	var rows []struct {
+		CollectionID int64
+		Cnt          int
+	}
+	result := dao.visible(ctx, uid).Select("b.collection_id, COUNT(*) AS cnt").
+		Where("b.collection_id IN ?", cids).
+		Group("b.collection_id").Scan(&rows)
+	if result.Error != nil {
+		// 系统层面错误
+		slog.Error(FindFailed, "user_id", uid, "collection_ids", cids, "error", result.Error)
+		return nil, ErrServerInternal
+	}
+
+	for _, row := range rows {
+		res[row.CollectionID] = row.Cnt
+	}
+	return res, nil
+}
+
+// visible 返回 uid 未取消的收藏中帖子未删除且 uid 有权查看的部分, 条件与 Service 层的 canView 一致
+func (dao *gormBookmarkDAO) visible(ctx context.Context, uid int64) *gorm.DB {
+	following := dao.db.Model(&model.Follow{}).Select("1").
+		Where("follower_id = ? AND followee_id = p.user_id AND deleted_at IS NULL", uid)
+
+	return dao.db.WithContext(ctx).Table("bookmarks AS b").
+		Joins("JOIN posts p ON p.id = b.post_id").
+		Where("b.user_id = ? AND b.deleted_at IS NULL AND p.deleted_at IS NULL", uid).
+		Where(dao.db.Where("p.status = ? AND p.user_id = ?", model.PostStatusReviewing, uid).
+			Or("p.status = ? AND (p.visibility = ? OR p.user_id = ? OR (p.visibility = ? AND EXISTS (?)))",
+				model.PostStatusNormal, model.PostVisibilityPublic, uid, model.PostVisibilityFollowers, following))
+}
@@ -0,0 +1,125 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/yzletter/go-postery/model"
+	"gorm.io/gorm"
+)
+
+// gormCommentBlockDAO 用 Gorm 实现 CommentBlockDAO
+type gormCommentBlockDAO struct {
+	db *gorm.DB
+}
+
+// NewCommentBlockDAO 构造函数
+func NewCommentBlockDAO(db *gorm.DB) CommentBlockDAO {
+	return &gormCommentBlockDAO{db: db}
+}
+
+// Create 创建 CommentBlock, 已拉黑时返回 ErrUniqueKey
+func (dao *gormCommentBlockDAO) Create(ctx context.Context, block *model.CommentBlock) error {
+	// 0. 兜底
+	if block == nil || block.AuthorID == 0 || block.UserID == 0 {
+		return ErrParamsInvalid
+	}
+
+	// 1. 恢复软删除
+	result := dao.db.WithContext(ctx).Model(&model.CommentBlock{}).
+		Where("author_id = ? AND user_id = ? AND deleted_at IS NOT NULL", block.AuthorID, block.UserID).
+		Updates(map[string]any{"deleted_at": nil, "created_at": time.Now()})
+	if result.Error != nil {
+		// 系统层面错误
+		slog.Error(UpdateFailed, "comment_block", block, "error", result.Error)
+		return ErrServerInternal
+	}
+	if result.RowsAffected > 0 {
+		// 恢复成功
+		return nil
+	}
+
+	// 2. 创建新记录
+	result = dao.db.WithContext(ctx).Create(block)
+	if result.Error != nil {
+		var mysqlErr *mysql.MySQLError
+		if errors.As(result.Error, &mysqlErr) && mysqlErr.Number == 1062 { // 记录没有被软删且已存在 -> 已经拉黑
+			// 业务层面错误
+			return ErrUniqueKey
+		}
+
+		// 系统层面错误
+		slog.Error(CreateFailed, "comment_block", block, "error", result.Error)
+		return ErrServerInternal
+	}
+
+	return nil
+}
+
+// Delete 删除 CommentBlock, 尚未拉黑时返回 ErrRecordNotFound
+func (dao *gormCommentBlockDAO) Delete(ctx context.Context, authorID, uid int64) error {
+	now := time.Now()
+	result := dao.db.WithContext(ctx).Model(&model.CommentBlock{}).
+		Where("author_id = ? AND user_id = ? AND deleted_at IS NULL", authorID, uid).Update("deleted_at", &now)
+	if result.Error != nil {
+		// 系统层面错误
+		slog.Error(DeleteFailed, "author_id", authorID, "user_id", uid, "error", result.Error)
+		return ErrServerInternal
+	}
+	if result.RowsAffected == 0 {
+		// 业务层面错误
+		return ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// Exists 查询 uid 是否被 authorID 禁止评论
+func (dao *gormCommentBlockDAO) Exists(ctx context.Context, authorID, uid int64) (bool, error) {
+	var cnt int64
+	result := dao.db.WithContext(ctx).Model(&model.CommentBlock{}).
+		Where("author_id = ? AND user_id = ? AND deleted_at IS NULL", authorID, uid).Count(&cnt)
+	if result.Error != nil {
+		// 系统层面错误
+		slog.Error(FindFailed, "author_id", authorID, "user_id", uid, "error", result.Error)
+		return false, ErrServerInternal
+	}
+
+	return cnt > 0, nil
+}
+
+// GetByAuthor 按拉黑时间倒序分页获取 authorID 禁止评论的用户 ID
+func (dao *gormCommentBlockDAO) GetByAuthor(ctx context.Context, authorID int64, pageNo, pageSize int) (int64, []int64, error) {
+	// 0. 兜底
+	if pageNo < 1 || pageSize <= 0 || pageSize > 100 {
+		return 0, nil, ErrParamsInvalid
+	}
+
+	// 1. 获取总数
+	base := dao.db.WithContext(ctx).Model(&model.CommentBlock{}).Where("author_id = ? AND deleted_at IS NULL", authorID)
+	var total int64
+	result := base.Count(&total)
+	if result.Error != nil {
+		// 系统层面错误
+		slog.Error(FindFailed, "author_id", authorID, "pageNo", pageNo, "pageSize", pageSize, "error", result.Error)
+		return 0, nil, ErrServerInternal
+	} else if total == 0 {
+		return 0, []int64{}, nil
+	}
+
+	// 2. 获取用户 ID
+	var uids []int64
+	offset := (pageNo - 1) * pageSize
+	result = base.Order("created_at DESC, id DESC").Offset(offset).Limit(pageSize).Pluck("user_id", &uids)
+	if result.Error != nil {
+		// 系统层面错误
+		slog.Error(FindFailed, "author_id", authorID, "pageNo", pageNo, "pageSize", pageSize, "error", result.Error)
+		return 0, nil, ErrServerInternal
+	}
+
+	// 3. 返回结果
+	return total, uids, nil
+}
@@ -3,6 +3,7 @@ package dao
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
 	"time"
 
@@ -11,6 +12,9 @@ import (
 	"gorm.io/gorm"
 )
 
+// commentHotDecay 热度排序中发布时间的权重: 晚发布 commentHotDecay 秒抵得上 10 倍的点赞数
+const commentHotDecay = 45000
+
 type gormCommentDAO struct {
 	db *gorm.DB
 }
@@ -69,34 +73,114 @@ func (dao *gormCommentDAO) Delete(ctx context.Context, id int64) (int, error) {
 	return int(result.RowsAffected), nil
 }
 
-// GetByPostID 查找 Post 的一级评论
-func (dao *gormCommentDAO) GetByPostID(ctx context.Context, id int64, pageNo, pageSize int) (int64, []*model.Comment, error) {
+// UpdateStatus 更新 Comment 的状态
+func (dao *gormCommentDAO) UpdateStatus(ctx context.Context, id int64, status int) error {
+	result := dao.db.WithContext(ctx).Model(&model.Comment{}).Where("id = ? AND deleted_at IS NULL", id).Update("status", status)
+	if result.Error != nil {
+		// 系统层面错误
+		slog.Error(UpdateFailed, "comment_id", id, "status", status, "error", result.Error)
+		return ErrServerInternal
+	} else if result.RowsAffected == 0 {
+		// 业务层面错误
+		return ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// UpdateLikeCount 将 Comment 的点赞数增加 delta, 不会减到 0 以下
+func (dao *gormCommentDAO) UpdateLikeCount(ctx context.Context, id int64, delta int) error {
+	result := dao.db.WithContext(ctx).Model(&model.Comment{}).Where("id = ?", id).
+		Update("like_count", gorm.Expr("GREATEST(like_count + ?, 0)", delta))
+	if result.Error != nil {
+		// 系统层面错误
+		slog.Error(UpdateFailed, "comment_id", id, "delta", delta, "error", result.Error)
+		return ErrServerInternal
+	}
+
+	return nil
+}
+
+// Edit 仅当编辑次数为 revision.Version 时更新 Comment 的内容和状态并自增编辑次数, 同时保存编辑前的内容;
+// 编辑次数不一致时返回 ErrVersionConflict
+func (dao *gormCommentDAO) Edit(ctx context.Context, revision *model.CommentRevision, content string, status int) error {
+	// 1. 在事务中更新评论并保存历史版本
+	err := dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.Comment{}).Where("id = ? AND edit_count = ? AND deleted_at IS NULL", revision.CommentID, revision.Version).
+			Updates(map[string]any{
+				"content":    content,
+				"status":     status,
+				"edit_count": gorm.Expr("edit_count + 1"),
+				"edited_at":  time.Now(),
+			})
+		if result.Error != nil {
+			slog.Error(UpdateFailed, "comment_id", revision.CommentID, "version", revision.Version, "error", result.Error)
+			return ErrServerInternal
+		}
+		if result.RowsAffected == 0 {
+			return ErrVersionConflict
+		}
+
+		if err := tx.Create(revision).Error; err != nil {
+			slog.Error(CreateFailed, "comment_id", revision.CommentID, "version", revision.Version, "error", err)
+			return ErrServerInternal
+		}
+		return nil
+	})
+
+	// 2. 返回结果
+	if err != nil {
+		if errors.Is(err, ErrVersionConflict) {
+			return ErrVersionConflict
+		}
+		return ErrServerInternal
+	}
+	return nil
+}
+
+// GetRevisions 按版本升序查找 Comment 的历史版本
+func (dao *gormCommentDAO) GetRevisions(ctx context.Context, id int64) ([]*model.CommentRevision, error) {
+	var revisions []*model.CommentRevision
+	result := dao.db.WithContext(ctx).Model(&model.CommentRevision{}).Where("comment_id = ?", id).Order("version ASC").Find(&revisions)
+	if result.Error != nil {
+		// 系统层面错误
+		slog.Error(FindFailed, "comment_id", id, "error", result.Error)
+		return nil, ErrServerInternal
+	}
+
+	return revisions, nil
+}
+
+// GetByPostID 按 sort 排序和 offset / limit 查找 Post 的一级评论, 跳过 excludeIDs 中的评论; limit 为 0 时只返回总数
+func (dao *gormCommentDAO) GetByPostID(ctx context.Context, id int64, excludeIDs []int64, sort model.CommentSort, offset, limit int) (int64, []*model.Comment, error) {
 	// 0. 兜底
-	if pageNo < 1 || pageSize <= 0 || pageSize > 100 {
+	if offset < 0 || limit < 0 || limit > 100 {
 		return 0, nil, ErrParamsInvalid
 	}
 
 	// 1. 操作数据库
-	base := dao.db.WithContext(ctx).Model(&model.Comment{}).Where("post_id = ? AND parent_id = 0 AND deleted_at IS NULL", id)
+	base := dao.db.WithContext(ctx).Model(&model.Comment{}).Where("post_id = ? AND parent_id = 0 AND status = ? AND deleted_at IS NULL", id, model.CommentStatusNormal)
+	if len(excludeIDs) > 0 {
+		base = base.Where("id NOT IN ?", excludeIDs)
+	}
 
 	// 2. 获取总数
 	var total int64
 	result := base.Count(&total)
 	if result.Error != nil {
 		// 系统层面错误
-		slog.Error(FindFailed, "post_id", id, "pageNo", pageNo, "pageSize", pageSize, "error", result.Error)
+		slog.Error(FindFailed, "post_id", id, "offset", offset, "limit", limit, "error", result.Error)
 		return 0, nil, ErrServerInternal
-	} else if total == 0 {
-		return 0, []*model.Comment{}, nil
+	} else if total == 0 || limit == 0 {
+		return total, []*model.Comment{}, nil
 	}
 
 	// 3. 获取评论
 	var comments []*model.Comment
-	offset := (pageNo - 1) * pageSize
-	result = base.Order("created_at DESC").Offset(offset).Limit(pageSize).Find(&comments)
+	result = base.Order(commentOrder(sort)).Offset(offset).Limit(limit).Find(&comments)
 	if result.Error != nil {
 		// 系统层面错误
-		slog.Error(FindFailed, "post_id", id, "pageNo", pageNo, "pageSize", pageSize, "error", result.Error)
+		slog.Error(FindFailed, "post_id", id, "offset", offset, "limit", limit, "error", result.Error)
 		return 0, nil, ErrServerInternal
 	}
 
@@ -104,11 +188,16 @@ func (dao *gormCommentDAO) GetByPostID(ctx context.Context, id int64, pageNo, pa
 	return total, comments, nil
 }
 
-// GetRepliesByParentIDs 根据多个 Comment 的 ID 查找 Comment 的子评论
-func (dao *gormCommentDAO) GetRepliesByParentID(ctx context.Context, id int64, pageNo, pageSize int) (int64, []*model.Comment, error) {
+// GetRepliesByParentID 按 sort 排序查找 Comment 的子评论
+func (dao *gormCommentDAO) GetRepliesByParentID(ctx context.Context, id int64, sort model.CommentSort, pageNo, pageSize int) (int64, []*model.Comment, error) {
+	// 0. 兜底
+	if pageNo < 1 || pageSize <= 0 || pageSize > 100 {
+		return 0, nil, ErrParamsInvalid
+	}
+
 	var comments []*model.Comment
 
-	base := dao.db.WithContext(ctx).Model(&model.Comment{}).Where("parent_id = ? AND deleted_at is NULL", id)
+	base := dao.db.WithContext(ctx).Model(&model.Comment{}).Where("parent_id = ? AND status = ? AND deleted_at is NULL", id, model.CommentStatusNormal)
 
 	var total int64
 	result := base.Count(&total)
@@ -121,7 +210,7 @@ func (dao *gormCommentDAO) GetRepliesByParentID(ctx context.Context, id int64, p
 	}
 
 	offset := (pageNo - 1) * pageSize
-	result = base.Order("parent_id ASC, created_at ASC").Offset(offset).Limit(pageSize).Find(&comments)
+	result = base.Order(commentOrder(sort)).Offset(offset).Limit(pageSize).Find(&comments)
 	if result.Error != nil {
 		// 系统层面错误
 		slog.Error(FindFailed, "parent_ids", id, "error", result.Error)
@@ -130,3 +219,95 @@ func (dao *gormCommentDAO) GetRepliesByParentID(ctx context.Context, id int64, p
 
 	return total, comments, nil
 }
+
+// GetUserIDs 批量查找 Comment 的作者, 返回评论 ID 到作者 ID 的映射, 不存在或已删除的评论直接忽略
+func (dao *gormCommentDAO) GetUserIDs(ctx context.Context, ids []int64) (map[int64]int64, error) {
+	// 0. 兜底
+	res := make(map[int64]int64, len(ids))
+	if len(ids) == 0 {
+		return res, nil
+	}
+
+	// 1. 操作数据库
+	var comments []*model.Comment
+	result := dao.db.WithContext(ctx).Model(&model.Comment{}).Select("id, user_id").Where("id IN ? AND deleted_at IS NULL", ids).Find(&comments)
+	if result.Error != nil {
+		// 系统层面错误
+		slog.Error(FindFailed, "ids", ids, "error", result.Error)
+		return nil, ErrServerInternal
+	}
+
+	// 2. 返回结果
+	for _, comment := range comments {
+		res[comment.ID] = comment.UserID
+	}
+	return res, nil
+}
+
+// GetTopReplies 按 sort 排序取每个一级评论下的前 limit 条子评论, 返回一级评论 ID 到子评论的映射
+func (dao *gormCommentDAO) GetTopReplies(ctx context.Context, parentIDs []int64, sort model.CommentSort, limit int) (map[int64][]*model.Comment, error) {
+	// 0. 兜底
+	res := make(map[int64][]*model.Comment, len(parentIDs))
+	if len(parentIDs) == 0 || limit <= 0 {
+		return res, nil
+	}
+
+	// 1. 操作数据库, 用窗口函数在每个一级评论内排名
+	ranked := dao.db.WithContext(ctx).Model(&model.Comment{}).
+		Select("comments.*, ROW_NUMBER() OVER (PARTITION BY parent_id ORDER BY "+commentOrder(sort)+") AS rn").
+		Where("parent_id IN ? AND status = ? AND deleted_at IS NULL", parentIDs, model.CommentStatusNormal)
+	var comments []*model.Comment
+	result := dao.db.WithContext(ctx).Table("(?) AS t", ranked).Where("rn <= ?", limit).Order("parent_id ASC, rn ASC").Find(&comments)
+	if result.Error != nil {
+		// 系统层面错误
+		slog.Error(FindFailed, "parent_ids", parentIDs, "error", result.Error)
+		return nil, ErrServerInternal
+	}
+
+	// 2. 返回结果
+	for _, comment := range comments {
+		res[comment.ParentID] = append(res[comment.ParentID], comment)
+	}
+	return res, nil
+}
+
+// CountReplies 统计每个一级评论下的子评论数
+func (dao *gormCommentDAO) CountReplies(ctx context.Context, parentIDs []int64) (map[int64]int, error) {
+	// 0. 兜底
+	res := make(map[int64]int, len(parentIDs))
+	if len(parentIDs) == 0 {
+		return res, nil
+	}
+
+	// 1. 操作数据库
+	var rows []struct {
+		ParentID int64
+		Cnt      int
+	}
+	result := dao.db.WithContext(ctx).Model(&model.Comment{}).Select("parent_id, COUNT(*) AS cnt").
+		Where("parent_id IN ? AND status = ? AND deleted_at IS NULL", parentIDs, model.CommentStatusNormal).
+		Group("parent_id").Scan(&rows)
+	if result.Error != nil {
+		// 系统层面错误
+		slog.Error(FindFailed, "parent_ids", parentIDs, "error", result.Error)
+		return nil, ErrServerInternal
+	}
+
+	// 2. 返回结果
+	for _, row := range rows {
+		res[row.ParentID] = row.Cnt
+	}
+	return res, nil
+}
+
+// commentOrder 评论排序方式对应的 ORDER BY 子句; 热度为 log10(点赞数) + 发布时间 / commentHotDecay, 新评论天然有更高的分数
+func commentOrder(sort model.CommentSort) string {
+	switch sort {
+	case model.CommentSortOldest:
+		return "created_at ASC, id ASC"
+	case model.CommentSortHot:
+		return fmt.Sprintf("LOG10(GREATEST(like_count, 1)) + UNIX_TIMESTAMP(created_at) / %d DESC, id DESC", commentHotDecay)
+	default:
+		return "created_at DESC, id DESC"
+	}
+}
@@ -0,0 +1,103 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/yzletter/go-postery/model"
+	"gorm.io/gorm"
+)
+
+// gormCommentLikeDAO 用 Gorm 实现 CommentLikeDAO
+type gormCommentLikeDAO struct {
+	db *gorm.DB
+}
+
+// NewCommentLikeDAO 构造函数
+func NewCommentLikeDAO(db *gorm.DB) CommentLikeDAO {
+	return &gormCommentLikeDAO{db: db}
+}
+
+// Create 创建 CommentLike, 已点赞时返回 ErrUniqueKey
+func (dao *gormCommentLikeDAO) Create(ctx context.Context, like *model.CommentLike) error {
+	// 0. 兜底
+	if like == nil || like.UserID == 0 || like.CommentID == 0 {
+		return ErrParamsInvalid
+	}
+
+	// 1. 恢复软删除
+	result := dao.db.WithContext(ctx).Model(&model.CommentLike{}).
+		Where("user_id = ? AND comment_id = ? AND deleted_at IS NOT NULL", like.UserID, like.CommentID).
+		Updates(map[string]any{"deleted_at": nil, "created_at": time.Now()})
+	if result.Error != nil {
+		// 系统层面错误
+		slog.Error(UpdateFailed, "comment_like", like, "error", result.Error)
+		return ErrServerInternal
+	}
+	if result.RowsAffected > 0 {
+		// 恢复成功
+		return nil
+	}
+
+	// 2. 创建新记录
+	result = dao.db.WithContext(ctx).Create(like)
+	if result.Error != nil {
+		var mysqlErr *mysql.MySQLError
+		if errors.As(result.Error, &mysqlErr) && mysqlErr.Number == 1062 { // 记录没有被软删且已存在 -> 已经点赞
+			// 业务层面错误
+			return ErrUniqueKey
+		}
+
+		// 系统层面错误
+		slog.Error(CreateFailed, "comment_like", like, "error", result.Error)
+		return ErrServerInternal
+	}
+
+	return nil
+}
+
+// Delete 删除 CommentLike, 尚未点赞时返回 ErrRecordNotFound
+func (dao *gormCommentLikeDAO) Delete(ctx context.Context, uid, cid int64) error {
+	now := time.Now()
+	result := dao.db.WithContext(ctx).Model(&model.CommentLike{}).
+		Where("user_id = ? AND comment_id = ? AND deleted_at IS NULL", uid, cid).Update("deleted_at", &now)
+	if result.Error != nil {
+		// 系统层面错误
+		slog.Error(DeleteFailed, "user_id", uid, "comment_id", cid, "error", result.Error)
+		return ErrServerInternal
+	}
+	if result.RowsAffected == 0 {
+		// 业务层面错误
+		return ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// GetLikedIDs 查询 cids 中哪些评论被 uid 点赞过
+func (dao *gormCommentLikeDAO) GetLikedIDs(ctx context.Context, uid int64, cids []int64) (map[int64]bool, error) {
+	// 0. 兜底
+	res := make(map[int64]bool, len(cids))
+	if uid == 0 || len(cids) == 0 {
+		return res, nil
+	}
+
+	// 1. 操作数据库
+	var ids []int64
+	result := dao.db.WithContext(ctx).Model(&model.CommentLike{}).
+		Where("user_id = ? AND comment_id IN ? AND deleted_at IS NULL", uid, cids).Pluck("comment_id", &ids)
+	if result.Error != nil {
+		// 系统层面错误
+		slog.Error(FindFailed, "user_id", uid, "comment_ids", cids, "error", result.Error)
+		return nil, ErrServerInternal
+	}
+
+	// 2. 返回结果
+	for _, id := range ids {
+		res[id] = true
+	}
+	return res, nil
+}
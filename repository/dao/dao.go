@@ -2,6 +2,7 @@ package dao
 
 import (
 	"context"
+	"time"
 
 	"github.com/yzletter/go-postery/dto/session"
 	"github.com/yzletter/go-postery/model"
@@ -15,7 +16,9 @@ type UserDAO interface {
 	GetPasswordHash(ctx context.Context, id int64) (string, error)
 	GetStatus(ctx context.Context, id int64) (int, error)
 	GetByID(ctx context.Context, id int64) (*model.User, error)
+	GetByIDs(ctx context.Context, ids []int64) ([]*model.User, error)
 	GetByUsername(ctx context.Context, username string) (*model.User, error)
+	GetByUsernames(ctx context.Context, usernames []string) ([]*model.User, error)
 	UpdatePasswordHash(ctx context.Context, id int64, newHash string) error
 	UpdateProfile(ctx context.Context, id int64, updates map[string]any) error
 }
@@ -23,26 +26,118 @@ type UserDAO interface {
 type PostDAO interface {
 	Create(ctx context.Context, post *model.Post) error
 	Delete(ctx context.Context, id int64) error
-	UpdateCount(ctx context.Context, id int64, field model.PostCntField, delta int) error
+	BatchUpdateCount(ctx context.Context, deltas map[int64]map[model.PostCntField]int) error
+	BatchSetCount(ctx context.Context, counts map[int64]map[model.PostCntField]int) error
+	ListIDs(ctx context.Context, cursor int64, limit int) ([]int64, error)
+	ListForRank(ctx context.Context, since time.Time, cursor int64, limit int) ([]*model.Post, error)
+	RecountInteractive(ctx context.Context, ids []int64) (map[int64]map[model.PostCntField]int, error)
 	Update(ctx context.Context, id int64, updates map[string]any) error
+	UpdateWithVersion(ctx context.Context, id int64, version int, updates map[string]any) (int, error)
 	GetByID(ctx context.Context, id int64) (*model.Post, error)
-	GetByUid(ctx context.Context, id int64, pageNo, pageSize int) (int64, []*model.Post, error)
-	GetByPage(ctx context.Context, pageNo, pageSize int) (int64, []*model.Post, error)
-	GetByPageAndTag(ctx context.Context, tid int64, pageNo, pageSize int) (int64, []*model.Post, error)
+	GetByIDs(ctx context.Context, ids []int64) ([]*model.Post, error)
+	GetByUid(ctx context.Context, id int64, maxVisibility model.PostVisibility, pageNo, pageSize int) (int64, []*model.Post, error)
+	GetByPage(ctx context.Context, maxVisibility model.PostVisibility, excludeIDs []int64, offset, limit int) (int64, []*model.Post, error)
+	GetByPageAndTag(ctx context.Context, tid int64, maxVisibility model.PostVisibility, excludeIDs []int64, offset, limit int) (int64, []*model.Post, error)
+	GetFeatured(ctx context.Context, pageNo, pageSize int) (int64, []*model.Post, error)
+	Pin(ctx context.Context, pin *model.PostPin) error
+	Unpin(ctx context.Context, pid, tid int64) error
+	GetPinnedIDs(ctx context.Context, tid int64, now time.Time, limit int) ([]int64, error)
+	AssignSlug(ctx context.Context, postSlug *model.PostSlug) error
+	GetIDBySlug(ctx context.Context, slug string) (int64, error)
+	ListWithoutSlug(ctx context.Context, cursor int64, limit int) ([]*model.Post, error)
 }
 
 type CommentDAO interface {
 	Create(ctx context.Context, comment *model.Comment) error
 	Delete(ctx context.Context, id int64) (int, error)
+	UpdateStatus(ctx context.Context, id int64, status int) error
 	GetByID(ctx context.Context, id int64) (*model.Comment, error)
-	GetByPostID(ctx context.Context, id int64, pageNo, pageSize int) (int64, []*model.Comment, error)
-	GetRepliesByParentID(ctx context.Context, id int64, pageNo, pageSize int) (int64, []*model.Comment, error)
+	UpdateLikeCount(ctx context.Context, id int64, delta int) error
+	Edit(ctx context.Context, revision *model.CommentRevision, content string, status int) error
+	GetRevisions(ctx context.Context, id int64) ([]*model.CommentRevision, error)
+	GetByPostID(ctx context.Context, id int64, excludeIDs []int64, sort model.CommentSort, offset, limit int) (int64, []*model.Comment, error)
+	GetRepliesByParentID(ctx context.Context, id int64, sort model.CommentSort, pageNo, pageSize int) (int64, []*model.Comment, error)
+	GetUserIDs(ctx context.Context, ids []int64) (map[int64]int64, error)
+	GetTopReplies(ctx context.Context, parentIDs []int64, sort model.CommentSort, limit int) (map[int64][]*model.Comment, error)
+	CountReplies(ctx context.Context, parentIDs []int64) (map[int64]int, error)
+}
+
+type MentionDAO interface {
+	Create(ctx context.Context, mentions []*model.Mention) error
+	Delete(ctx context.Context, targetType model.MentionTarget, targetID int64, uids []int64) error
+	GetByTargets(ctx context.Context, targetType model.MentionTarget, targetIDs []int64) ([]*model.Mention, error)
+}
+
+type CommentBlockDAO interface {
+	Create(ctx context.Context, block *model.CommentBlock) error
+	Delete(ctx context.Context, authorID, uid int64) error
+	Exists(ctx context.Context, authorID, uid int64) (bool, error)
+	GetByAuthor(ctx context.Context, authorID int64, pageNo, pageSize int) (int64, []int64, error)
+}
+
+type CommentLikeDAO interface {
+	Create(ctx context.Context, like *model.CommentLike) error
+	Delete(ctx context.Context, uid, cid int64) error
+	GetLikedIDs(ctx context.Context, uid int64, cids []int64) (map[int64]bool, error)
 }
 
 type LikeDAO interface {
 	Create(ctx context.Context, like *model.Like) error
+	CountCoLikes(ctx context.Context, pid int64, likers, limit int) (map[int64]int, error)
+	Delete(ctx context.Context, uid, pid int64) error
+	Exists(ctx context.Context, uid, pid int64) (bool, error)
+}
+
+type RepostDAO interface {
+	Create(ctx context.Context, repost *model.Repost) error
+	Delete(ctx context.Context, uid, pid int64) error
+	Exists(ctx context.Context, uid, pid int64) (bool, error)
+	GetPostIDs(ctx context.Context, uid int64, pageNo, pageSize int) (int64, []int64, error)
+}
+
+type BookmarkDAO interface {
+	Create(ctx context.Context, bookmark *model.Bookmark) error
 	Delete(ctx context.Context, uid, pid int64) error
 	Exists(ctx context.Context, uid, pid int64) (bool, error)
+	GetPostIDs(ctx context.Context, uid int64, cid *int64, pageNo, pageSize int) (int64, []int64, error)
+	MoveBookmark(ctx context.Context, uid, pid, cid int64) error
+	CreateCollection(ctx context.Context, collection *model.BookmarkCollection) error
+	RenameCollection(ctx context.Context, id int64, name string) error
+	DeleteCollection(ctx context.Context, id int64) error
+	GetCollectionByID(ctx context.Context, id int64) (*model.BookmarkCollection, error)
+	GetCollections(ctx context.Context, uid int64, pageNo, pageSize int) (int64, []*model.BookmarkCollection, error)
+	CountByCollections(ctx context.Context, uid int64, cids []int64) (map[int64]int, error)
+}
+
+type AttachmentDAO interface {
+	Create(ctx context.Context, attachment *model.Attachment, quota int64) error
+	GetByID(ctx context.Context, id int64) (*model.Attachment, error)
+	GetByPostID(ctx context.Context, pid int64) ([]*model.Attachment, error)
+	CountBindable(ctx context.Context, uid, pid int64, ids []int64) (int64, error)
+	SumSizeByUid(ctx context.Context, uid int64) (int64, error)
+	BindToPost(ctx context.Context, uid, pid int64, ids []int64) error
+	Discard(ctx context.Context, uid, id int64) error
+	DiscardByPostID(ctx context.Context, pid int64) error
+	ListGarbage(ctx context.Context, orphanBefore time.Time, limit int) ([]*model.Attachment, error)
+	Delete(ctx context.Context, ids []int64) error
+}
+
+type ReportDAO interface {
+	Create(ctx context.Context, report *model.Report) error
+	Submit(ctx context.Context, report *model.Report) error
+	GetPendingSummaries(ctx context.Context, targetType model.ReportTargetType, pageNo, pageSize int) (int64, []*model.ReportSummary, error)
+	HasPending(ctx context.Context, targetType model.ReportTargetType, targetID int64) (bool, error)
+	Resolve(ctx context.Context, log *model.ModerationLog) error
+	GetLogs(ctx context.Context, pageNo, pageSize int) (int64, []*model.ModerationLog, error)
+}
+
+type SensitiveDAO interface {
+	CreateWords(ctx context.Context, words []*model.SensitiveWord) (int, error)
+	DeleteWord(ctx context.Context, id int64) error
+	GetAllWords(ctx context.Context) ([]*model.SensitiveWord, error)
+	GetWordsByPage(ctx context.Context, category string, pageNo, pageSize int) (int64, []*model.SensitiveWord, error)
+	GetAllCategories(ctx context.Context) ([]*model.SensitiveCategory, error)
+	SaveCategory(ctx context.Context, category *model.SensitiveCategory) error
 }
 
 type FollowDAO interface {
@@ -60,6 +155,32 @@ type TagDAO interface {
 	Bind(ctx context.Context, postTag *model.PostTag) error
 	DeleteBind(ctx context.Context, pid, tid int64) error
 	FindTagsByPostID(ctx context.Context, pid int64) ([]string, error)
+	FindTagsByPostIDs(ctx context.Context, pids []int64) (map[int64][]string, error)
+	GetTagIDsByPostIDs(ctx context.Context, pids []int64) (map[int64][]int64, error)
+	CountSharedTags(ctx context.Context, pid int64, limit int) (map[int64]int, error)
+}
+
+type PollDAO interface {
+	Create(ctx context.Context, poll *model.Poll, options []*model.PollOption) error
+	GetByPostID(ctx context.Context, pid int64) (*model.Poll, []*model.PollOption, error)
+	CreateVotes(ctx context.Context, votes []*model.PollVote) error
+	GetVotedOptions(ctx context.Context, pollID, uid int64) ([]int64, error)
+	GetVoters(ctx context.Context, optionID int64, pageNo, pageSize int) (int64, []int64, error)
+	BatchIncrCount(ctx context.Context, deltas map[int64]*model.PollCnt) error
+}
+
+type SeriesDAO interface {
+	Create(ctx context.Context, series *model.Series) error
+	GetByID(ctx context.Context, id int64) (*model.Series, error)
+	GetByUid(ctx context.Context, uid int64, pageNo, pageSize int) (int64, []*model.Series, error)
+	Update(ctx context.Context, id int64, updates map[string]any) error
+	Delete(ctx context.Context, id int64) error
+	AddPost(ctx context.Context, seriesPost *model.SeriesPost) (int, error)
+	RemovePost(ctx context.Context, sid, pid int64) error
+	Reorder(ctx context.Context, sid int64, pids []int64) error
+	GetPostIDs(ctx context.Context, sid int64) ([]int64, error)
+	GetByPostID(ctx context.Context, pid int64) (*model.SeriesPost, error)
+	CountPosts(ctx context.Context, sids []int64) (map[int64]int, error)
 }
 
 type MessageDAO interface {
@@ -90,3 +211,12 @@ type GiftDAO interface {
 	GetAll(ctx context.Context) ([]*model.Gift, error)
 	GetByID(ctx context.Context, gid int64) (*model.Gift, error)
 }
+
+type AnalyticsDAO interface {
+	SaveBatch(ctx context.Context, batch *model.AnalyticsBatch) error
+	PruneHourly(ctx context.Context, before time.Time, limit int) (int64, error)
+	GetPostSeries(ctx context.Context, authorID, pid int64, granularity model.AnalyticsGranularity, from, to time.Time) ([]*model.PostStat, error)
+	GetTopPosts(ctx context.Context, authorID int64, granularity model.AnalyticsGranularity, from, to time.Time, limit int) ([]*model.PostStat, error)
+	GetReferrers(ctx context.Context, authorID, pid int64, from, to time.Time, limit int) ([]*model.PostReferrerStat, error)
+	GetFollowerSeries(ctx context.Context, uid int64, from, to time.Time) ([]*model.FollowerStat, error)
+}
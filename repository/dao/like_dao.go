@@ -88,3 +88,32 @@ func (dao *gormLikeDAO) Exists(ctx context.Context, uid, pid int64) (bool, error
 	}
 	return true, nil
 }
+
+// CountCoLikes 统计最近点赞 pid 的 likers 个用户还点赞了哪些其他 Post 及共同点赞人数, 取人数最多的 limit 个
+func (dao *gormLikeDAO) CountCoLikes(ctx context.Context, pid int64, likers, limit int) (map[int64]int, error) {
+	type row struct {
+		PostID int64
+		Cnt    int
+	}
+
+	// 1. 操作数据库
+	users := dao.db.WithContext(ctx).Model(&model.Like{}).Select("user_id").
+		Where("post_id = ? AND deleted_at IS NULL", pid).Order("id DESC").Limit(likers)
+	var rows []row
+	result := dao.db.WithContext(ctx).Table("likes AS l").Select("l.post_id, COUNT(*) AS cnt").
+		Joins("JOIN (?) AS u ON u.user_id = l.user_id", users).
+		Where("l.post_id <> ? AND l.deleted_at IS NULL", pid).
+		Group("l.post_id").Order("cnt DESC, l.post_id DESC").Limit(limit).Scan(&rows)
+	if result.Error != nil {
+		// 系统层面错误
+		slog.Error(FindFailed, "post_id", pid, "error", result.Error)
+		return nil, ErrServerInternal
+	}
+
+	// 2. 返回结果
+	res := make(map[int64]int, len(rows))
+	for _, r := range rows {
+		res[r.PostID] = r.Cnt
+	}
+	return res, nil
+}
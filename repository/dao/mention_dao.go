@@ -0,0 +1,79 @@
+package dao
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/yzletter/go-postery/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// gormMentionDAO 用 Gorm 实现 MentionDAO
+type gormMentionDAO struct {
+	db *gorm.DB
+}
+
+// NewMentionDAO 构造函数
+func NewMentionDAO(db *gorm.DB) MentionDAO {
+	return &gormMentionDAO{db: db}
+}
+
+// Create 批量创建 Mention, 已存在的提及直接跳过
+func (dao *gormMentionDAO) Create(ctx context.Context, mentions []*model.Mention) error {
+	// 0. 兜底
+	if len(mentions) == 0 {
+		return nil
+	}
+
+	// 1. 操作数据库
+	result := dao.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&mentions)
+	if result.Error != nil {
+		// 系统层面错误
+		slog.Error(CreateFailed, "mentions", len(mentions), "error", result.Error)
+		return ErrServerInternal
+	}
+
+	// 2. 返回结果
+	return nil
+}
+
+// Delete 删除对象中对 uids 的提及
+func (dao *gormMentionDAO) Delete(ctx context.Context, targetType model.MentionTarget, targetID int64, uids []int64) error {
+	// 0. 兜底
+	if len(uids) == 0 {
+		return nil
+	}
+
+	// 1. 操作数据库
+	result := dao.db.WithContext(ctx).Where("target_type = ? AND target_id = ? AND user_id IN ?", targetType, targetID, uids).
+		Delete(&model.Mention{})
+	if result.Error != nil {
+		// 系统层面错误
+		slog.Error(DeleteFailed, "target_type", targetType, "target_id", targetID, "uids", uids, "error", result.Error)
+		return ErrServerInternal
+	}
+
+	// 2. 返回结果
+	return nil
+}
+
+// GetByTargets 查找多个对象中的全部提及
+func (dao *gormMentionDAO) GetByTargets(ctx context.Context, targetType model.MentionTarget, targetIDs []int64) ([]*model.Mention, error) {
+	// 0. 兜底
+	var mentions []*model.Mention
+	if len(targetIDs) == 0 {
+		return mentions, nil
+	}
+
+	// 1. 操作数据库
+	result := dao.db.WithContext(ctx).Where("target_type = ? AND target_id IN ?", targetType, targetIDs).Find(&mentions)
+	if result.Error != nil {
+		// 系统层面错误
+		slog.Error(FindFailed, "target_type", targetType, "target_ids", targetIDs, "error", result.Error)
+		return nil, ErrServerInternal
+	}
+
+	// 2. 返回结果
+	return mentions, nil
+}
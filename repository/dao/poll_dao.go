@@ -0,0 +1,175 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/yzletter/go-postery/model"
+	"gorm.io/gorm"
+)
+
+type gormPollDAO struct {
+	db *gorm.DB
+}
+
+func NewPollDAO(db *gorm.DB) PollDAO {
+	return &gormPollDAO{db: db}
+}
+
+// Create 创建 Poll 及其选项, 帖子已有投票时返回 ErrUniqueKey
+func (dao *gormPollDAO) Create(ctx context.Context, poll *model.Poll, options []*model.PollOption) error {
+	// 1. 在事务中操作数据库
+	err := dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(poll).Error; err != nil {
+			return err
+		}
+		return tx.Create(options).Error
+	})
+	if err != nil {
+		var mysqlErr *mysql.MySQLError
+		if errors.As(err, &mysqlErr) && mysqlErr.Number == 1062 {
+			// 业务层面错误
+			return ErrUniqueKey
+		}
+		// 系统层面错误
+		slog.Error(CreateFailed, "poll", poll, "error", err)
+		return ErrServerInternal
+	}
+
+	// 2. 返回结果
+	return nil
+}
+
+// GetByPostID 查找 Post 的 Poll 及其选项, 选项按顺序排列
+func (dao *gormPollDAO) GetByPostID(ctx context.Context, pid int64) (*model.Poll, []*model.PollOption, error) {
+	// 1. 查找投票
+	var poll model.Poll
+	result := dao.db.WithContext(ctx).Where("post_id = ?", pid).First(&poll)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			// 业务层面错误
+			return nil, nil, ErrRecordNotFound
+		}
+		// 系统层面错误
+		slog.Error(FindFailed, "post_id", pid, "error", result.Error)
+		return nil, nil, ErrServerInternal
+	}
+
+	// 2. 查找选项
+	var options []*model.PollOption
+	result = dao.db.WithContext(ctx).Where("poll_id = ?", poll.ID).Order("position ASC").Find(&options)
+	if result.Error != nil {
+		// 系统层面错误
+		slog.Error(FindFailed, "poll_id", poll.ID, "error", result.Error)
+		return nil, nil, ErrServerInternal
+	}
+
+	// 3. 返回结果
+	return &poll, options, nil
+}
+
+// CreateVotes 记录一个用户的全部选票, 任一选项已投过时整体失败并返回 ErrUniqueKey
+func (dao *gormPollDAO) CreateVotes(ctx context.Context, votes []*model.PollVote) error {
+	// 1. 操作数据库
+	result := dao.db.WithContext(ctx).Create(votes)
+	if result.Error != nil {
+		var mysqlErr *mysql.MySQLError
+		if errors.As(result.Error, &mysqlErr) && mysqlErr.Number == 1062 {
+			// 业务层面错误
+			return ErrUniqueKey
+		}
+		// 系统层面错误
+		slog.Error(CreateFailed, "votes", votes, "error", result.Error)
+		return ErrServerInternal
+	}
+
+	// 2. 返回结果
+	return nil
+}
+
+// GetVotedOptions 查找用户在 Poll 中投过的选项 ID
+func (dao *gormPollDAO) GetVotedOptions(ctx context.Context, pollID, uid int64) ([]int64, error) {
+	// 1. 操作数据库
+	var optionIDs []int64
+	result := dao.db.WithContext(ctx).Model(&model.PollVote{}).Where("poll_id = ? AND user_id = ?", pollID, uid).Pluck("option_id", &optionIDs)
+	if result.Error != nil {
+		// 系统层面错误
+		slog.Error(FindFailed, "poll_id", pollID, "user_id", uid, "error", result.Error)
+		return nil, ErrServerInternal
+	}
+
+	// 2. 返回结果
+	return optionIDs, nil
+}
+
+// GetVoters 按页查找投了某个选项的用户 ID, 最近投票的在前
+func (dao *gormPollDAO) GetVoters(ctx context.Context, optionID int64, pageNo, pageSize int) (int64, []int64, error) {
+	// 0. 兜底
+	if pageNo < 1 || pageSize <= 0 || pageSize > 100 {
+		return 0, nil, ErrParamsInvalid
+	}
+
+	// 1. 操作数据库
+	base := dao.db.WithContext(ctx).Model(&model.PollVote{}).Where("option_id = ?", optionID)
+
+	// 2. 获取总数
+	var total int64
+	result := base.Count(&total)
+	if result.Error != nil {
+		// 系统层面错误
+		slog.Error(FindFailed, "option_id", optionID, "error", result.Error)
+		return 0, nil, ErrServerInternal
+	} else if total == 0 {
+		return 0, []int64{}, nil
+	}
+
+	// 3. 获取投票人
+	var uids []int64
+	result = base.Order("id DESC").Limit(pageSize).Offset((pageNo-1)*pageSize).Pluck("user_id", &uids)
+	if result.Error != nil {
+		// 系统层面错误
+		slog.Error(FindFailed, "option_id", optionID, "error", result.Error)
+		return 0, nil, ErrServerInternal
+	}
+
+	// 4. 返回结果
+	return total, uids, nil
+}
+
+// BatchIncrCount 在事务中将计数增量累加到投票人数和选项得票数上
+func (dao *gormPollDAO) BatchIncrCount(ctx context.Context, deltas map[int64]*model.PollCnt) error {
+	if len(deltas) == 0 {
+		return nil
+	}
+
+	// 1. 在事务中逐个更新
+	err := dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for pollID, cnt := range deltas {
+			if cnt.Voters != 0 {
+				result := tx.Model(&model.Poll{}).Where("id = ?", pollID).
+					UpdateColumn("voter_count", gorm.Expr("voter_count + ?", cnt.Voters))
+				if result.Error != nil {
+					return result.Error
+				}
+			}
+			for optionID, delta := range cnt.Options {
+				result := tx.Model(&model.PollOption{}).Where("id = ? AND poll_id = ?", optionID, pollID).
+					UpdateColumn("vote_count", gorm.Expr("vote_count + ?", delta))
+				if result.Error != nil {
+					return result.Error
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		// 系统层面错误
+		slog.Error(UpdateFailed, "deltas", deltas, "error", err)
+		return ErrServerInternal
+	}
+
+	// 2. 返回结果
+	return nil
+}
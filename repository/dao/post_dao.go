@@ -9,6 +9,7 @@ import (
 	"github.com/go-sql-driver/mysql"
 	"github.com/yzletter/go-postery/model"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // gormPostDAO 用 Gorm 实现 PostDAO
@@ -46,6 +47,37 @@ func (dao *gormPostDAO) Create(ctx context.Context, post *model.Post) error {
 	return nil
 }
 
+// UpdateWithVersion 仅当版本号为 version 时更新 Post 多个字段并自增版本号, 返回更新后的版本号; 版本不一致时返回当前版本号和 ErrVersionConflict
+func (dao *gormPostDAO) UpdateWithVersion(ctx context.Context, id int64, version int, updates map[string]any) (int, error) {
+	// 1. 操作数据库
+	updates["version"] = gorm.Expr("version + 1")
+	result := dao.db.WithContext(ctx).Model(&model.Post{}).Where("id = ? AND version = ? AND deleted_at IS NULL", id, version).Updates(updates)
+	if result.Error != nil {
+		// 系统层面错误
+		slog.Error(UpdateFailed, "id", id, "version", version, "error", result.Error)
+		return 0, ErrServerInternal
+	}
+	if result.RowsAffected > 0 {
+		return version + 1, nil
+	}
+
+	// 2. 没有更新到, 区分记录不存在和版本不一致
+	var current model.Post
+	result = dao.db.WithContext(ctx).Select("version").Where("id = ? AND deleted_at IS NULL", id).First(&current)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			// 记录不存在
+			return 0, ErrRecordNotFound
+		}
+		// 系统层面错误
+		slog.Error(FindFailed, "id", id, "error", result.Error)
+		return 0, ErrServerInternal
+	}
+
+	// 3. 返回结果
+	return current.Version, ErrVersionConflict
+}
+
 // Delete 删除 Post
 func (dao *gormPostDAO) Delete(ctx context.Context, id int64) error {
 	// 1. 操作数据库
@@ -64,39 +96,154 @@ func (dao *gormPostDAO) Delete(ctx context.Context, id int64) error {
 	return nil
 }
 
-// UpdateCount 更新 Post 的 ViewCount / CommentCount / LikeCount
-func (dao *gormPostDAO) UpdateCount(ctx context.Context, id int64, field model.PostCntField, delta int) error {
-	// 1. 获取更新列名
-	col, err := field.Column()
+// BatchUpdateCount 在同一事务中批量累加多个 Post 的 互动计数
+func (dao *gormPostDAO) BatchUpdateCount(ctx context.Context, deltas map[int64]map[model.PostCntField]int) error {
+	return dao.batchUpdateCount(ctx, deltas, func(col string, val int) any {
+		return gorm.Expr(col+" + ?", val)
+	})
+}
+
+// BatchSetCount 在同一事务中批量覆盖多个 Post 的计数列
+func (dao *gormPostDAO) BatchSetCount(ctx context.Context, counts map[int64]map[model.PostCntField]int) error {
+	return dao.batchUpdateCount(ctx, counts, func(col string, val int) any {
+		return val
+	})
+}
+
+// batchUpdateCount 由 toValue 决定每一列写入的值
+func (dao *gormPostDAO) batchUpdateCount(ctx context.Context, vals map[int64]map[model.PostCntField]int, toValue func(col string, val int) any) error {
+	if len(vals) == 0 {
+		return nil
+	}
+
+	// 1. 在事务中逐个更新
+	err := dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for id, fields := range vals {
+			updates := make(map[string]any, len(fields))
+			for field, val := range fields {
+				col, err := field.Column()
+				if err != nil {
+					return ErrParamsInvalid
+				}
+				updates[col] = toValue(col, val)
+			}
+			if len(updates) == 0 {
+				continue
+			}
+
+			// 帖子不存在或已删除时影响行数为 0, 直接忽略
+			result := tx.Model(&model.Post{}).Where("id = ? AND deleted_at IS NULL", id).UpdateColumns(updates)
+			if result.Error != nil {
+				slog.Error(UpdateFailed, "id", id, "updates", updates, "error", result.Error)
+				return result.Error
+			}
+		}
+		return nil
+	})
 	if err != nil {
-		return ErrParamsInvalid
+		if errors.Is(err, ErrParamsInvalid) {
+			return ErrParamsInvalid
+		}
+		// 系统层面错误
+		return ErrServerInternal
 	}
 
-	// 2. 操作数据库
-	result := dao.db.WithContext(ctx).Model(&model.Post{}).Where("id = ? AND deleted_at IS NULL", id).UpdateColumn(col, gorm.Expr(col+" + ?", delta))
+	// 2. 返回结果
+	return nil
+}
+
+// ListIDs 按 ID 升序获取 cursor 之后的 Post ID, 用于全表分批遍历
+func (dao *gormPostDAO) ListIDs(ctx context.Context, cursor int64, limit int) ([]int64, error) {
+	var ids []int64
+	result := dao.db.WithContext(ctx).Model(&model.Post{}).
+		Where("id > ? AND deleted_at IS NULL", cursor).Order("id ASC").Limit(limit).Pluck("id", &ids)
 	if result.Error != nil {
 		// 系统层面错误
-		slog.Error(UpdateFailed, "id", id, "field", col, "error", result.Error)
-		return ErrServerInternal
+		slog.Error(FindFailed, "cursor", cursor, "limit", limit, "error", result.Error)
+		return nil, ErrServerInternal
 	}
-	if result.RowsAffected == 0 {
-		// 业务层面错误
-		var cnt int64
-		result2 := dao.db.WithContext(ctx).Model(&model.Post{}).Where("id = ? AND deleted_at IS NULL", id).Count(&cnt)
-		if result2.Error != nil {
-			// 系统层面错误
-			slog.Error(FindFailed, "id", id, "error", result2.Error)
+	return ids, nil
+}
+
+// ListForRank 按 ID 升序获取 cursor 之后状态正常的 Post, 只查询计算热度需要的列; since 非零时只查询此后发布的
+func (dao *gormPostDAO) ListForRank(ctx context.Context, since time.Time, cursor int64, limit int) ([]*model.Post, error) {
+	var posts []*model.Post
+	query := dao.db.WithContext(ctx).Model(&model.Post{}).
+		Select("id, user_id, visibility, view_count, unique_view_count, like_count, comment_count, share_count, created_at").
+		Where("id > ? AND status = ? AND deleted_at IS NULL", cursor, model.PostStatusNormal)
+	if !since.IsZero() {
+		query = query.Where("created_at >= ?", since)
+	}
+	result := query.Order("id ASC").Limit(limit).Find(&posts)
+	if result.Error != nil {
+		// 系统层面错误
+		slog.Error(FindFailed, "since", since, "cursor", cursor, "limit", limit, "error", result.Error)
+		return nil, ErrServerInternal
+	}
+	return posts, nil
+}
+
+// RecountInteractive 根据 likes / comments / bookmarks 表重新统计 Post 的点赞数、评论数和收藏数
+func (dao *gormPostDAO) RecountInteractive(ctx context.Context, ids []int64) (map[int64]map[model.PostCntField]int, error) {
+	type row struct {
+		PostID int64
+		Cnt    int
+	}
+
+	// 所有帖子先置 0, 没有记录的帖子计数即为 0
+	res := make(map[int64]map[model.PostCntField]int, len(ids))
+	for _, id := range ids {
+		res[id] = map[model.PostCntField]int{model.PostLikeCount: 0, model.PostCommentCount: 0, model.PostBookmarkCount: 0, model.PostShareCount: 0}
+	}
+	if len(ids) == 0 {
+		return res, nil
+	}
+
+	// cond 为额外的筛选条件及其参数
+	count := func(table string, field model.PostCntField, cond ...any) error {
+		var rows []row
+		query := dao.db.WithContext(ctx).Table(table).Select("post_id, COUNT(*) AS cnt").
+			Where("post_id IN ? AND deleted_at IS NULL", ids)
+		if len(cond) > 0 {
+			query = query.Where(cond[0], cond[1:]...)
+		}
+		result := query.Group("post_id").Scan(&rows)
+		if result.Error != nil {
+			slog.Error(FindFailed, "table", table, "post_ids", ids, "error", result.Error)
 			return ErrServerInternal
 		}
-
-		if cnt == 0 {
-			// 记录不存在
-			return ErrRecordNotFound
+		for _, r := range rows {
+			res[r.PostID][field] = r.Cnt
 		}
+		return nil
 	}
 
-	// 3. 返回结果
-	return nil
+	if err := count("likes", model.PostLikeCount); err != nil {
+		return nil, err
+	}
+	if err := count("comments", model.PostCommentCount, "status = ?", model.CommentStatusNormal); err != nil { // 待审核的评论不计入
+		return nil, err
+	}
+	if err := count("bookmarks", model.PostBookmarkCount); err != nil {
+		return nil, err
+	}
+	if err := count("reposts", model.PostShareCount); err != nil {
+		return nil, err
+	}
+
+	// 转发数还包括未删除的引用转发帖子
+	var quotes []row
+	result := dao.db.WithContext(ctx).Model(&model.Post{}).Select("quote_id AS post_id, COUNT(*) AS cnt").
+		Where("quote_id IN ? AND deleted_at IS NULL", ids).Group("quote_id").Scan(&quotes)
+	if result.Error != nil {
+		slog.Error(FindFailed, "table", "posts", "quote_ids", ids, "error", result.Error)
+		return nil, ErrServerInternal
+	}
+	for _, r := range quotes {
+		res[r.PostID][model.PostShareCount] += r.Cnt
+	}
+
+	return res, nil
 }
 
 // Update 更新 Post 多个字段
@@ -149,15 +296,35 @@ func (dao *gormPostDAO) GetByID(ctx context.Context, id int64) (*model.Post, err
 	return post, nil
 }
 
-// GetByUid 根据 UserID 查找 Post
-func (dao *gormPostDAO) GetByUid(ctx context.Context, id int64, pageNo, pageSize int) (int64, []*model.Post, error) {
+// GetByIDs 根据多个 ID 批量查找 Post, 不存在的 ID 直接忽略
+func (dao *gormPostDAO) GetByIDs(ctx context.Context, ids []int64) ([]*model.Post, error) {
+	// 0. 兜底
+	var posts []*model.Post
+	if len(ids) == 0 {
+		return posts, nil
+	}
+
+	// 1. 操作数据库
+	result := dao.db.WithContext(ctx).Where("id IN ? AND deleted_at IS NULL", ids).Find(&posts)
+	if result.Error != nil {
+		// 系统层面错误
+		slog.Error(FindFailed, "ids", ids, "error", result.Error)
+		return nil, ErrServerInternal
+	}
+
+	// 2. 返回结果
+	return posts, nil
+}
+
+// GetByUid 根据 UserID 查找可见范围不超过 maxVisibility 的 Post
+func (dao *gormPostDAO) GetByUid(ctx context.Context, id int64, maxVisibility model.PostVisibility, pageNo, pageSize int) (int64, []*model.Post, error) {
 	// 0. 兜底
 	if pageNo < 1 || pageSize <= 0 || pageSize > 100 {
 		return 0, nil, ErrParamsInvalid
 	}
 
 	// 1. 操作数据库
-	base := dao.db.WithContext(ctx).Model(&model.Post{}).Where("user_id = ? AND deleted_at IS NULL", id)
+	base := dao.db.WithContext(ctx).Model(&model.Post{}).Where("user_id = ? AND status = ? AND visibility <= ? AND deleted_at IS NULL", id, model.PostStatusNormal, maxVisibility)
 
 	// 2. 获取总数
 	var total int64
@@ -185,34 +352,36 @@ func (dao *gormPostDAO) GetByUid(ctx context.Context, id int64, pageNo, pageSize
 	return total, posts, nil
 }
 
-// GetByPage 按页查找 Post
-func (dao *gormPostDAO) GetByPage(ctx context.Context, pageNo, pageSize int) (int64, []*model.Post, error) {
+// GetByPage 按 offset / limit 查找可见范围不超过 maxVisibility 的 Post, 跳过 excludeIDs 中的帖子; limit 为 0 时只返回总数
+func (dao *gormPostDAO) GetByPage(ctx context.Context, maxVisibility model.PostVisibility, excludeIDs []int64, offset, limit int) (int64, []*model.Post, error) {
 	// 0. 兜底
-	if pageNo < 1 || pageSize <= 0 || pageSize > 100 {
+	if offset < 0 || limit < 0 || limit > 100 {
 		return 0, nil, ErrParamsInvalid
 	}
 
 	// 1. 操作数据库
-	base := dao.db.WithContext(ctx).Model(&model.Post{}).Where("deleted_at IS NULL")
+	base := dao.db.WithContext(ctx).Model(&model.Post{}).Where("status = ? AND visibility <= ? AND deleted_at IS NULL", model.PostStatusNormal, maxVisibility)
+	if len(excludeIDs) > 0 {
+		base = base.Where("id NOT IN ?", excludeIDs)
+	}
 
 	// 2. 获取总数
 	var total int64
 	result := base.Count(&total)
 	if result.Error != nil {
 		// 系统层面错误
-		slog.Error(FindFailed, "pageNo", pageNo, "pageSize", pageSize, "error", result.Error)
+		slog.Error(FindFailed, "offset", offset, "limit", limit, "error", result.Error)
 		return 0, nil, ErrServerInternal
-	} else if total == 0 {
-		return 0, []*model.Post{}, nil
+	} else if total == 0 || limit == 0 {
+		return total, []*model.Post{}, nil
 	}
 
 	// 3. 获取帖子
 	var posts []*model.Post
-	offset := (pageNo - 1) * pageSize
-	result = base.Order("created_at DESC").Offset(offset).Limit(pageSize).Find(&posts)
+	result = base.Order("created_at DESC").Offset(offset).Limit(limit).Find(&posts)
 	if result.Error != nil {
 		// 系统层面错误
-		slog.Error(FindFailed, "pageNo", pageNo, "pageSize", pageSize, "error", result.Error)
+		slog.Error(FindFailed, "offset", offset, "limit", limit, "error", result.Error)
 		return 0, nil, ErrServerInternal
 	}
 
@@ -220,23 +389,61 @@ func (dao *gormPostDAO) GetByPage(ctx context.Context, pageNo, pageSize int) (in
 	return total, posts, nil
 }
 
-// GetByPageAndTag 根据 TagID 按页查找 Post
-func (dao *gormPostDAO) GetByPageAndTag(ctx context.Context, tid int64, pageNo, pageSize int) (int64, []*model.Post, error) {
+// GetByPageAndTag 根据 TagID 按 offset / limit 查找可见范围不超过 maxVisibility 的 Post, 跳过 excludeIDs 中的帖子; limit 为 0 时只返回总数
+func (dao *gormPostDAO) GetByPageAndTag(ctx context.Context, tid int64, maxVisibility model.PostVisibility, excludeIDs []int64, offset, limit int) (int64, []*model.Post, error) {
 	// 0. 兜底
-	if pageNo < 1 || pageSize <= 0 || pageSize > 100 {
+	if offset < 0 || limit < 0 || limit > 100 {
 		return 0, nil, ErrParamsInvalid
 	}
 
 	// 1. 操作数据库
 	base := dao.db.WithContext(ctx).Table("posts p").
-		Joins("JOIN post_tag pt ON p.id = pt.post_id").Where("pt.tag_id = ? AND p.deleted_at IS NULL", tid)
+		Joins("JOIN post_tag pt ON p.id = pt.post_id").Where("pt.tag_id = ? AND p.status = ? AND p.visibility <= ? AND p.deleted_at IS NULL", tid, model.PostStatusNormal, maxVisibility)
+	if len(excludeIDs) > 0 {
+		base = base.Where("p.id NOT IN ?", excludeIDs)
+	}
 
 	// 2. 获取总数
 	var total int64
 	result := base.Count(&total)
 	if result.Error != nil {
 		// 系统层面错误
-		slog.Error(FindFailed, "tag_id", tid, "pageNo", pageNo, "pageSize", pageSize, "error", result.Error)
+		slog.Error(FindFailed, "tag_id", tid, "offset", offset, "limit", limit, "error", result.Error)
+		return 0, nil, ErrServerInternal
+	} else if total == 0 || limit == 0 {
+		return total, []*model.Post{}, nil
+	}
+
+	// 3. 获取帖子
+	var posts []*model.Post
+	result = base.Select("p.*").Order("p.created_at DESC").Offset(offset).Limit(limit).Find(&posts)
+	if result.Error != nil {
+		// 系统层面错误
+		slog.Error(FindFailed, "tag_id", tid, "offset", offset, "limit", limit, "error", result.Error)
+		return 0, nil, ErrServerInternal
+	}
+
+	// 4. 返回结果
+	return total, posts, nil
+}
+
+// GetFeatured 按页查找所有人可见的精华 Post, 按加精时间倒序排序
+func (dao *gormPostDAO) GetFeatured(ctx context.Context, pageNo, pageSize int) (int64, []*model.Post, error) {
+	// 0. 兜底
+	if pageNo < 1 || pageSize <= 0 || pageSize > 100 {
+		return 0, nil, ErrParamsInvalid
+	}
+
+	// 1. 操作数据库
+	base := dao.db.WithContext(ctx).Model(&model.Post{}).
+		Where("featured_at IS NOT NULL AND status = ? AND visibility = ? AND deleted_at IS NULL", model.PostStatusNormal, model.PostVisibilityPublic)
+
+	// 2. 获取总数
+	var total int64
+	result := base.Count(&total)
+	if result.Error != nil {
+		// 系统层面错误
+		slog.Error(FindFailed, "pageNo", pageNo, "pageSize", pageSize, "error", result.Error)
 		return 0, nil, ErrServerInternal
 	} else if total == 0 {
 		return 0, []*model.Post{}, nil
@@ -245,13 +452,143 @@ func (dao *gormPostDAO) GetByPageAndTag(ctx context.Context, tid int64, pageNo,
 	// 3. 获取帖子
 	var posts []*model.Post
 	offset := (pageNo - 1) * pageSize
-	result = base.Order("p.created_at DESC").Offset(offset).Limit(pageSize).Find(&posts)
+	result = base.Order("featured_at DESC").Offset(offset).Limit(pageSize).Find(&posts)
 	if result.Error != nil {
 		// 系统层面错误
-		slog.Error(FindFailed, "tag_id", tid, "pageNo", pageNo, "pageSize", pageSize, "error", result.Error)
+		slog.Error(FindFailed, "pageNo", pageNo, "pageSize", pageSize, "error", result.Error)
 		return 0, nil, ErrServerInternal
 	}
 
 	// 4. 返回结果
 	return total, posts, nil
 }
+
+// Pin 置顶 Post, 已置顶时更新过期时间并重新排到最前
+func (dao *gormPostDAO) Pin(ctx context.Context, pin *model.PostPin) error {
+	// 0. 兜底
+	if pin == nil || pin.ID == 0 || pin.PostID == 0 || pin.PinnedBy == 0 {
+		return ErrParamsInvalid
+	}
+
+	// 1. 操作数据库
+	result := dao.db.WithContext(ctx).Clauses(clause.OnConflict{
+		DoUpdates: clause.AssignmentColumns([]string{"pinned_by", "expires_at", "created_at"}),
+	}).Create(pin)
+	if result.Error != nil {
+		// 系统层面错误
+		slog.Error(CreateFailed, "pin", pin, "error", result.Error)
+		return ErrServerInternal
+	}
+
+	// 2. 返回结果
+	return nil
+}
+
+// Unpin 取消 Post 在 tid 范围内的置顶, tid 为 0 表示全站
+func (dao *gormPostDAO) Unpin(ctx context.Context, pid, tid int64) error {
+	// 1. 操作数据库
+	result := dao.db.WithContext(ctx).Where("post_id = ? AND tag_id = ?", pid, tid).Delete(&model.PostPin{})
+	if result.Error != nil {
+		// 系统层面错误
+		slog.Error(DeleteFailed, "post_id", pid, "tag_id", tid, "error", result.Error)
+		return ErrServerInternal
+	} else if result.RowsAffected == 0 {
+		// 业务层面错误
+		return ErrRecordNotFound
+	}
+
+	// 2. 返回结果
+	return nil
+}
+
+// GetPinnedIDs 查找 tid 范围内在 now 时仍有效的置顶 Post ID, 最晚置顶的排在前面, tid 为 0 表示全站
+func (dao *gormPostDAO) GetPinnedIDs(ctx context.Context, tid int64, now time.Time, limit int) ([]int64, error) {
+	// 1. 操作数据库
+	var ids []int64
+	result := dao.db.WithContext(ctx).Model(&model.PostPin{}).
+		Where("tag_id = ? AND (expires_at IS NULL OR expires_at > ?)", tid, now).
+		Order("created_at DESC").Limit(limit).Pluck("post_id", &ids)
+	if result.Error != nil {
+		// 系统层面错误
+		slog.Error(FindFailed, "tag_id", tid, "error", result.Error)
+		return nil, ErrServerInternal
+	}
+
+	// 2. 返回结果
+	return ids, nil
+}
+
+// AssignSlug 将 slug 设为帖子的当前 slug, 并保留记录用于旧链接重定向; slug 已属于其他帖子时返回 ErrUniqueKey
+func (dao *gormPostDAO) AssignSlug(ctx context.Context, postSlug *model.PostSlug) error {
+	// 0. 兜底
+	if postSlug == nil || postSlug.ID == 0 || postSlug.PostID == 0 || postSlug.Slug == "" {
+		return ErrParamsInvalid
+	}
+
+	// 1. 在事务中操作数据库
+	err := dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 锁住已有的 slug 记录, 帖子改回用过的标题时复用自己的旧 slug
+		var existing model.PostSlug
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("slug = ?", postSlug.Slug).Limit(1).Find(&existing)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected > 0 {
+			if existing.PostID != postSlug.PostID {
+				return ErrUniqueKey
+			}
+		} else if err := tx.Create(postSlug).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&model.Post{}).Where("id = ?", postSlug.PostID).UpdateColumn("slug", postSlug.Slug).Error
+	})
+	if err != nil {
+		var mysqlErr *mysql.MySQLError
+		if errors.Is(err, ErrUniqueKey) || (errors.As(err, &mysqlErr) && mysqlErr.Number == 1062) {
+			// 业务层面错误
+			return ErrUniqueKey
+		}
+		// 系统层面错误
+		slog.Error(UpdateFailed, "post_slug", postSlug, "error", err)
+		return ErrServerInternal
+	}
+
+	// 2. 返回结果
+	return nil
+}
+
+// GetIDBySlug 根据 slug 查找帖子 ID, 包括标题修改前用过的 slug
+func (dao *gormPostDAO) GetIDBySlug(ctx context.Context, slug string) (int64, error) {
+	// 1. 操作数据库
+	var postSlug model.PostSlug
+	result := dao.db.WithContext(ctx).Where("slug = ?", slug).First(&postSlug)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			// 业务层面错误
+			return 0, ErrRecordNotFound
+		}
+		// 系统层面错误
+		slog.Error(FindFailed, "slug", slug, "error", result.Error)
+		return 0, ErrServerInternal
+	}
+
+	// 2. 返回结果
+	return postSlug.PostID, nil
+}
+
+// ListWithoutSlug 按 ID 分批获取尚未生成 slug 的帖子, 只查询 id 和 title
+func (dao *gormPostDAO) ListWithoutSlug(ctx context.Context, cursor int64, limit int) ([]*model.Post, error) {
+	// 1. 操作数据库
+	var posts []*model.Post
+	result := dao.db.WithContext(ctx).Model(&model.Post{}).Select("id, title").
+		Where("id > ? AND slug = '' AND deleted_at IS NULL", cursor).Order("id ASC").Limit(limit).Find(&posts)
+	if result.Error != nil {
+		// 系统层面错误
+		slog.Error(FindFailed, "cursor", cursor, "limit", limit, "error", result.Error)
+		return nil, ErrServerInternal
+	}
+
+	// 2. 返回结果
+	return posts, nil
+}
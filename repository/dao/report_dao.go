@@ -0,0 +1,206 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/yzletter/go-postery/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// gormReportDAO 用 Gorm 实现 ReportDAO
+type gormReportDAO struct {
+	db *gorm.DB
+}
+
+// NewReportDAO 构造函数
+func NewReportDAO(db *gorm.DB) ReportDAO {
+	return &gormReportDAO{db: db}
+}
+
+// Create 创建 Report
+func (dao *gormReportDAO) Create(ctx context.Context, report *model.Report) error {
+	// 0. 兜底
+	if report == nil || report.ID == 0 || report.ReporterID == 0 || report.TargetID == 0 {
+		return ErrParamsInvalid
+	}
+
+	// 1. 操作数据库
+	result := dao.db.WithContext(ctx).Create(report)
+	if result.Error != nil {
+		// 业务层面错误
+		var mysqlErr *mysql.MySQLError
+		if errors.As(result.Error, &mysqlErr) && mysqlErr.Number == 1062 { // 已经举报过
+			return ErrUniqueKey
+		}
+
+		// 系统层面错误
+		slog.Error(CreateFailed, "report", report, "error", result.Error)
+		return ErrServerInternal
+	}
+
+	// 2. 返回结果
+	return nil
+}
+
+// Submit 系统自动提交举报, ReporterID 为 0; 同一对象已被系统举报过时重新置为待处理
+func (dao *gormReportDAO) Submit(ctx context.Context, report *model.Report) error {
+	// 0. 兜底
+	if report == nil || report.ID == 0 || report.ReporterID != 0 || report.TargetID == 0 {
+		return ErrParamsInvalid
+	}
+
+	// 1. 操作数据库
+	result := dao.db.WithContext(ctx).Clauses(clause.OnConflict{
+		DoUpdates: clause.Assignments(map[string]any{
+			"reason":     report.Reason,
+			"detail":     report.Detail,
+			"status":     model.ReportPending,
+			"handled_by": 0,
+			"handled_at": nil,
+			"created_at": gorm.Expr("NOW()"),
+		}),
+	}).Create(report)
+	if result.Error != nil {
+		// 系统层面错误
+		slog.Error(CreateFailed, "report", report, "error", result.Error)
+		return ErrServerInternal
+	}
+
+	// 2. 返回结果
+	return nil
+}
+
+// GetPendingSummaries 按举报对象聚合待处理的举报, 举报数多的排在前面, targetType 为 0 时不筛选类型
+func (dao *gormReportDAO) GetPendingSummaries(ctx context.Context, targetType model.ReportTargetType, pageNo, pageSize int) (int64, []*model.ReportSummary, error) {
+	// 0. 兜底
+	if pageNo < 1 || pageSize <= 0 || pageSize > 100 {
+		return 0, nil, ErrParamsInvalid
+	}
+
+	// 1. 操作数据库, 计数和查询分别使用新的查询, 避免条件互相污染
+	grouped := func() *gorm.DB {
+		query := dao.db.WithContext(ctx).Model(&model.Report{}).Where("status = ?", model.ReportPending)
+		if targetType != 0 {
+			query = query.Where("target_type = ?", targetType)
+		}
+		return query.Group("target_type, target_id")
+	}
+
+	// 2. 获取举报对象总数
+	var total int64
+	result := dao.db.WithContext(ctx).Table("(?) AS t", grouped().Select("1")).Count(&total)
+	if result.Error != nil {
+		// 系统层面错误
+		slog.Error(FindFailed, "target_type", targetType, "pageNo", pageNo, "pageSize", pageSize, "error", result.Error)
+		return 0, nil, ErrServerInternal
+	} else if total == 0 {
+		return 0, []*model.ReportSummary{}, nil
+	}
+
+	// 3. 获取聚合结果
+	var summaries []*model.ReportSummary
+	offset := (pageNo - 1) * pageSize
+	result = grouped().Select("target_type, target_id, COUNT(*) AS report_count, GROUP_CONCAT(DISTINCT reason) AS reasons, " +
+		"MIN(created_at) AS first_reported_at, MAX(created_at) AS last_reported_at").
+		Order("report_count DESC, last_reported_at DESC").Offset(offset).Limit(pageSize).Scan(&summaries)
+	if result.Error != nil {
+		// 系统层面错误
+		slog.Error(FindFailed, "target_type", targetType, "pageNo", pageNo, "pageSize", pageSize, "error", result.Error)
+		return 0, nil, ErrServerInternal
+	}
+
+	// 4. 返回结果
+	return total, summaries, nil
+}
+
+// HasPending 判断对象是否有待处理的举报
+func (dao *gormReportDAO) HasPending(ctx context.Context, targetType model.ReportTargetType, targetID int64) (bool, error) {
+	var cnt int64
+	result := dao.db.WithContext(ctx).Model(&model.Report{}).
+		Where("target_type = ? AND target_id = ? AND status = ?", targetType, targetID, model.ReportPending).
+		Limit(1).Count(&cnt)
+	if result.Error != nil {
+		// 系统层面错误
+		slog.Error(FindFailed, "target_type", targetType, "target_id", targetID, "error", result.Error)
+		return false, ErrServerInternal
+	}
+	return cnt > 0, nil
+}
+
+// Resolve 将对象的待处理举报标记为处理结果并写入处理记录, 没有待处理的举报时返回 ErrRecordNotFound
+func (dao *gormReportDAO) Resolve(ctx context.Context, log *model.ModerationLog) error {
+	// 0. 兜底
+	if log == nil || log.ID == 0 || log.OperatorID == 0 || log.TargetID == 0 {
+		return ErrParamsInvalid
+	}
+
+	// 1. 在事务中更新举报并记录
+	err := dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		result := tx.Model(&model.Report{}).
+			Where("target_type = ? AND target_id = ? AND status = ?", log.TargetType, log.TargetID, model.ReportPending).
+			Updates(map[string]any{"status": log.Action, "handled_by": log.OperatorID, "handled_at": &now})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrRecordNotFound
+		}
+
+		log.ReportCount = int(result.RowsAffected)
+		return tx.Create(log).Error
+	})
+	if err != nil {
+		// 业务层面错误
+		if errors.Is(err, ErrRecordNotFound) {
+			return ErrRecordNotFound
+		}
+
+		// 系统层面错误
+		slog.Error(UpdateFailed, "moderation_log", log, "error", err)
+		return ErrServerInternal
+	}
+
+	// 2. 返回结果
+	return nil
+}
+
+// GetLogs 按页返回处理记录并按时间倒序排序
+func (dao *gormReportDAO) GetLogs(ctx context.Context, pageNo, pageSize int) (int64, []*model.ModerationLog, error) {
+	// 0. 兜底
+	if pageNo < 1 || pageSize <= 0 || pageSize > 100 {
+		return 0, nil, ErrParamsInvalid
+	}
+
+	// 1. 操作数据库
+	base := dao.db.WithContext(ctx).Model(&model.ModerationLog{})
+
+	// 2. 获取总数
+	var total int64
+	result := base.Count(&total)
+	if result.Error != nil {
+		// 系统层面错误
+		slog.Error(FindFailed, "pageNo", pageNo, "pageSize", pageSize, "error", result.Error)
+		return 0, nil, ErrServerInternal
+	} else if total == 0 {
+		return 0, []*model.ModerationLog{}, nil
+	}
+
+	// 3. 获取记录
+	var logs []*model.ModerationLog
+	offset := (pageNo - 1) * pageSize
+	result = base.Order("created_at DESC").Offset(offset).Limit(pageSize).Find(&logs)
+	if result.Error != nil {
+		// 系统层面错误
+		slog.Error(FindFailed, "pageNo", pageNo, "pageSize", pageSize, "error", result.Error)
+		return 0, nil, ErrServerInternal
+	}
+
+	// 4. 返回结果
+	return total, logs, nil
+}
@@ -0,0 +1,123 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/yzletter/go-postery/model"
+	"gorm.io/gorm"
+)
+
+// gormRepostDAO 用 Gorm 实现 RepostDAO
+type gormRepostDAO struct {
+	db *gorm.DB
+}
+
+// NewRepostDAO 构造函数
+func NewRepostDAO(db *gorm.DB) RepostDAO {
+	return &gormRepostDAO{db: db}
+}
+
+// Create 创建 Repost, 已转发时返回 ErrUniqueKey
+func (dao *gormRepostDAO) Create(ctx context.Context, repost *model.Repost) error {
+	// 0. 兜底
+	if repost == nil || repost.UserID == 0 || repost.PostID == 0 {
+		return ErrParamsInvalid
+	}
+
+	// 1. 恢复软删除, 转发时间更新为本次转发
+	result := dao.db.WithContext(ctx).Model(&model.Repost{}).
+		Where("user_id = ? AND post_id = ? AND deleted_at IS NOT NULL", repost.UserID, repost.PostID).
+		Updates(map[string]any{"deleted_at": nil, "created_at": time.Now()})
+	if result.Error != nil {
+		// 系统层面错误
+		slog.Error(UpdateFailed, "repost", repost, "error", result.Error)
+		return ErrServerInternal
+	}
+	if result.RowsAffected > 0 {
+		// 恢复成功
+		return nil
+	}
+
+	// 2. 创建新记录
+	result = dao.db.WithContext(ctx).Create(repost)
+	if result.Error != nil {
+		var mysqlErr *mysql.MySQLError
+		if errors.As(result.Error, &mysqlErr) && mysqlErr.Number == 1062 { // 记录没有被软删且已存在 -> 已经转发
+			// 业务层面错误
+			return ErrUniqueKey
+		}
+
+		// 系统层面错误
+		slog.Error(CreateFailed, "repost", repost, "error", result.Error)
+		return ErrServerInternal
+	}
+
+	return nil
+}
+
+// Delete 删除 Repost, 尚未转发时返回 ErrRecordNotFound
+func (dao *gormRepostDAO) Delete(ctx context.Context, uid, pid int64) error {
+	now := time.Now()
+	result := dao.db.WithContext(ctx).Model(&model.Repost{}).Where("user_id = ? AND post_id = ? AND deleted_at IS NULL", uid, pid).Update("deleted_at", &now)
+	if result.Error != nil {
+		// 系统层面错误
+		slog.Error(DeleteFailed, "user_id", uid, "post_id", pid, "error", result.Error)
+		return ErrServerInternal
+	}
+	if result.RowsAffected == 0 {
+		// 业务层面错误
+		return ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// Exists 判断 Repost 存在
+func (dao *gormRepostDAO) Exists(ctx context.Context, uid, pid int64) (bool, error) {
+	var cnt int64
+	result := dao.db.WithContext(ctx).Model(&model.Repost{}).Where("user_id = ? AND post_id = ? AND deleted_at IS NULL", uid, pid).Count(&cnt)
+	if result.Error != nil {
+		// 系统层面错误
+		slog.Error(FindFailed, "user_id", uid, "post_id", pid, "error", result.Error)
+		return false, ErrServerInternal
+	}
+	return cnt > 0, nil
+}
+
+// GetPostIDs 按页获取用户转发的帖子 ID, 最近转发的在前
+func (dao *gormRepostDAO) GetPostIDs(ctx context.Context, uid int64, pageNo, pageSize int) (int64, []int64, error) {
+	// 0. 兜底
+	if pageNo < 1 || pageSize <= 0 || pageSize > 100 {
+		return 0, nil, ErrParamsInvalid
+	}
+
+	// 1. 操作数据库
+	base := dao.db.WithContext(ctx).Model(&model.Repost{}).Where("user_id = ? AND deleted_at IS NULL", uid)
+
+	// 2. 获取总数
+	var total int64
+	result := base.Count(&total)
+	if result.Error != nil {
+		// 系统层面错误
+		slog.Error(FindFailed, "user_id", uid, "error", result.Error)
+		return 0, nil, ErrServerInternal
+	} else if total == 0 {
+		return 0, []int64{}, nil
+	}
+
+	// 3. 获取帖子 ID
+	var ids []int64
+	result = base.Order("created_at DESC").Offset((pageNo-1)*pageSize).Limit(pageSize).Pluck("post_id", &ids)
+	if result.Error != nil {
+		// 系统层面错误
+		slog.Error(FindFailed, "user_id", uid, "pageNo", pageNo, "pageSize", pageSize, "error", result.Error)
+		return 0, nil, ErrServerInternal
+	}
+
+	// 4. 返回结果
+	return total, ids, nil
+}
@@ -0,0 +1,146 @@
+package dao
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/yzletter/go-postery/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// gormSensitiveDAO 用 Gorm 实现 SensitiveDAO
+type gormSensitiveDAO struct {
+	db *gorm.DB
+}
+
+// NewSensitiveDAO 构造函数
+func NewSensitiveDAO(db *gorm.DB) SensitiveDAO {
+	return &gormSensitiveDAO{db: db}
+}
+
+// CreateWords 批量创建敏感词, 已存在的词会被跳过, 返回实际新增的个数
+func (dao *gormSensitiveDAO) CreateWords(ctx context.Context, words []*model.SensitiveWord) (int, error) {
+	// 0. 兜底
+	if len(words) == 0 {
+		return 0, ErrParamsInvalid
+	}
+
+	// 1. 操作数据库
+	result := dao.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(words)
+	if result.Error != nil {
+		// 系统层面错误
+		slog.Error(CreateFailed, "words", len(words), "error", result.Error)
+		return 0, ErrServerInternal
+	}
+
+	// 2. 返回结果
+	return int(result.RowsAffected), nil
+}
+
+// DeleteWord 删除敏感词
+func (dao *gormSensitiveDAO) DeleteWord(ctx context.Context, id int64) error {
+	// 1. 操作数据库
+	result := dao.db.WithContext(ctx).Where("id = ?", id).Delete(&model.SensitiveWord{})
+	if result.Error != nil {
+		// 系统层面错误
+		slog.Error(DeleteFailed, "id", id, "error", result.Error)
+		return ErrServerInternal
+	} else if result.RowsAffected == 0 {
+		// 业务层面错误
+		return ErrRecordNotFound
+	}
+
+	// 2. 返回结果
+	return nil
+}
+
+// GetAllWords 返回全部敏感词, 用于构建匹配自动机
+func (dao *gormSensitiveDAO) GetAllWords(ctx context.Context) ([]*model.SensitiveWord, error) {
+	// 1. 操作数据库
+	var words []*model.SensitiveWord
+	result := dao.db.WithContext(ctx).Model(&model.SensitiveWord{}).Select("id, word, category").Find(&words)
+	if result.Error != nil {
+		// 系统层面错误
+		slog.Error(FindFailed, "error", result.Error)
+		return nil, ErrServerInternal
+	}
+
+	// 2. 返回结果
+	return words, nil
+}
+
+// GetWordsByPage 按页返回敏感词并按时间倒序排序, category 为空时不筛选分类
+func (dao *gormSensitiveDAO) GetWordsByPage(ctx context.Context, category string, pageNo, pageSize int) (int64, []*model.SensitiveWord, error) {
+	// 0. 兜底
+	if pageNo < 1 || pageSize <= 0 || pageSize > 100 {
+		return 0, nil, ErrParamsInvalid
+	}
+
+	// 1. 操作数据库
+	base := dao.db.WithContext(ctx).Model(&model.SensitiveWord{})
+	if category != "" {
+		base = base.Where("category = ?", category)
+	}
+
+	// 2. 获取总数
+	var total int64
+	result := base.Count(&total)
+	if result.Error != nil {
+		// 系统层面错误
+		slog.Error(FindFailed, "category", category, "pageNo", pageNo, "pageSize", pageSize, "error", result.Error)
+		return 0, nil, ErrServerInternal
+	} else if total == 0 {
+		return 0, []*model.SensitiveWord{}, nil
+	}
+
+	// 3. 获取敏感词
+	var words []*model.SensitiveWord
+	offset := (pageNo - 1) * pageSize
+	result = base.Order("created_at DESC, id DESC").Offset(offset).Limit(pageSize).Find(&words)
+	if result.Error != nil {
+		// 系统层面错误
+		slog.Error(FindFailed, "category", category, "pageNo", pageNo, "pageSize", pageSize, "error", result.Error)
+		return 0, nil, ErrServerInternal
+	}
+
+	// 4. 返回结果
+	return total, words, nil
+}
+
+// GetAllCategories 返回全部分类
+func (dao *gormSensitiveDAO) GetAllCategories(ctx context.Context) ([]*model.SensitiveCategory, error) {
+	// 1. 操作数据库
+	var categories []*model.SensitiveCategory
+	result := dao.db.WithContext(ctx).Model(&model.SensitiveCategory{}).Order("name ASC").Find(&categories)
+	if result.Error != nil {
+		// 系统层面错误
+		slog.Error(FindFailed, "error", result.Error)
+		return nil, ErrServerInternal
+	}
+
+	// 2. 返回结果
+	return categories, nil
+}
+
+// SaveCategory 创建分类, 分类已存在时更新处理动作
+func (dao *gormSensitiveDAO) SaveCategory(ctx context.Context, category *model.SensitiveCategory) error {
+	// 0. 兜底
+	if category == nil || category.ID == 0 || category.Name == "" {
+		return ErrParamsInvalid
+	}
+
+	// 1. 操作数据库
+	result := dao.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"action", "updated_at"}),
+	}).Create(category)
+	if result.Error != nil {
+		// 系统层面错误
+		slog.Error(UpdateFailed, "category", category, "error", result.Error)
+		return ErrServerInternal
+	}
+
+	// 2. 返回结果
+	return nil
+}
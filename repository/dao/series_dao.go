@@ -0,0 +1,312 @@
+package dao
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/yzletter/go-postery/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type gormSeriesDAO struct {
+	db *gorm.DB
+}
+
+func NewSeriesDAO(db *gorm.DB) SeriesDAO {
+	return &gormSeriesDAO{db: db}
+}
+
+// Create 创建 Series
+func (dao *gormSeriesDAO) Create(ctx context.Context, series *model.Series) error {
+	// 1. 操作数据库
+	result := dao.db.WithContext(ctx).Create(series)
+	if result.Error != nil {
+		// 系统层面错误
+		slog.Error(CreateFailed, "series", series, "error", result.Error)
+		return ErrServerInternal
+	}
+
+	// 2. 返回结果
+	return nil
+}
+
+// GetByID 根据 ID 查找 Series
+func (dao *gormSeriesDAO) GetByID(ctx context.Context, id int64) (*model.Series, error) {
+	// 1. 操作数据库
+	var series model.Series
+	result := dao.db.WithContext(ctx).Where("id = ? AND deleted_at IS NULL", id).First(&series)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			// 业务层面错误
+			return nil, ErrRecordNotFound
+		}
+		// 系统层面错误
+		slog.Error(FindFailed, "id", id, "error", result.Error)
+		return nil, ErrServerInternal
+	}
+
+	// 2. 返回结果
+	return &series, nil
+}
+
+// GetByUid 按页查找用户的 Series, 最新创建的在前
+func (dao *gormSeriesDAO) GetByUid(ctx context.Context, uid int64, pageNo, pageSize int) (int64, []*model.Series, error) {
+	// 0. 兜底
+	if pageNo < 1 || pageSize <= 0 || pageSize > 100 {
+		return 0, nil, ErrParamsInvalid
+	}
+
+	// 1. 操作数据库
+	base := dao.db.WithContext(ctx).Model(&model.Series{}).Where("user_id = ? AND deleted_at IS NULL", uid)
+
+	// 2. 获取总数
+	var total int64
+	result := base.Count(&total)
+	if result.Error != nil {
+		// 系统层面错误
+		slog.Error(FindFailed, "user_id", uid, "error", result.Error)
+		return 0, nil, ErrServerInternal
+	} else if total == 0 {
+		return 0, []*model.Series{}, nil
+	}
+
+	// 3. 获取系列
+	var series []*model.Series
+	result = base.Order("created_at DESC").Limit(pageSize).Offset((pageNo - 1) * pageSize).Find(&series)
+	if result.Error != nil {
+		// 系统层面错误
+		slog.Error(FindFailed, "user_id", uid, "error", result.Error)
+		return 0, nil, ErrServerInternal
+	}
+
+	// 4. 返回结果
+	return total, series, nil
+}
+
+// Update 更新 Series 多个字段
+func (dao *gormSeriesDAO) Update(ctx context.Context, id int64, updates map[string]any) error {
+	// 1. 操作数据库
+	result := dao.db.WithContext(ctx).Model(&model.Series{}).Where("id = ? AND deleted_at IS NULL", id).Updates(updates)
+	if result.Error != nil {
+		// 系统层面错误
+		slog.Error(UpdateFailed, "id", id, "updates", updates, "error", result.Error)
+		return ErrServerInternal
+	}
+
+	// 2. 返回结果
+	return nil
+}
+
+// Delete 逻辑删除 Series, 同时移除其中所有帖子, 帖子可以再加入其他系列
+func (dao *gormSeriesDAO) Delete(ctx context.Context, id int64) error {
+	// 1. 在事务中操作数据库
+	err := dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.Series{}).Where("id = ? AND deleted_at IS NULL", id).Update("deleted_at", time.Now())
+		if result.Error != nil {
+			return result.Error
+		} else if result.RowsAffected == 0 {
+			return ErrRecordNotFound
+		}
+		return tx.Where("series_id = ?", id).Delete(&model.SeriesPost{}).Error
+	})
+	if err != nil {
+		if errors.Is(err, ErrRecordNotFound) {
+			// 业务层面错误
+			return ErrRecordNotFound
+		}
+		// 系统层面错误
+		slog.Error(DeleteFailed, "id", id, "error", err)
+		return ErrServerInternal
+	}
+
+	// 2. 返回结果
+	return nil
+}
+
+// AddPost 将 Post 追加到 Series 末尾, 返回其位置; Post 已属于某个系列时返回 ErrUniqueKey
+func (dao *gormSeriesDAO) AddPost(ctx context.Context, seriesPost *model.SeriesPost) (int, error) {
+	// 1. 在事务中操作数据库, 先锁住系列行, 同一系列的并发追加按顺序分配位置
+	err := dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var series model.Series
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").
+			Where("id = ? AND deleted_at IS NULL", seriesPost.SeriesID).First(&series)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		var cnt int64
+		if err := tx.Model(&model.SeriesPost{}).Where("series_id = ?", seriesPost.SeriesID).Count(&cnt).Error; err != nil {
+			return err
+		}
+		seriesPost.Position = int(cnt) + 1
+		return tx.Create(seriesPost).Error
+	})
+	if err != nil {
+		var mysqlErr *mysql.MySQLError
+		if errors.As(err, &mysqlErr) && mysqlErr.Number == 1062 {
+			// 业务层面错误
+			return 0, ErrUniqueKey
+		}
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// 业务层面错误
+			return 0, ErrRecordNotFound
+		}
+		// 系统层面错误
+		slog.Error(CreateFailed, "series_post", seriesPost, "error", err)
+		return 0, ErrServerInternal
+	}
+
+	// 2. 返回结果
+	return seriesPost.Position, nil
+}
+
+// RemovePost 将 Post 移出 Series, 其后的帖子依次前移, 保持位置连续
+func (dao *gormSeriesDAO) RemovePost(ctx context.Context, sid, pid int64) error {
+	// 1. 在事务中操作数据库
+	err := dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var seriesPost model.SeriesPost
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("series_id = ? AND post_id = ?", sid, pid).First(&seriesPost)
+		if result.Error != nil {
+			return result.Error
+		}
+		if err := tx.Delete(&seriesPost).Error; err != nil {
+			return err
+		}
+		return tx.Model(&model.SeriesPost{}).Where("series_id = ? AND position > ?", sid, seriesPost.Position).
+			UpdateColumn("position", gorm.Expr("position - 1")).Error
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// 业务层面错误
+			return ErrRecordNotFound
+		}
+		// 系统层面错误
+		slog.Error(DeleteFailed, "series_id", sid, "post_id", pid, "error", err)
+		return ErrServerInternal
+	}
+
+	// 2. 返回结果
+	return nil
+}
+
+// Reorder 按 pids 的顺序重排 Series 中的帖子, pids 必须恰好是系列中的全部帖子, 否则返回 ErrParamsInvalid
+func (dao *gormSeriesDAO) Reorder(ctx context.Context, sid int64, pids []int64) error {
+	// 1. 在事务中操作数据库
+	err := dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var current []int64
+		result := tx.Model(&model.SeriesPost{}).Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("series_id = ?", sid).Pluck("post_id", &current)
+		if result.Error != nil {
+			return result.Error
+		}
+		if !samePostIDs(current, pids) {
+			return ErrParamsInvalid
+		}
+
+		for i, pid := range pids {
+			result = tx.Model(&model.SeriesPost{}).Where("series_id = ? AND post_id = ?", sid, pid).UpdateColumn("position", i+1)
+			if result.Error != nil {
+				return result.Error
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, ErrParamsInvalid) {
+			// 业务层面错误
+			return ErrParamsInvalid
+		}
+		// 系统层面错误
+		slog.Error(UpdateFailed, "series_id", sid, "post_ids", pids, "error", err)
+		return ErrServerInternal
+	}
+
+	// 2. 返回结果
+	return nil
+}
+
+// GetPostIDs 按位置顺序查找 Series 中的所有 Post ID
+func (dao *gormSeriesDAO) GetPostIDs(ctx context.Context, sid int64) ([]int64, error) {
+	// 1. 操作数据库
+	var pids []int64
+	result := dao.db.WithContext(ctx).Model(&model.SeriesPost{}).Where("series_id = ?", sid).Order("position ASC").Pluck("post_id", &pids)
+	if result.Error != nil {
+		// 系统层面错误
+		slog.Error(FindFailed, "series_id", sid, "error", result.Error)
+		return nil, ErrServerInternal
+	}
+
+	// 2. 返回结果
+	return pids, nil
+}
+
+// GetByPostID 查找 Post 所属的系列记录
+func (dao *gormSeriesDAO) GetByPostID(ctx context.Context, pid int64) (*model.SeriesPost, error) {
+	// 1. 操作数据库
+	var seriesPost model.SeriesPost
+	result := dao.db.WithContext(ctx).Where("post_id = ?", pid).First(&seriesPost)
+	if result.Error != nil {
+		if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+			// 业务层面错误
+			return nil, ErrRecordNotFound
+		}
+		// 系统层面错误
+		slog.Error(FindFailed, "post_id", pid, "error", result.Error)
+		return nil, ErrServerInternal
+	}
+
+	// 2. 返回结果
+	return &seriesPost, nil
+}
+
+// CountPosts 统计多个 Series 中的帖子数
+func (dao *gormSeriesDAO) CountPosts(ctx context.Context, sids []int64) (map[int64]int, error) {
+	type row struct {
+		SeriesID int64
+		Cnt      int
+	}
+
+	// 0. 兜底
+	res := make(map[int64]int, len(sids))
+	if len(sids) == 0 {
+		return res, nil
+	}
+
+	// 1. 操作数据库
+	var rows []row
+	result := dao.db.WithContext(ctx).Model(&model.SeriesPost{}).Select("series_id, COUNT(*) AS cnt").
+		Where("series_id IN ?", sids).Group("series_id").Scan(&rows)
+	if result.Error != nil {
+		// 系统层面错误
+		slog.Error(FindFailed, "series_ids", sids, "error", result.Error)
+		return nil, ErrServerInternal
+	}
+
+	// 2. 返回结果
+	for _, r := range rows {
+		res[r.SeriesID] = r.Cnt
+	}
+	return res, nil
+}
+
+// samePostIDs 判断两组 ID 是否为同一集合, b 中不能有重复
+func samePostIDs(a, b []int64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	set := make(map[int64]struct{}, len(a))
+	for _, id := range a {
+		set[id] = struct{}{}
+	}
+	for _, id := range b {
+		if _, ok := set[id]; !ok {
+			return false
+		}
+		delete(set, id)
+	}
+	return true
+}
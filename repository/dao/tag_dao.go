@@ -145,3 +145,79 @@ func (dao *gormTagDAO) FindTagsByPostID(ctx context.Context, pid int64) ([]strin
 	}
 	return names, nil
 }
+
+// FindTagsByPostIDs 批量查找多个 Post 的 Tags
+func (dao *gormTagDAO) FindTagsByPostIDs(ctx context.Context, pids []int64) (map[int64][]string, error) {
+	res := make(map[int64][]string, len(pids))
+	if len(pids) == 0 {
+		return res, nil
+	}
+
+	var rows []struct {
+		PostID int64
+		Name   string
+	}
+	result := dao.db.WithContext(ctx).Table("post_tag pt").Select("pt.post_id, t.name").
+		Joins("JOIN tags t ON t.id = pt.tag_id").
+		Where("pt.post_id IN ? AND pt.deleted_at IS NULL AND t.deleted_at IS NULL", pids).
+		Scan(&rows)
+	if result.Error != nil {
+		// 系统层面错误
+		slog.Error(FindFailed, "post_ids", pids, "error", result.Error)
+		return nil, ErrServerInternal
+	}
+
+	for _, row := range rows {
+		res[row.PostID] = append(res[row.PostID], row.Name)
+	}
+	return res, nil
+}
+
+// GetTagIDsByPostIDs 批量查找多个 Post 绑定的 TagID
+func (dao *gormTagDAO) GetTagIDsByPostIDs(ctx context.Context, pids []int64) (map[int64][]int64, error) {
+	res := make(map[int64][]int64, len(pids))
+	if len(pids) == 0 {
+		return res, nil
+	}
+
+	var postTags []*model.PostTag
+	result := dao.db.WithContext(ctx).Model(&model.PostTag{}).Select("post_id, tag_id").
+		Where("post_id IN ? AND deleted_at IS NULL", pids).Find(&postTags)
+	if result.Error != nil {
+		// 系统层面错误
+		slog.Error(FindFailed, "post_ids", pids, "error", result.Error)
+		return nil, ErrServerInternal
+	}
+
+	for _, pt := range postTags {
+		res[pt.PostID] = append(res[pt.PostID], pt.TagID)
+	}
+	return res, nil
+}
+
+// CountSharedTags 统计与 pid 有共同标签的其他 Post 及共同标签数, 取共同标签最多的 limit 个
+func (dao *gormTagDAO) CountSharedTags(ctx context.Context, pid int64, limit int) (map[int64]int, error) {
+	type row struct {
+		PostID int64
+		Cnt    int
+	}
+
+	// 1. 操作数据库
+	var rows []row
+	result := dao.db.WithContext(ctx).Table("post_tag AS pt").Select("other.post_id, COUNT(*) AS cnt").
+		Joins("JOIN post_tag other ON other.tag_id = pt.tag_id AND other.post_id <> pt.post_id AND other.deleted_at IS NULL").
+		Where("pt.post_id = ? AND pt.deleted_at IS NULL", pid).
+		Group("other.post_id").Order("cnt DESC, other.post_id DESC").Limit(limit).Scan(&rows)
+	if result.Error != nil {
+		// 系统层面错误
+		slog.Error(FindFailed, "post_id", pid, "error", result.Error)
+		return nil, ErrServerInternal
+	}
+
+	// 2. 返回结果
+	res := make(map[int64]int, len(rows))
+	for _, r := range rows {
+		res[r.PostID] = r.Cnt
+	}
+	return res, nil
+}
@@ -120,6 +120,46 @@ func (dao *gormUserDAO) GetByID(ctx context.Context, id int64) (*model.User, err
 	return user, nil
 }
 
+// GetByIDs 根据多个 ID 批量查找不带密码的 User, 不存在的 ID 直接忽略
+func (dao *gormUserDAO) GetByIDs(ctx context.Context, ids []int64) ([]*model.User, error) {
+	// 0. 兜底
+	var users []*model.User
+	if len(ids) == 0 {
+		return users, nil
+	}
+
+	// 1. 操作数据库
+	result := dao.db.WithContext(ctx).Omit("password_hash").Where("id IN ? AND deleted_at IS NULL", ids).Find(&users)
+	if result.Error != nil {
+		// 系统层面错误
+		slog.Error(FindFailed, "ids", ids, "error", result.Error)
+		return nil, ErrServerInternal
+	}
+
+	// 2. 返回结果
+	return users, nil
+}
+
+// GetByUsernames 根据多个 Username 查找 User, 不存在的用户名直接跳过
+func (dao *gormUserDAO) GetByUsernames(ctx context.Context, usernames []string) ([]*model.User, error) {
+	// 0. 兜底
+	var users []*model.User
+	if len(usernames) == 0 {
+		return users, nil
+	}
+
+	// 1. 操作数据库
+	result := dao.db.WithContext(ctx).Omit("password_hash").Where("username IN ? AND deleted_at IS NULL", usernames).Find(&users)
+	if result.Error != nil {
+		// 系统层面错误
+		slog.Error(FindFailed, "usernames", usernames, "error", result.Error)
+		return nil, ErrServerInternal
+	}
+
+	// 2. 返回结果
+	return users, nil
+}
+
 // GetByUsername 根据 User 的 Username 查找带密码的 User
 func (dao *gormUserDAO) GetByUsername(ctx context.Context, username string) (*model.User, error) {
 	// 1. 构造结构体对象
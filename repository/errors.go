@@ -11,6 +11,8 @@ var (
 	ErrRecordNotFound   = errors.New("资源不存在")
 	ErrUniqueKey        = errors.New("唯一键冲突")
 	ErrResourceConflict = errors.New("资源冲突")
+	ErrParamsInvalid    = errors.New("参数有误")
+	ErrLimitExceeded    = errors.New("超出限额")
 )
 
 func toRepositoryErr(err error) error {
@@ -21,6 +23,12 @@ func toRepositoryErr(err error) error {
 		return ErrRecordNotFound
 	case errors.Is(err, dao.ErrUniqueKey):
 		return ErrUniqueKey
+	case errors.Is(err, dao.ErrVersionConflict):
+		return ErrResourceConflict
+	case errors.Is(err, dao.ErrParamsInvalid):
+		return ErrParamsInvalid
+	case errors.Is(err, dao.ErrLimitExceeded):
+		return ErrLimitExceeded
 	default:
 		return ErrServerInternal
 	}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 
+	"github.com/yzletter/go-postery/conf"
 	"github.com/yzletter/go-postery/model"
 	"github.com/yzletter/go-postery/repository/cache"
 	"github.com/yzletter/go-postery/repository/dao"
@@ -42,3 +43,13 @@ func (repo *likeRepository) HasLiked(ctx context.Context, uid, pid int64) (bool,
 
 	return ok, nil
 }
+
+// CountCoLikes 统计点赞 pid 的用户还点赞了哪些帖子及共同点赞人数
+func (repo *likeRepository) CountCoLikes(ctx context.Context, pid int64) (map[int64]int, error) {
+	res, err := repo.dao.CountCoLikes(ctx, pid, conf.PostRelatedLikers, conf.PostRelatedCandidates)
+	if err != nil {
+		return nil, toRepositoryErr(err)
+	}
+
+	return res, nil
+}
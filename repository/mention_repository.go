@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/yzletter/go-postery/model"
+	"github.com/yzletter/go-postery/repository/cache"
+	"github.com/yzletter/go-postery/repository/dao"
+)
+
+type mentionRepository struct {
+	dao   dao.MentionDAO
+	cache cache.MentionCache
+}
+
+func NewMentionRepository(mentionDAO dao.MentionDAO, mentionCache cache.MentionCache) MentionRepository {
+	return &mentionRepository{dao: mentionDAO, cache: mentionCache}
+}
+
+func (repo *mentionRepository) Create(ctx context.Context, mentions []*model.Mention) error {
+	err := repo.dao.Create(ctx, mentions)
+	if err != nil {
+		return toRepositoryErr(err)
+	}
+	return nil
+}
+
+func (repo *mentionRepository) Delete(ctx context.Context, targetType model.MentionTarget, targetID int64, uids []int64) error {
+	err := repo.dao.Delete(ctx, targetType, targetID, uids)
+	if err != nil {
+		return toRepositoryErr(err)
+	}
+	return nil
+}
+
+func (repo *mentionRepository) GetByTargets(ctx context.Context, targetType model.MentionTarget, targetIDs []int64) ([]*model.Mention, error) {
+	mentions, err := repo.dao.GetByTargets(ctx, targetType, targetIDs)
+	if err != nil {
+		return nil, toRepositoryErr(err)
+	}
+	return mentions, nil
+}
@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"github.com/yzletter/go-postery/conf"
+	"github.com/yzletter/go-postery/model"
+	"github.com/yzletter/go-postery/repository/cache"
+	"github.com/yzletter/go-postery/repository/dao"
+)
+
+type pollRepository struct {
+	dao   dao.PollDAO
+	cache cache.PollCache
+}
+
+func NewPollRepository(pollDAO dao.PollDAO, pollCache cache.PollCache) PollRepository {
+	return &pollRepository{dao: pollDAO, cache: pollCache}
+}
+
+func (repo *pollRepository) Create(ctx context.Context, poll *model.Poll, options []*model.PollOption) error {
+	err := repo.dao.Create(ctx, poll, options)
+	if err != nil {
+		return toRepositoryErr(err)
+	}
+	return nil
+}
+
+// GetByPostID 获取帖子的投票, 计数合并了 Redis 中尚未落库的增量
+func (repo *pollRepository) GetByPostID(ctx context.Context, pid int64) (*model.Poll, []*model.PollOption, error) {
+	poll, options, err := repo.dao.GetByPostID(ctx, pid)
+	if err != nil {
+		return nil, nil, toRepositoryErr(err)
+	}
+
+	pending, err := repo.cache.GetPendingCnt(ctx, poll.ID)
+	if err != nil {
+		// 读不到增量时退化为只返回 MySQL 中的值
+		slog.Error("Cache GetPendingCnt Failed", "poll_id", poll.ID, "error", err)
+		return poll, options, nil
+	}
+	poll.VoterCount += pending.Voters
+	for _, option := range options {
+		option.VoteCount += pending.Options[option.ID]
+	}
+	return poll, options, nil
+}
+
+// Vote 记录一个用户的全部选票, 已投过票时返回 ErrUniqueKey; 得票数先计入 Redis, 由定时任务落库
+func (repo *pollRepository) Vote(ctx context.Context, poll *model.Poll, votes []*model.PollVote) error {
+	if len(votes) == 0 {
+		return ErrParamsInvalid
+	}
+	uid := votes[0].UserID
+	optionIDs := make([]int64, 0, len(votes))
+	for _, vote := range votes {
+		optionIDs = append(optionIDs, vote.OptionID)
+	}
+
+	// Redis 中的投票人集合挡住并发的重复投票
+	first, err := repo.cache.Vote(ctx, poll.ID, uid, optionIDs)
+	if err != nil {
+		slog.Error("Cache Vote Failed", "poll_id", poll.ID, "uid", uid, "error", err)
+		return ErrServerInternal
+	}
+	if !first {
+		return ErrUniqueKey
+	}
+
+	if err := repo.dao.CreateVotes(ctx, votes); err != nil {
+		// 选票落库失败, 撤销 Redis 中的计数
+		if err := repo.cache.RevertVote(ctx, poll.ID, uid, optionIDs); err != nil {
+			slog.Error("Cache RevertVote Failed", "poll_id", poll.ID, "uid", uid, "error", err)
+		}
+		return toRepositoryErr(err)
+	}
+	return nil
+}
+
+func (repo *pollRepository) GetVotedOptions(ctx context.Context, pollID, uid int64) ([]int64, error) {
+	optionIDs, err := repo.dao.GetVotedOptions(ctx, pollID, uid)
+	if err != nil {
+		return nil, toRepositoryErr(err)
+	}
+	return optionIDs, nil
+}
+
+func (repo *pollRepository) GetVoters(ctx context.Context, optionID int64, pageNo, pageSize int) (int64, []int64, error) {
+	total, uids, err := repo.dao.GetVoters(ctx, optionID, pageNo, pageSize)
+	if err != nil {
+		return 0, nil, toRepositoryErr(err)
+	}
+	return total, uids, nil
+}
+
+// FlushCount 将 Redis 中的得票数增量批量落库, 返回落库的投票数
+// 每批先读取增量, 落库成功后再从 Redis 扣除, 多个实例的落库任务互斥执行
+func (repo *pollRepository) FlushCount(ctx context.Context, batchSize int) (int, error) {
+	token := uuid.New().String()
+	locked, err := repo.cache.LockPendingCnt(ctx, token, conf.PollCntLockTTL)
+	if err != nil {
+		slog.Error("Cache LockPendingCnt Failed", "error", err)
+		return 0, ErrServerInternal
+	}
+	if !locked {
+		// 其他实例正在落库, 留给下一轮
+		return 0, nil
+	}
+	defer func() {
+		if err := repo.cache.UnlockPendingCnt(ctx, token); err != nil {
+			slog.Error("Cache UnlockPendingCnt Failed", "error", err)
+		}
+	}()
+
+	flushed := 0
+	for {
+		cnt, err := repo.flushBatch(ctx, batchSize)
+		flushed += cnt
+		if err != nil || cnt == 0 {
+			return flushed, err
+		}
+	}
+}
+
+// flushBatch 落库一批增量, 返回落库的投票数
+func (repo *pollRepository) flushBatch(ctx context.Context, batchSize int) (int, error) {
+	deltas, err := repo.cache.PeekPendingCnt(ctx, batchSize)
+	if err != nil {
+		slog.Error("Cache PeekPendingCnt Failed", "error", err)
+		return 0, ErrServerInternal
+	}
+	if len(deltas) == 0 {
+		return 0, nil
+	}
+
+	// 落库失败时增量仍保留在 Redis 中, 等待下次重试
+	if err := repo.dao.BatchIncrCount(ctx, deltas); err != nil {
+		return 0, toRepositoryErr(err)
+	}
+
+	for pollID, cnt := range deltas {
+		if err := repo.cache.AckPendingCnt(ctx, pollID, cnt); err != nil {
+			// 扣除失败会导致增量被重复落库, 留到下一轮前停止
+			slog.Error("Cache AckPendingCnt Failed", "poll_id", pollID, "error", err)
+			return len(deltas), ErrServerInternal
+		}
+	}
+	return len(deltas), nil
+}
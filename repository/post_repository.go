@@ -5,13 +5,14 @@ import (
 	"log/slog"
 	"time"
 
+	"github.com/google/uuid"
+	"github.com/yzletter/go-postery/conf"
 	"github.com/yzletter/go-postery/model"
 	"github.com/yzletter/go-postery/repository/cache"
 	"github.com/yzletter/go-postery/repository/dao"
+	"github.com/yzletter/go-postery/utils"
 )
 
-const OneWeekTimeSecs = 60 * 60 * 24 * 7
-
 type postRepository struct {
 	dao   dao.PostDAO
 	cache cache.PostCache
@@ -30,11 +31,6 @@ func (repo *postRepository) Create(ctx context.Context, post *model.Post) error
 
 	// todo 写 Cache
 
-	// 初始化文章分数
-	err = repo.cache.SetScore(ctx, post.ID)
-	if err != nil {
-		return ErrServerInternal
-	}
 	return nil
 }
 
@@ -45,41 +41,156 @@ func (repo *postRepository) Delete(ctx context.Context, id int64) error {
 	}
 
 	// todo 删 Cache
-	err = repo.cache.DeleteScore(ctx, id)
+	if err := repo.cache.DeletePendingCnt(ctx, id); err != nil {
+		slog.Error("Cache DeletePendingCnt Failed", "id", id, "error", err)
+	}
+	err = repo.cache.RemoveHot(ctx, id)
 	if err != nil {
 		return ErrServerInternal
 	}
 	return nil
 }
 
+// UpdateCount 互动计数先写 Redis, 由 FlushCount 定期批量落库
 func (repo *postRepository) UpdateCount(ctx context.Context, id int64, field model.PostCntField, delta int) error {
-	// DAO
-	err := repo.dao.UpdateCount(ctx, id, field, delta)
-	if err != nil {
+	if _, err := field.Column(); err != nil {
+		return ErrServerInternal
+	}
+
+	err := repo.cache.IncrPendingCnt(ctx, id, field, delta)
+	if err == nil {
+		return nil
+	}
+
+	// Redis 不可用时退化为直接写 MySQL
+	slog.Error("Cache IncrPendingCnt Failed", "id", id, "field", field, "delta", delta, "error", err)
+	deltas := map[int64]map[model.PostCntField]int{id: {field: delta}}
+	if err := repo.dao.BatchUpdateCount(ctx, deltas); err != nil {
 		return toRepositoryErr(err)
 	}
+	return nil
+}
+
+// FlushCount 将 Redis 中待落库的互动计数批量写入 MySQL, 返回落库的帖子数
+// 每批先读取增量, 落库成功后再从 Redis 扣除, 并与 ReconcileCount 互斥执行
+func (repo *postRepository) FlushCount(ctx context.Context, batchSize int) (int, error) {
+	flushed := 0
+	for {
+		token := uuid.New().String()
+		locked, err := repo.cache.LockPendingCnt(ctx, token, conf.PostCntLockTTL)
+		if err != nil {
+			slog.Error("Cache LockPendingCnt Failed", "error", err)
+			return flushed, ErrServerInternal
+		}
+		if !locked {
+			// 校正任务进行中, 留给下一轮落库
+			return flushed, nil
+		}
+
+		cnt, err := repo.flushBatch(ctx, batchSize)
+		repo.unlockPendingCnt(ctx, token)
+		flushed += cnt
+		if err != nil || cnt == 0 {
+			return flushed, err
+		}
+	}
+}
+
+// flushBatch 落库一批增量, 返回落库的帖子数
+func (repo *postRepository) flushBatch(ctx context.Context, batchSize int) (int, error) {
+	deltas, err := repo.cache.PeekPendingCnt(ctx, batchSize)
+	if err != nil {
+		slog.Error("Cache PeekPendingCnt Failed", "error", err)
+		return 0, ErrServerInternal
+	}
+	if len(deltas) == 0 {
+		return 0, nil
+	}
+
+	// 落库失败时增量仍保留在 Redis 中, 等待下次重试
+	if err := repo.dao.BatchUpdateCount(ctx, deltas); err != nil {
+		return 0, toRepositoryErr(err)
+	}
+
+	for id, fields := range deltas {
+		if err := repo.cache.AckPendingCnt(ctx, id, fields); err != nil {
+			// 扣除失败会导致增量被重复落库, 由 ReconcileCount 兜底校正
+			slog.Error("Cache AckPendingCnt Failed", "id", id, "deltas", fields, "error", err)
+			return len(deltas), ErrServerInternal
+		}
+	}
+	return len(deltas), nil
+}
+
+// ReconcileCount 根据 likes / comments / bookmarks 表校正帖子的点赞数、评论数和收藏数
+func (repo *postRepository) ReconcileCount(ctx context.Context, batchSize int) error {
+	var cursor int64
+	for {
+		ids, err := repo.dao.ListIDs(ctx, cursor, batchSize)
+		if err != nil {
+			return toRepositoryErr(err)
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+		cursor = ids[len(ids)-1]
 
-	post, _ := repo.dao.GetByID(ctx, id)
+		if err := repo.reconcileBatch(ctx, ids); err != nil {
+			return err
+		}
+	}
+}
 
-	// Cache
-	ok, err := repo.cache.ChangeInteractiveCnt(ctx, id, field, delta)
-	if err != nil || !ok {
-		// 获取失败的 Field 名
-		col, colErr := field.Column()
-		if colErr != nil {
-			col = "invalid"
+// reconcileBatch 持锁校正一批帖子, 避免读取待落库增量与写回计数之间 FlushCount 落库同一批增量
+func (repo *postRepository) reconcileBatch(ctx context.Context, ids []int64) error {
+	token := uuid.New().String()
+	for {
+		locked, err := repo.cache.LockPendingCnt(ctx, token, conf.PostCntLockTTL)
+		if err != nil {
+			slog.Error("Cache LockPendingCnt Failed", "error", err)
+			return ErrServerInternal
+		}
+		if locked {
+			break
 		}
-		slog.Error("Cache ChangeInteractiveCnt Failed", "id", id, "field", col, "delta", delta, "error", err)
-		if post != nil {
-			fields := []model.PostCntField{model.PostViewCount, model.PostCommentCount, model.PostLikeCount}
-			vals := []int{post.ViewCount, post.ViewCount, post.LikeCount}
-			repo.cache.SetInteractiveKey(ctx, id, fields, vals)
+		select {
+		case <-ctx.Done():
+			return ErrServerInternal
+		case <-time.After(conf.PostCntLockInterval):
 		}
 	}
+	defer repo.unlockPendingCnt(ctx, token)
 
+	counts, err := repo.dao.RecountInteractive(ctx, ids)
+	if err != nil {
+		return toRepositoryErr(err)
+	}
+
+	// likes / comments / bookmarks 表是实时写入的, 而 Redis 中尚未落库的增量会在读取时再叠加一次, 需要先扣除
+	pending, err := repo.cache.GetPendingCnt(ctx, ids)
+	if err != nil {
+		slog.Error("Cache GetPendingCnt Failed", "error", err)
+		return ErrServerInternal
+	}
+	for id, fields := range counts {
+		for field := range fields {
+			fields[field] -= pending[id][field]
+		}
+	}
+
+	if err := repo.dao.BatchSetCount(ctx, counts); err != nil {
+		return toRepositoryErr(err)
+	}
 	return nil
 }
 
+// unlockPendingCnt 释放互动计数任务锁, 失败时等待锁自动过期
+func (repo *postRepository) unlockPendingCnt(ctx context.Context, token string) {
+	if err := repo.cache.UnlockPendingCnt(ctx, token); err != nil {
+		slog.Error("Cache UnlockPendingCnt Failed", "error", err)
+	}
+}
+
 func (repo *postRepository) Update(ctx context.Context, id int64, updates map[string]any) error {
 	err := repo.dao.Update(ctx, id, updates)
 	if err != nil {
@@ -91,6 +202,14 @@ func (repo *postRepository) Update(ctx context.Context, id int64, updates map[st
 	return nil
 }
 
+func (repo *postRepository) UpdateWithVersion(ctx context.Context, id int64, version int, updates map[string]any) (int, error) {
+	current, err := repo.dao.UpdateWithVersion(ctx, id, version, updates)
+	if err != nil {
+		return current, toRepositoryErr(err)
+	}
+	return current, nil
+}
+
 func (repo *postRepository) GetByID(ctx context.Context, id int64) (*model.Post, error) {
 	// todo 读 Cache
 
@@ -98,81 +217,251 @@ func (repo *postRepository) GetByID(ctx context.Context, id int64) (*model.Post,
 	if err != nil {
 		return nil, toRepositoryErr(err)
 	}
+	repo.mergePendingCnt(ctx, post)
 
 	return post, nil
 }
 
-func (repo *postRepository) GetByUid(ctx context.Context, id int64, pageNo, pageSize int) (int64, []*model.Post, error) {
+// GetByIDs 批量获取帖子, 结果按 ids 的顺序排列, 不存在的帖子直接跳过
+func (repo *postRepository) GetByIDs(ctx context.Context, ids []int64) ([]*model.Post, error) {
+	found, err := repo.dao.GetByIDs(ctx, utils.UniqueIDs(ids))
+	if err != nil {
+		return nil, toRepositoryErr(err)
+	}
+	repo.mergePendingCnt(ctx, found...)
+
+	byID := make(map[int64]*model.Post, len(found))
+	for _, post := range found {
+		byID[post.ID] = post
+	}
+	posts := make([]*model.Post, 0, len(found))
+	for _, id := range ids {
+		if post, ok := byID[id]; ok {
+			posts = append(posts, post)
+		}
+	}
+
+	return posts, nil
+}
+
+func (repo *postRepository) GetByUid(ctx context.Context, id int64, maxVisibility model.PostVisibility, pageNo, pageSize int) (int64, []*model.Post, error) {
 	// todo 读 Cache
 
-	total, posts, err := repo.dao.GetByUid(ctx, id, pageNo, pageSize)
+	total, posts, err := repo.dao.GetByUid(ctx, id, maxVisibility, pageNo, pageSize)
 	if err != nil {
 		return 0, nil, toRepositoryErr(err)
 	}
+	repo.mergePendingCnt(ctx, posts...)
 
 	return total, posts, nil
 }
 
-func (repo *postRepository) GetByPage(ctx context.Context, pageNo, pageSize int) (int64, []*model.Post, error) {
+func (repo *postRepository) GetByPage(ctx context.Context, maxVisibility model.PostVisibility, excludeIDs []int64, offset, limit int) (int64, []*model.Post, error) {
 	// todo 读 Cache
 
-	total, posts, err := repo.dao.GetByPage(ctx, pageNo, pageSize)
+	total, posts, err := repo.dao.GetByPage(ctx, maxVisibility, excludeIDs, offset, limit)
 	if err != nil {
 		return 0, nil, toRepositoryErr(err)
 	}
+	repo.mergePendingCnt(ctx, posts...)
 
 	return total, posts, nil
 }
 
-func (repo *postRepository) GetByPageAndTag(ctx context.Context, tid int64, pageNo, pageSize int) (int64, []*model.Post, error) {
+func (repo *postRepository) GetByPageAndTag(ctx context.Context, tid int64, maxVisibility model.PostVisibility, excludeIDs []int64, offset, limit int) (int64, []*model.Post, error) {
 	// todo 读 Cache
 
-	total, posts, err := repo.dao.GetByPageAndTag(ctx, tid, pageNo, pageSize)
+	total, posts, err := repo.dao.GetByPageAndTag(ctx, tid, maxVisibility, excludeIDs, offset, limit)
 	if err != nil {
 		return 0, nil, toRepositoryErr(err)
 	}
+	repo.mergePendingCnt(ctx, posts...)
 
 	return total, posts, nil
 }
 
-// ChangeScore 修改帖子分数
-func (repo *postRepository) ChangeScore(ctx context.Context, pid int64, delta int) {
-	// 查询是否在热度期内
-	value, err := repo.cache.CheckPostLikeTime(ctx, pid)
+func (repo *postRepository) GetFeatured(ctx context.Context, pageNo, pageSize int) (int64, []*model.Post, error) {
+	total, posts, err := repo.dao.GetFeatured(ctx, pageNo, pageSize)
 	if err != nil {
-		slog.Error("Check Post Like Time Failed", "error", err)
-		return
+		return 0, nil, toRepositoryErr(err)
 	}
+	repo.mergePendingCnt(ctx, posts...)
 
-	// 过了热度期，热度不再波动
-	if float64(time.Now().Unix())-value > OneWeekTimeSecs {
-		return
+	return total, posts, nil
+}
+
+func (repo *postRepository) Pin(ctx context.Context, pin *model.PostPin) error {
+	err := repo.dao.Pin(ctx, pin)
+	if err != nil {
+		return toRepositoryErr(err)
 	}
+	return nil
+}
 
-	err = repo.cache.ChangeScore(ctx, pid, delta)
+func (repo *postRepository) Unpin(ctx context.Context, pid, tid int64) error {
+	err := repo.dao.Unpin(ctx, pid, tid)
 	if err != nil {
-		slog.Error("Change Post Score Failed", "error", err)
-		return
+		return toRepositoryErr(err)
+	}
+	return nil
+}
+
+// GetPinnedIDs 获取 tid 范围内当前仍有效的置顶帖子 ID, 最晚置顶的排在前面
+func (repo *postRepository) GetPinnedIDs(ctx context.Context, tid int64, limit int) ([]int64, error) {
+	ids, err := repo.dao.GetPinnedIDs(ctx, tid, time.Now(), limit)
+	if err != nil {
+		return nil, toRepositoryErr(err)
+	}
+	return ids, nil
+}
+
+// ListForRank 分批获取参与热榜计算的帖子, 计数已叠加未落库的增量
+func (repo *postRepository) ListForRank(ctx context.Context, since time.Time, cursor int64, limit int) ([]*model.Post, error) {
+	posts, err := repo.dao.ListForRank(ctx, since, cursor, limit)
+	if err != nil {
+		return nil, toRepositoryErr(err)
+	}
+	repo.mergePendingCnt(ctx, posts...)
+
+	return posts, nil
+}
+
+// StageHot 暂存一批热榜分数, fresh 表示本轮第一次写入该热榜
+func (repo *postRepository) StageHot(ctx context.Context, key string, scores map[int64]float64, fresh bool) error {
+	err := repo.cache.StageHot(ctx, key, scores, conf.PostHotBoardSize, fresh)
+	if err != nil {
+		slog.Error("Cache StageHot Failed", "key", key, "error", err)
+		return ErrServerInternal
+	}
+	return nil
+}
+
+// PublishHot 发布本轮计算的时间窗口下的所有热榜
+func (repo *postRepository) PublishHot(ctx context.Context, window model.HotWindow, keys []string) error {
+	err := repo.cache.PublishHot(ctx, model.HotBoardsKey(window), keys)
+	if err != nil {
+		slog.Error("Cache PublishHot Failed", "window", window, "keys", keys, "error", err)
+		return ErrServerInternal
+	}
+	return nil
+}
+
+// RemoveHot 将帖子从所有热榜中移除
+func (repo *postRepository) RemoveHot(ctx context.Context, id int64) error {
+	err := repo.cache.RemoveHot(ctx, id)
+	if err != nil {
+		slog.Error("Cache RemoveHot Failed", "id", id, "error", err)
+		return ErrServerInternal
 	}
+	return nil
 }
 
-func (repo *postRepository) Top(ctx context.Context) ([]*model.Post, []float64, error) {
-	ids, scores, err := repo.cache.Top(ctx)
+func (repo *postRepository) Top(ctx context.Context, key string) ([]*model.Post, []float64, error) {
+	ids, scores, err := repo.cache.Top(ctx, key, conf.PostHotTopSize)
 	if err != nil {
 		return nil, nil, ErrServerInternal
 	}
 
+	found, err := repo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, nil, err
+	}
+	byID := make(map[int64]*model.Post, len(found))
+	for _, post := range found {
+		byID[post.ID] = post
+	}
+
 	var posts []*model.Post
-	for _, id := range ids {
-		post, err := repo.dao.GetByID(ctx, id)
-		if err != nil {
-			post = &model.Post{
-				ID:    0,
-				Title: "未知文章",
-			}
+	var postScores []float64
+	for k, id := range ids {
+		post, ok := byID[id]
+		if !ok {
+			// 已删除的帖子在下一轮计算前仍可能留在榜单中, 直接跳过
+			continue
 		}
 		posts = append(posts, post)
+		postScores = append(postScores, scores[k])
 	}
 
-	return posts, scores, nil
+	return posts, postScores, nil
+}
+
+// MarkViewed 判断 viewer 是否为去重窗口内首次浏览帖子
+func (repo *postRepository) MarkViewed(ctx context.Context, pid int64, viewer string) (bool, error) {
+	first, err := repo.cache.MarkViewed(ctx, pid, viewer, conf.PostViewDedupWindow)
+	if err != nil {
+		slog.Error("Cache MarkViewed Failed", "pid", pid, "viewer", viewer, "error", err)
+		return false, ErrServerInternal
+	}
+	return first, nil
+}
+
+// mergePendingCnt 将 Redis 中尚未落库的增量叠加到帖子计数上
+func (repo *postRepository) mergePendingCnt(ctx context.Context, posts ...*model.Post) {
+	ids := make([]int64, 0, len(posts))
+	for _, post := range posts {
+		if post != nil && post.ID != 0 {
+			ids = append(ids, post.ID)
+		}
+	}
+
+	pending, err := repo.cache.GetPendingCnt(ctx, ids)
+	if err != nil {
+		// 读不到增量时退化为只返回 MySQL 中的值
+		slog.Error("Cache GetPendingCnt Failed", "ids", ids, "error", err)
+		return
+	}
+
+	for _, post := range posts {
+		if post == nil {
+			continue
+		}
+		for field, delta := range pending[post.ID] {
+			post.AddCnt(field, delta)
+		}
+	}
+}
+
+// GetRelatedIDs 获取预计算的相关推荐, 按分数从高到低排列
+func (repo *postRepository) GetRelatedIDs(ctx context.Context, pid int64) ([]int64, error) {
+	ids, err := repo.cache.GetRelated(ctx, pid)
+	if err != nil {
+		slog.Error("Cache GetRelated Failed", "pid", pid, "error", err)
+		return nil, ErrServerInternal
+	}
+	return ids, nil
+}
+
+// SetRelated 写入预计算的相关推荐
+func (repo *postRepository) SetRelated(ctx context.Context, pid int64, scores map[int64]float64) error {
+	err := repo.cache.SetRelated(ctx, pid, scores, conf.PostRelatedSize, conf.PostRelatedExpireTime)
+	if err != nil {
+		slog.Error("Cache SetRelated Failed", "pid", pid, "error", err)
+		return ErrServerInternal
+	}
+	return nil
+}
+
+func (repo *postRepository) AssignSlug(ctx context.Context, postSlug *model.PostSlug) error {
+	err := repo.dao.AssignSlug(ctx, postSlug)
+	if err != nil {
+		return toRepositoryErr(err)
+	}
+	return nil
+}
+
+func (repo *postRepository) GetIDBySlug(ctx context.Context, slug string) (int64, error) {
+	pid, err := repo.dao.GetIDBySlug(ctx, slug)
+	if err != nil {
+		return 0, toRepositoryErr(err)
+	}
+	return pid, nil
+}
+
+func (repo *postRepository) ListWithoutSlug(ctx context.Context, cursor int64, limit int) ([]*model.Post, error) {
+	posts, err := repo.dao.ListWithoutSlug(ctx, cursor, limit)
+	if err != nil {
+		return nil, toRepositoryErr(err)
+	}
+	return posts, nil
 }
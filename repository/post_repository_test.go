@@ -0,0 +1,204 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"testing"
+	"time"
+
+	"github.com/yzletter/go-postery/model"
+	"github.com/yzletter/go-postery/repository"
+	"github.com/yzletter/go-postery/repository/cache"
+	"github.com/yzletter/go-postery/repository/dao"
+)
+
+// fakePostDAO 只实现落库计数用到的方法, 记录每个帖子累计落库的增量
+type fakePostDAO struct {
+	dao.PostDAO
+	counts  map[int64]map[model.PostCntField]int
+	fail    bool
+	onWrite func() // 写库期间执行, 模拟落库过程中有新的增量写入 Redis
+}
+
+func (d *fakePostDAO) BatchUpdateCount(ctx context.Context, deltas map[int64]map[model.PostCntField]int) error {
+	if d.fail {
+		return dao.ErrServerInternal
+	}
+	if d.onWrite != nil {
+		d.onWrite()
+	}
+	for id, fields := range deltas {
+		if d.counts[id] == nil {
+			d.counts[id] = make(map[model.PostCntField]int)
+		}
+		for field, delta := range fields {
+			d.counts[id][field] += delta
+		}
+	}
+	return nil
+}
+
+// fakePostCache 在内存中模拟待落库的增量和任务锁
+type fakePostCache struct {
+	cache.PostCache
+	pending map[int64]map[model.PostCntField]int
+	lock    string
+}
+
+func (c *fakePostCache) IncrPendingCnt(ctx context.Context, pid int64, field model.PostCntField, delta int) error {
+	if c.pending[pid] == nil {
+		c.pending[pid] = make(map[model.PostCntField]int)
+	}
+	c.pending[pid][field] += delta
+	return nil
+}
+
+func (c *fakePostCache) PeekPendingCnt(ctx context.Context, batchSize int) (map[int64]map[model.PostCntField]int, error) {
+	res := make(map[int64]map[model.PostCntField]int)
+	for id, fields := range c.pending {
+		if len(res) == batchSize {
+			break
+		}
+		res[id] = maps.Clone(fields)
+	}
+	return res, nil
+}
+
+func (c *fakePostCache) AckPendingCnt(ctx context.Context, pid int64, deltas map[model.PostCntField]int) error {
+	for field, delta := range deltas {
+		c.pending[pid][field] -= delta
+		if c.pending[pid][field] == 0 {
+			delete(c.pending[pid], field)
+		}
+	}
+	if len(c.pending[pid]) == 0 {
+		delete(c.pending, pid)
+	}
+	return nil
+}
+
+func (c *fakePostCache) LockPendingCnt(ctx context.Context, token string, ttl time.Duration) (bool, error) {
+	if c.lock != "" {
+		return false, nil
+	}
+	c.lock = token
+	return true, nil
+}
+
+func (c *fakePostCache) UnlockPendingCnt(ctx context.Context, token string) error {
+	if c.lock == token {
+		c.lock = ""
+	}
+	return nil
+}
+
+func newCounterRepo() (repository.PostRepository, *fakePostDAO, *fakePostCache) {
+	postDAO := &fakePostDAO{counts: make(map[int64]map[model.PostCntField]int)}
+	postCache := &fakePostCache{pending: make(map[int64]map[model.PostCntField]int)}
+	return repository.NewPostRepository(postDAO, postCache), postDAO, postCache
+}
+
+func TestFlushCount(t *testing.T) {
+	ctx := context.Background()
+	repo, postDAO, postCache := newCounterRepo()
+
+	// 增量先写入 Redis, 分多批落库
+	for pid := int64(1); pid <= 5; pid++ {
+		_ = repo.UpdateCount(ctx, pid, model.PostLikeCount, 2)
+		_ = repo.UpdateCount(ctx, pid, model.PostCommentCount, 1)
+	}
+	flushed, err := repo.FlushCount(ctx, 2)
+	if err != nil {
+		t.Fatalf("FlushCount error = %v", err)
+	}
+	if flushed != 5 {
+		t.Fatalf("FlushCount flushed = %d, want 5", flushed)
+	}
+	if len(postCache.pending) != 0 {
+		t.Fatalf("pending after flush = %v, want empty", postCache.pending)
+	}
+	for pid := int64(1); pid <= 5; pid++ {
+		if got := postDAO.counts[pid][model.PostLikeCount]; got != 2 {
+			t.Fatalf("like_count of %d = %d, want 2", pid, got)
+		}
+		if got := postDAO.counts[pid][model.PostCommentCount]; got != 1 {
+			t.Fatalf("comment_count of %d = %d, want 1", pid, got)
+		}
+	}
+	if postCache.lock != "" {
+		t.Fatalf("lock not released after flush")
+	}
+}
+
+// go test -v ./repository -run=^TestFlushCount$ -count=1
+
+func TestFlushCountKeepsConcurrentDelta(t *testing.T) {
+	ctx := context.Background()
+	repo, postDAO, postCache := newCounterRepo()
+
+	// 落库期间又有新的点赞, 只扣除已落库的部分, 新增量留到下一轮
+	_ = repo.UpdateCount(ctx, 1, model.PostLikeCount, 3)
+	postDAO.onWrite = func() {
+		postDAO.onWrite = nil
+		_ = repo.UpdateCount(ctx, 1, model.PostLikeCount, 1)
+	}
+	if _, err := repo.FlushCount(ctx, 10); err != nil {
+		t.Fatalf("FlushCount error = %v", err)
+	}
+	if got := postDAO.counts[1][model.PostLikeCount]; got != 4 {
+		t.Fatalf("like_count = %d, want 4", got)
+	}
+	if len(postCache.pending) != 0 {
+		t.Fatalf("pending after flush = %v, want empty", postCache.pending)
+	}
+}
+
+// go test -v ./repository -run=^TestFlushCountKeepsConcurrentDelta$ -count=1
+
+func TestFlushCountRetry(t *testing.T) {
+	ctx := context.Background()
+	repo, postDAO, postCache := newCounterRepo()
+	_ = repo.UpdateCount(ctx, 1, model.PostViewCount, 7)
+
+	// 落库失败时增量保留在 Redis 中
+	postDAO.fail = true
+	if _, err := repo.FlushCount(ctx, 10); !errors.Is(err, repository.ErrServerInternal) {
+		t.Fatalf("FlushCount error = %v, want %v", err, repository.ErrServerInternal)
+	}
+	if got := postCache.pending[1][model.PostViewCount]; got != 7 {
+		t.Fatalf("pending view_count = %d, want 7", got)
+	}
+
+	// 下一轮重试成功, 不会重复落库
+	postDAO.fail = false
+	if _, err := repo.FlushCount(ctx, 10); err != nil {
+		t.Fatalf("FlushCount error = %v", err)
+	}
+	if got := postDAO.counts[1][model.PostViewCount]; got != 7 {
+		t.Fatalf("view_count = %d, want 7", got)
+	}
+}
+
+// go test -v ./repository -run=^TestFlushCountRetry$ -count=1
+
+func TestFlushCountLocked(t *testing.T) {
+	ctx := context.Background()
+	repo, postDAO, postCache := newCounterRepo()
+	_ = repo.UpdateCount(ctx, 1, model.PostLikeCount, 1)
+
+	// 校正任务持有锁时跳过本轮
+	postCache.lock = "reconcile"
+	flushed, err := repo.FlushCount(ctx, 10)
+	if err != nil || flushed != 0 {
+		t.Fatalf("FlushCount = (%d, %v), want (0, nil)", flushed, err)
+	}
+	if len(postDAO.counts) != 0 {
+		t.Fatalf("counts = %v, want nothing written", postDAO.counts)
+	}
+	if got := postCache.pending[1][model.PostLikeCount]; got != 1 {
+		t.Fatalf("pending like_count = %d, want 1", got)
+	}
+}
+
+// go test -v ./repository -run=^TestFlushCountLocked$ -count=1
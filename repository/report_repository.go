@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/yzletter/go-postery/model"
+	"github.com/yzletter/go-postery/repository/cache"
+	"github.com/yzletter/go-postery/repository/dao"
+)
+
+type reportRepository struct {
+	dao   dao.ReportDAO
+	cache cache.ReportCache
+}
+
+func NewReportRepository(reportDAO dao.ReportDAO, reportCache cache.ReportCache) ReportRepository {
+	return &reportRepository{dao: reportDAO, cache: reportCache}
+}
+
+func (repo *reportRepository) Create(ctx context.Context, report *model.Report) error {
+	err := repo.dao.Create(ctx, report)
+	if err != nil {
+		return toRepositoryErr(err)
+	}
+	return nil
+}
+
+func (repo *reportRepository) Submit(ctx context.Context, report *model.Report) error {
+	err := repo.dao.Submit(ctx, report)
+	if err != nil {
+		return toRepositoryErr(err)
+	}
+	return nil
+}
+
+func (repo *reportRepository) GetPendingSummaries(ctx context.Context, targetType model.ReportTargetType, pageNo, pageSize int) (int64, []*model.ReportSummary, error) {
+	total, summaries, err := repo.dao.GetPendingSummaries(ctx, targetType, pageNo, pageSize)
+	if err != nil {
+		return 0, nil, toRepositoryErr(err)
+	}
+	return total, summaries, nil
+}
+
+func (repo *reportRepository) HasPending(ctx context.Context, targetType model.ReportTargetType, targetID int64) (bool, error) {
+	ok, err := repo.dao.HasPending(ctx, targetType, targetID)
+	if err != nil {
+		return false, toRepositoryErr(err)
+	}
+	return ok, nil
+}
+
+func (repo *reportRepository) Resolve(ctx context.Context, log *model.ModerationLog) error {
+	err := repo.dao.Resolve(ctx, log)
+	if err != nil {
+		return toRepositoryErr(err)
+	}
+	return nil
+}
+
+func (repo *reportRepository) GetLogs(ctx context.Context, pageNo, pageSize int) (int64, []*model.ModerationLog, error) {
+	total, logs, err := repo.dao.GetLogs(ctx, pageNo, pageSize)
+	if err != nil {
+		return 0, nil, toRepositoryErr(err)
+	}
+	return total, logs, nil
+}
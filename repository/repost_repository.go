@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/yzletter/go-postery/model"
+	"github.com/yzletter/go-postery/repository/cache"
+	"github.com/yzletter/go-postery/repository/dao"
+)
+
+type repostRepository struct {
+	dao   dao.RepostDAO
+	cache cache.RepostCache
+}
+
+func NewRepostRepository(repostDAO dao.RepostDAO, repostCache cache.RepostCache) RepostRepository {
+	return &repostRepository{dao: repostDAO, cache: repostCache}
+}
+
+func (repo *repostRepository) Repost(ctx context.Context, repost *model.Repost) error {
+	err := repo.dao.Create(ctx, repost)
+	if err != nil {
+		return toRepositoryErr(err)
+	}
+	return nil
+}
+
+func (repo *repostRepository) UnRepost(ctx context.Context, uid, pid int64) error {
+	err := repo.dao.Delete(ctx, uid, pid)
+	if err != nil {
+		return toRepositoryErr(err)
+	}
+	return nil
+}
+
+func (repo *repostRepository) HasReposted(ctx context.Context, uid, pid int64) (bool, error) {
+	ok, err := repo.dao.Exists(ctx, uid, pid)
+	if err != nil {
+		return false, toRepositoryErr(err)
+	}
+	return ok, nil
+}
+
+func (repo *repostRepository) GetPostIDs(ctx context.Context, uid int64, pageNo, pageSize int) (int64, []int64, error) {
+	total, ids, err := repo.dao.GetPostIDs(ctx, uid, pageNo, pageSize)
+	if err != nil {
+		return 0, nil, toRepositoryErr(err)
+	}
+	return total, ids, nil
+}
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/yzletter/go-postery/model"
+	"github.com/yzletter/go-postery/repository/cache"
+	"github.com/yzletter/go-postery/repository/dao"
+)
+
+type sensitiveRepository struct {
+	dao   dao.SensitiveDAO
+	cache cache.SensitiveCache
+}
+
+func NewSensitiveRepository(sensitiveDAO dao.SensitiveDAO, sensitiveCache cache.SensitiveCache) SensitiveRepository {
+	return &sensitiveRepository{dao: sensitiveDAO, cache: sensitiveCache}
+}
+
+// CreateWords 批量创建敏感词并自增词库版本号, 版本号更新失败时返回错误, 重试是幂等的
+func (repo *sensitiveRepository) CreateWords(ctx context.Context, words []*model.SensitiveWord) (int, error) {
+	cnt, err := repo.dao.CreateWords(ctx, words)
+	if err != nil {
+		return 0, toRepositoryErr(err)
+	}
+	if err := repo.incrVersion(ctx); err != nil {
+		return 0, err
+	}
+	return cnt, nil
+}
+
+func (repo *sensitiveRepository) DeleteWord(ctx context.Context, id int64) error {
+	err := repo.dao.DeleteWord(ctx, id)
+	if err != nil {
+		return toRepositoryErr(err)
+	}
+	return repo.incrVersion(ctx)
+}
+
+func (repo *sensitiveRepository) GetAllWords(ctx context.Context) ([]*model.SensitiveWord, error) {
+	words, err := repo.dao.GetAllWords(ctx)
+	if err != nil {
+		return nil, toRepositoryErr(err)
+	}
+	return words, nil
+}
+
+func (repo *sensitiveRepository) GetWordsByPage(ctx context.Context, category string, pageNo, pageSize int) (int64, []*model.SensitiveWord, error) {
+	total, words, err := repo.dao.GetWordsByPage(ctx, category, pageNo, pageSize)
+	if err != nil {
+		return 0, nil, toRepositoryErr(err)
+	}
+	return total, words, nil
+}
+
+func (repo *sensitiveRepository) GetAllCategories(ctx context.Context) ([]*model.SensitiveCategory, error) {
+	categories, err := repo.dao.GetAllCategories(ctx)
+	if err != nil {
+		return nil, toRepositoryErr(err)
+	}
+	return categories, nil
+}
+
+func (repo *sensitiveRepository) SaveCategory(ctx context.Context, category *model.SensitiveCategory) error {
+	err := repo.dao.SaveCategory(ctx, category)
+	if err != nil {
+		return toRepositoryErr(err)
+	}
+	return repo.incrVersion(ctx)
+}
+
+// GetVersion 获取词库版本号
+func (repo *sensitiveRepository) GetVersion(ctx context.Context) (int64, error) {
+	version, err := repo.cache.GetVersion(ctx)
+	if err != nil {
+		slog.Error("Get Sensitive Version Failed", "error", err)
+		return 0, ErrServerInternal
+	}
+	return version, nil
+}
+
+func (repo *sensitiveRepository) incrVersion(ctx context.Context) error {
+	if err := repo.cache.IncrVersion(ctx); err != nil {
+		slog.Error("Incr Sensitive Version Failed", "error", err)
+		return ErrServerInternal
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/yzletter/go-postery/model"
+	"github.com/yzletter/go-postery/repository/cache"
+	"github.com/yzletter/go-postery/repository/dao"
+)
+
+type seriesRepository struct {
+	dao   dao.SeriesDAO
+	cache cache.SeriesCache
+}
+
+func NewSeriesRepository(seriesDAO dao.SeriesDAO, seriesCache cache.SeriesCache) SeriesRepository {
+	return &seriesRepository{dao: seriesDAO, cache: seriesCache}
+}
+
+func (repo *seriesRepository) Create(ctx context.Context, series *model.Series) error {
+	err := repo.dao.Create(ctx, series)
+	if err != nil {
+		return toRepositoryErr(err)
+	}
+	return nil
+}
+
+func (repo *seriesRepository) GetByID(ctx context.Context, id int64) (*model.Series, error) {
+	series, err := repo.dao.GetByID(ctx, id)
+	if err != nil {
+		return nil, toRepositoryErr(err)
+	}
+	return series, nil
+}
+
+func (repo *seriesRepository) GetByUid(ctx context.Context, uid int64, pageNo, pageSize int) (int64, []*model.Series, error) {
+	total, series, err := repo.dao.GetByUid(ctx, uid, pageNo, pageSize)
+	if err != nil {
+		return 0, nil, toRepositoryErr(err)
+	}
+	return total, series, nil
+}
+
+func (repo *seriesRepository) Update(ctx context.Context, id int64, updates map[string]any) error {
+	err := repo.dao.Update(ctx, id, updates)
+	if err != nil {
+		return toRepositoryErr(err)
+	}
+	return nil
+}
+
+func (repo *seriesRepository) Delete(ctx context.Context, id int64) error {
+	err := repo.dao.Delete(ctx, id)
+	if err != nil {
+		return toRepositoryErr(err)
+	}
+	return nil
+}
+
+func (repo *seriesRepository) AddPost(ctx context.Context, seriesPost *model.SeriesPost) (int, error) {
+	position, err := repo.dao.AddPost(ctx, seriesPost)
+	if err != nil {
+		return 0, toRepositoryErr(err)
+	}
+	return position, nil
+}
+
+func (repo *seriesRepository) RemovePost(ctx context.Context, sid, pid int64) error {
+	err := repo.dao.RemovePost(ctx, sid, pid)
+	if err != nil {
+		return toRepositoryErr(err)
+	}
+	return nil
+}
+
+func (repo *seriesRepository) Reorder(ctx context.Context, sid int64, pids []int64) error {
+	err := repo.dao.Reorder(ctx, sid, pids)
+	if err != nil {
+		return toRepositoryErr(err)
+	}
+	return nil
+}
+
+func (repo *seriesRepository) GetPostIDs(ctx context.Context, sid int64) ([]int64, error) {
+	pids, err := repo.dao.GetPostIDs(ctx, sid)
+	if err != nil {
+		return nil, toRepositoryErr(err)
+	}
+	return pids, nil
+}
+
+func (repo *seriesRepository) GetByPostID(ctx context.Context, pid int64) (*model.SeriesPost, error) {
+	seriesPost, err := repo.dao.GetByPostID(ctx, pid)
+	if err != nil {
+		return nil, toRepositoryErr(err)
+	}
+	return seriesPost, nil
+}
+
+func (repo *seriesRepository) CountPosts(ctx context.Context, sids []int64) (map[int64]int, error) {
+	cnts, err := repo.dao.CountPosts(ctx, sids)
+	if err != nil {
+		return nil, toRepositoryErr(err)
+	}
+	return cnts, nil
+}
@@ -2,10 +2,13 @@ package repository
 
 import (
 	"context"
+	"log/slog"
 
+	"github.com/yzletter/go-postery/conf"
 	"github.com/yzletter/go-postery/model"
 	"github.com/yzletter/go-postery/repository/cache"
 	"github.com/yzletter/go-postery/repository/dao"
+	"github.com/yzletter/go-postery/utils"
 )
 
 type tagRepository struct {
@@ -46,6 +49,11 @@ func (repo *tagRepository) Bind(ctx context.Context, postTag *model.PostTag) err
 	if err != nil {
 		return toRepositoryErr(err)
 	}
+
+	// 删 Cache
+	if err := repo.cache.DeletePostTags(ctx, postTag.PostID); err != nil {
+		slog.Error("Cache DeletePostTags Failed", "pid", postTag.PostID, "error", err)
+	}
 	return nil
 }
 
@@ -54,6 +62,11 @@ func (repo *tagRepository) DeleteBind(ctx context.Context, pid, tid int64) error
 	if err != nil {
 		return toRepositoryErr(err)
 	}
+
+	// 删 Cache
+	if err := repo.cache.DeletePostTags(ctx, pid); err != nil {
+		slog.Error("Cache DeletePostTags Failed", "pid", pid, "error", err)
+	}
 	return nil
 }
 
@@ -65,3 +78,61 @@ func (repo *tagRepository) FindTagsByPostID(ctx context.Context, pid int64) ([]s
 
 	return tags, nil
 }
+
+// FindTagsByPostIDs 批量获取帖子的 Tags, 先查 Cache, 未命中的再用一次 IN 查询回源
+func (repo *tagRepository) FindTagsByPostIDs(ctx context.Context, pids []int64) (map[int64][]string, error) {
+	pids = utils.UniqueIDs(pids)
+
+	res, err := repo.cache.GetPostTags(ctx, pids)
+	if err != nil {
+		// 读 Cache 失败时全部回源
+		slog.Error("Cache GetPostTags Failed", "pids", pids, "error", err)
+		res = make(map[int64][]string, len(pids))
+	}
+
+	var missed []int64
+	for _, pid := range pids {
+		if _, ok := res[pid]; !ok {
+			missed = append(missed, pid)
+		}
+	}
+	if len(missed) == 0 {
+		return res, nil
+	}
+
+	found, err := repo.dao.FindTagsByPostIDs(ctx, missed)
+	if err != nil {
+		return nil, toRepositoryErr(err)
+	}
+
+	// 没有 Tag 的帖子也要回填, 避免每次都回源
+	fill := make(map[int64][]string, len(missed))
+	for _, pid := range missed {
+		fill[pid] = found[pid]
+		res[pid] = found[pid]
+	}
+	if err := repo.cache.SetPostTags(ctx, fill); err != nil {
+		slog.Error("Cache SetPostTags Failed", "pids", missed, "error", err)
+	}
+
+	return res, nil
+}
+
+func (repo *tagRepository) GetTagIDsByPostIDs(ctx context.Context, pids []int64) (map[int64][]int64, error) {
+	res, err := repo.dao.GetTagIDsByPostIDs(ctx, pids)
+	if err != nil {
+		return nil, toRepositoryErr(err)
+	}
+
+	return res, nil
+}
+
+// CountSharedTags 统计与 pid 有共同标签的帖子及共同标签数
+func (repo *tagRepository) CountSharedTags(ctx context.Context, pid int64) (map[int64]int, error) {
+	res, err := repo.dao.CountSharedTags(ctx, pid, conf.PostRelatedCandidates)
+	if err != nil {
+		return nil, toRepositoryErr(err)
+	}
+
+	return res, nil
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/yzletter/go-postery/model"
 	"github.com/yzletter/go-postery/repository/cache"
 	"github.com/yzletter/go-postery/repository/dao"
+	"github.com/yzletter/go-postery/utils"
 )
 
 // todo 错误映射
@@ -37,7 +38,10 @@ func (repo *userRepository) Delete(ctx context.Context, id int64) error {
 		return toRepositoryErr(err)
 	}
 
-	// todo 删 Cache
+	// 删 Cache
+	if err := repo.cache.DeleteUser(ctx, id); err != nil {
+		slog.Error("Cache DeleteUser Failed", "id", id, "error", err)
+	}
 
 	return nil
 }
@@ -70,6 +74,43 @@ func (repo *userRepository) GetByID(ctx context.Context, id int64) (*model.User,
 	return user, nil
 }
 
+// GetByIDs 批量获取用户, 先查 Cache, 未命中的再用一次 IN 查询回源; 不存在的用户不出现在结果中
+func (repo *userRepository) GetByIDs(ctx context.Context, ids []int64) (map[int64]*model.User, error) {
+	ids = utils.UniqueIDs(ids)
+
+	users, err := repo.cache.GetUsers(ctx, ids)
+	if err != nil {
+		// 读 Cache 失败时全部回源
+		slog.Error("Cache GetUsers Failed", "ids", ids, "error", err)
+		users = make(map[int64]*model.User, len(ids))
+	}
+
+	var missed []int64
+	for _, id := range ids {
+		if _, ok := users[id]; !ok {
+			missed = append(missed, id)
+		}
+	}
+	if len(missed) == 0 {
+		return users, nil
+	}
+
+	found, err := repo.dao.GetByIDs(ctx, missed)
+	if err != nil {
+		return nil, toRepositoryErr(err)
+	}
+	for _, user := range found {
+		users[user.ID] = user
+	}
+
+	// 回填 Cache
+	if err := repo.cache.SetUsers(ctx, found); err != nil {
+		slog.Error("Cache SetUsers Failed", "ids", missed, "error", err)
+	}
+
+	return users, nil
+}
+
 func (repo *userRepository) GetByUsername(ctx context.Context, username string) (*model.User, error) {
 	// todo 查 Cache
 
@@ -80,6 +121,14 @@ func (repo *userRepository) GetByUsername(ctx context.Context, username string)
 	return user, nil
 }
 
+func (repo *userRepository) GetByUsernames(ctx context.Context, usernames []string) ([]*model.User, error) {
+	users, err := repo.dao.GetByUsernames(ctx, usernames)
+	if err != nil {
+		return nil, toRepositoryErr(err)
+	}
+	return users, nil
+}
+
 func (repo *userRepository) UpdatePasswordHash(ctx context.Context, id int64, newHash string) error {
 	err := repo.dao.UpdatePasswordHash(ctx, id, newHash)
 	if err != nil {
@@ -95,7 +144,10 @@ func (repo *userRepository) UpdateProfile(ctx context.Context, id int64, updates
 		return toRepositoryErr(err)
 	}
 
-	// todo 更新 Cache
+	// 删 Cache, 下次读取时回填
+	if err := repo.cache.DeleteUser(ctx, id); err != nil {
+		slog.Error("Cache DeleteUser Failed", "id", id, "error", err)
+	}
 
 	return nil
 }
@@ -106,10 +158,15 @@ func (repo *userRepository) Top(ctx context.Context) ([]*model.User, []float64,
 		return nil, nil, toRepositoryErr(err)
 	}
 
+	found, err := repo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	var users []*model.User
 	for _, id := range ids {
-		user, err := repo.dao.GetByID(ctx, id)
-		if err != nil {
+		user, ok := found[id]
+		if !ok {
 			user = &model.User{
 				ID:       0,
 				Username: "未知用户",
@@ -0,0 +1,250 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/yzletter/go-postery/conf"
+	analyticsdto "github.com/yzletter/go-postery/dto/analytics"
+	"github.com/yzletter/go-postery/errno"
+	"github.com/yzletter/go-postery/model"
+	"github.com/yzletter/go-postery/repository"
+)
+
+type analyticsService struct {
+	analyticsRepo repository.AnalyticsRepository
+	postRepo      repository.PostRepository
+}
+
+func NewAnalyticsService(analyticsRepo repository.AnalyticsRepository, postRepo repository.PostRepository) AnalyticsService {
+	return &analyticsService{
+		analyticsRepo: analyticsRepo,
+		postRepo:      postRepo,
+	}
+}
+
+// Aggregate 将缓冲区中的事件汇总为小时和天两种粒度的统计并落库, 失败时事件归还缓冲区等待下次汇总
+func (svc *analyticsService) Aggregate(ctx context.Context) {
+	batch, err := svc.analyticsRepo.TakePending(ctx)
+	if err != nil {
+		slog.Error("Take Pending Analytics Failed", "error", err)
+		return
+	}
+
+	if !batch.Empty() {
+		rollup, err := svc.rollup(ctx, batch)
+		if err == nil {
+			err = svc.analyticsRepo.SaveBatch(ctx, rollup)
+		}
+		if err != nil {
+			slog.Error("Save Analytics Failed", "error", err)
+			if err := svc.analyticsRepo.RestorePending(ctx, batch); err != nil {
+				slog.Error("Restore Pending Analytics Failed", "error", err)
+			}
+			return
+		}
+	}
+
+	// 清理过期的小时统计
+	before := time.Now().Add(-conf.AnalyticsHourlyRetention)
+	if _, err := svc.analyticsRepo.PruneHourly(ctx, before, conf.AnalyticsPruneBatchSize); err != nil {
+		slog.Error("Prune Hourly Analytics Failed", "error", err)
+	}
+}
+
+// rollup 补齐帖子作者, 并将小时统计累加出按天统计; 已删除帖子的事件直接丢弃
+func (svc *analyticsService) rollup(ctx context.Context, batch *model.AnalyticsBatch) (*model.AnalyticsBatch, error) {
+	pids := make([]int64, 0, len(batch.Posts)+len(batch.Referrers))
+	for _, stat := range batch.Posts {
+		pids = append(pids, stat.PostID)
+	}
+	for _, stat := range batch.Referrers {
+		pids = append(pids, stat.PostID)
+	}
+	posts, err := svc.postRepo.GetByIDs(ctx, pids)
+	if err != nil {
+		return nil, err
+	}
+	authors := make(map[int64]int64, len(posts))
+	for _, post := range posts {
+		authors[post.ID] = post.UserID
+	}
+
+	type dayKey struct {
+		pid int64
+		day int64
+	}
+	res := &model.AnalyticsBatch{Followers: batch.Followers}
+	daily := make(map[dayKey]*model.PostStat)
+	for _, stat := range batch.Posts {
+		author, ok := authors[stat.PostID]
+		if !ok {
+			continue
+		}
+		hourly := *stat
+		hourly.AuthorID = author
+		res.Posts = append(res.Posts, &hourly)
+
+		day := model.AnalyticsDaily.Truncate(stat.Bucket)
+		k := dayKey{pid: stat.PostID, day: day.Unix()}
+		if _, ok := daily[k]; !ok {
+			daily[k] = &model.PostStat{PostID: stat.PostID, Granularity: model.AnalyticsDaily, Bucket: day, AuthorID: author}
+			res.Posts = append(res.Posts, daily[k])
+		}
+		daily[k].Merge(stat)
+	}
+	for _, stat := range batch.Referrers {
+		author, ok := authors[stat.PostID]
+		if !ok {
+			continue
+		}
+		referrer := *stat
+		referrer.AuthorID = author
+		res.Referrers = append(res.Referrers, &referrer)
+	}
+	return res, nil
+}
+
+// Dashboard 统计 uid 的帖子在 [from, to) 内的表现; from 和 to 为零值时默认最近 7 天, pid 非 0 时只统计该帖子
+func (svc *analyticsService) Dashboard(ctx context.Context, uid, pid int64, from, to time.Time) (analyticsdto.DashboardDTO, error) {
+	var empty analyticsdto.DashboardDTO
+
+	// 确定时间范围和粒度, 统计桶按本地时区划分, 截断前统一换算到本地时区
+	now := time.Now()
+	if to.IsZero() {
+		to = now
+	}
+	from, to = from.In(time.Local), to.In(time.Local)
+	if from.IsZero() {
+		from = to.Add(-conf.AnalyticsDefaultRange)
+	}
+	if !from.Before(to) || to.Sub(from) > conf.AnalyticsMaxRange {
+		return empty, errno.ErrAnalyticsRangeInvalid
+	}
+	granularity := model.AnalyticsDaily
+	if to.Sub(from) <= conf.AnalyticsHourlyMaxRange && from.After(now.Add(-conf.AnalyticsHourlyRetention)) {
+		granularity = model.AnalyticsHourly
+	}
+	from = granularity.Truncate(from)
+	day := model.AnalyticsDaily.Truncate(from)
+
+	// 只能查看自己的帖子, 无权查看时与帖子不存在的表现一致
+	if pid != 0 {
+		post, err := svc.postRepo.GetByID(ctx, pid)
+		if err != nil {
+			if errors.Is(err, repository.ErrRecordNotFound) {
+				return empty, errno.ErrPostNotFound
+			}
+			return empty, errno.ErrServerInternal
+		}
+		if post.UserID != uid {
+			return empty, errno.ErrPostNotFound
+		}
+	}
+
+	res := analyticsdto.DashboardDTO{
+		From:        from.Format(time.RFC3339),
+		To:          to.Format(time.RFC3339),
+		Granularity: granularity.String(),
+	}
+
+	// 时间序列
+	stats, err := svc.analyticsRepo.GetPostSeries(ctx, uid, pid, granularity, from, to)
+	if err != nil {
+		return empty, errno.ErrServerInternal
+	}
+	byBucket := make(map[int64]*model.PostStat, len(stats))
+	total := &model.PostStat{}
+	for _, stat := range stats {
+		byBucket[stat.Bucket.Unix()] = stat
+		total.Merge(stat)
+	}
+	res.Total = analyticsdto.ToPointDTO(total)
+	for bucket := from; bucket.Before(to); bucket = granularity.Next(bucket) {
+		stat, ok := byBucket[bucket.Unix()]
+		if !ok {
+			stat = &model.PostStat{Bucket: bucket}
+		}
+		res.Series = append(res.Series, analyticsdto.ToPointDTO(stat))
+	}
+
+	// 表现最好的帖子, 已删除的帖子跳过
+	topStats, err := svc.analyticsRepo.GetTopPosts(ctx, uid, granularity, from, to, conf.AnalyticsTopPostSize)
+	if err != nil {
+		return empty, errno.ErrServerInternal
+	}
+	topIDs := make([]int64, 0, len(topStats))
+	for _, stat := range topStats {
+		topIDs = append(topIDs, stat.PostID)
+	}
+	topPosts, err := svc.postRepo.GetByIDs(ctx, topIDs)
+	if err != nil {
+		return empty, errno.ErrServerInternal
+	}
+	postByID := make(map[int64]*model.Post, len(topPosts))
+	for _, post := range topPosts {
+		postByID[post.ID] = post
+	}
+	res.TopPosts = make([]analyticsdto.TopPostDTO, 0, len(topStats))
+	for _, stat := range topStats {
+		if post, ok := postByID[stat.PostID]; ok {
+			res.TopPosts = append(res.TopPosts, analyticsdto.ToTopPostDTO(stat, post))
+		}
+	}
+
+	// 粉丝变化, 按天统计
+	followerStats, err := svc.analyticsRepo.GetFollowerSeries(ctx, uid, day, to)
+	if err != nil {
+		return empty, errno.ErrServerInternal
+	}
+	byDay := make(map[int64]*model.FollowerStat, len(followerStats))
+	for _, stat := range followerStats {
+		byDay[stat.Bucket.Unix()] = stat
+		res.Followers.Gained += stat.Gained
+		res.Followers.Lost += stat.Lost
+	}
+	res.Followers.Net = res.Followers.Gained - res.Followers.Lost
+	for bucket := day; bucket.Before(to); bucket = model.AnalyticsDaily.Next(bucket) {
+		stat, ok := byDay[bucket.Unix()]
+		if !ok {
+			stat = &model.FollowerStat{Bucket: bucket}
+		}
+		res.Followers.Series = append(res.Followers.Series, analyticsdto.ToFollowerPointDTO(stat))
+	}
+
+	// 浏览来源, 按天统计
+	referrerStats, err := svc.analyticsRepo.GetReferrers(ctx, uid, pid, day, to, conf.AnalyticsTopReferrerSize)
+	if err != nil {
+		return empty, errno.ErrServerInternal
+	}
+	res.Referrers = make([]analyticsdto.ReferrerDTO, 0, len(referrerStats))
+	for _, stat := range referrerStats {
+		res.Referrers = append(res.Referrers, analyticsdto.ToReferrerDTO(stat))
+	}
+
+	return res, nil
+}
+
+// recordPostEvent 记录一次帖子事件, 失败时只记日志, 不影响主流程
+func recordPostEvent(ctx context.Context, analyticsRepo repository.AnalyticsRepository, pid int64, metric model.AnalyticsMetric) {
+	if err := analyticsRepo.RecordPostEvent(ctx, pid, metric, time.Now()); err != nil {
+		slog.Error("Record Analytics Event Failed", "pid", pid, "metric", metric, "error", err)
+	}
+}
+
+// referrerHost 从 Referer 请求头中提取来源域名, 去掉 www. 前缀; 没有或无法解析时返回 direct
+func referrerHost(referer string) string {
+	u, err := url.Parse(referer)
+	if err != nil || u.Hostname() == "" {
+		return model.AnalyticsDirectReferrer
+	}
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	if len(host) > conf.AnalyticsReferrerMaxLength {
+		host = host[:conf.AnalyticsReferrerMaxLength]
+	}
+	return host
+}
@@ -0,0 +1,283 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yzletter/go-postery/conf"
+	archivedto "github.com/yzletter/go-postery/dto/archive"
+	"github.com/yzletter/go-postery/errno"
+	"github.com/yzletter/go-postery/model"
+	"github.com/yzletter/go-postery/repository"
+	"github.com/yzletter/go-postery/utils"
+	"go.yaml.in/yaml/v3"
+)
+
+type archiveService struct {
+	postSvc    PostService
+	tagSvc     TagService
+	postRepo   repository.PostRepository
+	userRepo   repository.UserRepository
+	tagRepo    repository.TagRepository
+	followRepo repository.FollowRepository
+}
+
+func NewArchiveService(postSvc PostService, tagSvc TagService, postRepo repository.PostRepository, userRepo repository.UserRepository,
+	tagRepo repository.TagRepository, followRepo repository.FollowRepository) ArchiveService {
+	return &archiveService{
+		postSvc:    postSvc,
+		tagSvc:     tagSvc,
+		postRepo:   postRepo,
+		userRepo:   userRepo,
+		tagRepo:    tagRepo,
+		followRepo: followRepo,
+	}
+}
+
+// Import 从 ZIP 压缩包导入 Markdown 帖子, 每个文件独立导入, 单个文件失败时记录原因并继续导入其他文件;
+// front-matter 中的 author 不是导入者本人时需要管理员权限
+func (svc *archiveService) Import(ctx context.Context, uid int64, r io.ReaderAt, size int64) (archivedto.ImportResultDTO, error) {
+	var empty archivedto.ImportResultDTO
+
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return empty, errno.ErrArchiveInvalid
+	}
+
+	// 只导入 Markdown 文件, 跳过目录、隐藏文件和 macOS 生成的元数据
+	var files []*zip.File
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() || strings.HasPrefix(f.Name, "__MACOSX/") || strings.HasPrefix(path.Base(f.Name), ".") {
+			continue
+		}
+		if ext := strings.ToLower(path.Ext(f.Name)); ext != ".md" && ext != ".markdown" {
+			continue
+		}
+		files = append(files, f)
+	}
+	if len(files) > conf.ArchiveImportMaxFiles {
+		return empty, errno.ErrArchiveTooManyFiles
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Name < files[j].Name })
+
+	importer, err := svc.userRepo.GetByID(ctx, uid)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return empty, errno.ErrUserNotFound
+		}
+		return empty, errno.ErrServerInternal
+	}
+
+	res := archivedto.ImportResultDTO{Files: make([]archivedto.FileResultDTO, 0, len(files))}
+	authors := map[string]*model.User{importer.Username: importer}
+	for _, f := range files {
+		pid, err := svc.importFile(ctx, importer, f, authors)
+		res.Add(f.Name, pid, err)
+	}
+	return res, nil
+}
+
+// importFile 导入单个文件, 帖子已创建但标签绑定失败时同时返回帖子 ID 和错误
+func (svc *archiveService) importFile(ctx context.Context, importer *model.User, f *zip.File, authors map[string]*model.User) (int64, error) {
+	// 先看声明的大小, 再限制实际读取的字节数, 防止声明的大小被篡改
+	if f.UncompressedSize64 > conf.ArchiveFileMaxSize {
+		return 0, errno.ErrArchiveFileTooLarge
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return 0, errno.ErrArchiveFileInvalid
+	}
+	defer rc.Close()
+	data, err := io.ReadAll(io.LimitReader(rc, conf.ArchiveFileMaxSize+1))
+	if err != nil {
+		return 0, errno.ErrArchiveFileInvalid
+	}
+	if len(data) > conf.ArchiveFileMaxSize {
+		return 0, errno.ErrArchiveFileTooLarge
+	}
+
+	// 解析 front-matter
+	front, body, ok := utils.SplitFrontMatter(string(data))
+	if !ok {
+		return 0, errno.ErrArchiveFileInvalid
+	}
+	var fm archivedto.FrontMatter
+	if err := yaml.Unmarshal([]byte(front), &fm); err != nil {
+		return 0, errno.ErrArchiveFileInvalid
+	}
+	title, body := strings.TrimSpace(fm.Title), strings.TrimSpace(body)
+	if title == "" || body == "" {
+		return 0, errno.ErrArchiveFileEmpty
+	}
+
+	// 保留原始发布时间, 没有时取当前时间
+	var createdAt time.Time
+	s := fm.CreatedAt
+	if s == "" {
+		s = fm.Date
+	}
+	if s != "" {
+		createdAt, err = utils.ParseTimestamp(s, time.Local)
+		if err != nil || createdAt.After(time.Now()) {
+			return 0, errno.ErrArchiveTimeInvalid
+		}
+	}
+
+	var visibility model.PostVisibility
+	if fm.Visibility != "" {
+		visibility, err = model.ParsePostVisibility(fm.Visibility)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	author, err := svc.author(ctx, importer, strings.TrimSpace(fm.Author), authors)
+	if err != nil {
+		return 0, err
+	}
+
+	postDTO, err := svc.postSvc.Import(ctx, author.ID, title, body, visibility, createdAt)
+	if err != nil {
+		return 0, err
+	}
+	if tags := cleanTags(fm.Tags); len(tags) > 0 {
+		if err := svc.tagSvc.Bind(ctx, postDTO.ID, tags); err != nil {
+			return postDTO.ID, err
+		}
+	}
+	return postDTO.ID, nil
+}
+
+// author 解析 front-matter 中的作者, 为空时为导入者本人; 以其他用户身份导入需要管理员权限
+func (svc *archiveService) author(ctx context.Context, importer *model.User, username string, authors map[string]*model.User) (*model.User, error) {
+	if username == "" {
+		return importer, nil
+	}
+	if user, ok := authors[username]; ok {
+		return user, nil
+	}
+	if importer.Role != model.UserRoleAdmin {
+		return nil, errno.ErrUnauthorized
+	}
+
+	user, err := svc.userRepo.GetByUsername(ctx, username)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return nil, errno.ErrUserNotFound
+		}
+		return nil, errno.ErrServerInternal
+	}
+	authors[username] = user
+	return user, nil
+}
+
+// Export 将 uid 的帖子导出为 Markdown 文件的 ZIP 压缩包, 只包含 viewerUid 可见的帖子, 按发布时间倒序至多导出 conf.ArchiveExportMaxPosts 篇
+func (svc *archiveService) Export(ctx context.Context, uid, viewerUid int64) ([]byte, error) {
+	author, err := svc.userRepo.GetByID(ctx, uid)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return nil, errno.ErrUserNotFound
+		}
+		return nil, errno.ErrServerInternal
+	}
+	maxVis, err := maxVisibility(ctx, svc.followRepo, uid, viewerUid)
+	if err != nil {
+		return nil, errno.ErrServerInternal
+	}
+
+	// 分批读取帖子
+	var posts []*model.Post
+	for pageNo := 1; len(posts) < conf.ArchiveExportMaxPosts; pageNo++ {
+		total, page, err := svc.postRepo.GetByUid(ctx, uid, maxVis, pageNo, conf.ArchiveExportPageSize)
+		if err != nil {
+			return nil, errno.ErrServerInternal
+		}
+		posts = append(posts, page...)
+		if len(page) < conf.ArchiveExportPageSize || int64(len(posts)) >= total {
+			break
+		}
+	}
+	if len(posts) > conf.ArchiveExportMaxPosts {
+		posts = posts[:conf.ArchiveExportMaxPosts]
+	}
+
+	pids := make([]int64, 0, len(posts))
+	for _, post := range posts {
+		pids = append(pids, post.ID)
+	}
+	tags, err := svc.tagRepo.FindTagsByPostIDs(ctx, pids)
+	if err != nil {
+		return nil, errno.ErrServerInternal
+	}
+
+	// 逐篇写入压缩包
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	names := make(map[string]bool, len(posts))
+	for _, post := range posts {
+		front, err := yaml.Marshal(archivedto.FrontMatter{
+			Title:      post.Title,
+			Tags:       tags[post.ID],
+			CreatedAt:  post.CreatedAt.Format(time.RFC3339),
+			Author:     author.Username,
+			Visibility: post.Visibility.String(),
+		})
+		if err != nil {
+			return nil, errno.ErrServerInternal
+		}
+
+		w, err := zw.CreateHeader(&zip.FileHeader{
+			Name:     exportFileName(post, names),
+			Method:   zip.Deflate,
+			Modified: post.LastEdited(),
+		})
+		if err != nil {
+			return nil, errno.ErrServerInternal
+		}
+		if _, err := io.WriteString(w, utils.JoinFrontMatter(string(front), post.Content)); err != nil {
+			return nil, errno.ErrServerInternal
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, errno.ErrServerInternal
+	}
+	return buf.Bytes(), nil
+}
+
+// exportFileName 生成形如 2006-01-02-<slug>.md 的文件名, 没有 slug 或重名时用帖子 ID 区分
+func exportFileName(post *model.Post, names map[string]bool) string {
+	prefix := post.CreatedAt.Format(time.DateOnly) + "-"
+	name := prefix + strconv.FormatInt(post.ID, 10) + ".md"
+	if post.Slug != "" {
+		if slugName := prefix + post.Slug + ".md"; !names[slugName] {
+			name = slugName
+		} else {
+			name = prefix + post.Slug + "-" + strconv.FormatInt(post.ID, 10) + ".md"
+		}
+	}
+	names[name] = true
+	return name
+}
+
+// cleanTags 去掉标签两端空白, 跳过空标签和重复标签
+func cleanTags(tags []string) []string {
+	res := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		res = append(res, tag)
+	}
+	return res
+}
@@ -0,0 +1,348 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"time"
+
+	"github.com/yzletter/go-postery/conf"
+	attachmentdto "github.com/yzletter/go-postery/dto/attachment"
+	"github.com/yzletter/go-postery/errno"
+	"github.com/yzletter/go-postery/model"
+	"github.com/yzletter/go-postery/repository"
+	"github.com/yzletter/go-postery/service/ports"
+	"github.com/yzletter/go-postery/utils"
+	_ "golang.org/x/image/webp"
+)
+
+type attachmentService struct {
+	attachmentRepo repository.AttachmentRepository
+	postRepo       repository.PostRepository
+	followRepo     repository.FollowRepository
+	storage        ports.ObjectStorage
+	idGen          ports.IDGenerator
+}
+
+func NewAttachmentService(attachmentRepo repository.AttachmentRepository, postRepo repository.PostRepository,
+	followRepo repository.FollowRepository, storage ports.ObjectStorage, idGen ports.IDGenerator) AttachmentService {
+	return &attachmentService{
+		attachmentRepo: attachmentRepo,
+		postRepo:       postRepo,
+		followRepo:     followRepo,
+		storage:        storage,
+		idGen:          idGen,
+	}
+}
+
+// variant 待写入存储的文件
+type variant struct {
+	key  string
+	data []byte
+	mime string
+}
+
+// Upload 上传附件, 类型以内容嗅探为准, 图片会额外生成展示图和缩略图
+func (svc *attachmentService) Upload(ctx context.Context, uid int64, fileName string, r io.Reader, size int64) (attachmentdto.DTO, error) {
+	var empty attachmentdto.DTO
+
+	// 读取文件, 多读 1 字节用于判断是否超限
+	if size > conf.AttachmentMaxSize {
+		return empty, errno.ErrAttachmentTooLarge
+	}
+	data, err := io.ReadAll(io.LimitReader(r, conf.AttachmentMaxSize+1))
+	if err != nil {
+		return empty, errno.ErrInvalidParam
+	}
+	if len(data) > conf.AttachmentMaxSize {
+		return empty, errno.ErrAttachmentTooLarge
+	}
+	if len(data) == 0 {
+		return empty, errno.ErrInvalidParam
+	}
+
+	// 嗅探 MIME 类型
+	mimeType, _, err := mime.ParseMediaType(http.DetectContentType(data))
+	if err != nil {
+		return empty, errno.ErrAttachmentTypeNotAllowed
+	}
+	ext, ok := conf.AttachmentMimeTypes[mimeType]
+	if !ok {
+		return empty, errno.ErrAttachmentTypeNotAllowed
+	}
+
+	id := svc.idGen.NextID()
+	attachment := &model.Attachment{
+		ID:         id,
+		UserID:     uid,
+		FileName:   cleanFileName(fileName),
+		MimeType:   mimeType,
+		Size:       int64(len(data)),
+		StorageKey: fmt.Sprintf("attachments/%d/%d%s", uid, id, ext),
+		Status:     model.AttachmentStatusNormal,
+	}
+	files := []variant{{key: attachment.StorageKey, data: data, mime: mimeType}}
+
+	// 图片生成变体
+	if isImage(mimeType) {
+		variants, err := svc.buildVariants(attachment, data)
+		if err != nil {
+			return empty, err
+		}
+		files = append(files, variants...)
+	}
+	for _, f := range files {
+		attachment.TotalSize += int64(len(f.data))
+	}
+
+	// 校验配额, 写入存储前先粗略拦截, 写入数据库时再在事务中严格校验
+	used, err := svc.attachmentRepo.SumSizeByUid(ctx, uid)
+	if err != nil {
+		return empty, errno.ErrServerInternal
+	}
+	if used+attachment.TotalSize > conf.AttachmentUserQuota {
+		return empty, errno.ErrAttachmentQuotaExceeded
+	}
+
+	// 写入存储
+	for k, f := range files {
+		if err := svc.storage.Put(ctx, f.key, bytes.NewReader(f.data), int64(len(f.data)), f.mime); err != nil {
+			svc.deleteKeys(ctx, files[:k])
+			return empty, errno.ErrServerInternal
+		}
+	}
+
+	// 写入数据库
+	if err := svc.attachmentRepo.Create(ctx, attachment, conf.AttachmentUserQuota); err != nil {
+		svc.deleteKeys(ctx, files)
+		if errors.Is(err, repository.ErrLimitExceeded) {
+			return empty, errno.ErrAttachmentQuotaExceeded
+		}
+		return empty, errno.ErrServerInternal
+	}
+
+	return attachmentdto.ToDTO(attachment, svc.storage.URL), nil
+}
+
+// buildVariants 记录图片尺寸, 并为超过宽度限制的图片生成展示图和缩略图
+func (svc *attachmentService) buildVariants(attachment *model.Attachment, data []byte) ([]variant, error) {
+	// 先只解析尺寸, 防止解码炸弹
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, errno.ErrAttachmentTypeNotAllowed
+	}
+	if cfg.Width*cfg.Height > conf.AttachmentMaxPixels {
+		return nil, errno.ErrAttachmentTooLarge
+	}
+	attachment.Width, attachment.Height = cfg.Width, cfg.Height
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, errno.ErrAttachmentTypeNotAllowed
+	}
+
+	// PNG 保留透明通道, 其余统一编码为 JPEG; GIF 只取第一帧
+	ext, mimeType := ".jpg", "image/jpeg"
+	if attachment.MimeType == "image/png" {
+		ext, mimeType = ".png", "image/png"
+	}
+	base := attachment.StorageKey[:len(attachment.StorageKey)-len(filepath.Ext(attachment.StorageKey))]
+
+	var variants []variant
+	for _, v := range []struct {
+		width  int
+		suffix string
+		key    *string
+	}{
+		{conf.AttachmentMediumWidth, "_medium", &attachment.MediumKey},
+		{conf.AttachmentThumbWidth, "_thumb", &attachment.ThumbKey},
+	} {
+		if cfg.Width <= v.width {
+			// 原图已足够小, 直接使用原图
+			continue
+		}
+
+		var buf bytes.Buffer
+		resized := utils.FitWidth(img, v.width)
+		if mimeType == "image/png" {
+			err = png.Encode(&buf, resized)
+		} else {
+			err = jpeg.Encode(&buf, resized, &jpeg.Options{Quality: conf.AttachmentJPEGQuality})
+		}
+		if err != nil {
+			slog.Error("Encode Image Variant Failed", "id", attachment.ID, "error", err)
+			return nil, errno.ErrServerInternal
+		}
+
+		*v.key = base + v.suffix + ext
+		variants = append(variants, variant{key: *v.key, data: buf.Bytes(), mime: mimeType})
+	}
+	return variants, nil
+}
+
+// CheckBindable 校验 ids 能否关联到帖子 pid, pid 为 0 表示尚未创建的帖子; 在写入帖子前调用, 避免帖子保存后附件关联失败
+func (svc *attachmentService) CheckBindable(ctx context.Context, uid, pid int64, ids []int64) error {
+	ids = utils.UniqueIDs(ids)
+	if len(ids) > conf.AttachmentMaxPerPost {
+		return errno.ErrAttachmentTooMany
+	}
+
+	cnt, err := svc.attachmentRepo.CountBindable(ctx, uid, pid, ids)
+	if err != nil {
+		return errno.ErrServerInternal
+	}
+	if int(cnt) != len(ids) {
+		// 附件不存在、不属于当前用户或已被其他帖子使用
+		return errno.ErrAttachmentNotFound
+	}
+	return nil
+}
+
+// BindToPost 将帖子的附件设置为 ids, 不在 ids 中的旧附件会被回收
+func (svc *attachmentService) BindToPost(ctx context.Context, uid, pid int64, ids []int64) error {
+	ids = utils.UniqueIDs(ids)
+	if len(ids) > conf.AttachmentMaxPerPost {
+		return errno.ErrAttachmentTooMany
+	}
+
+	// 只有作者可以修改帖子附件
+	post, err := svc.postRepo.GetByID(ctx, pid)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return errno.ErrPostNotFound
+		}
+		return errno.ErrServerInternal
+	}
+	if post.UserID != uid {
+		return errno.ErrUnauthorized
+	}
+
+	err = svc.attachmentRepo.BindToPost(ctx, uid, pid, ids)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			// 附件不存在、不属于当前用户或已被其他帖子使用
+			return errno.ErrAttachmentNotFound
+		}
+		return errno.ErrServerInternal
+	}
+	return nil
+}
+
+// ListByPostID 获取帖子的附件, uid 无权查看帖子时视为帖子不存在
+func (svc *attachmentService) ListByPostID(ctx context.Context, pid, uid int64) ([]attachmentdto.DTO, error) {
+	post, err := svc.postRepo.GetByID(ctx, pid)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return nil, errno.ErrPostNotFound
+		}
+		return nil, errno.ErrServerInternal
+	}
+	ok, err := canView(ctx, svc.followRepo, post, uid)
+	if err != nil {
+		return nil, errno.ErrServerInternal
+	}
+	if !ok {
+		return nil, errno.ErrPostNotFound
+	}
+
+	attachments, err := svc.attachmentRepo.GetByPostID(ctx, pid)
+	if err != nil {
+		return nil, errno.ErrServerInternal
+	}
+
+	attachmentDTOs := make([]attachmentdto.DTO, 0, len(attachments))
+	for _, attachment := range attachments {
+		attachmentDTOs = append(attachmentDTOs, attachmentdto.ToDTO(attachment, svc.storage.URL))
+	}
+	return attachmentDTOs, nil
+}
+
+// Delete 删除自己上传的附件, 文件由 CollectGarbage 回收
+func (svc *attachmentService) Delete(ctx context.Context, uid, id int64) error {
+	err := svc.attachmentRepo.Discard(ctx, uid, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return errno.ErrAttachmentNotFound
+		}
+		return errno.ErrServerInternal
+	}
+	return nil
+}
+
+// CollectGarbage 回收待删除的附件和长时间未关联帖子的孤儿附件, 由定时任务调用
+func (svc *attachmentService) CollectGarbage(ctx context.Context) {
+	orphanBefore := time.Now().Add(-conf.AttachmentOrphanTTL)
+	collected := 0
+	for {
+		attachments, err := svc.attachmentRepo.ListGarbage(ctx, orphanBefore, conf.AttachmentGCBatchSize)
+		if err != nil {
+			slog.Error("List Garbage Attachments Failed", "error", err)
+			return
+		}
+
+		// 文件全部删除成功的附件才删除记录, 失败的留到下一轮重试
+		var ids []int64
+		for _, attachment := range attachments {
+			ok := true
+			for _, key := range attachment.Keys() {
+				if err := svc.storage.Delete(ctx, key); err != nil {
+					ok = false
+				}
+			}
+			if ok {
+				ids = append(ids, attachment.ID)
+			}
+		}
+		if err := svc.attachmentRepo.Delete(ctx, ids); err != nil {
+			slog.Error("Delete Garbage Attachments Failed", "error", err)
+			return
+		}
+		collected += len(ids)
+
+		if len(attachments) < conf.AttachmentGCBatchSize || len(ids) == 0 {
+			break
+		}
+	}
+	if collected > 0 {
+		slog.Info("Collect Garbage Attachments Succeed", "collected", collected)
+	}
+}
+
+// deleteKeys 上传失败时删除已写入的文件
+func (svc *attachmentService) deleteKeys(ctx context.Context, files []variant) {
+	for _, f := range files {
+		if err := svc.storage.Delete(ctx, f.key); err != nil {
+			slog.Error("Delete Uploaded File Failed", "key", f.key, "error", err)
+		}
+	}
+}
+
+func isImage(mimeType string) bool {
+	switch mimeType {
+	case "image/jpeg", "image/png", "image/gif", "image/webp":
+		return true
+	}
+	return false
+}
+
+// cleanFileName 去掉客户端文件名中的路径部分并限制长度
+func cleanFileName(name string) string {
+	name = filepath.Base(filepath.ToSlash(name))
+	if name == "." || name == "/" {
+		name = ""
+	}
+	if runes := []rune(name); len(runes) > 255 {
+		name = string(runes[len(runes)-255:])
+	}
+	return name
+}
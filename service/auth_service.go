@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"log/slog"
+	"strconv"
 
 	"github.com/google/uuid"
 	"github.com/rs/xid"
@@ -107,14 +108,28 @@ func (svc *authService) Login(ctx context.Context, username, pass string) (userd
 		return empty, errno.ErrServerInternal
 	}
 
+	// 被封禁的用户不能登录
+	if user.Status == model.UserStatusBanned {
+		return empty, errno.ErrUserBanned
+	}
+
 	return userdto.ToBriefDTO(user), nil
 }
 
 // ClearTokens 清除 Tokens
 func (svc *authService) ClearTokens(ctx context.Context, accessToken, refreshToken string) error {
-	// 删除 refreshToken
+	// 删除 refreshToken, 同时从用户的 Token 集合中移除
 	if refreshToken != "" {
-		if err := svc.client.Del(ctx, conf.RefreshTokenPrefix+refreshToken).Err(); err != nil {
+		uid, err := svc.client.HGet(ctx, conf.RefreshTokenPrefix+refreshToken, "user_id").Result()
+		if err != nil && !errors.Is(err, redis.Nil) {
+			return errno.ErrLogoutFailed
+		}
+		pipe := svc.client.TxPipeline()
+		pipe.Del(ctx, conf.RefreshTokenPrefix+refreshToken)
+		if uid != "" {
+			pipe.SRem(ctx, conf.UserTokensPrefix+uid, refreshToken)
+		}
+		if _, err := pipe.Exec(ctx); err != nil {
 			return errno.ErrLogoutFailed
 		}
 	}
@@ -171,6 +186,8 @@ func (svc *authService) IssueTokens(ctx context.Context, id int64, role int, age
 	ttl := time.Duration(conf.RefreshTokenMaxAgeSecs) * time.Second
 	pipe.HSet(ctx, conf.RefreshTokenPrefix+refreshToken, mp)
 	pipe.Expire(ctx, conf.RefreshTokenPrefix+refreshToken, ttl)
+	pipe.SAdd(ctx, userTokensKey(id), refreshToken)
+	pipe.Expire(ctx, userTokensKey(id), ttl)
 	if _, err := pipe.Exec(ctx); err != nil {
 		return "", "", errno.ErrServerInternal
 	}
@@ -178,6 +195,50 @@ func (svc *authService) IssueTokens(ctx context.Context, id int64, role int, age
 	return accessToken, refreshToken, nil
 }
 
+// RevokeTokens 吊销用户所有的 RefreshToken, 并拉黑对应的 ssid 使 AccessToken 同时失效
+func (svc *authService) RevokeTokens(ctx context.Context, uid int64) error {
+	tokens, err := svc.client.SMembers(ctx, userTokensKey(uid)).Result()
+	if err != nil {
+		slog.Error("Get User Tokens Failed", "uid", uid, "error", err)
+		return errno.ErrServerInternal
+	}
+
+	ttl := time.Duration(conf.RefreshTokenMaxAgeSecs) * time.Second
+	pipe := svc.client.TxPipeline()
+	for _, token := range tokens {
+		ssid, err := svc.client.HGet(ctx, conf.RefreshTokenPrefix+token, "ssid").Result()
+		if err != nil && !errors.Is(err, redis.Nil) {
+			slog.Error("Get Refresh Token Failed", "uid", uid, "error", err)
+			return errno.ErrServerInternal
+		}
+		pipe.Del(ctx, conf.RefreshTokenPrefix+token)
+		if ssid != "" {
+			pipe.Set(ctx, conf.ClearTokenPrefix+ssid, "", ttl)
+		}
+	}
+	pipe.Del(ctx, userTokensKey(uid))
+	if _, err := pipe.Exec(ctx); err != nil {
+		slog.Error("Revoke User Tokens Failed", "uid", uid, "error", err)
+		return errno.ErrServerInternal
+	}
+	return nil
+}
+
+// CheckStatus 检查用户仍可登录, 用于刷新 Token 前再次确认用户没有被封禁
+func (svc *authService) CheckStatus(ctx context.Context, uid int64) error {
+	user, err := svc.userRepo.GetByID(ctx, uid)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return errno.ErrUserNotFound
+		}
+		return errno.ErrServerInternal
+	}
+	if user.Status == model.UserStatusBanned {
+		return errno.ErrUserBanned
+	}
+	return nil
+}
+
 func (svc *authService) VerifyAccessToken(tokenString string) (*ports.JWTTokenClaims, error) {
 	claim, err := svc.jwtManager.VerifyToken(tokenString)
 	if err != nil {
@@ -185,3 +246,8 @@ func (svc *authService) VerifyAccessToken(tokenString string) (*ports.JWTTokenCl
 	}
 	return claim, nil
 }
+
+// userTokensKey 拼接用户的 RefreshToken 集合 Key
+func userTokensKey(uid int64) string {
+	return conf.UserTokensPrefix + strconv.FormatInt(uid, 10)
+}
@@ -0,0 +1,268 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	bookmarkdto "github.com/yzletter/go-postery/dto/bookmark"
+	postdto "github.com/yzletter/go-postery/dto/post"
+	"github.com/yzletter/go-postery/errno"
+	"github.com/yzletter/go-postery/model"
+	"github.com/yzletter/go-postery/repository"
+	"github.com/yzletter/go-postery/service/ports"
+)
+
+type bookmarkService struct {
+	bookmarkRepo  repository.BookmarkRepository
+	postRepo      repository.PostRepository
+	userRepo      repository.UserRepository
+	tagRepo       repository.TagRepository
+	followRepo    repository.FollowRepository
+	analyticsRepo repository.AnalyticsRepository
+	idGen         ports.IDGenerator
+	renderer      ports.ContentRenderer
+}
+
+func NewBookmarkService(bookmarkRepo repository.BookmarkRepository, postRepo repository.PostRepository,
+	userRepo repository.UserRepository, tagRepo repository.TagRepository, followRepo repository.FollowRepository,
+	analyticsRepo repository.AnalyticsRepository, idGen ports.IDGenerator, renderer ports.ContentRenderer) BookmarkService {
+	return &bookmarkService{
+		bookmarkRepo:  bookmarkRepo,
+		postRepo:      postRepo,
+		userRepo:      userRepo,
+		tagRepo:       tagRepo,
+		followRepo:    followRepo,
+		analyticsRepo: analyticsRepo,
+		idGen:         idGen,
+		renderer:      renderer,
+	}
+}
+
+// Bookmark 收藏帖子, cid 为 0 时收藏到默认收藏夹
+func (svc *bookmarkService) Bookmark(ctx context.Context, pid, uid, cid int64) error {
+	// 查找帖子
+	post, err := svc.postRepo.GetByID(ctx, pid)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return errno.ErrPostNotFound
+		}
+		return errno.ErrServerInternal
+	}
+	ok, err := canView(ctx, svc.followRepo, post, uid)
+	if err != nil {
+		return errno.ErrServerInternal
+	}
+	if !ok {
+		return errno.ErrPostNotFound
+	}
+
+	// 校验收藏夹归属
+	if cid != 0 {
+		if err := svc.checkCollection(ctx, uid, cid); err != nil {
+			return err
+		}
+	}
+
+	// 创建收藏记录
+	bookmark := &model.Bookmark{
+		ID:           svc.idGen.NextID(),
+		UserID:       uid,
+		PostID:       pid,
+		CollectionID: cid,
+	}
+	err = svc.bookmarkRepo.Bookmark(ctx, bookmark)
+	if err != nil {
+		if errors.Is(err, repository.ErrUniqueKey) {
+			// 重复收藏
+			return errno.ErrDuplicatedBookmark
+		}
+		// 系统内部错误
+		return errno.ErrServerInternal
+	}
+
+	field := model.PostBookmarkCount
+	if err := svc.postRepo.UpdateCount(ctx, pid, field, 1); err != nil {
+		slog.Error("Update Bookmark Count Failed", "error", err)
+	}
+	recordPostEvent(ctx, svc.analyticsRepo, pid, model.AnalyticsBookmark)
+
+	return nil
+}
+
+// UnBookmark 取消收藏
+func (svc *bookmarkService) UnBookmark(ctx context.Context, pid, uid int64) error {
+	// 删除收藏记录
+	err := svc.bookmarkRepo.UnBookmark(ctx, uid, pid)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			// 重复删除
+			return errno.ErrDuplicatedUnBookmark
+		}
+		// 系统内部错误
+		return errno.ErrServerInternal
+	}
+
+	field := model.PostBookmarkCount
+	if err := svc.postRepo.UpdateCount(ctx, pid, field, -1); err != nil {
+		slog.Error("Update Bookmark Count Failed", "error", err)
+	}
+
+	return nil
+}
+
+// IfBookmark 判断是否收藏过
+func (svc *bookmarkService) IfBookmark(ctx context.Context, pid, uid int64) (bool, error) {
+	ok, err := svc.bookmarkRepo.HasBookmarked(ctx, uid, pid)
+	if err != nil {
+		return false, errno.ErrServerInternal
+	}
+	return ok, nil
+}
+
+// ListBookmarks 按页获取收藏的帖子, cid 为 nil 时返回所有收藏, 为 0 时返回默认收藏夹
+func (svc *bookmarkService) ListBookmarks(ctx context.Context, uid int64, cid *int64, pageNo, pageSize int) (int, []postdto.DetailDTO, error) {
+	var empty []postdto.DetailDTO
+
+	if cid != nil && *cid != 0 {
+		if err := svc.checkCollection(ctx, uid, *cid); err != nil {
+			return 0, empty, err
+		}
+	}
+
+	total, pids, err := svc.bookmarkRepo.GetPostIDs(ctx, uid, cid, pageNo, pageSize)
+	if err != nil {
+		return 0, empty, errno.ErrServerInternal
+	}
+
+	// 总数和分页已在查询时排除已删除和无权查看的帖子, 这里兜底查询期间发生的变化
+	posts, err := svc.postRepo.GetByIDs(ctx, pids)
+	if err != nil {
+		return 0, empty, errno.ErrServerInternal
+	}
+
+	posts, err = filterVisible(ctx, svc.followRepo, posts, uid)
+	if err != nil {
+		return 0, empty, errno.ErrServerInternal
+	}
+
+	fillRendered(ctx, svc.renderer, svc.postRepo, posts...)
+	return int(total), toDetailDTOs(ctx, svc.userRepo, svc.tagRepo, posts), nil
+}
+
+// MoveBookmark 将已收藏的帖子移动到收藏夹 cid, cid 为 0 时移回默认收藏夹
+func (svc *bookmarkService) MoveBookmark(ctx context.Context, pid, uid, cid int64) error {
+	// 校验收藏夹归属
+	if cid != 0 {
+		if err := svc.checkCollection(ctx, uid, cid); err != nil {
+			return err
+		}
+	}
+
+	err := svc.bookmarkRepo.MoveBookmark(ctx, uid, pid, cid)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			// 尚未收藏
+			return errno.ErrDuplicatedUnBookmark
+		}
+		return errno.ErrServerInternal
+	}
+	return nil
+}
+
+// CreateCollection 新建收藏夹
+func (svc *bookmarkService) CreateCollection(ctx context.Context, uid int64, name string) (bookmarkdto.CollectionDTO, error) {
+	var empty bookmarkdto.CollectionDTO
+
+	collection := &model.BookmarkCollection{
+		ID:     svc.idGen.NextID(),
+		UserID: uid,
+		Name:   name,
+	}
+	err := svc.bookmarkRepo.CreateCollection(ctx, collection)
+	if err != nil {
+		if errors.Is(err, repository.ErrUniqueKey) {
+			return empty, errno.ErrDuplicatedCollection
+		}
+		return empty, errno.ErrServerInternal
+	}
+
+	return bookmarkdto.ToCollectionDTO(collection, 0), nil
+}
+
+// RenameCollection 修改收藏夹名称
+func (svc *bookmarkService) RenameCollection(ctx context.Context, uid, cid int64, name string) error {
+	if err := svc.checkCollection(ctx, uid, cid); err != nil {
+		return err
+	}
+
+	err := svc.bookmarkRepo.RenameCollection(ctx, cid, name)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return errno.ErrCollectionNotFound
+		}
+		if errors.Is(err, repository.ErrUniqueKey) {
+			return errno.ErrDuplicatedCollection
+		}
+		return errno.ErrServerInternal
+	}
+	return nil
+}
+
+// DeleteCollection 删除收藏夹, 其中的收藏移回默认收藏夹
+func (svc *bookmarkService) DeleteCollection(ctx context.Context, uid, cid int64) error {
+	if err := svc.checkCollection(ctx, uid, cid); err != nil {
+		return err
+	}
+
+	err := svc.bookmarkRepo.DeleteCollection(ctx, cid)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return errno.ErrCollectionNotFound
+		}
+		return errno.ErrServerInternal
+	}
+	return nil
+}
+
+// ListCollections 按页获取收藏夹及其收藏数
+func (svc *bookmarkService) ListCollections(ctx context.Context, uid int64, pageNo, pageSize int) (int, []bookmarkdto.CollectionDTO, error) {
+	var empty []bookmarkdto.CollectionDTO
+
+	total, collections, err := svc.bookmarkRepo.GetCollections(ctx, uid, pageNo, pageSize)
+	if err != nil {
+		return 0, empty, errno.ErrServerInternal
+	}
+
+	cids := make([]int64, 0, len(collections))
+	for _, collection := range collections {
+		cids = append(cids, collection.ID)
+	}
+	cnts, err := svc.bookmarkRepo.CountByCollections(ctx, uid, cids)
+	if err != nil {
+		return 0, empty, errno.ErrServerInternal
+	}
+
+	collectionDTOs := make([]bookmarkdto.CollectionDTO, 0, len(collections))
+	for _, collection := range collections {
+		collectionDTOs = append(collectionDTOs, bookmarkdto.ToCollectionDTO(collection, cnts[collection.ID]))
+	}
+
+	return int(total), collectionDTOs, nil
+}
+
+// checkCollection 校验收藏夹存在且属于 uid
+func (svc *bookmarkService) checkCollection(ctx context.Context, uid, cid int64) error {
+	collection, err := svc.bookmarkRepo.GetCollectionByID(ctx, cid)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return errno.ErrCollectionNotFound
+		}
+		return errno.ErrServerInternal
+	}
+	if collection.UserID != uid {
+		// 不暴露他人收藏夹的存在
+		return errno.ErrCollectionNotFound
+	}
+	return nil
+}
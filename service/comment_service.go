@@ -4,8 +4,11 @@ import (
 	"context"
 	"errors"
 	"log/slog"
+	"time"
 
+	"github.com/yzletter/go-postery/conf"
 	commentdto "github.com/yzletter/go-postery/dto/comment"
+	userdto "github.com/yzletter/go-postery/dto/user"
 	"github.com/yzletter/go-postery/errno"
 	"github.com/yzletter/go-postery/model"
 	"github.com/yzletter/go-postery/repository"
@@ -13,61 +16,61 @@ import (
 )
 
 type commentService struct {
-	commentRepo repository.CommentRepository
-	userRepo    repository.UserRepository
-	postRepo    repository.PostRepository
-	idGen       ports.IDGenerator
+	commentRepo      repository.CommentRepository
+	commentLikeRepo  repository.CommentLikeRepository
+	commentBlockRepo repository.CommentBlockRepository
+	userRepo         repository.UserRepository
+	postRepo         repository.PostRepository
+	followRepo       repository.FollowRepository
+	reportRepo       repository.ReportRepository
+	analyticsRepo    repository.AnalyticsRepository
+	idGen            ports.IDGenerator
+	filter           ports.ContentFilter
+	mentionSvc       MentionService
 }
 
-func (svc *commentService) ListReplies(ctx context.Context, id int64, pageNo, pageSize int) (int, []commentdto.DTO, error) {
-	var empty []commentdto.DTO
-	total, comments, err := svc.commentRepo.GetRepliesByParentID(ctx, id, pageNo, pageSize)
-	if err != nil {
-		if errors.Is(err, repository.ErrRecordNotFound) {
-			return 0, empty, errno.ErrCommentNotFound
-		}
-		return 0, empty, errno.ErrServerInternal
-	}
-
-	var commentDTOs []commentdto.DTO
-	for _, comment := range comments {
-		user, err := svc.userRepo.GetByID(ctx, comment.UserID)
-		if err != nil {
-			user = &model.User{}
-		}
-		commentDTO := commentdto.ToDTO(comment, user)
-		commentDTOs = append(commentDTOs, commentDTO)
-	}
-
-	return int(total), commentDTOs, nil
-}
-
-func NewCommentService(commentRepo repository.CommentRepository, userRepo repository.UserRepository, postRepo repository.PostRepository, idGen ports.IDGenerator) CommentService {
+func NewCommentService(commentRepo repository.CommentRepository, commentLikeRepo repository.CommentLikeRepository, commentBlockRepo repository.CommentBlockRepository,
+	userRepo repository.UserRepository, postRepo repository.PostRepository, followRepo repository.FollowRepository, reportRepo repository.ReportRepository,
+	analyticsRepo repository.AnalyticsRepository, idGen ports.IDGenerator, filter ports.ContentFilter, mentionSvc MentionService) CommentService {
 	return &commentService{
-		commentRepo: commentRepo,
-		userRepo:    userRepo,
-		postRepo:    postRepo,
-		idGen:       idGen,
+		commentRepo:      commentRepo,
+		commentLikeRepo:  commentLikeRepo,
+		commentBlockRepo: commentBlockRepo,
+		userRepo:         userRepo,
+		postRepo:         postRepo,
+		followRepo:       followRepo,
+		reportRepo:       reportRepo,
+		analyticsRepo:    analyticsRepo,
+		idGen:            idGen,
+		filter:           filter,
+		mentionSvc:       mentionSvc,
 	}
 }
 
+// Create 发表评论, 需能查看帖子并满足作者的评论设置; 命中审核类敏感词时评论在审核通过前不展示
 func (svc *commentService) Create(ctx context.Context, pid int64, uid int64, parentId int64, replyId int64, content string) (commentdto.DTO, error) {
 	var empty commentdto.DTO
 
-	// 查询作者
-	author, err := svc.userRepo.GetByID(ctx, uid)
+	// 查询帖子, 检查可见范围和作者的评论设置
+	post, err := svc.viewablePost(ctx, pid, uid)
 	if err != nil {
-		if errors.Is(err, repository.ErrRecordNotFound) {
-			return empty, errno.ErrUserNotFound
-		}
-		return empty, errno.ErrServerInternal
+		return empty, err
+	}
+	if err := svc.commentable(ctx, post, uid); err != nil {
+		return empty, err
 	}
 
-	// 查询帖子
-	_, err = svc.postRepo.GetByID(ctx, pid)
+	// 过滤敏感词
+	reviewCategories, err := screen(svc.filter, &content)
+	if err != nil {
+		return empty, err
+	}
+
+	// 查询作者
+	author, err := svc.userRepo.GetByID(ctx, uid)
 	if err != nil {
 		if errors.Is(err, repository.ErrRecordNotFound) {
-			return empty, errno.ErrPostNotFound
+			return empty, errno.ErrUserNotFound
 		}
 		return empty, errno.ErrServerInternal
 	}
@@ -80,6 +83,10 @@ func (svc *commentService) Create(ctx context.Context, pid int64, uid int64, par
 		ReplyID:  replyId,
 		UserID:   uid,
 		Content:  content,
+		Status:   model.CommentStatusNormal,
+	}
+	if len(reviewCategories) > 0 {
+		comment.Status = model.CommentStatusReviewing
 	}
 	err = svc.commentRepo.Create(ctx, comment)
 	if err != nil {
@@ -90,14 +97,157 @@ func (svc *commentService) Create(ctx context.Context, pid int64, uid int64, par
 		return empty, errno.ErrServerInternal
 	}
 
+	// 解析 @ 提及, 失败时不影响评论; 待审核的评论和并非所有人可见的帖子下的评论不发送通知
+	commentDTO := commentdto.ToDTO(comment, author)
+	notify := comment.Status == model.CommentStatusNormal && mentionNotify(post)
+	commentDTO.Mentions, err = svc.mentionSvc.Sync(ctx, model.MentionTargetComment, comment.ID, pid, uid, content, notify)
+	if err != nil {
+		slog.Error("Sync Comment Mentions Failed", "cid", comment.ID, "error", err)
+	}
+
+	// 待审核的评论提交审核, 放行后再计入评论数
+	if comment.Status == model.CommentStatusReviewing {
+		if err := submitForReview(ctx, svc.reportRepo, svc.idGen, model.ReportTargetComment, comment.ID, reviewCategories); err != nil {
+			slog.Error("Submit Comment For Review Failed", "cid", comment.ID, "error", err)
+		}
+		return commentDTO, nil
+	}
+
 	// 修改评论数
 	field := model.PostCommentCount
 	err = svc.postRepo.UpdateCount(ctx, pid, field, 1)
 	if err != nil {
 		slog.Error("Update Comment Count Failed", "error", err)
 	}
+	recordPostEvent(ctx, svc.analyticsRepo, pid, model.AnalyticsComment)
 
-	return commentdto.ToDTO(comment, author), err
+	return commentDTO, err
+}
+
+// Edit 作者在发表后 conf.CommentEditWindow 内编辑自己的评论, 编辑前的内容保存为历史版本;
+// 同样需满足作者的评论设置; 内容重新过滤敏感词, 命中审核类敏感词时评论转为待审核, 已在审核中的评论编辑后仍待审核
+func (svc *commentService) Edit(ctx context.Context, pid, cid, uid int64, content string) (commentdto.DTO, error) {
+	var empty commentdto.DTO
+
+	// 查询帖子, 已无法查看帖子时不能再编辑
+	post, err := svc.viewablePost(ctx, pid, uid)
+	if err != nil {
+		return empty, err
+	}
+
+	// 过滤敏感词, 被拒绝时不做任何修改
+	reviewCategories, err := screen(svc.filter, &content)
+	if err != nil {
+		return empty, err
+	}
+
+	// 查询评论并校验权限
+	comment, err := svc.commentRepo.GetByID(ctx, cid)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return empty, errno.ErrCommentNotFound
+		}
+		return empty, errno.ErrServerInternal
+	}
+	if comment.PostID != pid {
+		return empty, errno.ErrCommentNotFound
+	}
+	if comment.UserID != uid {
+		return empty, errno.ErrUnauthorized
+	}
+	if time.Since(comment.CreatedAt) > conf.CommentEditWindow {
+		return empty, errno.ErrCommentEditExpired
+	}
+	if comment.EditCount >= conf.CommentEditMaxCount {
+		return empty, errno.ErrCommentEditLimit
+	}
+
+	// 关闭评论或被作者拉黑后不能再编辑
+	if err := svc.commentable(ctx, post, uid); err != nil {
+		return empty, err
+	}
+
+	// 按编辑次数更新, 同时保存编辑前的内容
+	status := comment.Status
+	if len(reviewCategories) > 0 {
+		status = model.CommentStatusReviewing
+	}
+	revision := &model.CommentRevision{
+		ID:        svc.idGen.NextID(),
+		CommentID: cid,
+		Version:   comment.EditCount,
+		Content:   comment.Content,
+		Status:    comment.Status,
+		CreatedAt: comment.CreatedAt,
+	}
+	if comment.EditedAt != nil {
+		revision.CreatedAt = *comment.EditedAt
+	}
+	err = svc.commentRepo.Edit(ctx, revision, content, status)
+	if err != nil {
+		if errors.Is(err, repository.ErrResourceConflict) {
+			return empty, errno.ErrCommentEditConflict
+		}
+		return empty, errno.ErrServerInternal
+	}
+
+	// 正常的评论转为待审核时提交审核, 放行前不计入评论数
+	if comment.Status == model.CommentStatusNormal && status == model.CommentStatusReviewing {
+		if err := svc.postRepo.UpdateCount(ctx, pid, model.PostCommentCount, -1); err != nil {
+			slog.Error("Update Comment Count Failed", "error", err)
+		}
+		if err := submitForReview(ctx, svc.reportRepo, svc.idGen, model.ReportTargetComment, cid, reviewCategories); err != nil {
+			slog.Error("Submit Comment For Review Failed", "cid", cid, "error", err)
+		}
+	}
+
+	// 重新解析 @ 提及, 只通知新增的用户
+	notify := status == model.CommentStatusNormal && mentionNotify(post)
+	if _, err := svc.mentionSvc.Sync(ctx, model.MentionTargetComment, cid, pid, uid, content, notify); err != nil {
+		slog.Error("Sync Comment Mentions Failed", "cid", cid, "error", err)
+	}
+
+	// 返回编辑后的评论
+	comment, err = svc.commentRepo.GetByID(ctx, cid)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return empty, errno.ErrCommentNotFound
+		}
+		return empty, errno.ErrServerInternal
+	}
+	commentDTOs, err := svc.toDTOs(ctx, uid, []*model.Comment{comment})
+	if err != nil {
+		return empty, err
+	}
+	return commentDTOs[0], nil
+}
+
+// Revisions 获取评论的历史版本, 按版本升序排列, 仅管理员和版主可查看
+func (svc *commentService) Revisions(ctx context.Context, pid, cid, uid int64) ([]commentdto.RevisionDTO, error) {
+	if err := checkModerator(ctx, svc.userRepo, uid); err != nil {
+		return nil, err
+	}
+
+	comment, err := svc.commentRepo.GetByID(ctx, cid)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return nil, errno.ErrCommentNotFound
+		}
+		return nil, errno.ErrServerInternal
+	}
+	if comment.PostID != pid {
+		return nil, errno.ErrCommentNotFound
+	}
+
+	revisions, err := svc.commentRepo.GetRevisions(ctx, cid)
+	if err != nil {
+		return nil, errno.ErrServerInternal
+	}
+	revisionDTOs := make([]commentdto.RevisionDTO, 0, len(revisions))
+	for _, revision := range revisions {
+		revisionDTOs = append(revisionDTOs, commentdto.ToRevisionDTO(revision))
+	}
+	return revisionDTOs, nil
 }
 
 func (svc *commentService) Delete(ctx context.Context, uid, cid int64) error {
@@ -123,6 +273,9 @@ func (svc *commentService) Delete(ctx context.Context, uid, cid int64) error {
 		}
 		return errno.ErrServerInternal
 	}
+	if comment.Status == model.CommentStatusReviewing {
+		cnt-- // 待审核的评论未计入评论数
+	}
 
 	// 改变评论数
 	field := model.PostCommentCount
@@ -134,24 +287,463 @@ func (svc *commentService) Delete(ctx context.Context, uid, cid int64) error {
 	return nil
 }
 
-func (svc *commentService) List(ctx context.Context, pid int64, pageNo, pageSize int) (int, []commentdto.DTO, error) {
+// List 按 sort 排序获取帖子的一级评论, 每条一级评论附带热度最高的几条回复; uid 为当前登录用户, 用于标记是否点赞
+func (svc *commentService) List(ctx context.Context, pid, uid int64, sort model.CommentSort, pageNo, pageSize int) (int, []commentdto.DTO, error) {
+	return svc.listWithReplies(ctx, pid, uid, sort, pageNo, pageSize, model.CommentSortHot, conf.CommentInlineReplySize)
+}
+
+// Tree 按 sort 排序获取帖子的一级评论, 每条一级评论附带最早的 replySize 条回复, 用于一次渲染整个评论区
+func (svc *commentService) Tree(ctx context.Context, pid, uid int64, sort model.CommentSort, pageNo, pageSize, replySize int) (int, []commentdto.DTO, error) {
+	return svc.listWithReplies(ctx, pid, uid, sort, pageNo, pageSize, model.CommentSortOldest, replySize)
+}
+
+// listWithReplies 获取一页一级评论, 每条一级评论附带按 replySort 排序的前 replySize 条回复和回复总数;
+// 查询次数与分页大小无关
+func (svc *commentService) listWithReplies(ctx context.Context, pid, uid int64, sort model.CommentSort, pageNo, pageSize int,
+	replySort model.CommentSort, replySize int) (int, []commentdto.DTO, error) {
 	var empty []commentdto.DTO
-	total, comments, err := svc.commentRepo.GetByPostID(ctx, pid, pageNo, pageSize)
+	if pageNo < 1 || pageSize <= 0 {
+		return 0, empty, errno.ErrInvalidParam
+	}
+
+	post, err := svc.viewablePost(ctx, pid, uid)
+	if err != nil {
+		return 0, empty, err
+	}
+
+	// 置顶评论排在第一页最前面, 其余评论跳过它, 避免重复出现
+	pinned, err := svc.pinnedComment(ctx, post)
+	if err != nil {
+		return 0, empty, err
+	}
+	var excludeIDs []int64
+	start, limit := (pageNo-1)*pageSize, pageSize
+	if pinned != nil {
+		excludeIDs = []int64{pinned.ID}
+		if pageNo == 1 {
+			limit--
+		} else {
+			start--
+		}
+	}
+	total, comments, err := svc.commentRepo.GetByPostID(ctx, pid, excludeIDs, sort, start, limit)
 	if err != nil {
 		return 0, empty, errno.ErrCommentNotFound
 	}
+	if pinned != nil {
+		total++
+		if pageNo == 1 {
+			comments = append([]*model.Comment{pinned}, comments...)
+		}
+	}
+
+	// 查询回复和回复数
+	rootIDs := make([]int64, 0, len(comments))
+	for _, comment := range comments {
+		rootIDs = append(rootIDs, comment.ID)
+	}
+	replies, err := svc.commentRepo.GetTopReplies(ctx, rootIDs, replySort, replySize)
+	if err != nil {
+		return 0, empty, errno.ErrServerInternal
+	}
+	replyCnts, err := svc.commentRepo.CountReplies(ctx, rootIDs)
+	if err != nil {
+		return 0, empty, errno.ErrServerInternal
+	}
 
-	var commentDTOs []commentdto.DTO
+	// 一级评论和回复一起组装, 再按一级评论拆开
+	all := append([]*model.Comment{}, comments...)
 	for _, comment := range comments {
-		user, err := svc.userRepo.GetByID(ctx, comment.UserID)
+		all = append(all, replies[comment.ID]...)
+	}
+	allDTOs, err := svc.toDTOs(ctx, uid, all)
+	if err != nil {
+		return 0, empty, err
+	}
+	commentDTOs := allDTOs[:len(comments):len(comments)]
+	offset := len(comments)
+	for i, comment := range comments {
+		n := len(replies[comment.ID])
+		commentDTOs[i].Pinned = pinned != nil && comment.ID == pinned.ID
+		commentDTOs[i].ReplyCount = replyCnts[comment.ID]
+		commentDTOs[i].Replies = allDTOs[offset : offset+n : offset+n]
+		offset += n
+	}
+
+	return int(total), commentDTOs, nil
+}
+
+// ListReplies 按 sort 排序获取帖子 pid 下一级评论 id 的回复
+func (svc *commentService) ListReplies(ctx context.Context, pid, id, uid int64, sort model.CommentSort, pageNo, pageSize int) (int, []commentdto.DTO, error) {
+	var empty []commentdto.DTO
+	if _, err := svc.viewablePost(ctx, pid, uid); err != nil {
+		return 0, empty, err
+	}
+	parent, err := svc.commentRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return 0, empty, errno.ErrCommentNotFound
+		}
+		return 0, empty, errno.ErrServerInternal
+	}
+	if parent.PostID != pid {
+		return 0, empty, errno.ErrCommentNotFound
+	}
+
+	total, comments, err := svc.commentRepo.GetRepliesByParentID(ctx, id, sort, pageNo, pageSize)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return 0, empty, errno.ErrCommentNotFound
+		}
+		return 0, empty, errno.ErrServerInternal
+	}
+
+	commentDTOs, err := svc.toDTOs(ctx, uid, comments)
+	if err != nil {
+		return 0, empty, err
+	}
+
+	return int(total), commentDTOs, nil
+}
+
+// Like 点赞评论, 评论需属于帖子 pid
+func (svc *commentService) Like(ctx context.Context, pid, cid, uid int64) error {
+	if _, err := svc.likeable(ctx, pid, cid, uid); err != nil {
+		return err
+	}
+
+	// 创建点赞记录
+	like := &model.CommentLike{
+		ID:        svc.idGen.NextID(),
+		UserID:    uid,
+		CommentID: cid,
+	}
+	err := svc.commentLikeRepo.Like(ctx, like)
+	if err != nil {
+		if errors.Is(err, repository.ErrUniqueKey) {
+			// 重复点赞
+			return errno.ErrDuplicatedCommentLike
+		}
+		// 系统内部错误
+		return errno.ErrServerInternal
+	}
+
+	if err := svc.commentRepo.UpdateLikeCount(ctx, cid, 1); err != nil {
+		slog.Error("Update Comment Like Count Failed", "cid", cid, "error", err)
+	}
+
+	return nil
+}
+
+// UnLike 取消点赞评论
+func (svc *commentService) UnLike(ctx context.Context, pid, cid, uid int64) error {
+	if _, err := svc.likeable(ctx, pid, cid, uid); err != nil {
+		return err
+	}
+
+	// 删除点赞记录
+	err := svc.commentLikeRepo.UnLike(ctx, uid, cid)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			// 重复取消
+			return errno.ErrDuplicatedCommentUnLike
+		}
+		// 系统内部错误
+		return errno.ErrServerInternal
+	}
+
+	if err := svc.commentRepo.UpdateLikeCount(ctx, cid, -1); err != nil {
+		slog.Error("Update Comment Like Count Failed", "cid", cid, "error", err)
+	}
+
+	return nil
+}
+
+// SetPolicy 作者设置帖子的评论权限, 只影响之后的评论, 已有的评论保持不变
+func (svc *commentService) SetPolicy(ctx context.Context, pid, uid int64, policy model.CommentPolicy) error {
+	post, err := svc.ownedPost(ctx, pid, uid)
+	if err != nil {
+		return err
+	}
+	if post.CommentPolicy == policy {
+		return nil
+	}
+
+	return svc.updatePost(ctx, post, map[string]any{"comment_policy": policy})
+}
+
+// Pin 作者置顶帖子的一条一级评论, 每个帖子只能置顶一条, 置顶新评论时替换原来的置顶
+func (svc *commentService) Pin(ctx context.Context, pid, cid, uid int64) error {
+	post, err := svc.ownedPost(ctx, pid, uid)
+	if err != nil {
+		return err
+	}
+	if post.PinnedCommentID == cid {
+		return nil
+	}
+
+	comment, err := svc.commentRepo.GetByID(ctx, cid)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return errno.ErrCommentNotFound
+		}
+		return errno.ErrServerInternal
+	}
+	if comment.PostID != pid || comment.ParentID != 0 || comment.Status != model.CommentStatusNormal {
+		return errno.ErrCommentPinInvalid
+	}
+
+	return svc.updatePost(ctx, post, map[string]any{"pinned_comment_id": cid})
+}
+
+// Unpin 作者取消置顶评论, cid 不是当前置顶的评论时不做任何修改
+func (svc *commentService) Unpin(ctx context.Context, pid, cid, uid int64) error {
+	post, err := svc.ownedPost(ctx, pid, uid)
+	if err != nil {
+		return err
+	}
+	if post.PinnedCommentID != cid {
+		return nil
+	}
+
+	return svc.updatePost(ctx, post, map[string]any{"pinned_comment_id": 0})
+}
+
+// updatePost 修改帖子的评论设置, 不影响帖子的版本号, 作者正在进行的编辑不会因此冲突
+func (svc *commentService) updatePost(ctx context.Context, post *model.Post, updates map[string]any) error {
+	err := svc.postRepo.Update(ctx, post.ID, updates)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return errno.ErrPostNotFound
+		}
+		return errno.ErrServerInternal
+	}
+	return nil
+}
+
+// Block 禁止 target 评论 uid 的所有帖子, 已发表的评论保持不变
+func (svc *commentService) Block(ctx context.Context, uid, target int64) error {
+	if uid == target {
+		return errno.ErrCommentBlockSelf
+	}
+	if _, err := svc.userRepo.GetByID(ctx, target); err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return errno.ErrUserNotFound
+		}
+		return errno.ErrServerInternal
+	}
+
+	block := &model.CommentBlock{
+		ID:       svc.idGen.NextID(),
+		AuthorID: uid,
+		UserID:   target,
+	}
+	err := svc.commentBlockRepo.Block(ctx, block)
+	if err != nil {
+		if errors.Is(err, repository.ErrUniqueKey) {
+			// 重复拉黑
+			return errno.ErrDuplicatedCommentBlock
+		}
+		return errno.ErrServerInternal
+	}
+	return nil
+}
+
+// UnBlock 允许 target 重新评论 uid 的帖子
+func (svc *commentService) UnBlock(ctx context.Context, uid, target int64) error {
+	err := svc.commentBlockRepo.UnBlock(ctx, uid, target)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			// 重复取消
+			return errno.ErrDuplicatedCommentUnBlock
+		}
+		return errno.ErrServerInternal
+	}
+	return nil
+}
+
+// ListBlocked 按拉黑时间倒序分页获取被 uid 禁止评论的用户, 已注销的用户跳过
+func (svc *commentService) ListBlocked(ctx context.Context, uid int64, pageNo, pageSize int) (int, []userdto.BriefDTO, error) {
+	total, uids, err := svc.commentBlockRepo.GetByAuthor(ctx, uid, pageNo, pageSize)
+	if err != nil {
+		if errors.Is(err, repository.ErrParamsInvalid) {
+			return 0, nil, errno.ErrInvalidParam
+		}
+		return 0, nil, errno.ErrServerInternal
+	}
+	users, err := svc.userRepo.GetByIDs(ctx, uids)
+	if err != nil {
+		return 0, nil, errno.ErrServerInternal
+	}
+
+	userDTOs := make([]userdto.BriefDTO, 0, len(uids))
+	for _, id := range uids {
+		if user, ok := users[id]; ok {
+			userDTOs = append(userDTOs, userdto.ToBriefDTO(user))
+		}
+	}
+	return int(total), userDTOs, nil
+}
+
+// commentable 检查 uid 能否评论帖子: 作者不受限制, 其他用户需满足帖子的评论权限且没有被作者拉黑
+func (svc *commentService) commentable(ctx context.Context, post *model.Post, uid int64) error {
+	if post.UserID == uid {
+		return nil
+	}
+
+	switch post.CommentPolicy {
+	case model.CommentPolicyClosed:
+		return errno.ErrCommentClosed
+	case model.CommentPolicyFollowers:
+		followType, err := svc.followRepo.Exists(ctx, uid, post.UserID)
 		if err != nil {
+			return errno.ErrServerInternal
+		}
+		if followType != model.FollowIFollow && followType != model.FollowMutual {
+			return errno.ErrCommentFollowersOnly
+		}
+	}
+
+	blocked, err := svc.commentBlockRepo.Exists(ctx, post.UserID, uid)
+	if err != nil {
+		return errno.ErrServerInternal
+	}
+	if blocked {
+		return errno.ErrCommentBlocked
+	}
+	return nil
+}
+
+// viewablePost 查询 uid 能查看的帖子, 无权查看时与帖子不存在一样返回 ErrPostNotFound
+func (svc *commentService) viewablePost(ctx context.Context, pid, uid int64) (*model.Post, error) {
+	post, err := svc.postRepo.GetByID(ctx, pid)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return nil, errno.ErrPostNotFound
+		}
+		return nil, errno.ErrServerInternal
+	}
+	ok, err := canView(ctx, svc.followRepo, post, uid)
+	if err != nil {
+		return nil, errno.ErrServerInternal
+	}
+	if !ok {
+		return nil, errno.ErrPostNotFound
+	}
+	return post, nil
+}
+
+// ownedPost 查询 uid 发布的帖子, 不是作者时返回无权限
+func (svc *commentService) ownedPost(ctx context.Context, pid, uid int64) (*model.Post, error) {
+	post, err := svc.postRepo.GetByID(ctx, pid)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return nil, errno.ErrPostNotFound
+		}
+		return nil, errno.ErrServerInternal
+	}
+	if post.UserID != uid {
+		return nil, errno.ErrUnauthorized
+	}
+	return post, nil
+}
+
+// pinnedComment 查询帖子当前置顶的评论, 没有置顶或置顶的评论已删除、待审核时返回 nil
+func (svc *commentService) pinnedComment(ctx context.Context, post *model.Post) (*model.Comment, error) {
+	if post.PinnedCommentID == 0 {
+		return nil, nil
+	}
+
+	comment, err := svc.commentRepo.GetByID(ctx, post.PinnedCommentID)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, errno.ErrServerInternal
+	}
+	if comment.PostID != post.ID || comment.ParentID != 0 || comment.Status != model.CommentStatusNormal {
+		return nil, nil
+	}
+	return comment, nil
+}
+
+// likeable 查询 uid 可点赞的评论, 无权查看帖子时视为帖子不存在, 评论不属于帖子 pid 或尚在审核中时视为评论不存在
+func (svc *commentService) likeable(ctx context.Context, pid, cid, uid int64) (*model.Comment, error) {
+	if _, err := svc.viewablePost(ctx, pid, uid); err != nil {
+		return nil, err
+	}
+
+	comment, err := svc.commentRepo.GetByID(ctx, cid)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return nil, errno.ErrCommentNotFound
+		}
+		return nil, errno.ErrServerInternal
+	}
+	if comment.PostID != pid || comment.Status != model.CommentStatusNormal {
+		return nil, errno.ErrCommentNotFound
+	}
+	return comment, nil
+}
+
+// toDTOs 批量查询评论作者、回复目标的作者和当前用户的点赞状态, 组装评论 DTO; 作者不存在时返回空作者
+func (svc *commentService) toDTOs(ctx context.Context, uid int64, comments []*model.Comment) ([]commentdto.DTO, error) {
+	cids := make([]int64, 0, len(comments))
+	replyIDs := make([]int64, 0, len(comments))
+	for _, comment := range comments {
+		cids = append(cids, comment.ID)
+		if comment.ReplyID != 0 {
+			replyIDs = append(replyIDs, comment.ReplyID)
+		}
+	}
+
+	// 回复目标评论的作者与评论作者一起批量查询
+	replyUids, err := svc.commentRepo.GetUserIDs(ctx, replyIDs)
+	if err != nil {
+		return nil, errno.ErrServerInternal
+	}
+	uids := make([]int64, 0, len(comments)+len(replyUids))
+	for _, comment := range comments {
+		uids = append(uids, comment.UserID)
+	}
+	for _, replyUid := range replyUids {
+		uids = append(uids, replyUid)
+	}
+	users, err := svc.userRepo.GetByIDs(ctx, uids)
+	if err != nil {
+		return nil, errno.ErrServerInternal
+	}
+	liked, err := svc.commentLikeRepo.GetLikedIDs(ctx, uid, cids)
+	if err != nil {
+		return nil, errno.ErrServerInternal
+	}
+	texts := make(map[int64]string, len(comments))
+	for _, comment := range comments {
+		texts[comment.ID] = comment.Content
+	}
+	spans, err := svc.mentionSvc.Spans(ctx, model.MentionTargetComment, texts)
+	if err != nil {
+		return nil, err
+	}
+
+	commentDTOs := make([]commentdto.DTO, 0, len(comments))
+	for _, comment := range comments {
+		user, ok := users[comment.UserID]
+		if !ok {
 			user = &model.User{}
 		}
 		commentDTO := commentdto.ToDTO(comment, user)
+		commentDTO.Liked = liked[comment.ID]
+		commentDTO.Mentions = spans[comment.ID]
+		if replyUid, ok := replyUids[comment.ReplyID]; ok {
+			if replyUser, ok := users[replyUid]; ok {
+				replyTo := userdto.ToBriefDTO(replyUser)
+				commentDTO.ReplyTo = &replyTo
+			}
+		}
 		commentDTOs = append(commentDTOs, commentDTO)
 	}
-
-	return int(total), commentDTOs, nil
+	return commentDTOs, nil
 }
 
 // CheckAuth 判断是否有删除权限
@@ -0,0 +1,309 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/yzletter/go-postery/conf"
+	mentiondto "github.com/yzletter/go-postery/dto/mention"
+	"github.com/yzletter/go-postery/errno"
+	"github.com/yzletter/go-postery/model"
+	"github.com/yzletter/go-postery/repository"
+)
+
+// fakeCommentRepo 在内存中保存评论及其历史版本, 编辑时按编辑次数做乐观锁
+type fakeCommentRepo struct {
+	repository.CommentRepository
+	comments  map[int64]*model.Comment
+	revisions []*model.CommentRevision
+}
+
+func (repo *fakeCommentRepo) Create(ctx context.Context, comment *model.Comment) error {
+	comment.CreatedAt = time.Now()
+	repo.comments[comment.ID] = comment
+	return nil
+}
+
+func (repo *fakeCommentRepo) GetByID(ctx context.Context, id int64) (*model.Comment, error) {
+	comment, ok := repo.comments[id]
+	if !ok {
+		return nil, repository.ErrRecordNotFound
+	}
+	clone := *comment
+	return &clone, nil
+}
+
+func (repo *fakeCommentRepo) Edit(ctx context.Context, revision *model.CommentRevision, content string, status int) error {
+	comment := repo.comments[revision.CommentID]
+	if comment.EditCount != revision.Version {
+		return repository.ErrResourceConflict
+	}
+	now := time.Now()
+	repo.revisions = append(repo.revisions, revision)
+	comment.Content, comment.Status, comment.EditedAt = content, status, &now
+	comment.EditCount++
+	return nil
+}
+
+func (repo *fakeCommentRepo) GetUserIDs(ctx context.Context, ids []int64) (map[int64]int64, error) {
+	res := make(map[int64]int64, len(ids))
+	for _, id := range ids {
+		if comment, ok := repo.comments[id]; ok {
+			res[id] = comment.UserID
+		}
+	}
+	return res, nil
+}
+
+type fakeCommentLikeRepo struct {
+	repository.CommentLikeRepository
+}
+
+func (repo *fakeCommentLikeRepo) GetLikedIDs(ctx context.Context, uid int64, cids []int64) (map[int64]bool, error) {
+	return map[int64]bool{}, nil
+}
+
+// fakeCommentBlockRepo blocked[[2]int64{author, uid}] 为 true 表示 author 禁止了 uid 评论
+type fakeCommentBlockRepo struct {
+	repository.CommentBlockRepository
+	blocked map[[2]int64]bool
+}
+
+func (repo *fakeCommentBlockRepo) Exists(ctx context.Context, authorID, uid int64) (bool, error) {
+	return repo.blocked[[2]int64{authorID, uid}], nil
+}
+
+type fakeReportRepo struct {
+	repository.ReportRepository
+	submitted []*model.Report
+}
+
+func (repo *fakeReportRepo) Submit(ctx context.Context, report *model.Report) error {
+	repo.submitted = append(repo.submitted, report)
+	return nil
+}
+
+type fakeAnalyticsRepo struct {
+	repository.AnalyticsRepository
+}
+
+func (repo *fakeAnalyticsRepo) RecordPostEvent(ctx context.Context, pid int64, metric model.AnalyticsMetric, at time.Time) error {
+	return nil
+}
+
+type fakeMentionSvc struct {
+	MentionService
+}
+
+func (svc *fakeMentionSvc) Sync(ctx context.Context, targetType model.MentionTarget, targetID, postID, fromUID int64, text string, notify bool) ([]mentiondto.SpanDTO, error) {
+	return nil, nil
+}
+
+func (svc *fakeMentionSvc) Spans(ctx context.Context, targetType model.MentionTarget, texts map[int64]string) (map[int64][]mentiondto.SpanDTO, error) {
+	return map[int64][]mentiondto.SpanDTO{}, nil
+}
+
+// commentFixture 被测的评论服务及其使用的内存仓库
+type commentFixture struct {
+	svc        CommentService
+	posts      *fakePostRepo
+	comments   *fakeCommentRepo
+	follows    *fakeFollowRepo
+	blocks     *fakeCommentBlockRepo
+	reports    *fakeReportRepo
+	sensitives *fakeFilter
+}
+
+func newCommentFixture(posts ...*model.Post) *commentFixture {
+	f := &commentFixture{
+		posts:      &fakePostRepo{posts: make(map[int64]*model.Post)},
+		comments:   &fakeCommentRepo{comments: make(map[int64]*model.Comment)},
+		follows:    &fakeFollowRepo{following: make(map[[2]int64]bool)},
+		blocks:     &fakeCommentBlockRepo{blocked: make(map[[2]int64]bool)},
+		reports:    &fakeReportRepo{},
+		sensitives: &fakeFilter{actions: make(map[string]model.SensitiveAction)},
+	}
+	for _, post := range posts {
+		f.posts.posts[post.ID] = post
+	}
+	users := &fakeUserRepo{users: map[int64]*model.User{1: {ID: 1}, 2: {ID: 2}, 3: {ID: 3}}}
+	f.svc = NewCommentService(f.comments, &fakeCommentLikeRepo{}, f.blocks, users, f.posts, f.follows, f.reports,
+		&fakeAnalyticsRepo{}, &fakeIDGen{id: 1000}, f.sensitives, &fakeMentionSvc{})
+	return f
+}
+
+func TestCommentEditWindow(t *testing.T) {
+	ctx := context.Background()
+	const author, commenter, pid int64 = 1, 2, 100
+	f := newCommentFixture(&model.Post{ID: pid, UserID: author, Status: model.PostStatusNormal,
+		Visibility: model.PostVisibilityPublic, CommentPolicy: model.CommentPolicyOpen})
+
+	created, err := f.svc.Create(ctx, pid, commenter, 0, 0, "first")
+	if err != nil {
+		t.Fatalf("Create error = %v", err)
+	}
+
+	// 编辑窗口内可以编辑, 编辑前的内容保存为历史版本
+	edited, err := f.svc.Edit(ctx, pid, created.ID, commenter, "second")
+	if err != nil {
+		t.Fatalf("Edit error = %v", err)
+	}
+	if edited.Content != "second" || edited.EditCount != 1 || edited.EditedAt == "" {
+		t.Fatalf("Edit = %+v, want content second and edit_count 1", edited)
+	}
+	if len(f.comments.revisions) != 1 || f.comments.revisions[0].Content != "first" {
+		t.Fatalf("revisions = %+v, want the original content", f.comments.revisions)
+	}
+
+	// 只有评论作者可以编辑
+	if _, err := f.svc.Edit(ctx, pid, created.ID, author, "third"); !errors.Is(err, errno.ErrUnauthorized) {
+		t.Fatalf("Edit by others error = %v, want %v", err, errno.ErrUnauthorized)
+	}
+
+	// 超过编辑次数上限
+	f.comments.comments[created.ID].EditCount = conf.CommentEditMaxCount
+	if _, err := f.svc.Edit(ctx, pid, created.ID, commenter, "third"); !errors.Is(err, errno.ErrCommentEditLimit) {
+		t.Fatalf("Edit over limit error = %v, want %v", err, errno.ErrCommentEditLimit)
+	}
+
+	// 超过编辑窗口后不能再编辑, 内容保持不变
+	f.comments.comments[created.ID].EditCount = 1
+	f.comments.comments[created.ID].CreatedAt = time.Now().Add(-conf.CommentEditWindow - time.Second)
+	if _, err := f.svc.Edit(ctx, pid, created.ID, commenter, "third"); !errors.Is(err, errno.ErrCommentEditExpired) {
+		t.Fatalf("Edit after window error = %v, want %v", err, errno.ErrCommentEditExpired)
+	}
+	if got := f.comments.comments[created.ID].Content; got != "second" {
+		t.Fatalf("content after expired edit = %q, want %q", got, "second")
+	}
+}
+
+// go test -v ./service -run=^TestCommentEditWindow$ -count=1
+
+func TestCommentEditToReview(t *testing.T) {
+	ctx := context.Background()
+	const author, commenter, pid int64 = 1, 2, 100
+	f := newCommentFixture(&model.Post{ID: pid, UserID: author, Status: model.PostStatusNormal,
+		Visibility: model.PostVisibilityPublic, CommentPolicy: model.CommentPolicyOpen})
+	f.sensitives.actions["review"] = model.SensitiveReview
+
+	created, err := f.svc.Create(ctx, pid, commenter, 0, 0, "first")
+	if err != nil {
+		t.Fatalf("Create error = %v", err)
+	}
+	if got := f.posts.counts[pid][model.PostCommentCount]; got != 1 {
+		t.Fatalf("comment_count after create = %d, want 1", got)
+	}
+
+	// 编辑后命中审核类敏感词, 评论转为待审核并不再计入评论数
+	edited, err := f.svc.Edit(ctx, pid, created.ID, commenter, "review")
+	if err != nil {
+		t.Fatalf("Edit error = %v", err)
+	}
+	if !edited.Reviewing {
+		t.Fatalf("Edit reviewing = false, want true")
+	}
+	if got := f.posts.counts[pid][model.PostCommentCount]; got != 0 {
+		t.Fatalf("comment_count after edit = %d, want 0", got)
+	}
+	if len(f.reports.submitted) != 1 || f.reports.submitted[0].TargetID != created.ID {
+		t.Fatalf("submitted reports = %+v, want one for the comment", f.reports.submitted)
+	}
+}
+
+// go test -v ./service -run=^TestCommentEditToReview$ -count=1
+
+func TestCommentPolicy(t *testing.T) {
+	ctx := context.Background()
+	const author, follower, stranger, pid int64 = 1, 2, 3, 100
+	f := newCommentFixture(&model.Post{ID: pid, UserID: author, Status: model.PostStatusNormal,
+		Visibility: model.PostVisibilityPublic, CommentPolicy: model.CommentPolicyOpen, Version: 1})
+	f.follows.following[[2]int64{follower, author}] = true
+
+	// 默认所有人可评论
+	if _, err := f.svc.Create(ctx, pid, stranger, 0, 0, "open"); err != nil {
+		t.Fatalf("Create on open post error = %v", err)
+	}
+
+	// 只有作者可以修改评论权限, 修改评论设置不改变帖子的版本号, 不影响作者正在进行的编辑
+	if err := f.svc.SetPolicy(ctx, pid, stranger, model.CommentPolicyFollowers); !errors.Is(err, errno.ErrUnauthorized) {
+		t.Fatalf("SetPolicy by others error = %v, want %v", err, errno.ErrUnauthorized)
+	}
+	if err := f.svc.SetPolicy(ctx, pid, author, model.CommentPolicyFollowers); err != nil {
+		t.Fatalf("SetPolicy error = %v", err)
+	}
+	if post := f.posts.posts[pid]; post.CommentPolicy != model.CommentPolicyFollowers || post.Version != 1 {
+		t.Fatalf("after SetPolicy policy = %d version = %d, want %d and 1", post.CommentPolicy, post.Version, model.CommentPolicyFollowers)
+	}
+
+	// 仅粉丝可评论, 作者本人不受限制
+	if _, err := f.svc.Create(ctx, pid, stranger, 0, 0, "followers"); !errors.Is(err, errno.ErrCommentFollowersOnly) {
+		t.Fatalf("Create by stranger error = %v, want %v", err, errno.ErrCommentFollowersOnly)
+	}
+	if _, err := f.svc.Create(ctx, pid, follower, 0, 0, "followers"); err != nil {
+		t.Fatalf("Create by follower error = %v", err)
+	}
+	if _, err := f.svc.Create(ctx, pid, author, 0, 0, "followers"); err != nil {
+		t.Fatalf("Create by author error = %v", err)
+	}
+
+	// 被作者禁止评论的粉丝同样不能评论
+	f.blocks.blocked[[2]int64{author, follower}] = true
+	if _, err := f.svc.Create(ctx, pid, follower, 0, 0, "blocked"); !errors.Is(err, errno.ErrCommentBlocked) {
+		t.Fatalf("Create by blocked follower error = %v, want %v", err, errno.ErrCommentBlocked)
+	}
+	f.blocks.blocked[[2]int64{author, follower}] = false
+
+	// 关闭评论后不能发表也不能编辑已有的评论
+	created, err := f.svc.Create(ctx, pid, follower, 0, 0, "before close")
+	if err != nil {
+		t.Fatalf("Create error = %v", err)
+	}
+	if err := f.svc.SetPolicy(ctx, pid, author, model.CommentPolicyClosed); err != nil {
+		t.Fatalf("SetPolicy error = %v", err)
+	}
+	if _, err := f.svc.Create(ctx, pid, follower, 0, 0, "closed"); !errors.Is(err, errno.ErrCommentClosed) {
+		t.Fatalf("Create on closed post error = %v, want %v", err, errno.ErrCommentClosed)
+	}
+	if _, err := f.svc.Edit(ctx, pid, created.ID, follower, "closed"); !errors.Is(err, errno.ErrCommentClosed) {
+		t.Fatalf("Edit on closed post error = %v, want %v", err, errno.ErrCommentClosed)
+	}
+}
+
+// go test -v ./service -run=^TestCommentPolicy$ -count=1
+
+func TestCommentPin(t *testing.T) {
+	ctx := context.Background()
+	const author, commenter, pid int64 = 1, 2, 100
+	f := newCommentFixture(&model.Post{ID: pid, UserID: author, Status: model.PostStatusNormal,
+		Visibility: model.PostVisibilityPublic, CommentPolicy: model.CommentPolicyOpen, Version: 1})
+
+	root, err := f.svc.Create(ctx, pid, commenter, 0, 0, "root")
+	if err != nil {
+		t.Fatalf("Create error = %v", err)
+	}
+	reply, err := f.svc.Create(ctx, pid, commenter, root.ID, root.ID, "reply")
+	if err != nil {
+		t.Fatalf("Create error = %v", err)
+	}
+
+	// 只能置顶一级评论, 置顶和取消置顶都不改变帖子的版本号
+	if err := f.svc.Pin(ctx, pid, reply.ID, author); !errors.Is(err, errno.ErrCommentPinInvalid) {
+		t.Fatalf("Pin reply error = %v, want %v", err, errno.ErrCommentPinInvalid)
+	}
+	if err := f.svc.Pin(ctx, pid, root.ID, author); err != nil {
+		t.Fatalf("Pin error = %v", err)
+	}
+	if post := f.posts.posts[pid]; post.PinnedCommentID != root.ID || post.Version != 1 {
+		t.Fatalf("after Pin pinned = %d version = %d, want %d and 1", post.PinnedCommentID, post.Version, root.ID)
+	}
+	if err := f.svc.Unpin(ctx, pid, root.ID, author); err != nil {
+		t.Fatalf("Unpin error = %v", err)
+	}
+	if post := f.posts.posts[pid]; post.PinnedCommentID != 0 || post.Version != 1 {
+		t.Fatalf("after Unpin pinned = %d version = %d, want 0 and 1", post.PinnedCommentID, post.Version)
+	}
+}
+
+// go test -v ./service -run=^TestCommentPin$ -count=1
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+
+	"github.com/yzletter/go-postery/model"
+	"github.com/yzletter/go-postery/repository"
+	"github.com/yzletter/go-postery/service/ports"
+)
+
+// 以下为服务层测试使用的内存实现, 只实现被测方法用到的接口方法, 其余方法调用时 panic
+
+// fakeFollowRepo following[[2]int64{fer, fee}] 为 true 表示 fer 关注了 fee
+type fakeFollowRepo struct {
+	repository.FollowRepository
+	following map[[2]int64]bool
+}
+
+func (repo *fakeFollowRepo) Exists(ctx context.Context, ferID, feeID int64) (model.FollowType, error) {
+	if repo.following[[2]int64{ferID, feeID}] {
+		return model.FollowIFollow, nil
+	}
+	return model.FollowNone, nil
+}
+
+// fakePostRepo 按 ID 保存帖子, 记录评论数等计数增量
+type fakePostRepo struct {
+	repository.PostRepository
+	posts  map[int64]*model.Post
+	counts map[int64]map[model.PostCntField]int
+}
+
+func (repo *fakePostRepo) GetByID(ctx context.Context, id int64) (*model.Post, error) {
+	post, ok := repo.posts[id]
+	if !ok {
+		return nil, repository.ErrRecordNotFound
+	}
+	clone := *post
+	return &clone, nil
+}
+
+func (repo *fakePostRepo) UpdateCount(ctx context.Context, id int64, field model.PostCntField, delta int) error {
+	if repo.counts == nil {
+		repo.counts = make(map[int64]map[model.PostCntField]int)
+	}
+	if repo.counts[id] == nil {
+		repo.counts[id] = make(map[model.PostCntField]int)
+	}
+	repo.counts[id][field] += delta
+	return nil
+}
+
+// Update 只支持评论设置相关的字段
+func (repo *fakePostRepo) Update(ctx context.Context, id int64, updates map[string]any) error {
+	post, ok := repo.posts[id]
+	if !ok {
+		return repository.ErrRecordNotFound
+	}
+	for column, value := range updates {
+		switch column {
+		case "comment_policy":
+			post.CommentPolicy = value.(model.CommentPolicy)
+		case "pinned_comment_id":
+			switch cid := value.(type) {
+			case int64:
+				post.PinnedCommentID = cid
+			case int:
+				post.PinnedCommentID = int64(cid)
+			}
+		}
+	}
+	return nil
+}
+
+// fakeUserRepo 按 ID 保存用户
+type fakeUserRepo struct {
+	repository.UserRepository
+	users map[int64]*model.User
+}
+
+func (repo *fakeUserRepo) GetByID(ctx context.Context, id int64) (*model.User, error) {
+	user, ok := repo.users[id]
+	if !ok {
+		return nil, repository.ErrRecordNotFound
+	}
+	return user, nil
+}
+
+func (repo *fakeUserRepo) GetByIDs(ctx context.Context, ids []int64) (map[int64]*model.User, error) {
+	res := make(map[int64]*model.User, len(ids))
+	for _, id := range ids {
+		if user, ok := repo.users[id]; ok {
+			res[id] = user
+		}
+	}
+	return res, nil
+}
+
+// fakeIDGen 从 1 开始依次生成 ID
+type fakeIDGen struct {
+	id int64
+}
+
+func (gen *fakeIDGen) NextID() int64 {
+	gen.id++
+	return gen.id
+}
+
+// fakeFilter 按整段文本匹配敏感词, 未配置的文本直接放行
+type fakeFilter struct {
+	actions map[string]model.SensitiveAction
+}
+
+func (filter *fakeFilter) Filter(text string) ports.FilterResult {
+	action, ok := filter.actions[text]
+	if !ok {
+		return ports.FilterResult{Action: model.SensitivePass, Text: text}
+	}
+	return ports.FilterResult{Action: action, Text: "***", Categories: []string{"test"}}
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/yzletter/go-postery/conf"
+	feeddto "github.com/yzletter/go-postery/dto/feed"
+	"github.com/yzletter/go-postery/errno"
+	"github.com/yzletter/go-postery/model"
+	"github.com/yzletter/go-postery/repository"
+	"github.com/yzletter/go-postery/service/ports"
+)
+
+type feedService struct {
+	postRepo repository.PostRepository
+	userRepo repository.UserRepository
+	tagRepo  repository.TagRepository
+	renderer ports.ContentRenderer // 用于补全旧帖子的摘要
+}
+
+func NewFeedService(postRepo repository.PostRepository, userRepo repository.UserRepository,
+	tagRepo repository.TagRepository, renderer ports.ContentRenderer) FeedService {
+	return &feedService{
+		postRepo: postRepo,
+		userRepo: userRepo,
+		tagRepo:  tagRepo,
+		renderer: renderer,
+	}
+}
+
+// Posts 全站最新帖子的订阅源, 只包含所有人可见的正常帖子
+func (svc *feedService) Posts(ctx context.Context) (feeddto.Feed, error) {
+	_, posts, err := svc.postRepo.GetByPage(ctx, model.PostVisibilityPublic, nil, 0, conf.FeedSize)
+	if err != nil {
+		return feeddto.Feed{}, errno.ErrServerInternal
+	}
+	return svc.build(ctx, "/feeds/posts", "go-postery 最新帖子", posts)
+}
+
+// Tag 某个标签下最新帖子的订阅源
+func (svc *feedService) Tag(ctx context.Context, slug string) (feeddto.Feed, error) {
+	tag, err := svc.tagRepo.GetBySlug(ctx, slug)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return feeddto.Feed{}, errno.ErrTagNotFound
+		}
+		return feeddto.Feed{}, errno.ErrServerInternal
+	}
+
+	_, posts, err := svc.postRepo.GetByPageAndTag(ctx, tag.ID, model.PostVisibilityPublic, nil, 0, conf.FeedSize)
+	if err != nil {
+		return feeddto.Feed{}, errno.ErrServerInternal
+	}
+	return svc.build(ctx, "/feeds/tags/"+tag.Slug, "go-postery 标签: "+tag.Name, posts)
+}
+
+// User 某个用户最新帖子的订阅源
+func (svc *feedService) User(ctx context.Context, uid int64) (feeddto.Feed, error) {
+	user, err := svc.userRepo.GetByID(ctx, uid)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return feeddto.Feed{}, errno.ErrUserNotFound
+		}
+		return feeddto.Feed{}, errno.ErrServerInternal
+	}
+
+	_, posts, err := svc.postRepo.GetByUid(ctx, uid, model.PostVisibilityPublic, 1, conf.FeedSize)
+	if err != nil {
+		return feeddto.Feed{}, errno.ErrServerInternal
+	}
+	return svc.build(ctx, "/feeds/users/"+strconv.FormatInt(user.ID, 10), "go-postery 用户: "+user.Username, posts)
+}
+
+// build 补全摘要、作者和标签, 订阅源的更新时间取其中最近的编辑时间
+func (svc *feedService) build(ctx context.Context, path, title string, posts []*model.Post) (feeddto.Feed, error) {
+	fillRendered(ctx, svc.renderer, svc.postRepo, posts...)
+
+	uids := make([]int64, 0, len(posts))
+	pids := make([]int64, 0, len(posts))
+	for _, post := range posts {
+		uids = append(uids, post.UserID)
+		pids = append(pids, post.ID)
+	}
+	slices.Sort(uids)
+	authors, err := svc.userRepo.GetByIDs(ctx, slices.Compact(uids))
+	if err != nil {
+		return feeddto.Feed{}, errno.ErrServerInternal
+	}
+	tags, err := svc.tagRepo.FindTagsByPostIDs(ctx, pids)
+	if err != nil {
+		return feeddto.Feed{}, errno.ErrServerInternal
+	}
+
+	feed := feeddto.Feed{
+		Path:  path,
+		Title: title,
+		Items: make([]feeddto.Item, 0, len(posts)),
+	}
+	for _, post := range posts {
+		item := feeddto.ToItem(post, authors[post.UserID], tags[post.ID])
+		if item.Updated.After(feed.Updated) {
+			feed.Updated = item.Updated
+		}
+		feed.Items = append(feed.Items, item)
+	}
+	// MySQL DATETIME 只精确到秒, 与 Last-Modified 保持一致
+	feed.Updated = feed.Updated.Truncate(time.Second)
+	return feed, nil
+}
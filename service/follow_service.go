@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"log/slog"
+	"time"
 
 	dto "github.com/yzletter/go-postery/dto/user"
 	"github.com/yzletter/go-postery/errno"
@@ -13,16 +14,19 @@ import (
 )
 
 type followService struct {
-	followRepo repository.FollowRepository
-	userRepo   repository.UserRepository
-	idGen      ports.IDGenerator
+	followRepo    repository.FollowRepository
+	userRepo      repository.UserRepository
+	analyticsRepo repository.AnalyticsRepository
+	idGen         ports.IDGenerator
 }
 
-func NewFollowService(followRepo repository.FollowRepository, userRepo repository.UserRepository, idGen ports.IDGenerator) FollowService {
+func NewFollowService(followRepo repository.FollowRepository, userRepo repository.UserRepository,
+	analyticsRepo repository.AnalyticsRepository, idGen ports.IDGenerator) FollowService {
 	return &followService{
-		followRepo: followRepo,
-		userRepo:   userRepo,
-		idGen:      idGen,
+		followRepo:    followRepo,
+		userRepo:      userRepo,
+		analyticsRepo: analyticsRepo,
+		idGen:         idGen,
 	}
 }
 
@@ -52,6 +56,7 @@ func (svc *followService) Follow(ctx context.Context, ferId, feeId int64) error
 		return errno.ErrServerInternal
 	}
 	svc.userRepo.ChangeScore(ctx, feeId, 1)
+	svc.recordFollower(ctx, feeId, true)
 	return nil
 }
 
@@ -76,6 +81,7 @@ func (svc *followService) UnFollow(ctx context.Context, ferId, feeId int64) erro
 	}
 
 	svc.userRepo.ChangeScore(ctx, feeId, -1)
+	svc.recordFollower(ctx, feeId, false)
 
 	return nil
 }
@@ -131,3 +137,10 @@ func (svc *followService) ListFolloweesByPage(ctx context.Context, uid int64, pa
 
 	return int(total), res, nil
 }
+
+// recordFollower 将粉丝变化计入被关注者的数据统计
+func (svc *followService) recordFollower(ctx context.Context, uid int64, gained bool) {
+	if err := svc.analyticsRepo.RecordFollower(ctx, uid, gained, time.Now()); err != nil {
+		slog.Error("Record Analytics Follower Failed", "uid", uid, "error", err)
+	}
+}
@@ -0,0 +1,209 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+
+	"github.com/yzletter/go-postery/conf"
+	mentiondto "github.com/yzletter/go-postery/dto/mention"
+	"github.com/yzletter/go-postery/errno"
+	"github.com/yzletter/go-postery/model"
+	"github.com/yzletter/go-postery/repository"
+	"github.com/yzletter/go-postery/service/ports"
+	"github.com/yzletter/go-postery/utils"
+)
+
+type mentionService struct {
+	mentionRepo repository.MentionRepository
+	userRepo    repository.UserRepository
+	idGen       ports.IDGenerator
+
+	mu       sync.RWMutex
+	handlers []MentionHandler
+}
+
+func NewMentionService(mentionRepo repository.MentionRepository, userRepo repository.UserRepository, idGen ports.IDGenerator) MentionService {
+	return &mentionService{
+		mentionRepo: mentionRepo,
+		userRepo:    userRepo,
+		idGen:       idGen,
+	}
+}
+
+// Subscribe 订阅 @ 事件, 每次有用户被新提及时 handler 在独立的 goroutine 中被调用一次
+func (svc *mentionService) Subscribe(handler MentionHandler) {
+	svc.mu.Lock()
+	defer svc.mu.Unlock()
+	svc.handlers = append(svc.handlers, handler)
+}
+
+// Sync 解析 text 中的 @用户名, 将对象的提及记录更新为解析结果, 返回生效的提及位置;
+// 作者提及自己不生效, 至多 conf.MentionMaxPerItem 个用户生效; notify 为 true 时为新增的提及发布事件, 编辑时已提及过的用户不会重复收到
+func (svc *mentionService) Sync(ctx context.Context, targetType model.MentionTarget, targetID, postID, fromUID int64, text string, notify bool) ([]mentiondto.SpanDTO, error) {
+	spans := utils.ParseMentions(text)
+
+	// 按出现顺序取不同的用户名, 用户名不区分大小写
+	var names []string
+	seen := make(map[string]bool)
+	for _, span := range spans {
+		name := strings.ToLower(span.Username)
+		if seen[name] {
+			continue
+		}
+		if len(names) == conf.MentionMaxCandidate {
+			break
+		}
+		seen[name] = true
+		names = append(names, span.Username)
+	}
+
+	// 查找被提及的用户
+	users, err := svc.userRepo.GetByUsernames(ctx, names)
+	if err != nil {
+		return nil, errno.ErrServerInternal
+	}
+	byName := make(map[string]*model.User, len(users))
+	for _, user := range users {
+		byName[strings.ToLower(user.Username)] = user
+	}
+	mentioned := make(map[string]*model.User)
+	var mentionedIDs []int64
+	for _, name := range names {
+		user, ok := byName[strings.ToLower(name)]
+		if !ok || user.ID == fromUID || mentioned[strings.ToLower(user.Username)] != nil {
+			continue
+		}
+		if len(mentionedIDs) == conf.MentionMaxPerItem {
+			break
+		}
+		mentioned[strings.ToLower(user.Username)] = user
+		mentionedIDs = append(mentionedIDs, user.ID)
+	}
+
+	// 与已有的提及对比, 只增删变化的部分
+	existing, err := svc.mentionRepo.GetByTargets(ctx, targetType, []int64{targetID})
+	if err != nil {
+		return nil, errno.ErrServerInternal
+	}
+	before := make(map[int64]bool, len(existing))
+	for _, mention := range existing {
+		before[mention.UserID] = true
+	}
+	now := make(map[int64]bool, len(mentionedIDs))
+	var added []*model.Mention
+	for _, uid := range mentionedIDs {
+		now[uid] = true
+		if !before[uid] {
+			added = append(added, &model.Mention{
+				ID:         svc.idGen.NextID(),
+				TargetType: targetType,
+				TargetID:   targetID,
+				PostID:     postID,
+				FromUID:    fromUID,
+				UserID:     uid,
+			})
+		}
+	}
+	var removed []int64
+	for uid := range before {
+		if !now[uid] {
+			removed = append(removed, uid)
+		}
+	}
+	if err := svc.mentionRepo.Create(ctx, added); err != nil {
+		return nil, errno.ErrServerInternal
+	}
+	if err := svc.mentionRepo.Delete(ctx, targetType, targetID, removed); err != nil {
+		return nil, errno.ErrServerInternal
+	}
+
+	if notify {
+		svc.publish(added)
+	}
+	return mentionSpans(text, mentioned), nil
+}
+
+// Spans 批量计算对象中生效的提及位置, texts 为对象 ID 到正文的映射
+func (svc *mentionService) Spans(ctx context.Context, targetType model.MentionTarget, texts map[int64]string) (map[int64][]mentiondto.SpanDTO, error) {
+	ids := make([]int64, 0, len(texts))
+	for id := range texts {
+		ids = append(ids, id)
+	}
+	mentions, err := svc.mentionRepo.GetByTargets(ctx, targetType, ids)
+	if err != nil {
+		return nil, errno.ErrServerInternal
+	}
+	uids := make([]int64, 0, len(mentions))
+	for _, mention := range mentions {
+		uids = append(uids, mention.UserID)
+	}
+	users, err := svc.userRepo.GetByIDs(ctx, uids)
+	if err != nil {
+		return nil, errno.ErrServerInternal
+	}
+
+	// 按对象归集被提及的用户, 用户改名后旧的 @ 不再生效
+	mentioned := make(map[int64]map[string]*model.User)
+	for _, mention := range mentions {
+		user, ok := users[mention.UserID]
+		if !ok {
+			continue
+		}
+		if mentioned[mention.TargetID] == nil {
+			mentioned[mention.TargetID] = make(map[string]*model.User)
+		}
+		mentioned[mention.TargetID][strings.ToLower(user.Username)] = user
+	}
+
+	res := make(map[int64][]mentiondto.SpanDTO, len(mentioned))
+	for id, users := range mentioned {
+		res[id] = mentionSpans(texts[id], users)
+	}
+	return res, nil
+}
+
+// publish 在独立的 goroutine 中把新增的提及分发给所有订阅者, 订阅者 panic 不影响主流程
+func (svc *mentionService) publish(mentions []*model.Mention) {
+	svc.mu.RLock()
+	handlers := svc.handlers
+	svc.mu.RUnlock()
+
+	for _, handler := range handlers {
+		for _, mention := range mentions {
+			go func(handler MentionHandler, mention model.Mention) {
+				defer func() {
+					if r := recover(); r != nil {
+						slog.Error("Mention Handler Panic", "mention_id", mention.ID, "panic", r)
+					}
+				}()
+				// 请求结束后上下文会被回收, 订阅者使用独立的上下文
+				handler(context.Background(), mention)
+			}(handler, *mention)
+		}
+	}
+}
+
+// mentionSpans 在 text 中找出 users 里用户的 @ 位置, users 以小写用户名为键
+func mentionSpans(text string, users map[string]*model.User) []mentiondto.SpanDTO {
+	var res []mentiondto.SpanDTO
+	offset, last := 0, 0
+	for _, span := range utils.ParseMentions(text) {
+		user, ok := users[strings.ToLower(span.Username)]
+		if !ok {
+			continue
+		}
+		offset += utils.UTF16Len(text[last:span.Start])
+		length := utils.UTF16Len(text[span.Start:span.End])
+		res = append(res, mentiondto.SpanDTO{
+			UserID:   user.ID,
+			Username: user.Username,
+			Offset:   offset,
+			Length:   length,
+		})
+		offset += length
+		last = span.End
+	}
+	return res
+}
@@ -0,0 +1,288 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/yzletter/go-postery/conf"
+	polldto "github.com/yzletter/go-postery/dto/poll"
+	userdto "github.com/yzletter/go-postery/dto/user"
+	"github.com/yzletter/go-postery/errno"
+	"github.com/yzletter/go-postery/model"
+	"github.com/yzletter/go-postery/repository"
+	"github.com/yzletter/go-postery/service/ports"
+)
+
+type pollService struct {
+	pollRepo   repository.PollRepository
+	postRepo   repository.PostRepository
+	userRepo   repository.UserRepository
+	followRepo repository.FollowRepository
+	idGen      ports.IDGenerator   // 用于生成 ID
+	filter     ports.ContentFilter // 用于过滤敏感词
+}
+
+func NewPollService(pollRepo repository.PollRepository, postRepo repository.PostRepository,
+	userRepo repository.UserRepository, followRepo repository.FollowRepository,
+	idGen ports.IDGenerator, filter ports.ContentFilter) PollService {
+	return &pollService{
+		pollRepo:   pollRepo,
+		postRepo:   postRepo,
+		userRepo:   userRepo,
+		followRepo: followRepo,
+		idGen:      idGen,
+		filter:     filter,
+	}
+}
+
+// Create 为帖子附带一个投票, 仅作者可用, 每篇帖子最多一个投票; deadline 为 nil 时不截止
+func (svc *pollService) Create(ctx context.Context, uid, pid int64, options []string, multiple, anonymous bool, deadline *time.Time) (polldto.DTO, error) {
+	var empty polldto.DTO
+	texts, err := svc.screenOptions(options, deadline)
+	if err != nil {
+		return empty, err
+	}
+
+	post, err := svc.postRepo.GetByID(ctx, pid)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return empty, errno.ErrPostNotFound
+		}
+		return empty, errno.ErrServerInternal
+	}
+	if post.UserID != uid {
+		return empty, errno.ErrUnauthorized
+	}
+
+	poll := &model.Poll{
+		ID:        svc.idGen.NextID(),
+		PostID:    pid,
+		Multiple:  multiple,
+		Anonymous: anonymous,
+		Deadline:  deadline,
+	}
+	pollOptions := make([]*model.PollOption, 0, len(texts))
+	for i, text := range texts {
+		pollOptions = append(pollOptions, &model.PollOption{
+			ID:       svc.idGen.NextID(),
+			PollID:   poll.ID,
+			Position: i + 1,
+			Text:     text,
+		})
+	}
+	if err := svc.pollRepo.Create(ctx, poll, pollOptions); err != nil {
+		if errors.Is(err, repository.ErrUniqueKey) {
+			return empty, errno.ErrPollInvalid
+		}
+		return empty, errno.ErrServerInternal
+	}
+	return polldto.ToDTO(poll, pollOptions, nil, time.Now()), nil
+}
+
+// Result 获取帖子的投票结果, uid 为当前登录用户, 未登录时为 0
+func (svc *pollService) Result(ctx context.Context, pid, uid int64) (polldto.DTO, error) {
+	var empty polldto.DTO
+	if _, err := svc.visiblePost(ctx, pid, uid); err != nil {
+		return empty, err
+	}
+
+	res, err := pollResult(ctx, svc.pollRepo, pid, uid)
+	if err != nil {
+		return empty, err
+	}
+	if res == nil {
+		return empty, errno.ErrPollNotFound
+	}
+	return *res, nil
+}
+
+// Vote 投票, 单选时只能选一个选项, 每人只能投一次且不可修改; 返回投票后的结果
+func (svc *pollService) Vote(ctx context.Context, pid, uid int64, optionIDs []int64) (polldto.DTO, error) {
+	var empty polldto.DTO
+	if _, err := svc.visiblePost(ctx, pid, uid); err != nil {
+		return empty, err
+	}
+
+	poll, options, err := svc.pollRepo.GetByPostID(ctx, pid)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return empty, errno.ErrPollNotFound
+		}
+		return empty, errno.ErrServerInternal
+	}
+	now := time.Now()
+	if poll.Closed(now) {
+		return empty, errno.ErrPollClosed
+	}
+
+	// 选项去重后必须都属于该投票
+	slices.Sort(optionIDs)
+	optionIDs = slices.Compact(optionIDs)
+	if len(optionIDs) == 0 || (!poll.Multiple && len(optionIDs) > 1) {
+		return empty, errno.ErrPollOptionInvalid
+	}
+	for _, optionID := range optionIDs {
+		if !slices.ContainsFunc(options, func(option *model.PollOption) bool { return option.ID == optionID }) {
+			return empty, errno.ErrPollOptionInvalid
+		}
+	}
+
+	// Redis 中的投票人集合丢失时以 MySQL 为准
+	voted, err := svc.pollRepo.GetVotedOptions(ctx, poll.ID, uid)
+	if err != nil {
+		return empty, errno.ErrServerInternal
+	}
+	if len(voted) > 0 {
+		return empty, errno.ErrPollVoted
+	}
+
+	votes := make([]*model.PollVote, 0, len(optionIDs))
+	for _, optionID := range optionIDs {
+		votes = append(votes, &model.PollVote{
+			ID:       svc.idGen.NextID(),
+			PollID:   poll.ID,
+			OptionID: optionID,
+			UserID:   uid,
+		})
+	}
+	if err := svc.pollRepo.Vote(ctx, poll, votes); err != nil {
+		if errors.Is(err, repository.ErrUniqueKey) {
+			return empty, errno.ErrPollVoted
+		}
+		return empty, errno.ErrServerInternal
+	}
+
+	// 计数已计入 Redis, 直接在本次读到的结果上累加
+	poll.VoterCount += 1
+	for _, option := range options {
+		if slices.Contains(optionIDs, option.ID) {
+			option.VoteCount += 1
+		}
+	}
+	return polldto.ToDTO(poll, options, optionIDs, now), nil
+}
+
+// ListVoters 按页查看投了某个选项的用户, 匿名投票不公开投票人
+func (svc *pollService) ListVoters(ctx context.Context, pid, optionID, uid int64, pageNo, pageSize int) (int, []userdto.BriefDTO, error) {
+	if _, err := svc.visiblePost(ctx, pid, uid); err != nil {
+		return 0, nil, err
+	}
+
+	poll, options, err := svc.pollRepo.GetByPostID(ctx, pid)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return 0, nil, errno.ErrPollNotFound
+		}
+		return 0, nil, errno.ErrServerInternal
+	}
+	if poll.Anonymous {
+		return 0, nil, errno.ErrPollAnonymous
+	}
+	if !slices.ContainsFunc(options, func(option *model.PollOption) bool { return option.ID == optionID }) {
+		return 0, nil, errno.ErrPollOptionInvalid
+	}
+
+	total, uids, err := svc.pollRepo.GetVoters(ctx, optionID, pageNo, pageSize)
+	if err != nil {
+		return 0, nil, errno.ErrServerInternal
+	}
+	users, err := svc.userRepo.GetByIDs(ctx, uids)
+	if err != nil {
+		return 0, nil, errno.ErrServerInternal
+	}
+
+	res := make([]userdto.BriefDTO, 0, len(uids))
+	for _, id := range uids {
+		if user, ok := users[id]; ok {
+			res = append(res, userdto.ToBriefDTO(user))
+		}
+	}
+	return int(total), res, nil
+}
+
+// FlushCount 将 Redis 中的得票数增量批量落库, 由定时任务调用
+func (svc *pollService) FlushCount(ctx context.Context) {
+	cnt, err := svc.pollRepo.FlushCount(ctx, conf.PollCntFlushBatchSize)
+	if err != nil {
+		slog.Error("Flush Poll Count Failed", "flushed", cnt, "error", err)
+		return
+	}
+	if cnt > 0 {
+		slog.Info("Flush Poll Count Succeed", "flushed", cnt)
+	}
+}
+
+// visiblePost 查找 uid 能看到的帖子, 无权查看时与帖子不存在的表现一致
+func (svc *pollService) visiblePost(ctx context.Context, pid, uid int64) (*model.Post, error) {
+	post, err := svc.postRepo.GetByID(ctx, pid)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return nil, errno.ErrPostNotFound
+		}
+		return nil, errno.ErrServerInternal
+	}
+	ok, err := canView(ctx, svc.followRepo, post, uid)
+	if err != nil {
+		return nil, errno.ErrServerInternal
+	}
+	if !ok {
+		return nil, errno.ErrPostNotFound
+	}
+	return post, nil
+}
+
+// Validate 校验投票选项数量、内容、敏感词和截止时间, 供创建帖子前提前校验, 避免帖子已创建而投票创建失败
+func (svc *pollService) Validate(options []string, deadline *time.Time) error {
+	_, err := svc.screenOptions(options, deadline)
+	return err
+}
+
+// screenOptions 校验投票参数并过滤选项中的敏感词, 返回打码后的选项; 命中拒绝类敏感词或打码后选项重复时返回错误
+// 命中审核类敏感词时只做替换, 帖子本身已送审
+func (svc *pollService) screenOptions(options []string, deadline *time.Time) ([]string, error) {
+	if len(options) < conf.PollOptionMin || len(options) > conf.PollOptionMax {
+		return nil, errno.ErrPollInvalid
+	}
+	if deadline != nil && !deadline.After(time.Now()) {
+		return nil, errno.ErrPollInvalid
+	}
+
+	texts := slices.Clone(options)
+	ptrs := make([]*string, 0, len(texts))
+	for i := range texts {
+		ptrs = append(ptrs, &texts[i])
+	}
+	if _, err := screen(svc.filter, ptrs...); err != nil {
+		return nil, err
+	}
+	for i, text := range texts {
+		if text == "" || slices.Contains(texts[:i], text) {
+			return nil, errno.ErrPollInvalid
+		}
+	}
+	return texts, nil
+}
+
+// pollResult 获取帖子的投票结果及 uid 的投票情况, 帖子没有投票时返回 nil
+func pollResult(ctx context.Context, pollRepo repository.PollRepository, pid, uid int64) (*polldto.DTO, error) {
+	poll, options, err := pollRepo.GetByPostID(ctx, pid)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, errno.ErrServerInternal
+	}
+
+	var voted []int64
+	if uid != 0 {
+		voted, err = pollRepo.GetVotedOptions(ctx, poll.ID, uid)
+		if err != nil {
+			return nil, errno.ErrServerInternal
+		}
+	}
+	res := polldto.ToDTO(poll, options, voted, time.Now())
+	return &res, nil
+}
@@ -0,0 +1,193 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	polldto "github.com/yzletter/go-postery/dto/poll"
+	"github.com/yzletter/go-postery/errno"
+	"github.com/yzletter/go-postery/model"
+	"github.com/yzletter/go-postery/repository"
+)
+
+// fakePollRepo 在内存中保存投票, 投票时直接累加得票数, 相当于 Redis 增量已经落库
+type fakePollRepo struct {
+	repository.PollRepository
+	polls   map[int64]*model.Poll // 帖子 ID -> 投票
+	options map[int64][]*model.PollOption
+	votes   []*model.PollVote
+}
+
+func (repo *fakePollRepo) Create(ctx context.Context, poll *model.Poll, options []*model.PollOption) error {
+	repo.polls[poll.PostID] = poll
+	repo.options[poll.ID] = options
+	return nil
+}
+
+func (repo *fakePollRepo) GetByPostID(ctx context.Context, pid int64) (*model.Poll, []*model.PollOption, error) {
+	poll, ok := repo.polls[pid]
+	if !ok {
+		return nil, nil, repository.ErrRecordNotFound
+	}
+	pollClone := *poll
+	options := make([]*model.PollOption, 0, len(repo.options[poll.ID]))
+	for _, option := range repo.options[poll.ID] {
+		optionClone := *option
+		options = append(options, &optionClone)
+	}
+	return &pollClone, options, nil
+}
+
+func (repo *fakePollRepo) Vote(ctx context.Context, poll *model.Poll, votes []*model.PollVote) error {
+	for _, vote := range votes {
+		for _, existing := range repo.votes {
+			if existing.OptionID == vote.OptionID && existing.UserID == vote.UserID {
+				return repository.ErrUniqueKey
+			}
+		}
+	}
+	repo.votes = append(repo.votes, votes...)
+	repo.polls[poll.PostID].VoterCount++
+	for _, vote := range votes {
+		for _, option := range repo.options[poll.ID] {
+			if option.ID == vote.OptionID {
+				option.VoteCount++
+			}
+		}
+	}
+	return nil
+}
+
+func (repo *fakePollRepo) GetVotedOptions(ctx context.Context, pollID, uid int64) ([]int64, error) {
+	var voted []int64
+	for _, vote := range repo.votes {
+		if vote.PollID == pollID && vote.UserID == uid {
+			voted = append(voted, vote.OptionID)
+		}
+	}
+	return voted, nil
+}
+
+func newTestPollService(posts ...*model.Post) (PollService, *fakeFollowRepo) {
+	postRepo := &fakePostRepo{posts: make(map[int64]*model.Post)}
+	for _, post := range posts {
+		postRepo.posts[post.ID] = post
+	}
+	pollRepo := &fakePollRepo{polls: make(map[int64]*model.Poll), options: make(map[int64][]*model.PollOption)}
+	followRepo := &fakeFollowRepo{following: make(map[[2]int64]bool)}
+	filter := &fakeFilter{actions: map[string]model.SensitiveAction{"违禁": model.SensitiveReject}}
+	return NewPollService(pollRepo, postRepo, &fakeUserRepo{}, followRepo, &fakeIDGen{id: 1000}, filter), followRepo
+}
+
+// voteCounts 按选项顺序返回得票数
+func voteCounts(res polldto.DTO) []int {
+	counts := make([]int, 0, len(res.Options))
+	for _, option := range res.Options {
+		counts = append(counts, option.VoteCount)
+	}
+	return counts
+}
+
+func TestPollTally(t *testing.T) {
+	ctx := context.Background()
+	const author, pid int64 = 1, 100
+	svc, _ := newTestPollService(&model.Post{ID: pid, UserID: author, Status: model.PostStatusNormal, Visibility: model.PostVisibilityPublic})
+
+	created, err := svc.Create(ctx, author, pid, []string{"A", "B", "C"}, true, false, nil)
+	if err != nil {
+		t.Fatalf("Create error = %v", err)
+	}
+	a, b, c := created.Options[0].ID, created.Options[1].ID, created.Options[2].ID
+
+	// 多选投票, 重复的选项只计一次
+	res, err := svc.Vote(ctx, pid, 2, []int64{a, c, a})
+	if err != nil {
+		t.Fatalf("Vote error = %v", err)
+	}
+	if res.VoterCount != 1 || !slices.Equal(voteCounts(res), []int{1, 0, 1}) {
+		t.Fatalf("Vote result = %d voters %v, want 1 voters [1 0 1]", res.VoterCount, voteCounts(res))
+	}
+	if !res.Voted || !res.Options[0].Voted || res.Options[1].Voted {
+		t.Fatalf("Vote voted flags = %+v", res)
+	}
+
+	if _, err := svc.Vote(ctx, pid, 3, []int64{b, c}); err != nil {
+		t.Fatalf("Vote error = %v", err)
+	}
+
+	// 每人只能投一次
+	if _, err := svc.Vote(ctx, pid, 2, []int64{b}); !errors.Is(err, errno.ErrPollVoted) {
+		t.Fatalf("Vote again error = %v, want %v", err, errno.ErrPollVoted)
+	}
+
+	// 结果中包含所有人的票数和当前用户自己的投票
+	res, err = svc.Result(ctx, pid, 3)
+	if err != nil {
+		t.Fatalf("Result error = %v", err)
+	}
+	if res.VoterCount != 2 || !slices.Equal(voteCounts(res), []int{1, 1, 2}) {
+		t.Fatalf("Result = %d voters %v, want 2 voters [1 1 2]", res.VoterCount, voteCounts(res))
+	}
+	if res.Options[0].Voted || !res.Options[1].Voted || !res.Options[2].Voted {
+		t.Fatalf("Result voted flags = %+v", res)
+	}
+
+	// 未登录时不返回投票情况
+	res, err = svc.Result(ctx, pid, 0)
+	if err != nil {
+		t.Fatalf("Result error = %v", err)
+	}
+	if res.Voted {
+		t.Fatalf("Result for guest voted = true, want false")
+	}
+}
+
+// go test -v ./service -run=^TestPollTally$ -count=1
+
+func TestPollVoteRejected(t *testing.T) {
+	ctx := context.Background()
+	const author int64 = 1
+	past := time.Now().Add(-time.Hour)
+	svc, followRepo := newTestPollService(
+		&model.Post{ID: 100, UserID: author, Status: model.PostStatusNormal, Visibility: model.PostVisibilityPublic},
+		&model.Post{ID: 200, UserID: author, Status: model.PostStatusNormal, Visibility: model.PostVisibilityFollowers},
+	)
+
+	single, err := svc.Create(ctx, author, 100, []string{"A", "B"}, false, false, nil)
+	if err != nil {
+		t.Fatalf("Create error = %v", err)
+	}
+	// 单选投票不能选多个, 也不能选其他投票的选项
+	if _, err := svc.Vote(ctx, 100, 2, []int64{single.Options[0].ID, single.Options[1].ID}); !errors.Is(err, errno.ErrPollOptionInvalid) {
+		t.Fatalf("Vote multiple error = %v, want %v", err, errno.ErrPollOptionInvalid)
+	}
+	if _, err := svc.Vote(ctx, 100, 2, []int64{-1}); !errors.Is(err, errno.ErrPollOptionInvalid) {
+		t.Fatalf("Vote unknown option error = %v, want %v", err, errno.ErrPollOptionInvalid)
+	}
+
+	// 仅粉丝可见的帖子, 未关注的用户与帖子不存在一致
+	hidden, err := svc.Create(ctx, author, 200, []string{"A", "B"}, false, false, nil)
+	if err != nil {
+		t.Fatalf("Create error = %v", err)
+	}
+	if _, err := svc.Vote(ctx, 200, 2, []int64{hidden.Options[0].ID}); !errors.Is(err, errno.ErrPostNotFound) {
+		t.Fatalf("Vote hidden error = %v, want %v", err, errno.ErrPostNotFound)
+	}
+	followRepo.following[[2]int64{2, author}] = true
+	if _, err := svc.Vote(ctx, 200, 2, []int64{hidden.Options[0].ID}); err != nil {
+		t.Fatalf("Vote as follower error = %v", err)
+	}
+
+	// 截止时间必须晚于当前时间, 选项命中拒绝类敏感词时整体拒绝
+	if err := svc.Validate([]string{"A", "B"}, &past); !errors.Is(err, errno.ErrPollInvalid) {
+		t.Fatalf("Validate past deadline error = %v, want %v", err, errno.ErrPollInvalid)
+	}
+	if err := svc.Validate([]string{"A", "违禁"}, nil); !errors.Is(err, errno.ErrContentRejected) {
+		t.Fatalf("Validate rejected option error = %v, want %v", err, errno.ErrContentRejected)
+	}
+}
+
+// go test -v ./service -run=^TestPollVoteRejected$ -count=1
@@ -0,0 +1,14 @@
+package ports
+
+import "github.com/yzletter/go-postery/model"
+
+// FilterResult 过滤结果, Text 为按 Action 处理后的文本, Categories 为命中的分类
+type FilterResult struct {
+	Action     model.SensitiveAction
+	Text       string
+	Categories []string
+}
+
+type ContentFilter interface {
+	Filter(text string) FilterResult
+}
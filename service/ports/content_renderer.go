@@ -0,0 +1,18 @@
+package ports
+
+import "errors"
+
+// RenderedContent 渲染结果, HTML 已经过白名单清洗, Text 为去掉标签后的纯文本
+type RenderedContent struct {
+	HTML string
+	Text string
+}
+
+type ContentRenderer interface {
+	Render(source string) (RenderedContent, error)
+}
+
+// 定义 ContentRenderer 所需要返回的错误
+var (
+	ErrRenderFailed = errors.New("正文渲染失败")
+)
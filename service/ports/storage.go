@@ -0,0 +1,19 @@
+package ports
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+type ObjectStorage interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	Delete(ctx context.Context, key string) error
+	URL(key string) string
+}
+
+// 定义 ObjectStorage 所需要返回的错误
+var (
+	ErrStoragePutFailed    = errors.New("文件写入失败")
+	ErrStorageDeleteFailed = errors.New("文件删除失败")
+)
@@ -1,10 +1,15 @@
 package service
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"log/slog"
+	"slices"
+	"strconv"
+	"time"
 
+	"github.com/yzletter/go-postery/conf"
 	postdto "github.com/yzletter/go-postery/dto/post"
 	"github.com/yzletter/go-postery/errno"
 	"github.com/yzletter/go-postery/model"
@@ -14,30 +19,74 @@ import (
 )
 
 type postService struct {
-	postRepo repository.PostRepository
-	userRepo repository.UserRepository
-	likeRepo repository.LikeRepository
-	tagRepo  repository.TagRepository
-	idGen    ports.IDGenerator // 用于生成 ID
+	postRepo       repository.PostRepository
+	userRepo       repository.UserRepository
+	likeRepo       repository.LikeRepository
+	tagRepo        repository.TagRepository
+	attachmentRepo repository.AttachmentRepository
+	followRepo     repository.FollowRepository
+	reportRepo     repository.ReportRepository
+	seriesRepo     repository.SeriesRepository
+	pollRepo       repository.PollRepository
+	analyticsRepo  repository.AnalyticsRepository
+	idGen          ports.IDGenerator     // 用于生成 ID
+	renderer       ports.ContentRenderer // 用于渲染正文
+	filter         ports.ContentFilter   // 用于过滤敏感词
+	mentionSvc     MentionService        // 用于解析 @ 提及
 }
 
 func NewPostService(postRepo repository.PostRepository, userRepo repository.UserRepository,
-	likeRepo repository.LikeRepository, tagRepo repository.TagRepository,
-	idGen ports.IDGenerator) PostService {
+	likeRepo repository.LikeRepository, tagRepo repository.TagRepository, attachmentRepo repository.AttachmentRepository,
+	followRepo repository.FollowRepository, reportRepo repository.ReportRepository, seriesRepo repository.SeriesRepository,
+	pollRepo repository.PollRepository, analyticsRepo repository.AnalyticsRepository,
+	idGen ports.IDGenerator, renderer ports.ContentRenderer, filter ports.ContentFilter, mentionSvc MentionService) PostService {
 	return &postService{
-		postRepo: postRepo,
-		userRepo: userRepo,
-		likeRepo: likeRepo,
-		tagRepo:  tagRepo,
-		idGen:    idGen,
+		postRepo:       postRepo,
+		userRepo:       userRepo,
+		likeRepo:       likeRepo,
+		tagRepo:        tagRepo,
+		attachmentRepo: attachmentRepo,
+		followRepo:     followRepo,
+		reportRepo:     reportRepo,
+		seriesRepo:     seriesRepo,
+		pollRepo:       pollRepo,
+		analyticsRepo:  analyticsRepo,
+		idGen:          idGen,
+		renderer:       renderer,
+		filter:         filter,
+		mentionSvc:     mentionSvc,
 	}
 }
 
-// Create 新建一篇帖子
-func (svc *postService) Create(ctx context.Context, uid int64, title, content string) (postdto.DetailDTO, error) {
+// Create 新建一篇帖子, visibility 为 0 时默认所有人可见; 命中审核类敏感词时帖子在审核通过前仅作者可见;
+// quoteID 非 0 时为引用转发, 原帖需所有人可见
+func (svc *postService) Create(ctx context.Context, uid int64, title, content string, visibility model.PostVisibility, quoteID int64) (postdto.DetailDTO, error) {
+	return svc.create(ctx, uid, title, content, visibility, quoteID, time.Time{})
+}
+
+// Import 以原始发布时间导入一篇帖子, 与 Create 一样经过敏感词过滤和渲染; createdAt 为零值时取当前时间
+func (svc *postService) Import(ctx context.Context, uid int64, title, content string, visibility model.PostVisibility, createdAt time.Time) (postdto.DetailDTO, error) {
+	return svc.create(ctx, uid, title, content, visibility, 0, createdAt)
+}
+
+// create 新建帖子, createdAt 为零值时由数据库填入当前时间
+func (svc *postService) create(ctx context.Context, uid int64, title, content string, visibility model.PostVisibility, quoteID int64, createdAt time.Time) (postdto.DetailDTO, error) {
 	var empty postdto.DetailDTO
 
-	// 先查找作者
+	// 引用的原帖需要能被所有人看到, 避免通过引用扩散非公开内容
+	if quoteID != 0 {
+		if _, err := shareable(ctx, svc.postRepo, svc.followRepo, quoteID, uid); err != nil {
+			return empty, err
+		}
+	}
+
+	// 过滤敏感词
+	reviewCategories, err := screen(svc.filter, &title, &content)
+	if err != nil {
+		return empty, err
+	}
+
+	// 查找作者
 	user, err := svc.userRepo.GetByID(ctx, uid)
 	if err != nil {
 		if errors.Is(err, repository.ErrRecordNotFound) {
@@ -46,13 +95,33 @@ func (svc *postService) Create(ctx context.Context, uid int64, title, content st
 		return empty, errno.ErrServerInternal
 	}
 
+	// 渲染正文
+	contentHTML, excerpt, err := renderContent(svc.renderer, content)
+	if err != nil {
+		slog.Error("Render Post Content Failed", "error", err)
+		return empty, errno.ErrServerInternal
+	}
+
 	// 创建帖子
 	post := &model.Post{
-		ID:      svc.idGen.NextID(),
-		UserID:  uid,
-		Title:   title,
-		Content: content,
-		Status:  1,
+		ID:            svc.idGen.NextID(),
+		UserID:        uid,
+		Title:         title,
+		Content:       content,
+		ContentHTML:   contentHTML,
+		Excerpt:       excerpt,
+		Status:        model.PostStatusNormal,
+		Visibility:    visibility,
+		QuoteID:       quoteID,
+		CommentPolicy: model.CommentPolicyOpen,
+		Version:       1,
+		CreatedAt:     createdAt,
+	}
+	if post.Visibility == 0 {
+		post.Visibility = model.PostVisibilityPublic
+	}
+	if len(reviewCategories) > 0 {
+		post.Status = model.PostStatusReviewing
 	}
 	err = svc.postRepo.Create(ctx, post)
 	if err != nil {
@@ -63,11 +132,36 @@ func (svc *postService) Create(ctx context.Context, uid int64, title, content st
 		return empty, errno.ErrServerInternal
 	}
 
-	return postdto.ToDetailDTO(post, user), err
+	// 生成 slug, 失败时由定时任务补全
+	if err := svc.assignSlug(ctx, post); err != nil {
+		slog.Error("Assign Post Slug Failed", "pid", post.ID, "error", err)
+	}
+
+	// 引用转发计入原帖的转发数
+	if quoteID != 0 {
+		if err := svc.postRepo.UpdateCount(ctx, quoteID, model.PostShareCount, 1); err != nil {
+			slog.Error("Update Share Count Failed", "error", err)
+		}
+	}
+
+	if len(reviewCategories) > 0 {
+		if err := submitForReview(ctx, svc.reportRepo, svc.idGen, model.ReportTargetPost, post.ID, reviewCategories); err != nil {
+			slog.Error("Submit Post For Review Failed", "pid", post.ID, "error", err)
+		}
+	}
+
+	// 解析 @ 提及, 失败时不影响发帖; 导入的历史帖子不发送通知
+	postDTO := postdto.ToDetailDTO(post, user)
+	postDTO.Mentions, err = svc.mentionSvc.Sync(ctx, model.MentionTargetPost, post.ID, post.ID, uid, content, mentionNotify(post) && createdAt.IsZero())
+	if err != nil {
+		slog.Error("Sync Post Mentions Failed", "pid", post.ID, "error", err)
+	}
+
+	return postDTO, nil
 }
 
-// GetDetailById 获取帖子详情，并选择是否增加浏览量
-func (svc *postService) GetDetailById(ctx context.Context, id int64, addViewCnt bool) (postdto.DetailDTO, error) {
+// GetDetailById 获取帖子详情, uid 为当前登录用户, 未登录时为 0; 不记录浏览, 需要时另行调用 RecordView
+func (svc *postService) GetDetailById(ctx context.Context, id, uid int64) (postdto.DetailDTO, error) {
 	// 查找帖子详情
 	var empty postdto.DetailDTO
 	post, err := svc.postRepo.GetByID(ctx, id)
@@ -78,6 +172,15 @@ func (svc *postService) GetDetailById(ctx context.Context, id int64, addViewCnt
 		return empty, errno.ErrServerInternal
 	}
 
+	// 无权查看时与帖子不存在的表现一致, 避免泄露帖子是否存在
+	ok, err := canView(ctx, svc.followRepo, post, uid)
+	if err != nil {
+		return empty, errno.ErrServerInternal
+	}
+	if !ok {
+		return empty, errno.ErrPostNotFound
+	}
+
 	// 查找作者信息
 	user, err := svc.userRepo.GetByID(ctx, post.UserID)
 	if err != nil {
@@ -87,45 +190,169 @@ func (svc *postService) GetDetailById(ctx context.Context, id int64, addViewCnt
 		return empty, errno.ErrServerInternal
 	}
 
-	if addViewCnt {
-		// 记录 ViewCount + 1
-		filed := model.PostViewCount
-		err = svc.postRepo.UpdateCount(ctx, post.ID, filed, 1) // 数据库中 + 1
+	fillRendered(ctx, svc.renderer, svc.postRepo, post)
+
+	postDTO := postdto.ToDetailDTO(post, user)
+
+	// 附带投票结果, 查询失败时不影响帖子详情
+	postDTO.Poll, err = pollResult(ctx, svc.pollRepo, post.ID, uid)
+	if err != nil {
+		slog.Error("Get Post Poll Failed", "pid", post.ID, "error", err)
+	}
+
+	// 附带引用的原帖
+	if post.QuoteID != 0 {
+		postDTO.Quote = svc.quote(ctx, post.QuoteID, uid)
+	}
+
+	// 附带 @ 提及的位置, 查询失败时不影响帖子详情
+	spans, err := svc.mentionSvc.Spans(ctx, model.MentionTargetPost, map[int64]string{post.ID: post.Content})
+	if err != nil {
+		slog.Error("Get Post Mentions Failed", "pid", post.ID, "error", err)
+	}
+	postDTO.Mentions = spans[post.ID]
+	return postDTO, nil
+}
+
+// ResolveSlug 根据 slug 查找 uid 能看到的帖子, 返回帖子 ID 和当前 slug; 传入的是标题修改前的旧 slug 时两者不同
+func (svc *postService) ResolveSlug(ctx context.Context, slug string, uid int64) (int64, string, error) {
+	pid, err := svc.postRepo.GetIDBySlug(ctx, slug)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return 0, "", errno.ErrPostNotFound
+		}
+		return 0, "", errno.ErrServerInternal
+	}
+	post, err := svc.postRepo.GetByID(ctx, pid)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return 0, "", errno.ErrPostNotFound
+		}
+		return 0, "", errno.ErrServerInternal
+	}
+
+	// 无权查看时与帖子不存在的表现一致, 避免通过重定向泄露新标题
+	ok, err := canView(ctx, svc.followRepo, post, uid)
+	if err != nil {
+		return 0, "", errno.ErrServerInternal
+	}
+	if !ok {
+		return 0, "", errno.ErrPostNotFound
+	}
+	return post.ID, post.Slug, nil
+}
+
+// BackfillSlugs 为尚未生成 slug 的旧帖子补全 slug, 由定时任务调用
+func (svc *postService) BackfillSlugs(ctx context.Context) {
+	filled := 0
+	var cursor int64
+	for {
+		posts, err := svc.postRepo.ListWithoutSlug(ctx, cursor, conf.PostSlugBackfillBatchSize)
 		if err != nil {
-			if errors.Is(err, repository.ErrRecordNotFound) {
-				slog.Error("Update View Cnt Failed", "error", err)
+			slog.Error("List Posts Without Slug Failed", "error", err)
+			return
+		}
+		if len(posts) == 0 {
+			break
+		}
+		cursor = posts[len(posts)-1].ID
+
+		for _, post := range posts {
+			if err := svc.assignSlug(ctx, post); err != nil {
+				slog.Error("Assign Post Slug Failed", "pid", post.ID, "error", err)
+				return
 			}
+			filled++
 		}
-		post.ViewCount += 1
 	}
+	if filled > 0 {
+		slog.Info("Backfill Post Slug Succeed", "posts", filled)
+	}
+}
 
-	postDTO := postdto.ToDetailDTO(post, user)
-	return postDTO, nil
+// assignSlug 由标题生成 slug 并设为帖子的当前 slug, 与其他帖子冲突时追加数字后缀
+func (svc *postService) assignSlug(ctx context.Context, post *model.Post) error {
+	base := utils.SlugifyTitle(post.Title, conf.PostSlugMaxLength)
+
+	// 帖子 ID 唯一, 以它作后缀一定不会冲突; 标题中没有可用字符时直接使用
+	var candidates []string
+	if base == "" {
+		candidates = append(candidates, "post-"+strconv.FormatInt(post.ID, 10))
+	} else {
+		candidates = append(candidates, base)
+		for i := 2; i <= conf.PostSlugMaxAttempts; i++ {
+			candidates = append(candidates, base+"-"+strconv.Itoa(i))
+		}
+		candidates = append(candidates, base+"-"+strconv.FormatInt(post.ID, 10))
+	}
+
+	for _, slug := range candidates {
+		err := svc.postRepo.AssignSlug(ctx, &model.PostSlug{
+			ID:     svc.idGen.NextID(),
+			PostID: post.ID,
+			Slug:   slug,
+		})
+		if err == nil {
+			post.Slug = slug
+			return nil
+		}
+		if !errors.Is(err, repository.ErrUniqueKey) {
+			return err
+		}
+	}
+	return repository.ErrUniqueKey
 }
 
-// GetBriefById 根据 ID 获取帖子简要信息
-func (svc *postService) GetBriefById(ctx context.Context, id int64) (postdto.BriefDTO, error) {
+// RecordView 记录 viewer 的一次浏览, referer 为浏览来源页面, 并同步更新 postDTO 中的浏览数
+// 原始浏览量每次都 + 1, 去重浏览量只在去重窗口内首次浏览时增加; 每次浏览及其来源都计入作者的数据统计
+func (svc *postService) RecordView(ctx context.Context, postDTO *postdto.DetailDTO, viewer, referer string) {
+	if err := svc.postRepo.UpdateCount(ctx, postDTO.ID, model.PostViewCount, 1); err != nil {
+		slog.Error("Update View Cnt Failed", "error", err)
+	}
+	postDTO.ViewCount += 1
+
+	recordPostEvent(ctx, svc.analyticsRepo, postDTO.ID, model.AnalyticsView)
+	if err := svc.analyticsRepo.RecordReferrer(ctx, postDTO.ID, referrerHost(referer), time.Now()); err != nil {
+		slog.Error("Record Analytics Referrer Failed", "pid", postDTO.ID, "error", err)
+	}
+
+	first, err := svc.postRepo.MarkViewed(ctx, postDTO.ID, viewer)
+	if err != nil || !first {
+		return
+	}
+
+	if err := svc.postRepo.UpdateCount(ctx, postDTO.ID, model.PostUniqueViewCount, 1); err != nil {
+		slog.Error("Update Unique View Cnt Failed", "error", err)
+	}
+	postDTO.UniqueViewCount += 1
+}
+
+// GetBriefById 根据 ID 获取帖子简要信息, uid 为当前登录用户
+func (svc *postService) GetBriefById(ctx context.Context, id, uid int64) (postdto.BriefDTO, error) {
 	var empty postdto.BriefDTO
 
 	// 获取帖子详情
-	postDetailDTO, err := svc.GetDetailById(ctx, id, false) // 选择不加浏览量
+	postDetailDTO, err := svc.GetDetailById(ctx, id, uid) // 不加浏览量
 	if err != nil {
 		// 这里的错误是 errno 错误, 直接返回即可
 		return empty, err
 	}
 
 	return postdto.BriefDTO{
-		ID:        postDetailDTO.ID,
-		Title:     postDetailDTO.Title,
-		CreatedAt: postDetailDTO.CreatedAt,
-		Author:    postDetailDTO.Author,
+		ID:         postDetailDTO.ID,
+		Title:      postDetailDTO.Title,
+		Slug:       postDetailDTO.Slug,
+		Excerpt:    postDetailDTO.Excerpt,
+		Visibility: postDetailDTO.Visibility,
+		CreatedAt:  postDetailDTO.CreatedAt,
+		Author:     postDetailDTO.Author,
 	}, nil
 }
 
 // Belong 判断登录用户是否是帖子作者
 func (svc *postService) Belong(ctx context.Context, pid, uid int64) bool {
 	// todo 优化只查 user_id 字段
-	postBriefDTO, err := svc.GetBriefById(ctx, pid)
+	postBriefDTO, err := svc.GetBriefById(ctx, pid, uid)
 	if err != nil || uid != postBriefDTO.Author.ID {
 		return false
 	}
@@ -140,30 +367,114 @@ func (svc *postService) Delete(ctx context.Context, pid, uid int64) error {
 		return errno.ErrUnauthorized
 	}
 
+	post, err := svc.postRepo.GetByID(ctx, pid)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return nil
+		}
+		return errno.ErrServerInternal
+	}
+
 	// 删除帖子
-	err := svc.postRepo.Delete(ctx, pid)
+	err = svc.postRepo.Delete(ctx, pid)
 	if err != nil {
 		// 如果是记录不存在, 则幂等
 		if !errors.Is(err, repository.ErrRecordNotFound) {
 			return errno.ErrServerInternal
 		}
+		return nil
+	}
+
+	// 引用转发被删除, 原帖转发数减一
+	if post.QuoteID != 0 {
+		if err := svc.postRepo.UpdateCount(ctx, post.QuoteID, model.PostShareCount, -1); err != nil {
+			slog.Error("Update Share Count Failed", "error", err)
+		}
+	}
+
+	// 附件标记为待回收, 由定时任务删除文件
+	if err := svc.attachmentRepo.DiscardByPostID(ctx, pid); err != nil {
+		slog.Error("Discard Post Attachments Failed", "pid", pid, "error", err)
+	}
+
+	// 移出所属系列, 其后的帖子依次前移
+	if seriesPost, err := svc.seriesRepo.GetByPostID(ctx, pid); err == nil {
+		if err := svc.seriesRepo.RemovePost(ctx, seriesPost.SeriesID, pid); err != nil {
+			slog.Error("Remove Post From Series Failed", "pid", pid, "sid", seriesPost.SeriesID, "error", err)
+		}
 	}
 	return nil
 }
 
-// Update 更新帖子
-func (svc *postService) Update(ctx context.Context, pid int64, uid int64, title, content string, tags []string) error {
+// Update 基于 version 版本更新帖子, visibility 为 0 时不修改可见范围; 返回更新后的版本号, 版本不一致时返回当前版本号和 ErrPostVersionConflict
+func (svc *postService) Update(ctx context.Context, pid int64, uid int64, version int, title, content string, tags []string, visibility model.PostVisibility) (int, error) {
 	// 判断登录用户是否是作者
 	ok := svc.Belong(ctx, pid, uid)
 	if !ok {
 		// 无权限更新
-		return errno.ErrUnauthorized
+		return 0, errno.ErrUnauthorized
+	}
+
+	// 先过滤敏感词, 被拒绝时不做任何修改
+	reviewCategories, err := screen(svc.filter, &title, &content)
+	if err != nil {
+		return 0, err
+	}
+
+	// 正文变化后重新渲染, 旧的 HTML 和摘要随之失效
+	contentHTML, excerpt, err := renderContent(svc.renderer, content)
+	if err != nil {
+		slog.Error("Render Post Content Failed", "error", err)
+		return 0, errno.ErrServerInternal
+	}
+
+	updates := map[string]any{
+		"title":        title,
+		"content":      content,
+		"content_html": contentHTML,
+		"excerpt":      excerpt,
+		"edited_at":    time.Now(),
+	}
+	if visibility != 0 {
+		updates["visibility"] = visibility
+	}
+	if len(reviewCategories) > 0 {
+		// 能走到这里的帖子只会是正常或待审核状态
+		updates["status"] = model.PostStatusReviewing
+	}
+
+	// 记下修改前的标题和 slug, 用于判断是否需要重新分配 slug
+	before, err := svc.postRepo.GetByID(ctx, pid)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return 0, errno.ErrPostNotFound
+		}
+		return 0, errno.ErrServerInternal
+	}
+
+	// 先按版本号更新标题和正文, 版本不一致说明帖子已被其他请求修改, 此时标签也保持不变
+	current, err := svc.postRepo.UpdateWithVersion(ctx, pid, version, updates)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return 0, errno.ErrPostNotFound
+		}
+		if errors.Is(err, repository.ErrResourceConflict) {
+			return current, errno.ErrPostVersionConflict
+		}
+		return 0, errno.ErrServerInternal
+	}
+
+	// 标题变化后 slug 随之变化, 旧 slug 保留用于重定向; 标题未变或生成的 slug 与当前一致时无需重新分配
+	if title != before.Title && utils.SlugifyTitle(title, conf.PostSlugMaxLength) != before.Slug {
+		if err := svc.assignSlug(ctx, &model.Post{ID: pid, Title: title}); err != nil {
+			slog.Error("Assign Post Slug Failed", "pid", pid, "error", err)
+		}
 	}
 
 	tagsBefore, err := svc.tagRepo.FindTagsByPostID(ctx, pid)
 	if err != nil {
 		slog.Error("Get Tags_Before Failed", "error", err)
-		return errno.ErrServerInternal
+		return 0, errno.ErrServerInternal
 	}
 
 	tagsNow := tags
@@ -223,49 +534,59 @@ func (svc *postService) Update(ctx context.Context, pid int64, uid int64, title,
 		}
 	}
 
-	updates := map[string]any{
-		"title":   title,
-		"content": content,
+	if len(reviewCategories) > 0 {
+		if err := svc.postRepo.RemoveHot(ctx, pid); err != nil {
+			slog.Error("Remove Hot Post Failed", "pid", pid, "error", err)
+		}
+		if err := submitForReview(ctx, svc.reportRepo, svc.idGen, model.ReportTargetPost, pid, reviewCategories); err != nil {
+			slog.Error("Submit Post For Review Failed", "pid", pid, "error", err)
+		}
 	}
 
-	err = svc.postRepo.Update(ctx, pid, updates) // 更新标题和正文
-	if err != nil {
-		if errors.Is(err, repository.ErrRecordNotFound) {
-			return errno.ErrPostNotFound
-		}
-		return errno.ErrServerInternal
+	// 重新解析 @ 提及, 只通知新增的用户
+	if post, err := svc.postRepo.GetByID(ctx, pid); err != nil {
+		slog.Error("Get Post Failed", "pid", pid, "error", err)
+	} else if _, err := svc.mentionSvc.Sync(ctx, model.MentionTargetPost, pid, pid, uid, content, mentionNotify(post)); err != nil {
+		slog.Error("Sync Post Mentions Failed", "pid", pid, "error", err)
 	}
-	return nil
+	return current, nil
 }
 
-// ListByPage 按页获取帖子列表
+// ListByPage 按页获取帖子列表, 只包含所有人可见的帖子; 全站置顶的帖子排在最前面, 与其他帖子一起分页
 func (svc *postService) ListByPage(ctx context.Context, pageNo, pageSize int) (int, []postdto.DetailDTO, error) {
 	var empty []postdto.DetailDTO
+	if pageNo < 1 || pageSize <= 0 {
+		return 0, empty, errno.ErrInvalidParam
+	}
+
+	// 先取置顶帖子, 其余帖子跳过它们, 避免重复出现
+	pinned, pinnedIDs := svc.pinnedPosts(ctx, 0)
+	head, offset, limit := pageWithPinned(pinned, pageNo, pageSize)
+
 	// 获取帖子总数和当前页帖子列表
-	total, posts, err := svc.postRepo.GetByPage(ctx, pageNo, pageSize)
+	total, posts, err := svc.postRepo.GetByPage(ctx, model.PostVisibilityPublic, pinnedIDs, offset, limit)
 	if err != nil {
 		return 0, empty, errno.ErrPostNotFound
 	}
+	posts = append(head, posts...)
 
-	// todo 避免性能问题，优化 SQL
-	var postDTOs []postdto.DetailDTO
-	for _, post := range posts {
-		// 根据 uid 找到 username 进行赋值
-		author, err := svc.userRepo.GetByID(ctx, post.UserID)
-		if err != nil {
-			slog.Warn("could not get name of user", "uid", post.UserID)
-			author = &model.User{}
-		}
-		postDTO := postdto.ToDetailDTO(post, author)
-		postDTOs = append(postDTOs, postDTO)
+	fillRendered(ctx, svc.renderer, svc.postRepo, posts...)
+	postDTOs := toDetailDTOs(ctx, svc.userRepo, svc.tagRepo, posts)
+	for i := range head {
+		postDTOs[i].Pinned = true
 	}
-	return int(total), postDTOs, nil
+	return int(total) + len(pinned), postDTOs, nil
 }
 
-// ListByPageAndUid 根据作者 ID 获取帖子简要信息列表
-func (svc *postService) ListByPageAndUid(ctx context.Context, uid int64, pageNo, pageSize int) (int, []postdto.BriefDTO, error) {
+// ListByPageAndUid 根据作者 ID 获取帖子简要信息列表, viewerUid 为当前登录用户, 决定能看到的帖子范围
+func (svc *postService) ListByPageAndUid(ctx context.Context, uid, viewerUid int64, pageNo, pageSize int) (int, []postdto.BriefDTO, error) {
 	var empty []postdto.BriefDTO
-	total, posts, err := svc.postRepo.GetByUid(ctx, uid, pageNo, pageSize)
+	maxVisibility, err := maxVisibility(ctx, svc.followRepo, uid, viewerUid)
+	if err != nil {
+		return 0, empty, errno.ErrServerInternal
+	}
+
+	total, posts, err := svc.postRepo.GetByUid(ctx, uid, maxVisibility, pageNo, pageSize)
 	if err != nil {
 		return 0, empty, errno.ErrPostNotFound
 	}
@@ -276,6 +597,8 @@ func (svc *postService) ListByPageAndUid(ctx context.Context, uid int64, pageNo,
 		return 0, empty, errno.ErrPostNotFound
 	}
 
+	fillRendered(ctx, svc.renderer, svc.postRepo, posts...)
+
 	// 转化 Post
 	postDTOs := make([]postdto.BriefDTO, 0, len(posts))
 	for _, post := range posts {
@@ -287,48 +610,394 @@ func (svc *postService) ListByPageAndUid(ctx context.Context, uid int64, pageNo,
 	return int(total), postDTOs, nil
 }
 
-// ListByPageAndTag 根据 Tag 分页查找帖子
+// ListByPageAndTag 根据 Tag 分页查找帖子, 只包含所有人可见的帖子; 置顶到该标签的帖子排在最前面, 与其他帖子一起分页
 func (svc *postService) ListByPageAndTag(ctx context.Context, name string, pageNo, pageSize int) (int, []postdto.DetailDTO, error) {
 	var empty []postdto.DetailDTO
+	if pageNo < 1 || pageSize <= 0 {
+		return 0, empty, errno.ErrInvalidParam
+	}
 
 	tag, err := svc.tagRepo.GetByName(ctx, name)
 	if err != nil {
 		return 0, empty, errno.ErrPostNotFound
 	}
 
-	// todo 避免性能问题，优化 SQL
+	// 先取置顶帖子, 其余帖子跳过它们, 避免重复出现
+	pinned, pinnedIDs := svc.pinnedPosts(ctx, tag.ID)
+	head, offset, limit := pageWithPinned(pinned, pageNo, pageSize)
+
 	// 获取帖子总数和当前页帖子列表
-	total, posts, err := svc.postRepo.GetByPageAndTag(ctx, tag.ID, pageNo, pageSize)
+	total, posts, err := svc.postRepo.GetByPageAndTag(ctx, tag.ID, model.PostVisibilityPublic, pinnedIDs, offset, limit)
 	if err != nil {
 		return 0, empty, errno.ErrPostNotFound
 	}
+	posts = append(head, posts...)
 
-	// 转化
-	var postDTOs []postdto.DetailDTO
+	fillRendered(ctx, svc.renderer, svc.postRepo, posts...)
+	postDTOs := toDetailDTOs(ctx, svc.userRepo, svc.tagRepo, posts)
+	for i := range head {
+		postDTOs[i].Pinned = true
+	}
+	return int(total) + len(pinned), postDTOs, nil
+}
+
+// ListFeatured 按页获取精华帖子, 按加精时间倒序排序
+func (svc *postService) ListFeatured(ctx context.Context, pageNo, pageSize int) (int, []postdto.DetailDTO, error) {
+	var empty []postdto.DetailDTO
+	total, posts, err := svc.postRepo.GetFeatured(ctx, pageNo, pageSize)
+	if err != nil {
+		return 0, empty, errno.ErrPostNotFound
+	}
+
+	fillRendered(ctx, svc.renderer, svc.postRepo, posts...)
+	return int(total), toDetailDTOs(ctx, svc.userRepo, svc.tagRepo, posts), nil
+}
+
+// Pin 将帖子置顶到全站或某个标签下, tag 为空表示全站, expiresAt 为空表示永久置顶; 仅管理员和版主可用
+func (svc *postService) Pin(ctx context.Context, uid, pid int64, tag string, expiresAt *time.Time) error {
+	if err := checkModerator(ctx, svc.userRepo, uid); err != nil {
+		return err
+	}
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return errno.ErrInvalidParam
+	}
+
+	if err := svc.checkPublic(ctx, pid); err != nil {
+		return err
+	}
+	tid, err := svc.pinScope(ctx, tag)
+	if err != nil {
+		return err
+	}
+	if tid != 0 {
+		// 只能置顶到帖子自己的标签下
+		tags, err := svc.tagRepo.FindTagsByPostID(ctx, pid)
+		if err != nil {
+			return errno.ErrServerInternal
+		}
+		if !slices.Contains(tags, tag) {
+			return errno.ErrInvalidParam
+		}
+	}
+
+	pin := &model.PostPin{
+		ID:        svc.idGen.NextID(),
+		PostID:    pid,
+		TagID:     tid,
+		PinnedBy:  uid,
+		ExpiresAt: expiresAt,
+	}
+	if err := svc.postRepo.Pin(ctx, pin); err != nil {
+		return errno.ErrServerInternal
+	}
+	return nil
+}
+
+// Unpin 取消帖子在全站或某个标签下的置顶; 仅管理员和版主可用
+func (svc *postService) Unpin(ctx context.Context, uid, pid int64, tag string) error {
+	if err := checkModerator(ctx, svc.userRepo, uid); err != nil {
+		return err
+	}
+
+	tid, err := svc.pinScope(ctx, tag)
+	if err != nil {
+		return err
+	}
+	err = svc.postRepo.Unpin(ctx, pid, tid)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return errno.ErrPinNotFound
+		}
+		return errno.ErrServerInternal
+	}
+	return nil
+}
+
+// Feature 将帖子加精或取消加精, 重复加精会刷新加精时间; 仅管理员和版主可用
+func (svc *postService) Feature(ctx context.Context, uid, pid int64, featured bool) error {
+	if err := checkModerator(ctx, svc.userRepo, uid); err != nil {
+		return err
+	}
+
+	var featuredAt *time.Time
+	if featured {
+		if err := svc.checkPublic(ctx, pid); err != nil {
+			return err
+		}
+		now := time.Now()
+		featuredAt = &now
+	}
+
+	err := svc.postRepo.Update(ctx, pid, map[string]any{"featured_at": featuredAt})
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return errno.ErrPostNotFound
+		}
+		return errno.ErrServerInternal
+	}
+	return nil
+}
+
+// checkPublic 检查帖子是否为所有人可见的正常帖子, 只有这样的帖子才能置顶或加精
+func (svc *postService) checkPublic(ctx context.Context, pid int64) error {
+	post, err := svc.postRepo.GetByID(ctx, pid)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return errno.ErrPostNotFound
+		}
+		return errno.ErrServerInternal
+	}
+	if post.Status != model.PostStatusNormal || post.Visibility != model.PostVisibilityPublic {
+		return errno.ErrPostNotPublic
+	}
+	return nil
+}
+
+// quote 获取引用的原帖, 原帖被删除、下架或 uid 无权查看时只返回 ID, 不影响引用帖本身的展示
+func (svc *postService) quote(ctx context.Context, qid, uid int64) *postdto.QuoteDTO {
+	res := &postdto.QuoteDTO{ID: qid}
+
+	post, err := svc.postRepo.GetByID(ctx, qid)
+	if err != nil {
+		if !errors.Is(err, repository.ErrRecordNotFound) {
+			slog.Error("Get Quoted Post Failed", "pid", qid, "error", err)
+		}
+		return res
+	}
+	ok, err := canView(ctx, svc.followRepo, post, uid)
+	if err != nil || !ok {
+		return res
+	}
+	author, err := svc.userRepo.GetByID(ctx, post.UserID)
+	if err != nil {
+		return res
+	}
+
+	fillRendered(ctx, svc.renderer, svc.postRepo, post)
+	brief := postdto.ToBriefDTO(post, author)
+	res.Available = true
+	res.Post = &brief
+	return res
+}
+
+// shareable 检查 uid 能否转发或引用帖子, 只有所有人可见的正常帖子才能被转发
+func shareable(ctx context.Context, postRepo repository.PostRepository, followRepo repository.FollowRepository, pid, uid int64) (*model.Post, error) {
+	post, err := postRepo.GetByID(ctx, pid)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return nil, errno.ErrPostNotFound
+		}
+		return nil, errno.ErrServerInternal
+	}
+
+	// 无权查看时与帖子不存在的表现一致
+	ok, err := canView(ctx, followRepo, post, uid)
+	if err != nil {
+		return nil, errno.ErrServerInternal
+	}
+	if !ok {
+		return nil, errno.ErrPostNotFound
+	}
+	if post.Status != model.PostStatusNormal || post.Visibility != model.PostVisibilityPublic {
+		return nil, errno.ErrPostNotShareable
+	}
+	return post, nil
+}
+
+// pinScope 将标签名转为置顶范围, 空标签名表示全站, 返回 0
+func (svc *postService) pinScope(ctx context.Context, tag string) (int64, error) {
+	if tag == "" {
+		return 0, nil
+	}
+	t, err := svc.tagRepo.GetByName(ctx, tag)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return 0, errno.ErrTagNotFound
+		}
+		return 0, errno.ErrServerInternal
+	}
+	return t.ID, nil
+}
+
+// pinnedPosts 获取 tid 范围内当前有效且所有人可见的置顶帖子, 同时返回所有有效置顶的 ID 用于在其余帖子中排除;
+// 获取失败时不影响列表, 按没有置顶处理
+func (svc *postService) pinnedPosts(ctx context.Context, tid int64) ([]*model.Post, []int64) {
+	ids, err := svc.postRepo.GetPinnedIDs(ctx, tid, conf.PostPinMax)
+	if err != nil || len(ids) == 0 {
+		return nil, nil
+	}
+	posts, err := svc.postRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		slog.Error("Get Pinned Posts Failed", "tag_id", tid, "error", err)
+		return nil, nil
+	}
+
+	pinned := make([]*model.Post, 0, len(posts))
+	for _, post := range posts {
+		if post.Status == model.PostStatusNormal && post.Visibility == model.PostVisibilityPublic {
+			pinned = append(pinned, post)
+		}
+	}
+	return pinned, ids
+}
+
+// pageWithPinned 将置顶帖子与其余帖子视为一个列表分页, 返回当前页中的置顶帖子, 以及其余帖子需要查询的 offset 和 limit
+func pageWithPinned(pinned []*model.Post, pageNo, pageSize int) ([]*model.Post, int, int) {
+	start := (pageNo - 1) * pageSize
+	if start >= len(pinned) {
+		return nil, start - len(pinned), pageSize
+	}
+	end := min(start+pageSize, len(pinned))
+	head := pinned[start:end:end] // 限制容量, 调用方在其后追加时不会覆盖 pinned
+	return head, 0, pageSize - len(head)
+}
+
+// canView 判断 uid 能否查看帖子, uid 为 0 表示未登录
+func canView(ctx context.Context, followRepo repository.FollowRepository, post *model.Post, uid int64) (bool, error) {
+	// 被下架的帖子任何人都不可见, 待审核的帖子仅作者可见
+	if post.Status == model.PostStatusReviewing {
+		return post.UserID == uid, nil
+	}
+	if post.Status != model.PostStatusNormal {
+		return false, nil
+	}
+	if post.Visibility <= model.PostVisibilityPublic || post.UserID == uid {
+		return true, nil
+	}
+	if post.Visibility == model.PostVisibilityPrivate || uid == 0 {
+		return false, nil
+	}
+
+	// 仅粉丝可见, 查看者需关注了作者
+	followType, err := followRepo.Exists(ctx, uid, post.UserID)
+	if err != nil {
+		return false, err
+	}
+	return followType == model.FollowIFollow || followType == model.FollowMutual, nil
+}
+
+// mentionNotify 只有所有人可见且不在审核中的帖子才发送 @ 通知, 避免被提及的用户看不到帖子
+func mentionNotify(post *model.Post) bool {
+	return post.Status == model.PostStatusNormal && post.Visibility == model.PostVisibilityPublic
+}
+
+// maxVisibility 计算 uid 能看到的 author 帖子的最大可见范围
+func maxVisibility(ctx context.Context, followRepo repository.FollowRepository, author, uid int64) (model.PostVisibility, error) {
+	if uid == author {
+		return model.PostVisibilityPrivate, nil
+	}
+	if uid == 0 {
+		return model.PostVisibilityPublic, nil
+	}
+
+	followType, err := followRepo.Exists(ctx, uid, author)
+	if err != nil {
+		return 0, err
+	}
+	if followType == model.FollowIFollow || followType == model.FollowMutual {
+		return model.PostVisibilityFollowers, nil
+	}
+	return model.PostVisibilityPublic, nil
+}
+
+// filterVisible 过滤掉 uid 无权查看的帖子
+func filterVisible(ctx context.Context, followRepo repository.FollowRepository, posts []*model.Post, uid int64) ([]*model.Post, error) {
+	visible := make([]*model.Post, 0, len(posts))
 	for _, post := range posts {
-		// 根据 uid 找到 username 进行赋值
-		author, err := svc.userRepo.GetByID(ctx, post.UserID)
+		ok, err := canView(ctx, followRepo, post, uid)
 		if err != nil {
+			return nil, err
+		}
+		if ok {
+			visible = append(visible, post)
+		}
+	}
+	return visible, nil
+}
+
+// renderContent 将 Markdown 正文渲染为清洗后的 HTML, 并提取纯文本摘要
+func renderContent(renderer ports.ContentRenderer, content string) (string, string, error) {
+	rendered, err := renderer.Render(content)
+	if err != nil {
+		return "", "", err
+	}
+	return rendered.HTML, utils.Excerpt(rendered.Text, conf.PostExcerptLength), nil
+}
+
+// fillRendered 为还没有渲染结果的旧帖子补齐 HTML 和摘要, 并回写数据库
+func fillRendered(ctx context.Context, renderer ports.ContentRenderer, postRepo repository.PostRepository, posts ...*model.Post) {
+	for _, post := range posts {
+		if post == nil || post.ContentHTML != "" || post.Content == "" {
+			continue
+		}
+
+		contentHTML, excerpt, err := renderContent(renderer, post.Content)
+		if err != nil {
+			slog.Error("Render Post Content Failed", "pid", post.ID, "error", err)
+			continue
+		}
+		post.ContentHTML, post.Excerpt = contentHTML, excerpt
+
+		updates := map[string]any{
+			"content_html": contentHTML,
+			"excerpt":      excerpt,
+		}
+		if err := postRepo.Update(ctx, post.ID, updates); err != nil {
+			slog.Error("Backfill Rendered Content Failed", "pid", post.ID, "error", err)
+		}
+	}
+}
+
+// toDetailDTOs 批量查出作者和 Tags 后转化为 DTO, 一页帖子只需固定次数的查询
+func toDetailDTOs(ctx context.Context, userRepo repository.UserRepository, tagRepo repository.TagRepository,
+	posts []*model.Post) []postdto.DetailDTO {
+	pids := make([]int64, 0, len(posts))
+	uids := make([]int64, 0, len(posts))
+	for _, post := range posts {
+		pids = append(pids, post.ID)
+		uids = append(uids, post.UserID)
+	}
+
+	authors, err := userRepo.GetByIDs(ctx, uids)
+	if err != nil {
+		slog.Warn("could not get authors of posts", "uids", uids, "error", err)
+	}
+	tags, err := tagRepo.FindTagsByPostIDs(ctx, pids)
+	if err != nil {
+		slog.Warn("could not get tags of posts", "pids", pids, "error", err)
+	}
+
+	var postDTOs []postdto.DetailDTO
+	for _, post := range posts {
+		author, ok := authors[post.UserID]
+		if !ok {
 			slog.Warn("could not get name of user", "uid", post.UserID)
 			author = &model.User{}
 		}
-
 		postDTO := postdto.ToDetailDTO(post, author)
+		postDTO.Tags = tags[post.ID]
 		postDTOs = append(postDTOs, postDTO)
 	}
-	return int(total), postDTOs, nil
+	return postDTOs
 }
 
 // Like 点赞帖子
 func (svc *postService) Like(ctx context.Context, pid, uid int64) error {
 	// 查找帖子
-	_, err := svc.postRepo.GetByID(ctx, pid)
+	post, err := svc.postRepo.GetByID(ctx, pid)
 	if err != nil {
 		if errors.Is(err, repository.ErrRecordNotFound) {
 			return errno.ErrPostNotFound
 		}
 		return errno.ErrServerInternal
 	}
+	ok, err := canView(ctx, svc.followRepo, post, uid)
+	if err != nil {
+		return errno.ErrServerInternal
+	}
+	if !ok {
+		return errno.ErrPostNotFound
+	}
 
 	// 创建点赞记录
 	like := &model.Like{
@@ -347,13 +1016,11 @@ func (svc *postService) Like(ctx context.Context, pid, uid int64) error {
 		return errno.ErrServerInternal
 	}
 
-	// 修改分数
-	svc.postRepo.ChangeScore(ctx, pid, 432)
-
 	field := model.PostLikeCount
 	if err := svc.postRepo.UpdateCount(ctx, pid, field, 1); err != nil {
 		slog.Error("Update Like Count Failed", "error", err)
 	}
+	recordPostEvent(ctx, svc.analyticsRepo, pid, model.AnalyticsLike)
 
 	return nil
 }
@@ -380,9 +1047,6 @@ func (svc *postService) Unlike(ctx context.Context, pid, uid int64) error {
 		return errno.ErrServerInternal
 	}
 
-	// 修改分数
-	svc.postRepo.ChangeScore(ctx, pid, -432)
-
 	field := model.PostLikeCount
 	if err := svc.postRepo.UpdateCount(ctx, pid, field, -1); err != nil {
 		slog.Error("Update Like Count Failed", "error", err)
@@ -400,19 +1064,352 @@ func (svc *postService) IfLike(ctx context.Context, pid, uid int64) (bool, error
 	return ok, nil
 }
 
-func (svc *postService) Top(ctx context.Context) ([]postdto.TopDTO, error) {
+// Top 获取热榜, tag 为空时返回全站榜
+func (svc *postService) Top(ctx context.Context, window string, tag string) ([]postdto.TopDTO, error) {
 	var empty []postdto.TopDTO
 
-	posts, scores, err := svc.postRepo.Top(ctx)
+	hotWindow, err := model.ParseHotWindow(window)
+	if err != nil {
+		return empty, errno.ErrInvalidParam
+	}
+
+	var tid int64
+	if tag != "" {
+		t, err := svc.tagRepo.GetByName(ctx, tag)
+		if err != nil {
+			if errors.Is(err, repository.ErrRecordNotFound) {
+				return empty, nil
+			}
+			return empty, errno.ErrServerInternal
+		}
+		tid = t.ID
+	}
+
+	posts, scores, err := svc.postRepo.Top(ctx, model.HotBoardKey(hotWindow, tid))
 	if err != nil {
 		return empty, errno.ErrServerInternal
 	}
 
 	var postDTOs []postdto.TopDTO
 	for k, post := range posts {
+		// 榜单只收录所有人可见的正常帖子, 修改了可见范围的帖子在下一轮计算前直接跳过
+		if post.Status != model.PostStatusNormal || post.Visibility != model.PostVisibilityPublic {
+			continue
+		}
 		postDTO := postdto.ToTopDTO(post, scores[k])
 		postDTOs = append(postDTOs, postDTO)
 	}
 
 	return postDTOs, nil
 }
+
+// RefreshHot 重新计算 24h / 7d 热榜, 只扫描最长窗口内发布的帖子, 由定时任务调用
+func (svc *postService) RefreshHot(ctx context.Context) {
+	now := time.Now()
+	windows := []model.HotWindow{model.HotWindowDay, model.HotWindowWeek}
+	var longest time.Duration
+	for _, window := range windows {
+		longest = max(longest, window.Duration())
+	}
+	svc.refreshHot(ctx, windows, now.Add(-longest), now)
+}
+
+// RefreshHotAll 重新计算全时段热榜, 需要扫描全部帖子, 由频率更低的定时任务调用
+func (svc *postService) RefreshHotAll(ctx context.Context) {
+	svc.refreshHot(ctx, []model.HotWindow{model.HotWindowAll}, time.Time{}, time.Now())
+}
+
+// refreshHot 扫描 since 之后发布的帖子, 重新计算 windows 下的全站榜和标签榜
+func (svc *postService) refreshHot(ctx context.Context, windows []model.HotWindow, since, now time.Time) {
+	staged := make(map[model.HotWindow]map[string]struct{}, len(windows))
+	for _, window := range windows {
+		staged[window] = make(map[string]struct{})
+	}
+
+	var cursor int64
+	for {
+		posts, err := svc.postRepo.ListForRank(ctx, since, cursor, conf.PostHotBatchSize)
+		if err != nil {
+			slog.Error("List Posts For Rank Failed", "error", err)
+			return
+		}
+		if len(posts) == 0 {
+			break
+		}
+		cursor = posts[len(posts)-1].ID
+
+		ids := make([]int64, 0, len(posts))
+		for _, post := range posts {
+			ids = append(ids, post.ID)
+		}
+		tagIDs, err := svc.tagRepo.GetTagIDsByPostIDs(ctx, ids)
+		if err != nil {
+			slog.Error("Get Tag IDs Failed", "error", err)
+			return
+		}
+
+		// 每个帖子计入各时间窗口的全站榜和其标签榜
+		boards := make(map[model.HotWindow]map[string]map[int64]float64, len(windows))
+		add := func(window model.HotWindow, key string, pid int64, score float64) {
+			if boards[window] == nil {
+				boards[window] = make(map[string]map[int64]float64)
+			}
+			if boards[window][key] == nil {
+				boards[window][key] = make(map[int64]float64)
+			}
+			boards[window][key][pid] = score
+		}
+		for _, post := range posts {
+			if post.Visibility != model.PostVisibilityPublic {
+				continue
+			}
+			for _, window := range windows {
+				score, ok := hotScore(post, window, now)
+				if !ok {
+					continue
+				}
+				add(window, model.HotBoardKey(window, 0), post.ID, score)
+				for _, tid := range tagIDs[post.ID] {
+					add(window, model.HotBoardKey(window, tid), post.ID, score)
+				}
+			}
+		}
+
+		for window, keys := range boards {
+			for key, scores := range keys {
+				_, ok := staged[window][key]
+				if err := svc.postRepo.StageHot(ctx, key, scores, !ok); err != nil {
+					return
+				}
+				staged[window][key] = struct{}{}
+			}
+		}
+	}
+
+	cnt := 0
+	for _, window := range windows {
+		keys := make([]string, 0, len(staged[window]))
+		for key := range staged[window] {
+			keys = append(keys, key)
+		}
+		if err := svc.postRepo.PublishHot(ctx, window, keys); err != nil {
+			return
+		}
+		cnt += len(keys)
+	}
+	slog.Info("Refresh Hot Succeed", "windows", windows, "boards", cnt)
+}
+
+// hotScore 计算帖子在指定时间窗口内的热度, 帖子不在窗口内时返回 false
+func hotScore(post *model.Post, window model.HotWindow, now time.Time) (float64, bool) {
+	age := now.Sub(post.CreatedAt)
+	if d := window.Duration(); d > 0 && age > d {
+		return 0, false
+	}
+
+	points := float64(post.UniqueViewCount)*conf.PostHotViewWeight +
+		float64(post.LikeCount)*conf.PostHotLikeWeight +
+		float64(post.CommentCount)*conf.PostHotCommentWeight +
+		float64(post.ShareCount)*conf.PostHotShareWeight
+	if window == model.HotWindowAll {
+		return points, true
+	}
+	return utils.GravityScore(points, age.Hours(), conf.PostHotGravity, conf.PostHotAgeOffset), true
+}
+
+// ListRelated 获取与帖子相关的推荐, 只包含所有人可见的帖子; 尚未预计算过的新帖子和预计算窗口之外的旧帖子实时按共同标签推荐
+func (svc *postService) ListRelated(ctx context.Context, pid, uid int64) ([]postdto.BriefDTO, error) {
+	var empty []postdto.BriefDTO
+	post, err := svc.postRepo.GetByID(ctx, pid)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return empty, errno.ErrPostNotFound
+		}
+		return empty, errno.ErrServerInternal
+	}
+	ok, err := canView(ctx, svc.followRepo, post, uid)
+	if err != nil {
+		return empty, errno.ErrServerInternal
+	}
+	if !ok {
+		return empty, errno.ErrPostNotFound
+	}
+
+	ids, err := svc.postRepo.GetRelatedIDs(ctx, pid)
+	if err != nil || len(ids) == 0 {
+		shared, err := svc.tagRepo.CountSharedTags(ctx, pid)
+		if err != nil {
+			return empty, errno.ErrServerInternal
+		}
+		scores := make(map[int64]float64, len(shared))
+		for id, cnt := range shared {
+			scores[id] = float64(cnt)
+		}
+		ids = rankByScore(scores)
+	}
+
+	found, err := svc.postRepo.GetByIDs(ctx, ids)
+	if err != nil {
+		return empty, errno.ErrServerInternal
+	}
+	byID := make(map[int64]*model.Post, len(found))
+	for _, p := range found {
+		byID[p.ID] = p
+	}
+
+	// 预计算之后被删除、下架或改为非公开的帖子直接跳过
+	var posts []*model.Post
+	uids := make([]int64, 0, conf.PostRelatedTopSize)
+	for _, id := range ids {
+		p, ok := byID[id]
+		if !ok || id == pid || !recommendable(p) {
+			continue
+		}
+		posts = append(posts, p)
+		uids = append(uids, p.UserID)
+		if len(posts) == conf.PostRelatedTopSize {
+			break
+		}
+	}
+	fillRendered(ctx, svc.renderer, svc.postRepo, posts...)
+
+	authors, err := svc.userRepo.GetByIDs(ctx, uids)
+	if err != nil {
+		slog.Warn("could not get authors of posts", "uids", uids, "error", err)
+	}
+	postDTOs := make([]postdto.BriefDTO, 0, len(posts))
+	for _, p := range posts {
+		author, ok := authors[p.UserID]
+		if !ok {
+			author = &model.User{}
+		}
+		postDTOs = append(postDTOs, postdto.ToBriefDTO(p, author))
+	}
+	return postDTOs, nil
+}
+
+// RefreshRelated 预计算近期公开帖子的相关推荐并写入 Redis, 由定时任务调用
+// 每个帖子约 4 次查询, 只处理 PostRelatedWindow 内发布的帖子; 单个帖子计算失败时记录日志并跳过, 不影响其余帖子
+func (svc *postService) RefreshRelated(ctx context.Context) {
+	refreshed, failed := 0, 0
+	since := time.Now().Add(-conf.PostRelatedWindow)
+	var cursor int64
+	for {
+		posts, err := svc.postRepo.ListForRank(ctx, since, cursor, conf.PostRelatedBatchSize)
+		if err != nil {
+			slog.Error("List Posts For Related Failed", "error", err)
+			return
+		}
+		if len(posts) == 0 {
+			break
+		}
+		cursor = posts[len(posts)-1].ID
+
+		for _, post := range posts {
+			if post.Visibility != model.PostVisibilityPublic {
+				continue
+			}
+			if ctx.Err() != nil {
+				slog.Error("Refresh Related Canceled", "posts", refreshed, "failed", failed, "error", ctx.Err())
+				return
+			}
+			scores, err := svc.relatedScores(ctx, post)
+			if err != nil {
+				slog.Error("Compute Related Posts Failed", "pid", post.ID, "error", err)
+				failed++
+				continue
+			}
+			if err := svc.postRepo.SetRelated(ctx, post.ID, scores); err != nil {
+				slog.Error("Set Related Posts Failed", "pid", post.ID, "error", err)
+				failed++
+				continue
+			}
+			refreshed++
+		}
+	}
+	slog.Info("Refresh Related Succeed", "posts", refreshed, "failed", failed)
+}
+
+// relatedScores 综合共同标签、共同点赞和同一作者为候选帖子打分, 只保留所有人可见的正常帖子
+func (svc *postService) relatedScores(ctx context.Context, post *model.Post) (map[int64]float64, error) {
+	scores := make(map[int64]float64)
+
+	shared, err := svc.tagRepo.CountSharedTags(ctx, post.ID)
+	if err != nil {
+		return nil, err
+	}
+	for id, cnt := range shared {
+		scores[id] += float64(cnt) * conf.PostRelatedTagWeight
+	}
+
+	coLikes, err := svc.likeRepo.CountCoLikes(ctx, post.ID)
+	if err != nil {
+		return nil, err
+	}
+	for id, cnt := range coLikes {
+		scores[id] += float64(cnt) * conf.PostRelatedCoLikeWeight
+	}
+
+	_, sameAuthor, err := svc.postRepo.GetByUid(ctx, post.UserID, model.PostVisibilityPublic, 1, conf.PostRelatedCandidates)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range sameAuthor {
+		scores[p.ID] += conf.PostRelatedAuthorWeight
+	}
+	delete(scores, post.ID)
+
+	candidates, err := svc.postRepo.GetByIDs(ctx, rankByScore(scores))
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[int64]float64, len(candidates))
+	for _, candidate := range candidates {
+		if recommendable(candidate) {
+			res[candidate.ID] = scores[candidate.ID]
+		}
+	}
+	return res, nil
+}
+
+// recommendable 判断帖子能否出现在推荐中, 只推荐所有人可见的正常帖子
+func recommendable(post *model.Post) bool {
+	return post.Status == model.PostStatusNormal && post.Visibility == model.PostVisibilityPublic
+}
+
+// rankByScore 按分数从高到低排列 ID, 分数相同时新帖子在前
+func rankByScore(scores map[int64]float64) []int64 {
+	ids := make([]int64, 0, len(scores))
+	for id := range scores {
+		ids = append(ids, id)
+	}
+	slices.SortFunc(ids, func(a, b int64) int {
+		if c := cmp.Compare(scores[b], scores[a]); c != 0 {
+			return c
+		}
+		return cmp.Compare(b, a)
+	})
+	return ids
+}
+
+// FlushCount 将 Redis 中的互动计数增量批量落库, 由定时任务调用
+func (svc *postService) FlushCount(ctx context.Context) {
+	cnt, err := svc.postRepo.FlushCount(ctx, conf.PostCntFlushBatchSize)
+	if err != nil {
+		slog.Error("Flush Post Count Failed", "flushed", cnt, "error", err)
+		return
+	}
+	if cnt > 0 {
+		slog.Info("Flush Post Count Succeed", "flushed", cnt)
+	}
+}
+
+// ReconcileCount 根据点赞表、评论表和收藏表校正帖子计数, 由定时任务调用
+func (svc *postService) ReconcileCount(ctx context.Context) {
+	err := svc.postRepo.ReconcileCount(ctx, conf.PostCntReconcileBatchSize)
+	if err != nil {
+		slog.Error("Reconcile Post Count Failed", "error", err)
+		return
+	}
+	slog.Info("Reconcile Post Count Succeed")
+}
@@ -0,0 +1,300 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+
+	reportdto "github.com/yzletter/go-postery/dto/report"
+	"github.com/yzletter/go-postery/errno"
+	"github.com/yzletter/go-postery/model"
+	"github.com/yzletter/go-postery/repository"
+	"github.com/yzletter/go-postery/service/ports"
+)
+
+type reportService struct {
+	reportRepo  repository.ReportRepository
+	postRepo    repository.PostRepository
+	commentRepo repository.CommentRepository
+	userRepo    repository.UserRepository
+	authSvc     AuthService
+	idGen       ports.IDGenerator
+}
+
+func NewReportService(reportRepo repository.ReportRepository, postRepo repository.PostRepository,
+	commentRepo repository.CommentRepository, userRepo repository.UserRepository, authSvc AuthService, idGen ports.IDGenerator) ReportService {
+	return &reportService{
+		reportRepo:  reportRepo,
+		postRepo:    postRepo,
+		commentRepo: commentRepo,
+		userRepo:    userRepo,
+		authSvc:     authSvc,
+		idGen:       idGen,
+	}
+}
+
+// Create 举报帖子、评论或用户, 同一用户对同一对象只能举报一次
+func (svc *reportService) Create(ctx context.Context, uid int64, targetType model.ReportTargetType, targetID int64, reason, detail string) error {
+	if !slices.Contains(model.ReportReasons, reason) {
+		return errno.ErrInvalidParam
+	}
+	if targetType == model.ReportTargetUser && targetID == uid {
+		return errno.ErrInvalidParam
+	}
+
+	// 举报对象必须存在
+	if err := svc.checkTarget(ctx, targetType, targetID); err != nil {
+		return err
+	}
+
+	report := &model.Report{
+		ID:         svc.idGen.NextID(),
+		ReporterID: uid,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Reason:     reason,
+		Detail:     detail,
+		Status:     model.ReportPending,
+	}
+	err := svc.reportRepo.Create(ctx, report)
+	if err != nil {
+		if errors.Is(err, repository.ErrUniqueKey) {
+			return errno.ErrDuplicatedReport
+		}
+		return errno.ErrServerInternal
+	}
+	return nil
+}
+
+// ListPending 按页获取审核队列, 仅管理员可用
+func (svc *reportService) ListPending(ctx context.Context, uid int64, targetType model.ReportTargetType, pageNo, pageSize int) (int, []reportdto.SummaryDTO, error) {
+	var empty []reportdto.SummaryDTO
+	if err := checkAdmin(ctx, svc.userRepo, uid); err != nil {
+		return 0, empty, err
+	}
+
+	total, summaries, err := svc.reportRepo.GetPendingSummaries(ctx, targetType, pageNo, pageSize)
+	if err != nil {
+		return 0, empty, errno.ErrServerInternal
+	}
+
+	summaryDTOs := make([]reportdto.SummaryDTO, 0, len(summaries))
+	for _, summary := range summaries {
+		summaryDTOs = append(summaryDTOs, reportdto.ToSummaryDTO(summary))
+	}
+	return int(total), summaryDTOs, nil
+}
+
+// Resolve 处理对象的所有待处理举报, 仅管理员可用; takedown 会先下架内容或封禁用户, 其余动作会放行待审核的内容
+func (svc *reportService) Resolve(ctx context.Context, uid int64, targetType model.ReportTargetType, targetID int64, action model.ReportStatus, note string) error {
+	if err := checkAdmin(ctx, svc.userRepo, uid); err != nil {
+		return err
+	}
+
+	// 没有待处理的举报时不做任何处理, 避免对未被举报的对象执行下架
+	pending, err := svc.reportRepo.HasPending(ctx, targetType, targetID)
+	if err != nil {
+		return errno.ErrServerInternal
+	}
+	if !pending {
+		return errno.ErrReportNotFound
+	}
+
+	// 先执行下架或放行, 失败时举报保持待处理, 可以重试
+	if action == model.ReportTakenDown {
+		if err := svc.takeDown(ctx, targetType, targetID); err != nil {
+			return err
+		}
+	} else if err := svc.release(ctx, targetType, targetID); err != nil {
+		return err
+	}
+
+	log := &model.ModerationLog{
+		ID:         svc.idGen.NextID(),
+		OperatorID: uid,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Action:     action,
+		Note:       note,
+	}
+	err = svc.reportRepo.Resolve(ctx, log)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return errno.ErrReportNotFound
+		}
+		return errno.ErrServerInternal
+	}
+	return nil
+}
+
+// ListLogs 按页获取处理记录, 仅管理员可用
+func (svc *reportService) ListLogs(ctx context.Context, uid int64, pageNo, pageSize int) (int, []reportdto.LogDTO, error) {
+	var empty []reportdto.LogDTO
+	if err := checkAdmin(ctx, svc.userRepo, uid); err != nil {
+		return 0, empty, err
+	}
+
+	total, logs, err := svc.reportRepo.GetLogs(ctx, pageNo, pageSize)
+	if err != nil {
+		return 0, empty, errno.ErrServerInternal
+	}
+
+	logDTOs := make([]reportdto.LogDTO, 0, len(logs))
+	for _, log := range logs {
+		logDTOs = append(logDTOs, reportdto.ToLogDTO(log))
+	}
+	return int(total), logDTOs, nil
+}
+
+// takeDown 下架帖子、删除评论或封禁用户, 对象已不存在时视为成功
+func (svc *reportService) takeDown(ctx context.Context, targetType model.ReportTargetType, targetID int64) error {
+	switch targetType {
+	case model.ReportTargetPost:
+		err := svc.postRepo.Update(ctx, targetID, map[string]any{"status": model.PostStatusBanned})
+		if err != nil && !errors.Is(err, repository.ErrRecordNotFound) {
+			return errno.ErrServerInternal
+		}
+		if err := svc.postRepo.RemoveHot(ctx, targetID); err != nil {
+			return errno.ErrServerInternal
+		}
+
+	case model.ReportTargetComment:
+		comment, err := svc.commentRepo.GetByID(ctx, targetID)
+		if err != nil {
+			if errors.Is(err, repository.ErrRecordNotFound) {
+				return nil
+			}
+			return errno.ErrServerInternal
+		}
+		cnt, err := svc.commentRepo.Delete(ctx, targetID) // 返回被删除的个数, 包含回复
+		if err != nil {
+			if errors.Is(err, repository.ErrRecordNotFound) {
+				return nil
+			}
+			return errno.ErrServerInternal
+		}
+		if comment.Status == model.CommentStatusReviewing {
+			cnt-- // 待审核的评论未计入评论数
+		}
+		if err := svc.postRepo.UpdateCount(ctx, comment.PostID, model.PostCommentCount, -cnt); err != nil {
+			slog.Error("Update Comment Cnt Failed", "error", err)
+		}
+
+	case model.ReportTargetUser:
+		err := svc.userRepo.UpdateProfile(ctx, targetID, map[string]any{"status": model.UserStatusBanned})
+		if err != nil && !errors.Is(err, repository.ErrRecordNotFound) {
+			return errno.ErrServerInternal
+		}
+		// 吊销已签发的 Token, 被封禁的用户立即下线
+		if err := svc.authSvc.RevokeTokens(ctx, targetID); err != nil {
+			return err
+		}
+
+	default:
+		return errno.ErrInvalidParam
+	}
+	return nil
+}
+
+// release 放行命中敏感词而待审核的帖子或评论, 其他状态的对象不受影响
+func (svc *reportService) release(ctx context.Context, targetType model.ReportTargetType, targetID int64) error {
+	switch targetType {
+	case model.ReportTargetPost:
+		post, err := svc.postRepo.GetByID(ctx, targetID)
+		if err != nil {
+			if errors.Is(err, repository.ErrRecordNotFound) {
+				return nil
+			}
+			return errno.ErrServerInternal
+		}
+		if post.Status != model.PostStatusReviewing {
+			return nil
+		}
+		err = svc.postRepo.Update(ctx, targetID, map[string]any{"status": model.PostStatusNormal})
+		if err != nil && !errors.Is(err, repository.ErrRecordNotFound) {
+			return errno.ErrServerInternal
+		}
+
+	case model.ReportTargetComment:
+		comment, err := svc.commentRepo.GetByID(ctx, targetID)
+		if err != nil {
+			if errors.Is(err, repository.ErrRecordNotFound) {
+				return nil
+			}
+			return errno.ErrServerInternal
+		}
+		if comment.Status != model.CommentStatusReviewing {
+			return nil
+		}
+		err = svc.commentRepo.UpdateStatus(ctx, targetID, model.CommentStatusNormal)
+		if err != nil {
+			if errors.Is(err, repository.ErrRecordNotFound) {
+				return nil
+			}
+			return errno.ErrServerInternal
+		}
+		if err := svc.postRepo.UpdateCount(ctx, comment.PostID, model.PostCommentCount, 1); err != nil {
+			slog.Error("Update Comment Cnt Failed", "error", err)
+		}
+	}
+	return nil
+}
+
+// checkTarget 检查举报对象是否存在
+func (svc *reportService) checkTarget(ctx context.Context, targetType model.ReportTargetType, targetID int64) error {
+	var err error
+	switch targetType {
+	case model.ReportTargetPost:
+		var post *model.Post
+		post, err = svc.postRepo.GetByID(ctx, targetID)
+		if err == nil && post.Status != model.PostStatusNormal {
+			// 已下架的帖子无需再举报
+			return errno.ErrReportTargetNotFound
+		}
+	case model.ReportTargetComment:
+		_, err = svc.commentRepo.GetByID(ctx, targetID)
+	case model.ReportTargetUser:
+		_, err = svc.userRepo.GetByID(ctx, targetID)
+	default:
+		return errno.ErrInvalidParam
+	}
+
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return errno.ErrReportTargetNotFound
+		}
+		return errno.ErrServerInternal
+	}
+	return nil
+}
+
+// checkAdmin 检查 uid 是否为管理员, 以数据库中的角色为准
+func checkAdmin(ctx context.Context, userRepo repository.UserRepository, uid int64) error {
+	user, err := userRepo.GetByID(ctx, uid)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return errno.ErrUserNotFound
+		}
+		return errno.ErrServerInternal
+	}
+	if user.Role != model.UserRoleAdmin {
+		return errno.ErrUnauthorized
+	}
+	return nil
+}
+
+// checkModerator 检查 uid 是否为管理员或版主, 以数据库中的角色为准
+func checkModerator(ctx context.Context, userRepo repository.UserRepository, uid int64) error {
+	user, err := userRepo.GetByID(ctx, uid)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return errno.ErrUserNotFound
+		}
+		return errno.ErrServerInternal
+	}
+	if user.Role != model.UserRoleAdmin && user.Role != model.UserRoleModerator {
+		return errno.ErrUnauthorized
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	postdto "github.com/yzletter/go-postery/dto/post"
+	"github.com/yzletter/go-postery/errno"
+	"github.com/yzletter/go-postery/model"
+	"github.com/yzletter/go-postery/repository"
+	"github.com/yzletter/go-postery/service/ports"
+)
+
+type repostService struct {
+	repostRepo repository.RepostRepository
+	postRepo   repository.PostRepository
+	userRepo   repository.UserRepository
+	tagRepo    repository.TagRepository
+	followRepo repository.FollowRepository
+	idGen      ports.IDGenerator
+	renderer   ports.ContentRenderer
+}
+
+func NewRepostService(repostRepo repository.RepostRepository, postRepo repository.PostRepository,
+	userRepo repository.UserRepository, tagRepo repository.TagRepository, followRepo repository.FollowRepository,
+	idGen ports.IDGenerator, renderer ports.ContentRenderer) RepostService {
+	return &repostService{
+		repostRepo: repostRepo,
+		postRepo:   postRepo,
+		userRepo:   userRepo,
+		tagRepo:    tagRepo,
+		followRepo: followRepo,
+		idGen:      idGen,
+		renderer:   renderer,
+	}
+}
+
+// Repost 转发帖子, 只有所有人可见的帖子才能转发
+func (svc *repostService) Repost(ctx context.Context, pid, uid int64) error {
+	if _, err := shareable(ctx, svc.postRepo, svc.followRepo, pid, uid); err != nil {
+		return err
+	}
+
+	// 创建转发记录
+	repost := &model.Repost{
+		ID:     svc.idGen.NextID(),
+		UserID: uid,
+		PostID: pid,
+	}
+	err := svc.repostRepo.Repost(ctx, repost)
+	if err != nil {
+		if errors.Is(err, repository.ErrUniqueKey) {
+			// 重复转发
+			return errno.ErrDuplicatedRepost
+		}
+		// 系统内部错误
+		return errno.ErrServerInternal
+	}
+
+	field := model.PostShareCount
+	if err := svc.postRepo.UpdateCount(ctx, pid, field, 1); err != nil {
+		slog.Error("Update Share Count Failed", "error", err)
+	}
+
+	return nil
+}
+
+// UnRepost 取消转发
+func (svc *repostService) UnRepost(ctx context.Context, pid, uid int64) error {
+	// 删除转发记录
+	err := svc.repostRepo.UnRepost(ctx, uid, pid)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			// 重复删除
+			return errno.ErrDuplicatedUnRepost
+		}
+		// 系统内部错误
+		return errno.ErrServerInternal
+	}
+
+	field := model.PostShareCount
+	if err := svc.postRepo.UpdateCount(ctx, pid, field, -1); err != nil {
+		slog.Error("Update Share Count Failed", "error", err)
+	}
+
+	return nil
+}
+
+// IfRepost 判断是否转发过
+func (svc *repostService) IfRepost(ctx context.Context, pid, uid int64) (bool, error) {
+	ok, err := svc.repostRepo.HasReposted(ctx, uid, pid)
+	if err != nil {
+		return false, errno.ErrServerInternal
+	}
+	return ok, nil
+}
+
+// ListByUid 按页获取 uid 转发的帖子, viewerUid 为当前登录用户; 原帖被删除或不再对 viewerUid 可见时跳过
+func (svc *repostService) ListByUid(ctx context.Context, uid, viewerUid int64, pageNo, pageSize int) (int, []postdto.DetailDTO, error) {
+	var empty []postdto.DetailDTO
+
+	total, pids, err := svc.repostRepo.GetPostIDs(ctx, uid, pageNo, pageSize)
+	if err != nil {
+		return 0, empty, errno.ErrServerInternal
+	}
+
+	// 已删除的帖子直接跳过
+	posts, err := svc.postRepo.GetByIDs(ctx, pids)
+	if err != nil {
+		return 0, empty, errno.ErrServerInternal
+	}
+
+	// 转发后被作者改为不可见的帖子同样跳过
+	posts, err = filterVisible(ctx, svc.followRepo, posts, viewerUid)
+	if err != nil {
+		return 0, empty, errno.ErrServerInternal
+	}
+
+	fillRendered(ctx, svc.renderer, svc.postRepo, posts...)
+	return int(total), toDetailDTOs(ctx, svc.userRepo, svc.tagRepo, posts), nil
+}
@@ -0,0 +1,263 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync/atomic"
+
+	sensitivedto "github.com/yzletter/go-postery/dto/sensitive"
+	"github.com/yzletter/go-postery/errno"
+	"github.com/yzletter/go-postery/model"
+	"github.com/yzletter/go-postery/repository"
+	"github.com/yzletter/go-postery/service/ports"
+	"github.com/yzletter/go-postery/utils"
+)
+
+// 未配置处理动作的分类默认打码
+const defaultSensitiveAction = model.SensitiveMask
+
+// sensitiveMatcher 某个版本词库构建出的匹配器, 构建后只读
+type sensitiveMatcher struct {
+	version    int64
+	ac         *utils.AhoCorasick
+	categories []string                // 每个词的分类
+	actions    []model.SensitiveAction // 每个词的处理动作
+}
+
+type sensitiveService struct {
+	sensitiveRepo repository.SensitiveRepository
+	userRepo      repository.UserRepository
+	idGen         ports.IDGenerator
+	matcher       atomic.Pointer[sensitiveMatcher] // 当前使用的匹配器, 重新加载时整体替换
+}
+
+func NewSensitiveService(sensitiveRepo repository.SensitiveRepository, userRepo repository.UserRepository, idGen ports.IDGenerator) SensitiveService {
+	return &sensitiveService{
+		sensitiveRepo: sensitiveRepo,
+		userRepo:      userRepo,
+		idGen:         idGen,
+	}
+}
+
+// Filter 过滤文本, 打码类的命中替换为 *, 同时命中多个分类时 Action 取最严格的; 词库未加载时直接放行
+func (svc *sensitiveService) Filter(text string) ports.FilterResult {
+	result := ports.FilterResult{Action: model.SensitivePass, Text: text}
+	matcher := svc.matcher.Load()
+	if matcher == nil || text == "" {
+		return result
+	}
+
+	matches := matcher.ac.FindAll(text)
+	if len(matches) == 0 {
+		return result
+	}
+
+	runes := []rune(text)
+	masked := false
+	for _, match := range matches {
+		action := matcher.actions[match.Pattern]
+		result.Action = max(result.Action, action)
+		if category := matcher.categories[match.Pattern]; !slices.Contains(result.Categories, category) {
+			result.Categories = append(result.Categories, category)
+		}
+		if action == model.SensitiveMask {
+			for i := match.Start; i < match.End; i++ {
+				runes[i] = '*'
+			}
+			masked = true
+		}
+	}
+	if masked {
+		result.Text = string(runes)
+	}
+	return result
+}
+
+// Reload 词库版本号变化时从数据库重新加载词库, 由定时任务调用, 各实例各自加载
+func (svc *sensitiveService) Reload(ctx context.Context) error {
+	version, err := svc.sensitiveRepo.GetVersion(ctx)
+	if err != nil {
+		return err
+	}
+	if current := svc.matcher.Load(); current != nil && current.version == version {
+		return nil
+	}
+
+	// 先读版本号再读词库, 加载期间的变更会让版本号再次变化, 下次定时任务时重新加载
+	categories, err := svc.sensitiveRepo.GetAllCategories(ctx)
+	if err != nil {
+		return err
+	}
+	words, err := svc.sensitiveRepo.GetAllWords(ctx)
+	if err != nil {
+		return err
+	}
+
+	actionOf := make(map[string]model.SensitiveAction, len(categories))
+	for _, category := range categories {
+		actionOf[category.Name] = category.Action
+	}
+
+	matcher := &sensitiveMatcher{
+		version:    version,
+		categories: make([]string, 0, len(words)),
+		actions:    make([]model.SensitiveAction, 0, len(words)),
+	}
+	patterns := make([]string, 0, len(words))
+	for _, word := range words {
+		action, ok := actionOf[word.Category]
+		if !ok {
+			action = defaultSensitiveAction
+		}
+		patterns = append(patterns, word.Word)
+		matcher.categories = append(matcher.categories, word.Category)
+		matcher.actions = append(matcher.actions, action)
+	}
+	matcher.ac = utils.NewAhoCorasick(patterns)
+
+	svc.matcher.Store(matcher)
+	slog.Info("Sensitive Words Reloaded", "version", version, "words", len(words))
+	return nil
+}
+
+// AddWords 向分类中批量添加敏感词, 已存在的词会被跳过, 返回实际新增的个数, 仅管理员可用
+func (svc *sensitiveService) AddWords(ctx context.Context, uid int64, category string, words []string) (int, error) {
+	if err := checkAdmin(ctx, svc.userRepo, uid); err != nil {
+		return 0, err
+	}
+
+	var sensitiveWords []*model.SensitiveWord
+	seen := make(map[string]struct{}, len(words))
+	for _, word := range words {
+		word = strings.ToLower(strings.TrimSpace(word)) // 匹配时忽略大小写, 入库时统一小写便于去重
+		if word == "" {
+			continue
+		}
+		if _, ok := seen[word]; ok {
+			continue
+		}
+		seen[word] = struct{}{}
+		sensitiveWords = append(sensitiveWords, &model.SensitiveWord{
+			ID:       svc.idGen.NextID(),
+			Word:     word,
+			Category: category,
+		})
+	}
+	if len(sensitiveWords) == 0 {
+		return 0, errno.ErrInvalidParam
+	}
+
+	cnt, err := svc.sensitiveRepo.CreateWords(ctx, sensitiveWords)
+	if err != nil {
+		return 0, errno.ErrServerInternal
+	}
+	return cnt, nil
+}
+
+// DeleteWord 删除敏感词, 仅管理员可用
+func (svc *sensitiveService) DeleteWord(ctx context.Context, uid, id int64) error {
+	if err := checkAdmin(ctx, svc.userRepo, uid); err != nil {
+		return err
+	}
+
+	err := svc.sensitiveRepo.DeleteWord(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return errno.ErrSensitiveWordNotFound
+		}
+		return errno.ErrServerInternal
+	}
+	return nil
+}
+
+// ListWords 按页获取敏感词, 仅管理员可用
+func (svc *sensitiveService) ListWords(ctx context.Context, uid int64, category string, pageNo, pageSize int) (int, []sensitivedto.WordDTO, error) {
+	var empty []sensitivedto.WordDTO
+	if err := checkAdmin(ctx, svc.userRepo, uid); err != nil {
+		return 0, empty, err
+	}
+
+	total, words, err := svc.sensitiveRepo.GetWordsByPage(ctx, category, pageNo, pageSize)
+	if err != nil {
+		return 0, empty, errno.ErrServerInternal
+	}
+
+	wordDTOs := make([]sensitivedto.WordDTO, 0, len(words))
+	for _, word := range words {
+		wordDTOs = append(wordDTOs, sensitivedto.ToWordDTO(word))
+	}
+	return int(total), wordDTOs, nil
+}
+
+// ListCategories 获取所有分类及其处理动作, 仅管理员可用
+func (svc *sensitiveService) ListCategories(ctx context.Context, uid int64) ([]sensitivedto.CategoryDTO, error) {
+	var empty []sensitivedto.CategoryDTO
+	if err := checkAdmin(ctx, svc.userRepo, uid); err != nil {
+		return empty, err
+	}
+
+	categories, err := svc.sensitiveRepo.GetAllCategories(ctx)
+	if err != nil {
+		return empty, errno.ErrServerInternal
+	}
+
+	categoryDTOs := make([]sensitivedto.CategoryDTO, 0, len(categories))
+	for _, category := range categories {
+		categoryDTOs = append(categoryDTOs, sensitivedto.ToCategoryDTO(category))
+	}
+	return categoryDTOs, nil
+}
+
+// SaveCategory 设置分类的处理动作, 分类不存在时创建, 仅管理员可用
+func (svc *sensitiveService) SaveCategory(ctx context.Context, uid int64, name string, action model.SensitiveAction) error {
+	if err := checkAdmin(ctx, svc.userRepo, uid); err != nil {
+		return err
+	}
+
+	category := &model.SensitiveCategory{
+		ID:     svc.idGen.NextID(),
+		Name:   name,
+		Action: action,
+	}
+	if err := svc.sensitiveRepo.SaveCategory(ctx, category); err != nil {
+		return errno.ErrServerInternal
+	}
+	return nil
+}
+
+// screen 依次过滤多段文本并将打码结果写回; 命中拒绝类时返回 ErrContentRejected, 否则返回命中审核类的分类
+func screen(filter ports.ContentFilter, texts ...*string) ([]string, error) {
+	var reviewCategories []string
+	for _, text := range texts {
+		result := filter.Filter(*text)
+		switch result.Action {
+		case model.SensitiveReject:
+			return nil, errno.ErrContentRejected
+		case model.SensitiveReview:
+			for _, category := range result.Categories {
+				if !slices.Contains(reviewCategories, category) {
+					reviewCategories = append(reviewCategories, category)
+				}
+			}
+		}
+		*text = result.Text
+	}
+	return reviewCategories, nil
+}
+
+// submitForReview 将命中审核类敏感词的内容提交到审核队列, 由管理员在举报审核中处理
+func submitForReview(ctx context.Context, reportRepo repository.ReportRepository, idGen ports.IDGenerator,
+	targetType model.ReportTargetType, targetID int64, categories []string) error {
+	report := &model.Report{
+		ID:         idGen.NextID(),
+		TargetType: targetType,
+		TargetID:   targetID,
+		Reason:     model.ReportReasonSensitive,
+		Detail:     strings.Join(categories, ","),
+		Status:     model.ReportPending,
+	}
+	return reportRepo.Submit(ctx, report)
+}
@@ -0,0 +1,310 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+
+	"github.com/yzletter/go-postery/conf"
+	postdto "github.com/yzletter/go-postery/dto/post"
+	seriesdto "github.com/yzletter/go-postery/dto/series"
+	"github.com/yzletter/go-postery/errno"
+	"github.com/yzletter/go-postery/model"
+	"github.com/yzletter/go-postery/repository"
+	"github.com/yzletter/go-postery/service/ports"
+)
+
+type seriesService struct {
+	seriesRepo repository.SeriesRepository
+	postRepo   repository.PostRepository
+	userRepo   repository.UserRepository
+	followRepo repository.FollowRepository
+	idGen      ports.IDGenerator
+	renderer   ports.ContentRenderer
+}
+
+func NewSeriesService(seriesRepo repository.SeriesRepository, postRepo repository.PostRepository,
+	userRepo repository.UserRepository, followRepo repository.FollowRepository,
+	idGen ports.IDGenerator, renderer ports.ContentRenderer) SeriesService {
+	return &seriesService{
+		seriesRepo: seriesRepo,
+		postRepo:   postRepo,
+		userRepo:   userRepo,
+		followRepo: followRepo,
+		idGen:      idGen,
+		renderer:   renderer,
+	}
+}
+
+// Create 新建系列
+func (svc *seriesService) Create(ctx context.Context, uid int64, title, description string) (seriesdto.DTO, error) {
+	var empty seriesdto.DTO
+	author, err := svc.userRepo.GetByID(ctx, uid)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return empty, errno.ErrUserNotFound
+		}
+		return empty, errno.ErrServerInternal
+	}
+
+	series := &model.Series{
+		ID:          svc.idGen.NextID(),
+		UserID:      uid,
+		Title:       title,
+		Description: description,
+	}
+	if err := svc.seriesRepo.Create(ctx, series); err != nil {
+		return empty, errno.ErrServerInternal
+	}
+	return seriesdto.ToDTO(series, author, 0), nil
+}
+
+// Update 修改系列标题和简介, 仅作者可用
+func (svc *seriesService) Update(ctx context.Context, uid, sid int64, title, description string) error {
+	if err := svc.checkOwner(ctx, uid, sid); err != nil {
+		return err
+	}
+
+	updates := map[string]any{
+		"title":       title,
+		"description": description,
+	}
+	if err := svc.seriesRepo.Update(ctx, sid, updates); err != nil {
+		return errno.ErrServerInternal
+	}
+	return nil
+}
+
+// Delete 删除系列, 其中的帖子不受影响, 仅作者可用
+func (svc *seriesService) Delete(ctx context.Context, uid, sid int64) error {
+	if err := svc.checkOwner(ctx, uid, sid); err != nil {
+		return err
+	}
+
+	if err := svc.seriesRepo.Delete(ctx, sid); err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return errno.ErrSeriesNotFound
+		}
+		return errno.ErrServerInternal
+	}
+	return nil
+}
+
+// Detail 获取系列信息及其中 uid 能看到的帖子, 按系列顺序排列
+func (svc *seriesService) Detail(ctx context.Context, sid, uid int64) (seriesdto.DTO, []postdto.BriefDTO, error) {
+	var empty seriesdto.DTO
+	series, err := svc.seriesRepo.GetByID(ctx, sid)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return empty, nil, errno.ErrSeriesNotFound
+		}
+		return empty, nil, errno.ErrServerInternal
+	}
+	author, err := svc.userRepo.GetByID(ctx, series.UserID)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return empty, nil, errno.ErrUserNotFound
+		}
+		return empty, nil, errno.ErrServerInternal
+	}
+
+	posts, err := svc.visiblePosts(ctx, sid, uid)
+	if err != nil {
+		return empty, nil, errno.ErrServerInternal
+	}
+	fillRendered(ctx, svc.renderer, svc.postRepo, posts...)
+
+	// 系列中只有作者本人的帖子
+	postDTOs := make([]postdto.BriefDTO, 0, len(posts))
+	for _, post := range posts {
+		postDTOs = append(postDTOs, postdto.ToBriefDTO(post, author))
+	}
+	return seriesdto.ToDTO(series, author, len(posts)), postDTOs, nil
+}
+
+// ListByUid 按页获取用户的系列
+func (svc *seriesService) ListByUid(ctx context.Context, uid int64, pageNo, pageSize int) (int, []seriesdto.DTO, error) {
+	var empty []seriesdto.DTO
+	author, err := svc.userRepo.GetByID(ctx, uid)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return 0, empty, errno.ErrUserNotFound
+		}
+		return 0, empty, errno.ErrServerInternal
+	}
+
+	total, series, err := svc.seriesRepo.GetByUid(ctx, uid, pageNo, pageSize)
+	if err != nil {
+		return 0, empty, errno.ErrServerInternal
+	}
+
+	sids := make([]int64, 0, len(series))
+	for _, s := range series {
+		sids = append(sids, s.ID)
+	}
+	cnts, err := svc.seriesRepo.CountPosts(ctx, sids)
+	if err != nil {
+		return 0, empty, errno.ErrServerInternal
+	}
+
+	seriesDTOs := make([]seriesdto.DTO, 0, len(series))
+	for _, s := range series {
+		seriesDTOs = append(seriesDTOs, seriesdto.ToDTO(s, author, cnts[s.ID]))
+	}
+	return int(total), seriesDTOs, nil
+}
+
+// AddPost 将自己的帖子追加到系列末尾, 一篇帖子最多属于一个系列
+func (svc *seriesService) AddPost(ctx context.Context, uid, sid, pid int64) error {
+	if err := svc.checkOwner(ctx, uid, sid); err != nil {
+		return err
+	}
+
+	// 只能加入自己的帖子, 别人的帖子与不存在的表现一致
+	post, err := svc.postRepo.GetByID(ctx, pid)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return errno.ErrPostNotFound
+		}
+		return errno.ErrServerInternal
+	}
+	if post.UserID != uid {
+		return errno.ErrPostNotFound
+	}
+
+	pids, err := svc.seriesRepo.GetPostIDs(ctx, sid)
+	if err != nil {
+		return errno.ErrServerInternal
+	}
+	if len(pids) >= conf.SeriesPostMax {
+		return errno.ErrSeriesFull
+	}
+
+	seriesPost := &model.SeriesPost{
+		ID:       svc.idGen.NextID(),
+		SeriesID: sid,
+		PostID:   pid,
+	}
+	if _, err := svc.seriesRepo.AddPost(ctx, seriesPost); err != nil {
+		if errors.Is(err, repository.ErrUniqueKey) {
+			return errno.ErrPostInSeries
+		}
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return errno.ErrSeriesNotFound
+		}
+		return errno.ErrServerInternal
+	}
+	return nil
+}
+
+// RemovePost 将帖子移出系列, 其后的帖子依次前移
+func (svc *seriesService) RemovePost(ctx context.Context, uid, sid, pid int64) error {
+	if err := svc.checkOwner(ctx, uid, sid); err != nil {
+		return err
+	}
+
+	if err := svc.seriesRepo.RemovePost(ctx, sid, pid); err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return errno.ErrPostNotInSeries
+		}
+		return errno.ErrServerInternal
+	}
+	return nil
+}
+
+// Reorder 调整系列中帖子的顺序, pids 需包含系列中的全部帖子
+func (svc *seriesService) Reorder(ctx context.Context, uid, sid int64, pids []int64) error {
+	if err := svc.checkOwner(ctx, uid, sid); err != nil {
+		return err
+	}
+
+	if err := svc.seriesRepo.Reorder(ctx, sid, pids); err != nil {
+		if errors.Is(err, repository.ErrParamsInvalid) {
+			return errno.ErrSeriesOrderMismatch
+		}
+		return errno.ErrServerInternal
+	}
+	return nil
+}
+
+// Navigation 获取帖子所属系列及 uid 能看到的上一篇和下一篇, 不属于任何系列时返回 nil
+func (svc *seriesService) Navigation(ctx context.Context, pid, uid int64) (*postdto.SeriesNavDTO, error) {
+	seriesPost, err := svc.seriesRepo.GetByPostID(ctx, pid)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, errno.ErrServerInternal
+	}
+	series, err := svc.seriesRepo.GetByID(ctx, seriesPost.SeriesID)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, errno.ErrServerInternal
+	}
+
+	posts, err := svc.visiblePosts(ctx, series.ID, uid)
+	if err != nil {
+		return nil, errno.ErrServerInternal
+	}
+	idx := slices.IndexFunc(posts, func(post *model.Post) bool { return post.ID == pid })
+	if idx < 0 {
+		return nil, nil
+	}
+
+	nav := &postdto.SeriesNavDTO{
+		ID:       series.ID,
+		Title:    series.Title,
+		Position: idx + 1,
+		Total:    len(posts),
+	}
+	if idx > 0 {
+		nav.Prev = &postdto.LinkDTO{ID: posts[idx-1].ID, Title: posts[idx-1].Title}
+	}
+	if idx < len(posts)-1 {
+		nav.Next = &postdto.LinkDTO{ID: posts[idx+1].ID, Title: posts[idx+1].Title}
+	}
+	return nav, nil
+}
+
+// visiblePosts 按系列顺序获取 uid 能看到的帖子
+func (svc *seriesService) visiblePosts(ctx context.Context, sid, uid int64) ([]*model.Post, error) {
+	pids, err := svc.seriesRepo.GetPostIDs(ctx, sid)
+	if err != nil {
+		return nil, err
+	}
+	found, err := svc.postRepo.GetByIDs(ctx, pids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[int64]*model.Post, len(found))
+	for _, post := range found {
+		byID[post.ID] = post
+	}
+
+	posts := make([]*model.Post, 0, len(pids))
+	for _, pid := range pids {
+		if post, ok := byID[pid]; ok {
+			posts = append(posts, post)
+		}
+	}
+	return filterVisible(ctx, svc.followRepo, posts, uid)
+}
+
+// checkOwner 校验系列存在且属于 uid, 别人的系列与不存在的表现一致
+func (svc *seriesService) checkOwner(ctx context.Context, uid, sid int64) error {
+	series, err := svc.seriesRepo.GetByID(ctx, sid)
+	if err != nil {
+		if errors.Is(err, repository.ErrRecordNotFound) {
+			return errno.ErrSeriesNotFound
+		}
+		slog.Error("Get Series Failed", "sid", sid, "error", err)
+		return errno.ErrServerInternal
+	}
+	if series.UserID != uid {
+		return errno.ErrSeriesNotFound
+	}
+	return nil
+}
@@ -2,13 +2,25 @@ package service
 
 import (
 	"context"
+	"io"
 	"net/http"
+	"time"
 
+	analyticsdto "github.com/yzletter/go-postery/dto/analytics"
+	archivedto "github.com/yzletter/go-postery/dto/archive"
+	attachmentdto "github.com/yzletter/go-postery/dto/attachment"
+	bookmarkdto "github.com/yzletter/go-postery/dto/bookmark"
 	commentdto "github.com/yzletter/go-postery/dto/comment"
+	feeddto "github.com/yzletter/go-postery/dto/feed"
 	giftdto "github.com/yzletter/go-postery/dto/gift"
+	mentiondto "github.com/yzletter/go-postery/dto/mention"
 	messagedto "github.com/yzletter/go-postery/dto/message"
 	orderdto "github.com/yzletter/go-postery/dto/order"
+	polldto "github.com/yzletter/go-postery/dto/poll"
 	postdto "github.com/yzletter/go-postery/dto/post"
+	reportdto "github.com/yzletter/go-postery/dto/report"
+	sensitivedto "github.com/yzletter/go-postery/dto/sensitive"
+	seriesdto "github.com/yzletter/go-postery/dto/series"
 	sessiondto "github.com/yzletter/go-postery/dto/session"
 	userdto "github.com/yzletter/go-postery/dto/user"
 	"github.com/yzletter/go-postery/model"
@@ -22,7 +34,9 @@ type AuthService interface {
 	Login(ctx context.Context, username, pass string) (userdto.BriefDTO, error)
 	ClearTokens(ctx context.Context, accessToken, refreshToken string) error
 	IssueTokens(ctx context.Context, id int64, role int, agent string) (string, string, error)
+	RevokeTokens(ctx context.Context, uid int64) error
 	VerifyAccessToken(tokenString string) (*ports.JWTTokenClaims, error)
+	CheckStatus(ctx context.Context, uid int64) error
 }
 
 type UserService interface {
@@ -35,26 +49,51 @@ type UserService interface {
 }
 
 type PostService interface {
-	Create(ctx context.Context, uid int64, title, content string) (postdto.DetailDTO, error)
-	GetDetailById(ctx context.Context, id int64, addViewCnt bool) (postdto.DetailDTO, error)
-	GetBriefById(ctx context.Context, id int64) (postdto.BriefDTO, error)
+	Create(ctx context.Context, uid int64, title, content string, visibility model.PostVisibility, quoteID int64) (postdto.DetailDTO, error)
+	Import(ctx context.Context, uid int64, title, content string, visibility model.PostVisibility, createdAt time.Time) (postdto.DetailDTO, error)
+	GetDetailById(ctx context.Context, id, uid int64) (postdto.DetailDTO, error)
+	RecordView(ctx context.Context, postDTO *postdto.DetailDTO, viewer, referer string)
+	ResolveSlug(ctx context.Context, slug string, uid int64) (int64, string, error)
+	BackfillSlugs(ctx context.Context)
+	GetBriefById(ctx context.Context, id, uid int64) (postdto.BriefDTO, error)
 	Belong(ctx context.Context, pid, uid int64) bool
 	Delete(ctx context.Context, pid, uid int64) error
-	Update(ctx context.Context, pid int64, uid int64, title, content string, tags []string) error
+	Update(ctx context.Context, pid int64, uid int64, version int, title, content string, tags []string, visibility model.PostVisibility) (int, error)
 	ListByPage(ctx context.Context, pageNo, pageSize int) (int, []postdto.DetailDTO, error)
-	ListByPageAndUid(ctx context.Context, uid int64, pageNo, pageSize int) (int, []postdto.BriefDTO, error)
+	ListByPageAndUid(ctx context.Context, uid, viewerUid int64, pageNo, pageSize int) (int, []postdto.BriefDTO, error)
 	ListByPageAndTag(ctx context.Context, name string, pageNo, pageSize int) (int, []postdto.DetailDTO, error)
+	ListFeatured(ctx context.Context, pageNo, pageSize int) (int, []postdto.DetailDTO, error)
+	Pin(ctx context.Context, uid, pid int64, tag string, expiresAt *time.Time) error
+	Unpin(ctx context.Context, uid, pid int64, tag string) error
+	Feature(ctx context.Context, uid, pid int64, featured bool) error
 	Like(ctx context.Context, pid, uid int64) error
 	Unlike(ctx context.Context, pid, uid int64) error
 	IfLike(ctx context.Context, pid, uid int64) (bool, error)
-	Top(ctx context.Context) ([]postdto.TopDTO, error)
+	Top(ctx context.Context, window string, tag string) ([]postdto.TopDTO, error)
+	RefreshHot(ctx context.Context)
+	RefreshHotAll(ctx context.Context)
+	ListRelated(ctx context.Context, pid, uid int64) ([]postdto.BriefDTO, error)
+	RefreshRelated(ctx context.Context)
+	FlushCount(ctx context.Context)
+	ReconcileCount(ctx context.Context)
 }
 
 type CommentService interface {
 	Create(ctx context.Context, pid int64, uid int64, parentId int64, replyId int64, content string) (commentdto.DTO, error)
+	Edit(ctx context.Context, pid, cid, uid int64, content string) (commentdto.DTO, error)
+	Revisions(ctx context.Context, pid, cid, uid int64) ([]commentdto.RevisionDTO, error)
 	Delete(ctx context.Context, uid, cid int64) error
-	List(ctx context.Context, pid int64, pageNo, pageSize int) (int, []commentdto.DTO, error)
-	ListReplies(ctx context.Context, ids int64, pageNo, pageSize int) (int, []commentdto.DTO, error)
+	List(ctx context.Context, pid, uid int64, sort model.CommentSort, pageNo, pageSize int) (int, []commentdto.DTO, error)
+	Tree(ctx context.Context, pid, uid int64, sort model.CommentSort, pageNo, pageSize, replySize int) (int, []commentdto.DTO, error)
+	ListReplies(ctx context.Context, pid, id, uid int64, sort model.CommentSort, pageNo, pageSize int) (int, []commentdto.DTO, error)
+	Like(ctx context.Context, pid, cid, uid int64) error
+	UnLike(ctx context.Context, pid, cid, uid int64) error
+	SetPolicy(ctx context.Context, pid, uid int64, policy model.CommentPolicy) error
+	Pin(ctx context.Context, pid, cid, uid int64) error
+	Unpin(ctx context.Context, pid, cid, uid int64) error
+	Block(ctx context.Context, uid, target int64) error
+	UnBlock(ctx context.Context, uid, target int64) error
+	ListBlocked(ctx context.Context, uid int64, pageNo, pageSize int) (int, []userdto.BriefDTO, error)
 	CheckAuth(ctx context.Context, cid, uid int64) bool
 }
 
@@ -64,6 +103,97 @@ type TagService interface {
 	FindTagsByPostID(ctx context.Context, pid int64) ([]string, error)
 }
 
+type AttachmentService interface {
+	Upload(ctx context.Context, uid int64, fileName string, r io.Reader, size int64) (attachmentdto.DTO, error)
+	CheckBindable(ctx context.Context, uid, pid int64, ids []int64) error
+	BindToPost(ctx context.Context, uid, pid int64, ids []int64) error
+	ListByPostID(ctx context.Context, pid, uid int64) ([]attachmentdto.DTO, error)
+	Delete(ctx context.Context, uid, id int64) error
+	CollectGarbage(ctx context.Context)
+}
+
+type ReportService interface {
+	Create(ctx context.Context, uid int64, targetType model.ReportTargetType, targetID int64, reason, detail string) error
+	ListPending(ctx context.Context, uid int64, targetType model.ReportTargetType, pageNo, pageSize int) (int, []reportdto.SummaryDTO, error)
+	Resolve(ctx context.Context, uid int64, targetType model.ReportTargetType, targetID int64, action model.ReportStatus, note string) error
+	ListLogs(ctx context.Context, uid int64, pageNo, pageSize int) (int, []reportdto.LogDTO, error)
+}
+
+type SensitiveService interface {
+	ports.ContentFilter
+	Reload(ctx context.Context) error
+	AddWords(ctx context.Context, uid int64, category string, words []string) (int, error)
+	DeleteWord(ctx context.Context, uid, id int64) error
+	ListWords(ctx context.Context, uid int64, category string, pageNo, pageSize int) (int, []sensitivedto.WordDTO, error)
+	ListCategories(ctx context.Context, uid int64) ([]sensitivedto.CategoryDTO, error)
+	SaveCategory(ctx context.Context, uid int64, name string, action model.SensitiveAction) error
+}
+
+type BookmarkService interface {
+	Bookmark(ctx context.Context, pid, uid, cid int64) error
+	UnBookmark(ctx context.Context, pid, uid int64) error
+	IfBookmark(ctx context.Context, pid, uid int64) (bool, error)
+	ListBookmarks(ctx context.Context, uid int64, cid *int64, pageNo, pageSize int) (int, []postdto.DetailDTO, error)
+	MoveBookmark(ctx context.Context, pid, uid, cid int64) error
+	CreateCollection(ctx context.Context, uid int64, name string) (bookmarkdto.CollectionDTO, error)
+	RenameCollection(ctx context.Context, uid, cid int64, name string) error
+	DeleteCollection(ctx context.Context, uid, cid int64) error
+	ListCollections(ctx context.Context, uid int64, pageNo, pageSize int) (int, []bookmarkdto.CollectionDTO, error)
+}
+
+type RepostService interface {
+	Repost(ctx context.Context, pid, uid int64) error
+	UnRepost(ctx context.Context, pid, uid int64) error
+	IfRepost(ctx context.Context, pid, uid int64) (bool, error)
+	ListByUid(ctx context.Context, uid, viewerUid int64, pageNo, pageSize int) (int, []postdto.DetailDTO, error)
+}
+
+type AnalyticsService interface {
+	Aggregate(ctx context.Context)
+	Dashboard(ctx context.Context, uid, pid int64, from, to time.Time) (analyticsdto.DashboardDTO, error)
+}
+
+// MentionHandler 处理一次 @ 事件
+type MentionHandler func(ctx context.Context, mention model.Mention)
+
+type MentionService interface {
+	Sync(ctx context.Context, targetType model.MentionTarget, targetID, postID, fromUID int64, text string, notify bool) ([]mentiondto.SpanDTO, error)
+	Spans(ctx context.Context, targetType model.MentionTarget, texts map[int64]string) (map[int64][]mentiondto.SpanDTO, error)
+	Subscribe(handler MentionHandler)
+}
+
+type ArchiveService interface {
+	Import(ctx context.Context, uid int64, r io.ReaderAt, size int64) (archivedto.ImportResultDTO, error)
+	Export(ctx context.Context, uid, viewerUid int64) ([]byte, error)
+}
+
+type FeedService interface {
+	Posts(ctx context.Context) (feeddto.Feed, error)
+	Tag(ctx context.Context, slug string) (feeddto.Feed, error)
+	User(ctx context.Context, uid int64) (feeddto.Feed, error)
+}
+
+type PollService interface {
+	Validate(options []string, deadline *time.Time) error
+	Create(ctx context.Context, uid, pid int64, options []string, multiple, anonymous bool, deadline *time.Time) (polldto.DTO, error)
+	Result(ctx context.Context, pid, uid int64) (polldto.DTO, error)
+	Vote(ctx context.Context, pid, uid int64, optionIDs []int64) (polldto.DTO, error)
+	ListVoters(ctx context.Context, pid, optionID, uid int64, pageNo, pageSize int) (int, []userdto.BriefDTO, error)
+	FlushCount(ctx context.Context)
+}
+
+type SeriesService interface {
+	Create(ctx context.Context, uid int64, title, description string) (seriesdto.DTO, error)
+	Update(ctx context.Context, uid, sid int64, title, description string) error
+	Delete(ctx context.Context, uid, sid int64) error
+	Detail(ctx context.Context, sid, uid int64) (seriesdto.DTO, []postdto.BriefDTO, error)
+	ListByUid(ctx context.Context, uid int64, pageNo, pageSize int) (int, []seriesdto.DTO, error)
+	AddPost(ctx context.Context, uid, sid, pid int64) error
+	RemovePost(ctx context.Context, uid, sid, pid int64) error
+	Reorder(ctx context.Context, uid, sid int64, pids []int64) error
+	Navigation(ctx context.Context, pid, uid int64) (*postdto.SeriesNavDTO, error)
+}
+
 type FollowService interface {
 	Follow(ctx context.Context, ferId, feeId int64) error
 	UnFollow(ctx context.Context, ferId, feeId int64) error
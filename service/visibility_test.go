@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/yzletter/go-postery/model"
+)
+
+func TestCanView(t *testing.T) {
+	const (
+		author   int64 = 1
+		follower int64 = 2
+		stranger int64 = 3
+	)
+	followRepo := &fakeFollowRepo{following: map[[2]int64]bool{{follower, author}: true}}
+
+	cases := []struct {
+		name       string
+		status     int
+		visibility model.PostVisibility
+		want       map[int64]bool // 查看者 -> 能否查看, 0 表示未登录
+	}{
+		{"公开", model.PostStatusNormal, model.PostVisibilityPublic,
+			map[int64]bool{0: true, stranger: true, follower: true, author: true}},
+		{"仅粉丝", model.PostStatusNormal, model.PostVisibilityFollowers,
+			map[int64]bool{0: false, stranger: false, follower: true, author: true}},
+		{"仅自己", model.PostStatusNormal, model.PostVisibilityPrivate,
+			map[int64]bool{0: false, stranger: false, follower: false, author: true}},
+		{"待审核", model.PostStatusReviewing, model.PostVisibilityPublic,
+			map[int64]bool{0: false, stranger: false, follower: false, author: true}},
+		{"被下架", model.PostStatusBanned, model.PostVisibilityPublic,
+			map[int64]bool{0: false, stranger: false, follower: false, author: false}},
+	}
+	for _, c := range cases {
+		post := &model.Post{ID: 100, UserID: author, Status: c.status, Visibility: c.visibility}
+		for uid, want := range c.want {
+			got, err := canView(context.Background(), followRepo, post, uid)
+			if err != nil {
+				t.Fatalf("%s: canView(uid=%d) error = %v", c.name, uid, err)
+			}
+			if got != want {
+				t.Fatalf("%s: canView(uid=%d) = %v, want %v", c.name, uid, got, want)
+			}
+		}
+	}
+}
+
+// go test -v ./service -run=^TestCanView$ -count=1
+
+func TestFilterVisible(t *testing.T) {
+	const (
+		author   int64 = 1
+		follower int64 = 2
+	)
+	followRepo := &fakeFollowRepo{following: map[[2]int64]bool{{follower, author}: true}}
+	posts := []*model.Post{
+		{ID: 1, UserID: author, Status: model.PostStatusNormal, Visibility: model.PostVisibilityPublic},
+		{ID: 2, UserID: author, Status: model.PostStatusNormal, Visibility: model.PostVisibilityFollowers},
+		{ID: 3, UserID: author, Status: model.PostStatusNormal, Visibility: model.PostVisibilityPrivate},
+		{ID: 4, UserID: author, Status: model.PostStatusReviewing, Visibility: model.PostVisibilityPublic},
+		{ID: 5, UserID: author, Status: model.PostStatusBanned, Visibility: model.PostVisibilityPublic},
+	}
+
+	cases := []struct {
+		uid  int64
+		want []int64
+	}{
+		{0, []int64{1}},
+		{follower, []int64{1, 2}},
+		{author, []int64{1, 2, 3, 4}},
+	}
+	for _, c := range cases {
+		visible, err := filterVisible(context.Background(), followRepo, posts, c.uid)
+		if err != nil {
+			t.Fatalf("filterVisible(uid=%d) error = %v", c.uid, err)
+		}
+		var ids []int64
+		for _, post := range visible {
+			ids = append(ids, post.ID)
+		}
+		if !slices.Equal(ids, c.want) {
+			t.Fatalf("filterVisible(uid=%d) = %v, want %v", c.uid, ids, c.want)
+		}
+	}
+}
+
+// go test -v ./service -run=^TestFilterVisible$ -count=1
@@ -66,16 +66,18 @@ type websocketService struct {
 	userRepo    repository.UserRepository
 	mqConn      *amqp.Connection
 	idGen       ports.IDGenerator
+	filter      ports.ContentFilter
 }
 
 func NewWebsocketService(sessionRepo repository.SessionRepository, messageRepo repository.MessageRepository, userRepo repository.UserRepository,
-	mq *amqp.Connection, idGen ports.IDGenerator) WebsocketService {
+	mq *amqp.Connection, idGen ports.IDGenerator, filter ports.ContentFilter) WebsocketService {
 	return &websocketService{
 		sessionRepo: sessionRepo,
 		messageRepo: messageRepo,
 		userRepo:    userRepo,
 		mqConn:      mq,
 		idGen:       idGen,
+		filter:      filter,
 	}
 }
 
@@ -189,7 +191,7 @@ func (svc *websocketService) Connect(ctx context.Context, w http.ResponseWriter,
 			}
 
 			// 过滤消息
-			ok := intercept(message, uid)
+			ok := intercept(svc.filter, &message, uid)
 			if !ok {
 				continue
 			}
@@ -274,11 +276,19 @@ func heartBeat(ctx context.Context, conn *websocket.Conn, send func(wsWriteReque
 	}
 }
 
-// todo 处理消息内容, 正常应进行对非法内容进行拦截。比如机器人消息（发言频率过快）；包含欺诈、涉政等违规内容；涉嫌私下联系/交易等。
-func intercept(message model.Message, uid int64) bool {
+// intercept 拦截冒充他人发送的消息和命中敏感词的消息, 打码类的命中直接改写消息内容。
+// 私信是实时送达的, 无法先隐藏再审核, 命中审核类敏感词时与拒绝类一样直接拦截。
+// todo 拦截机器人消息（发言频率过快）
+func intercept(filter ports.ContentFilter, message *model.Message, uid int64) bool {
 	if message.MessageFrom != uid {
 		return false
 	}
+
+	result := filter.Filter(message.Content)
+	if result.Action == model.SensitiveReject || result.Action == model.SensitiveReview {
+		return false
+	}
+	message.Content = result.Text
 	return true
 }
 
@@ -0,0 +1,101 @@
+package utils
+
+import "unicode"
+
+// ACMatch 一次命中, Start / End 为命中片段在文本中的字符下标, 左闭右开
+type ACMatch struct {
+	Pattern int // 命中的模式串下标
+	Start   int
+	End     int
+}
+
+// AhoCorasick 多模式串匹配自动机, 匹配时忽略大小写; 构建完成后只读, 可并发使用
+type AhoCorasick struct {
+	nodes   []acNode
+	lengths []int // 每个模式串的字符数
+}
+
+type acNode struct {
+	next map[rune]int
+	fail int
+	out  []int // 以该节点结尾的模式串下标, 包含 fail 链上的
+}
+
+// NewAhoCorasick 根据模式串构建自动机, 空串会被忽略
+func NewAhoCorasick(patterns []string) *AhoCorasick {
+	ac := &AhoCorasick{
+		nodes:   []acNode{{next: map[rune]int{}}},
+		lengths: make([]int, len(patterns)),
+	}
+
+	// 1. 构建 Trie
+	for i, pattern := range patterns {
+		runes := []rune(pattern)
+		ac.lengths[i] = len(runes)
+		if len(runes) == 0 {
+			continue
+		}
+
+		cur := 0
+		for _, r := range runes {
+			r = unicode.ToLower(r)
+			child, ok := ac.nodes[cur].next[r]
+			if !ok {
+				child = len(ac.nodes)
+				ac.nodes = append(ac.nodes, acNode{next: map[rune]int{}})
+				ac.nodes[cur].next[r] = child
+			}
+			cur = child
+		}
+		ac.nodes[cur].out = append(ac.nodes[cur].out, i)
+	}
+
+	// 2. 按层构建 fail 指针, 同时合并 fail 链上的输出
+	queue := make([]int, 0, len(ac.nodes))
+	for _, child := range ac.nodes[0].next {
+		queue = append(queue, child)
+	}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for r, child := range ac.nodes[cur].next {
+			fail := ac.nodes[cur].fail
+			for fail != 0 {
+				if _, ok := ac.nodes[fail].next[r]; ok {
+					break
+				}
+				fail = ac.nodes[fail].fail
+			}
+			if next, ok := ac.nodes[fail].next[r]; ok && next != child {
+				fail = next
+			}
+			ac.nodes[child].fail = fail
+			ac.nodes[child].out = append(ac.nodes[child].out, ac.nodes[fail].out...)
+			queue = append(queue, child)
+		}
+	}
+
+	return ac
+}
+
+// FindAll 返回文本中所有命中, 按结束位置排序, 重叠的命中都会返回
+func (ac *AhoCorasick) FindAll(text string) []ACMatch {
+	var matches []ACMatch
+	cur := 0
+	for i, r := range []rune(text) {
+		r = unicode.ToLower(r)
+		for cur != 0 {
+			if _, ok := ac.nodes[cur].next[r]; ok {
+				break
+			}
+			cur = ac.nodes[cur].fail
+		}
+		if next, ok := ac.nodes[cur].next[r]; ok {
+			cur = next
+		}
+		for _, pattern := range ac.nodes[cur].out {
+			matches = append(matches, ACMatch{Pattern: pattern, Start: i + 1 - ac.lengths[pattern], End: i + 1})
+		}
+	}
+	return matches
+}
@@ -0,0 +1,43 @@
+package utils_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/yzletter/go-postery/utils"
+)
+
+func TestAhoCorasick(t *testing.T) {
+	ac := utils.NewAhoCorasick([]string{"he", "she", "his", "hers", "刷单"})
+
+	// 重叠的命中都要返回, 按结束位置排序
+	got := ac.FindAll("ushers")
+	want := []utils.ACMatch{
+		{Pattern: 1, Start: 1, End: 4},
+		{Pattern: 0, Start: 2, End: 4},
+		{Pattern: 3, Start: 2, End: 6},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("FindAll = %v, want %v", got, want)
+	}
+
+	// 忽略大小写, 下标按字符计算
+	got = ac.FindAll("兼职刷单 HIS")
+	want = []utils.ACMatch{
+		{Pattern: 4, Start: 2, End: 4},
+		{Pattern: 2, Start: 5, End: 8},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("FindAll = %v, want %v", got, want)
+	}
+
+	// 只命中前缀 he, 空自动机没有命中
+	if got := ac.FindAll("hello"); len(got) != 1 || got[0].Pattern != 0 {
+		t.Fatalf("FindAll = %v, want only \"he\"", got)
+	}
+	if got := utils.NewAhoCorasick(nil).FindAll("anything"); len(got) != 0 {
+		t.Fatalf("FindAll = %v, want empty", got)
+	}
+}
+
+// go test -v ./utils -run=^TestAhoCorasick$ -count=1
@@ -0,0 +1,14 @@
+package utils
+
+import "strings"
+
+// Excerpt 将纯文本的连续空白压缩为一个空格, 并截取前 n 个字符作为摘要, 被截断时以 … 结尾
+func Excerpt(text string, n int) string {
+	text = strings.Join(strings.Fields(text), " ")
+
+	runes := []rune(text)
+	if n <= 0 || len(runes) <= n {
+		return text
+	}
+	return strings.TrimSpace(string(runes[:n])) + "…"
+}
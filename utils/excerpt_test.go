@@ -0,0 +1,26 @@
+package utils_test
+
+import (
+	"testing"
+
+	"github.com/yzletter/go-postery/utils"
+)
+
+func TestExcerpt(t *testing.T) {
+	// 连续空白被压缩
+	if got := utils.Excerpt("hello \n\n  world", 20); got != "hello world" {
+		t.Fatalf("Excerpt = %q, want %q", got, "hello world")
+	}
+
+	// 按字符而不是字节截断
+	if got := utils.Excerpt("你好世界再见", 4); got != "你好世界…" {
+		t.Fatalf("Excerpt = %q, want %q", got, "你好世界…")
+	}
+
+	// 截断处的空格不保留
+	if got := utils.Excerpt("hello world", 6); got != "hello…" {
+		t.Fatalf("Excerpt = %q, want %q", got, "hello…")
+	}
+}
+
+// go test -v ./utils -run=^TestExcerpt$ -count=1
@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+const frontMatterDelimiter = "---"
+
+// SplitFrontMatter 拆分 Markdown 开头以 --- 包围的 YAML front-matter 和正文, 没有 front-matter 时 ok 为 false, 正文原样返回
+func SplitFrontMatter(content string) (front, body string, ok bool) {
+	content = strings.TrimPrefix(content, "\ufeff") // 去掉 BOM
+	first, rest, found := strings.Cut(content, "\n")
+	if !found || strings.TrimSpace(first) != frontMatterDelimiter {
+		return "", content, false
+	}
+
+	// 逐行找结束分隔符
+	offset := 0
+	for offset <= len(rest) {
+		line, _, _ := strings.Cut(rest[offset:], "\n")
+		if strings.TrimSpace(line) == frontMatterDelimiter {
+			front = rest[:offset]
+			body = rest[min(offset+len(line)+1, len(rest)):]
+			return front, strings.TrimLeft(body, "\r\n"), true
+		}
+		if offset+len(line) >= len(rest) {
+			break
+		}
+		offset += len(line) + 1
+	}
+	return "", content, false
+}
+
+// JoinFrontMatter 将 YAML front-matter 和正文拼接为 Markdown
+func JoinFrontMatter(front, body string) string {
+	var sb strings.Builder
+	sb.WriteString(frontMatterDelimiter + "\n")
+	sb.WriteString(front)
+	if !strings.HasSuffix(front, "\n") {
+		sb.WriteString("\n")
+	}
+	sb.WriteString(frontMatterDelimiter + "\n\n")
+	sb.WriteString(body)
+	return sb.String()
+}
+
+// timestampLayouts 常见博客和 Wiki 导出的时间格式, 不带时区的按 loc 解析
+var timestampLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05 -0700",
+	"2006-01-02 15:04:05 -07:00",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	time.DateOnly,
+}
+
+// ParseTimestamp 按常见格式解析时间
+func ParseTimestamp(s string, loc *time.Location) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	for _, layout := range timestampLayouts {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, errors.New("unrecognized timestamp: " + s)
+}
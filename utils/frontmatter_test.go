@@ -0,0 +1,67 @@
+package utils_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/yzletter/go-postery/utils"
+)
+
+func TestSplitFrontMatter(t *testing.T) {
+	// 标准 front-matter, 正文开头的空行被去掉
+	front, body, ok := utils.SplitFrontMatter("---\ntitle: hello\ntags: [go]\n---\n\n# hello\n")
+	if !ok || front != "title: hello\ntags: [go]\n" || body != "# hello\n" {
+		t.Fatalf("SplitFrontMatter = %q, %q, %v", front, body, ok)
+	}
+
+	// Windows 换行和 BOM
+	front, body, ok = utils.SplitFrontMatter("\ufeff---\r\ntitle: hello\r\n---\r\nbody")
+	if !ok || front != "title: hello\r\n" || body != "body" {
+		t.Fatalf("SplitFrontMatter = %q, %q, %v", front, body, ok)
+	}
+
+	// 正文中的分隔线不算 front-matter
+	if _, body, ok = utils.SplitFrontMatter("hello\n---\nworld"); ok || body != "hello\n---\nworld" {
+		t.Fatalf("SplitFrontMatter = %q, %v, want no front-matter", body, ok)
+	}
+
+	// 没有结束分隔符
+	if _, _, ok = utils.SplitFrontMatter("---\ntitle: hello\n"); ok {
+		t.Fatalf("SplitFrontMatter ok = true, want false")
+	}
+
+	// 结束分隔符后没有正文
+	if front, body, ok = utils.SplitFrontMatter("---\ntitle: hello\n---"); !ok || front != "title: hello\n" || body != "" {
+		t.Fatalf("SplitFrontMatter = %q, %q, %v", front, body, ok)
+	}
+
+	// 拼接后可以拆回
+	joined := utils.JoinFrontMatter("title: hello", "body\n")
+	if front, body, ok = utils.SplitFrontMatter(joined); !ok || front != "title: hello\n" || body != "body\n" {
+		t.Fatalf("SplitFrontMatter(JoinFrontMatter) = %q, %q, %v", front, body, ok)
+	}
+}
+
+// go test -v ./utils -run=^TestSplitFrontMatter$ -count=1
+
+func TestParseTimestamp(t *testing.T) {
+	loc := time.FixedZone("CST", 8*3600)
+	cases := map[string]time.Time{
+		"2024-01-02T15:04:05Z":      time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC),
+		"2024-01-02 15:04:05":       time.Date(2024, 1, 2, 15, 4, 5, 0, loc),
+		"2024-01-02 15:04:05 +0000": time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC),
+		" 2024-01-02 ":              time.Date(2024, 1, 2, 0, 0, 0, 0, loc),
+	}
+	for s, want := range cases {
+		got, err := utils.ParseTimestamp(s, loc)
+		if err != nil || !got.Equal(want) {
+			t.Fatalf("ParseTimestamp(%q) = %v, %v, want %v", s, got, err, want)
+		}
+	}
+
+	if _, err := utils.ParseTimestamp("yesterday", loc); err == nil {
+		t.Fatalf("ParseTimestamp(%q) err = nil, want error", "yesterday")
+	}
+}
+
+// go test -v ./utils -run=^TestParseTimestamp$ -count=1
@@ -0,0 +1,23 @@
+package utils
+
+import (
+	"image"
+
+	"golang.org/x/image/draw"
+)
+
+// FitWidth 将图片等比缩放到宽度不超过 maxWidth, 本身不超过时原样返回
+func FitWidth(src image.Image, maxWidth int) image.Image {
+	b := src.Bounds()
+	if maxWidth <= 0 || b.Dx() <= maxWidth {
+		return src
+	}
+
+	height := b.Dy() * maxWidth / b.Dx()
+	if height < 1 {
+		height = 1
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, maxWidth, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, b, draw.Src, nil)
+	return dst
+}
@@ -0,0 +1,31 @@
+package utils_test
+
+import (
+	"image"
+	"testing"
+
+	"github.com/yzletter/go-postery/utils"
+)
+
+func TestFitWidth(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 1000, 500))
+
+	// 等比缩放
+	dst := utils.FitWidth(src, 320)
+	if w, h := dst.Bounds().Dx(), dst.Bounds().Dy(); w != 320 || h != 160 {
+		t.Fatalf("FitWidth size = %dx%d, want 320x160", w, h)
+	}
+
+	// 不放大
+	if dst := utils.FitWidth(src, 2000); dst != image.Image(src) {
+		t.Fatal("image narrower than maxWidth should be returned as is")
+	}
+
+	// 极端长宽比时高度至少为 1
+	wide := image.NewRGBA(image.Rect(0, 0, 10000, 1))
+	if h := utils.FitWidth(wide, 100).Bounds().Dy(); h != 1 {
+		t.Fatalf("FitWidth height = %d, want 1", h)
+	}
+}
+
+// go test -v ./utils -run=^TestFitWidth$ -count=1
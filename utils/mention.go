@@ -0,0 +1,71 @@
+package utils
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// MentionSpan 正文中的一处 @用户名, Start 和 End 为包含 @ 在内的字节偏移
+type MentionSpan struct {
+	Username string
+	Start    int
+	End      int
+}
+
+// ParseMentions 按出现顺序解析 text 中的 @用户名; 用户名由字母、数字、下划线、连字符和点组成, 末尾的点视为标点;
+// @ 紧跟在英文字母、数字或 _ . - + 之后时不算提及, 避免把邮箱地址当成提及
+func ParseMentions(text string) []MentionSpan {
+	var spans []MentionSpan
+	prev := ' '
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		if r != '@' || isEmailRune(prev) {
+			prev = r
+			i += size
+			continue
+		}
+
+		// 读取用户名
+		start := i + size
+		j := start
+		for j < len(text) {
+			r, size := utf8.DecodeRuneInString(text[j:])
+			if !isMentionRune(r) {
+				break
+			}
+			j += size
+		}
+		end := j
+		for end > start && text[end-1] == '.' {
+			end--
+		}
+
+		if end > start {
+			spans = append(spans, MentionSpan{Username: text[start:end], Start: i, End: end})
+		}
+		prev, _ = utf8.DecodeLastRuneInString(text[:j])
+		i = j
+	}
+	return spans
+}
+
+// UTF16Len 返回 s 按 UTF-16 编码的长度, 与 JavaScript 字符串的 length 一致
+func UTF16Len(s string) int {
+	n := 0
+	for _, r := range s {
+		if r >= 0x10000 {
+			n += 2
+		} else {
+			n++
+		}
+	}
+	return n
+}
+
+func isMentionRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '-' || r == '.'
+}
+
+func isEmailRune(r rune) bool {
+	return r < utf8.RuneSelf && (unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '.' || r == '-' || r == '+')
+}
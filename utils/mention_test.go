@@ -0,0 +1,54 @@
+package utils_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/yzletter/go-postery/utils"
+)
+
+func TestParseMentions(t *testing.T) {
+	cases := []struct {
+		text string
+		want []string
+	}{
+		{"hi @bob and @alice.", []string{"bob", "alice"}},
+		{"(@bob) @bob...", []string{"bob", "bob"}},
+		{"mail me at bob@example.com", nil},
+		{"@bob@alice", []string{"bob"}},
+		{"你好@小明 早", []string{"小明"}},
+		{"@ alone and trailing @", nil},
+		{"@go-lang_fan_1", []string{"go-lang_fan_1"}},
+	}
+	for _, c := range cases {
+		var got []string
+		for _, span := range utils.ParseMentions(c.text) {
+			if c.text[span.Start:span.End] != "@"+span.Username {
+				t.Fatalf("ParseMentions(%q) span %q, want %q", c.text, c.text[span.Start:span.End], "@"+span.Username)
+			}
+			got = append(got, span.Username)
+		}
+		if !reflect.DeepEqual(got, c.want) {
+			t.Fatalf("ParseMentions(%q) = %q, want %q", c.text, got, c.want)
+		}
+	}
+}
+
+// go test -v ./utils -run=^TestParseMentions$ -count=1
+
+func TestUTF16Len(t *testing.T) {
+	cases := map[string]int{
+		"":     0,
+		"abc":  3,
+		"你好":   2,
+		"a😀b":  4,
+		"@小明😀": 5,
+	}
+	for s, want := range cases {
+		if got := utils.UTF16Len(s); got != want {
+			t.Fatalf("UTF16Len(%q) = %d, want %d", s, got, want)
+		}
+	}
+}
+
+// go test -v ./utils -run=^TestUTF16Len$ -count=1
@@ -0,0 +1,11 @@
+package utils
+
+import "math"
+
+// GravityScore 按 points / (ageHours + offset) ^ gravity 计算随时间衰减的热度
+func GravityScore(points, ageHours, gravity, offset float64) float64 {
+	if ageHours < 0 {
+		ageHours = 0
+	}
+	return points / math.Pow(ageHours+offset, gravity)
+}
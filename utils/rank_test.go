@@ -0,0 +1,28 @@
+package utils_test
+
+import (
+	"testing"
+
+	"github.com/yzletter/go-postery/utils"
+)
+
+func TestGravityScore(t *testing.T) {
+	// 同样的互动, 越新的帖子分数越高
+	fresh := utils.GravityScore(100, 1, 1.8, 2)
+	old := utils.GravityScore(100, 48, 1.8, 2)
+	if fresh <= old {
+		t.Fatalf("fresh score %f should be greater than old score %f", fresh, old)
+	}
+
+	// gravity 为 0 时不衰减
+	if got := utils.GravityScore(100, 48, 0, 2); got != 100 {
+		t.Fatalf("score without gravity = %f, want 100", got)
+	}
+
+	// 时钟回拨时按 0 小时处理
+	if utils.GravityScore(100, -1, 1.8, 2) != utils.GravityScore(100, 0, 1.8, 2) {
+		t.Fatal("negative age should be treated as zero")
+	}
+}
+
+// go test -v ./utils -run=^TestGravityScore$ -count=1
@@ -52,6 +52,20 @@ func Error(ctx *gin.Context, err error) {
 	})
 }
 
+// ErrorWithData 失败, 同时返回数据, 例如冲突时的当前版本
+func ErrorWithData(ctx *gin.Context, err error, data interface{}) {
+	var e *errno.Error
+	if errors.As(err, &e) && e != nil {
+		ctx.JSON(e.HTTPStatus, Response{
+			Code: e.Code,
+			Msg:  e.Msg,
+			Data: data,
+		})
+		return
+	}
+	Error(ctx, err)
+}
+
 func failWithHTTP(ctx *gin.Context, httpCode int, code int, msg string) {
 	ctx.JSON(httpCode, Response{
 		Code: code,
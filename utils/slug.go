@@ -43,3 +43,50 @@ func Slugify(name string) string {
 
 	return slug + "-" + hash
 }
+
+// SlugifyTitle 将帖子标题转为可读的 slug: 中文转拼音, 英文和数字保留, 其余字符作为分隔符;
+// 单词之间以 - 连接, 超过 maxLen 时在单词边界截断, 标题中没有可用字符时返回空串
+func SlugifyTitle(title string, maxLen int) string {
+	title = strings.ToLower(title)
+	args := pinyin.NewArgs()
+
+	var words []string
+	var word strings.Builder
+	flush := func() {
+		if word.Len() > 0 {
+			words = append(words, word.String())
+			word.Reset()
+		}
+	}
+	for _, r := range title {
+		switch {
+		case unicode.Is(unicode.Han, r):
+			flush()
+			if py := pinyin.Pinyin(string(r), args); len(py) > 0 && len(py[0]) > 0 && py[0][0] != "" {
+				words = append(words, py[0][0])
+			}
+		case (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9'):
+			word.WriteRune(r)
+		default:
+			flush()
+		}
+	}
+	flush()
+
+	var sb strings.Builder
+	for _, w := range words {
+		if sb.Len() > 0 && sb.Len()+1+len(w) > maxLen {
+			break
+		}
+		if sb.Len() > 0 {
+			sb.WriteByte('-')
+		}
+		sb.WriteString(w)
+	}
+	slug := sb.String()
+	if len(slug) > maxLen {
+		// 第一个单词就超长时直接截断
+		slug = slug[:maxLen]
+	}
+	return slug
+}
@@ -22,3 +22,27 @@ func TestSlug(t *testing.T) {
 }
 
 // go test -v ./utils -run=^TestSlug$ -count=1
+
+func TestSlugifyTitle(t *testing.T) {
+	// 标点和空白作为分隔符, 连续分隔符只保留一个
+	if got := utils.SlugifyTitle("Hello, World!  Go 1.22", 64); got != "hello-world-go-1-22" {
+		t.Fatalf("SlugifyTitle = %q, want %q", got, "hello-world-go-1-22")
+	}
+
+	// 中文逐字转拼音
+	if got := utils.SlugifyTitle("Go 并发入门", 64); got != "go-bing-fa-ru-men" {
+		t.Fatalf("SlugifyTitle = %q, want %q", got, "go-bing-fa-ru-men")
+	}
+
+	// 在单词边界截断
+	if got := utils.SlugifyTitle("alpha beta gamma", 12); got != "alpha-beta" {
+		t.Fatalf("SlugifyTitle = %q, want %q", got, "alpha-beta")
+	}
+
+	// 没有可用字符
+	if got := utils.SlugifyTitle("!!!", 64); got != "" {
+		t.Fatalf("SlugifyTitle = %q, want %q", got, "")
+	}
+}
+
+// go test -v ./utils -run=^TestSlugifyTitle$ -count=1
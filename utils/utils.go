@@ -19,3 +19,17 @@ func GetUidFromCTX(ctx *gin.Context, key string) (int64, error) {
 	slog.Info("Get Uid From CTX Success", "uid", uid)
 	return uid, nil
 }
+
+// UniqueIDs 去掉重复和为 0 的 ID, 保持原有顺序
+func UniqueIDs(ids []int64) []int64 {
+	seen := make(map[int64]struct{}, len(ids))
+	res := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		if _, ok := seen[id]; ok || id == 0 {
+			continue
+		}
+		seen[id] = struct{}{}
+		res = append(res, id)
+	}
+	return res
+}